@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"tradingplatform/backtest/handler"
+	"tradingplatform/shared/logging"
+	"tradingplatform/shared/requests"
+	"tradingplatform/shared/types"
+
+	"github.com/spf13/cobra"
+)
+
+// Run a backtest
+func NewRunCmd() *cobra.Command {
+	runCmd := cobra.Command{
+		Use:   "run",
+		Short: "Run a strategy against the market data of the datastorage",
+		Long: `Run a strategy against the bars, trades, quotes and news of symbols stored by the datastorage.
+		The equity curve, the trade log and summary statistics are returned as JSON.
+		Built-in strategies: buy-and-hold (quantity), sma-crossover (fast, slow, quantity) and
+		news-sentiment (quantity, llm).`,
+		Run: func(cmd *cobra.Command, args []string) {
+			strategyName, _ := cmd.Flags().GetString("strategy")
+			parameters, _ := cmd.Flags().GetStringToString("parameters")
+			source, _ := cmd.Flags().GetString("source")
+			assetClass, _ := cmd.Flags().GetString("asset-class")
+			symbols, _ := cmd.Flags().GetStringArray("symbols")
+			dataTypes, _ := cmd.Flags().GetStringArray("data-types")
+			startTime, _ := cmd.Flags().GetInt64("start-time")
+			endTime, _ := cmd.Flags().GetInt64("end-time")
+			timeFrame, _ := cmd.Flags().GetString("time-frame")
+			initialCash, _ := cmd.Flags().GetFloat64("initial-cash")
+			slippage, _ := cmd.Flags().GetFloat64("slippage")
+			commission, _ := cmd.Flags().GetFloat64("commission")
+			commissionRate, _ := cmd.Flags().GetFloat64("commission-rate")
+
+			req, err := requests.NewBacktestRequestFromRaw(strategyName,
+				parameters,
+				source,
+				assetClass,
+				symbols,
+				dataTypes,
+				startTime,
+				endTime,
+				timeFrame,
+				initialCash,
+				slippage,
+				commission,
+				commissionRate,
+				requests.DefaultForEmptyBacktestRequest)
+
+			logging.Log().Info().
+				RawJSON("backtestRequest", req.JSON()).
+				Msg("receiving backtest request")
+
+			if err != nil {
+				cmd.Print(types.NewBacktestError(err).Respond())
+				return
+			}
+
+			cmd.Print(handler.HandleBacktestRequest(cmd.Context(), req).Respond())
+		},
+	}
+
+	runCmd.Flags().StringP("strategy", "r", "",
+		"Name of the strategy")
+	runCmd.Flags().StringToStringP("parameters", "p", map[string]string{},
+		"Parameters of the strategy (e.g. fast=10,slow=30)")
+	runCmd.Flags().StringP("source", "s", "",
+		"Source of the data")
+	runCmd.Flags().StringP("asset-class", "a", "",
+		"Asset class")
+	runCmd.Flags().StringArrayP("symbols", "y", []string{},
+		"Symbols")
+	runCmd.Flags().StringArrayP("data-types", "t", []string{},
+		"Types of data the strategy receives (bar, trades, quotes and raw-text for news), bar by default")
+	runCmd.Flags().Int64P("start-time", "b", 0,
+		"Start time of the backtest (unix nanoseconds, seconds are also accepted)")
+	runCmd.Flags().Int64P("end-time", "e", 0,
+		"End time of the backtest (unix nanoseconds, seconds are also accepted)")
+	runCmd.Flags().StringP("time-frame", "f", "",
+		"Time frame of the bars")
+	runCmd.Flags().Float64P("initial-cash", "c", 0,
+		"Initial cash of the simulated account")
+	runCmd.Flags().Float64P("slippage", "l", 0,
+		"Slippage applied against market order fills, in basis points of the price")
+	runCmd.Flags().Float64P("commission", "m", 0,
+		"Commission per unit filled")
+	runCmd.Flags().Float64P("commission-rate", "k", 0,
+		"Commission in basis points of the notional filled")
+
+	return &runCmd
+}
@@ -0,0 +1,76 @@
+package local
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"tradingplatform/backtest/command/cli"
+	"tradingplatform/backtest/command/json"
+	"tradingplatform/backtest/strategy"
+	"tradingplatform/shared/communication"
+	"tradingplatform/shared/communication/command"
+	"tradingplatform/shared/logging"
+	"tradingplatform/shared/metrics"
+	"tradingplatform/shared/tracing"
+	"tradingplatform/shared/types"
+	"tradingplatform/shared/utils"
+
+	"github.com/nats-io/nats.go"
+	"github.com/spf13/cobra"
+)
+
+func NewRootCmd() *cobra.Command {
+	rootCmd := cobra.Command{
+		Use:   "backtest",
+		Short: "Backtest startup command",
+		Run: func(cmd *cobra.Command, args []string) {
+			natsURL, _ := cmd.Flags().GetString("nats-url")
+			communication.SetNatsURL(natsURL)
+			nc, err := nats.Connect(communication.GetNatsURL())
+			if err != nil {
+				panic(err)
+			}
+			defer nc.Close()
+
+			loggingTopic := utils.NewLoggingTopic(types.Backtest).Generate()
+			mlLogger := logging.NewMultiLevelLogger(types.Backtest,
+				os.Stdout, logging.NewNatsWriter(nc, loggingTopic))
+			logging.SetLogger(&mlLogger)
+			shutdownTracing, err := tracing.InitFromFlags(types.Backtest, cmd.Flags())
+			if err != nil {
+				panic(err)
+			}
+			defer shutdownTracing()
+
+			logging.Log().Info().
+				Str("natsUrl", communication.GetNatsURL()).
+				Msg("starting backtest, remote logging enabled")
+
+			strategy.RegisterStrategies()
+
+			// Create a channel to receive OS signals
+			sigs := make(chan os.Signal, 1)
+
+			// Register the channel to receive SIGINT and SIGTERM signals
+			signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+
+			command.StartCommandHandler(types.Backtest, cli.NewRootCmd, json.HandleJSONCommand)
+			cmdHandler := command.GetCommandHandler()
+			command.StartHealthServerFromFlags(cmd.Flags())
+			metrics.StartServerFromFlags(cmdHandler.Ctx(), cmd.Flags())
+
+			go func() {
+				<-sigs
+				cmdHandler.Cancel()
+			}()
+			<-cmdHandler.Ctx().Done()
+			cmdHandler.Wg.Wait()
+		},
+	}
+	rootCmd.Flags().StringP("nats-url", "n", communication.GetNatsURL(), "NATS server URL")
+	command.AddHealthFlags(rootCmd.Flags())
+	metrics.AddMetricsFlags(rootCmd.Flags())
+	tracing.AddTracingFlags(rootCmd.Flags())
+	return &rootCmd
+}
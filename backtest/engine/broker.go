@@ -0,0 +1,224 @@
+package engine
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"tradingplatform/backtest/strategy"
+	"tradingplatform/shared/entities"
+	"tradingplatform/shared/requests"
+	"tradingplatform/shared/types"
+)
+
+type pendingOrder struct {
+	id    int64
+	order strategy.Order
+	// Time at which the order was submitted
+	submitted int64
+}
+
+// The simulated account of a backtest, orders are filled against the prices of the events following
+// their submission. Cash and positions are not constrained, a strategy can borrow cash and sell short
+type broker struct {
+	req       requests.BacktestRequest
+	symbols   map[string]bool
+	cash      float64
+	positions map[string]*entities.Position
+	// Last price of each symbol
+	prices map[string]float64
+	orders []pendingOrder
+	nextID int64
+	now    int64
+
+	fills      []types.BacktestFill
+	traded     float64
+	commission float64
+}
+
+func newBroker(req requests.BacktestRequest) *broker {
+	b := &broker{
+		req:       req,
+		symbols:   make(map[string]bool),
+		cash:      req.InitialCash,
+		positions: make(map[string]*entities.Position),
+		prices:    make(map[string]float64),
+		now:       req.StartTime,
+	}
+	for _, symbol := range req.Symbols {
+		b.symbols[symbol] = true
+	}
+	return b
+}
+
+func (b *broker) SubmitOrder(order strategy.Order) (int64, error) {
+	if !b.symbols[order.Symbol] {
+		return 0, fmt.Errorf("symbol %s is not part of the backtest", order.Symbol)
+	}
+	if order.Side != strategy.Buy && order.Side != strategy.Sell {
+		return 0, fmt.Errorf("invalid order side %s", order.Side)
+	}
+	if order.Quantity <= 0 {
+		return 0, fmt.Errorf("order quantity must be positive, got %f", order.Quantity)
+	}
+	switch order.Type {
+	case strategy.Market:
+	case strategy.Limit:
+		if order.LimitPrice <= 0 {
+			return 0, fmt.Errorf("limit price must be positive, got %f", order.LimitPrice)
+		}
+	default:
+		return 0, fmt.Errorf("invalid order type %s", order.Type)
+	}
+	b.nextID++
+	b.orders = append(b.orders, pendingOrder{id: b.nextID, order: order, submitted: b.now})
+	return b.nextID, nil
+}
+
+func (b *broker) CancelOrder(id int64) bool {
+	for i, pending := range b.orders {
+		if pending.id == id {
+			b.orders = append(b.orders[:i], b.orders[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (b *broker) Position(symbol string) float64 {
+	if p, ok := b.positions[symbol]; ok {
+		return p.Quantity
+	}
+	return 0
+}
+
+func (b *broker) Cash() float64 {
+	return b.cash
+}
+
+func (b *broker) Equity() float64 {
+	equity := b.cash
+	for symbol, p := range b.positions {
+		equity += p.Quantity * b.prices[symbol]
+	}
+	return equity
+}
+
+func (b *broker) Time() time.Time {
+	return time.Unix(0, b.now)
+}
+
+// Price at which an order is filled by an event, false if the event does not fill it
+func (b *broker) fillPrice(pending pendingOrder, e *Event) (float64, bool) {
+	order := pending.order
+	buy := order.Side == strategy.Buy
+	var price float64
+	// Best price reached by the event for the order, checked against the limit price
+	var best float64
+	switch {
+	case e.Bar != nil:
+		// A bar starting before the order was submitted only fills it at its close
+		price = e.Bar.Close
+		best = e.Bar.Close
+		if pending.submitted <= e.Bar.TimestampNs {
+			price = e.Bar.Open
+			best = e.Bar.High
+			if buy {
+				best = e.Bar.Low
+			}
+		}
+	case e.Trade != nil:
+		// Corrections and cancels of trades stored from the streams do not fill orders
+		if e.Trade.Update != "" {
+			return 0, false
+		}
+		price = e.Trade.Price
+		best = price
+	case e.Quote != nil:
+		price = e.Quote.BidPrice
+		if buy {
+			price = e.Quote.AskPrice
+		}
+		best = price
+	}
+	if price <= 0 {
+		return 0, false
+	}
+	if order.Type == strategy.Market {
+		slippage := price * b.req.Slippage / 10000
+		if buy {
+			return price + slippage, true
+		}
+		return price - slippage, true
+	}
+	if buy && best <= order.LimitPrice {
+		return math.Min(price, order.LimitPrice), true
+	}
+	if !buy && best >= order.LimitPrice {
+		return math.Max(price, order.LimitPrice), true
+	}
+	return 0, false
+}
+
+// Fill the orders of the symbol of an event and update the last price of the symbol
+func (b *broker) handleEvent(e *Event) {
+	b.now = e.Timestamp
+	symbol := e.symbol()
+	if symbol == "" {
+		return
+	}
+
+	remaining := b.orders[:0]
+	for _, pending := range b.orders {
+		if pending.order.Symbol != symbol {
+			remaining = append(remaining, pending)
+			continue
+		}
+		price, ok := b.fillPrice(pending, e)
+		if !ok {
+			remaining = append(remaining, pending)
+			continue
+		}
+		b.fill(pending, price)
+	}
+	b.orders = remaining
+
+	switch {
+	case e.Bar != nil:
+		b.prices[symbol] = e.Bar.Close
+	case e.Trade != nil && e.Trade.Update == "":
+		b.prices[symbol] = e.Trade.Price
+	case e.Quote != nil && e.Quote.BidPrice > 0 && e.Quote.AskPrice > 0:
+		b.prices[symbol] = (e.Quote.BidPrice + e.Quote.AskPrice) / 2
+	}
+}
+
+func (b *broker) fill(pending pendingOrder, price float64) {
+	order := pending.order
+	quantity := order.Quantity
+	if order.Side == strategy.Sell {
+		quantity = -quantity
+	}
+	notional := order.Quantity * price
+	commission := order.Quantity*b.req.Commission + notional*b.req.CommissionRate/10000
+
+	p, ok := b.positions[order.Symbol]
+	if !ok {
+		p = &entities.Position{Symbol: order.Symbol}
+		b.positions[order.Symbol] = p
+	}
+	realized := p.Apply(quantity, price)
+	b.cash -= quantity*price + commission
+	b.traded += notional
+	b.commission += commission
+	b.fills = append(b.fills, types.BacktestFill{
+		OrderID:     pending.id,
+		Timestamp:   b.now,
+		Symbol:      order.Symbol,
+		Side:        string(order.Side),
+		Quantity:    order.Quantity,
+		Price:       price,
+		Commission:  commission,
+		RealizedPnL: realized,
+	})
+}
@@ -0,0 +1,72 @@
+package engine
+
+import (
+	"context"
+
+	"tradingplatform/backtest/strategy"
+	"tradingplatform/shared/requests"
+	"tradingplatform/shared/types"
+)
+
+// Number of events handled between checks of the cancellation of a backtest
+const cancelCheckInterval = 1000
+
+// Result of a backtest
+type Result struct {
+	Summary     types.BacktestSummary
+	EquityCurve []types.EquityPoint
+	Trades      []types.BacktestFill
+}
+
+// Run a strategy on streams of events ordered by timestamp, the events of all streams are passed to
+// the strategy in a single time order. The equity curve has a point at the start of the backtest and
+// after the events of each timestamp. The backtest fails if a stream ends with an error
+func Run(ctx context.Context, s strategy.Strategy, req requests.BacktestRequest, streams []EventStream) (Result, error) {
+	b := newBroker(req)
+	if err := s.Init(b, req.Parameters); err != nil {
+		return Result{}, err
+	}
+
+	curve := []types.EquityPoint{{Timestamp: req.StartTime, Equity: req.InitialCash, Cash: req.InitialCash}}
+	merger := newEventMerger(streams)
+	events := 0
+	for {
+		e, ok := merger.next()
+		if !ok {
+			break
+		}
+		events++
+		if events%cancelCheckInterval == 0 && ctx.Err() != nil {
+			return Result{}, ctx.Err()
+		}
+
+		b.handleEvent(&e)
+		switch {
+		case e.Bar != nil:
+			s.OnBar(e.Bar)
+		case e.Trade != nil:
+			s.OnTrade(e.Trade)
+		case e.Quote != nil:
+			s.OnQuote(e.Quote)
+		case e.News != nil:
+			s.OnNews(e.News)
+		}
+
+		point := types.EquityPoint{Timestamp: e.Timestamp, Equity: b.Equity(), Cash: b.cash}
+		if last := &curve[len(curve)-1]; last.Timestamp == e.Timestamp {
+			*last = point
+		} else {
+			curve = append(curve, point)
+		}
+	}
+
+	if err := merger.err(); err != nil {
+		return Result{}, err
+	}
+
+	return Result{
+		Summary:     summarize(req.InitialCash, curve, b.fills, b.traded, b.commission, events),
+		EquityCurve: curve,
+		Trades:      b.fills,
+	}, nil
+}
@@ -0,0 +1,136 @@
+package engine
+
+import (
+	"container/heap"
+
+	"tradingplatform/shared/entities"
+)
+
+// An event of the market data of a backtest, one of the entities is set. The timestamp is the time
+// at which the data is known, the end of the period of a bar
+type Event struct {
+	Timestamp int64
+	Bar       *entities.Bar
+	Trade     *entities.Trade
+	Quote     *entities.Quote
+	News      *entities.News
+}
+
+// Symbol of the price carried by the event, empty for news
+func (e *Event) symbol() string {
+	switch {
+	case e.Bar != nil:
+		return e.Bar.Symbol
+	case e.Trade != nil:
+		return e.Trade.Symbol
+	case e.Quote != nil:
+		return e.Quote.Symbol
+	}
+	return ""
+}
+
+// EventStream is a stream of events ordered by timestamp, read as the backtest advances
+type EventStream interface {
+	// Next returns the next event, false once the stream is exhausted
+	Next() (Event, bool)
+	// Err returns the error that ended the stream early, if any
+	Err() error
+}
+
+// A stream of events held in memory
+type sliceStream struct {
+	events []Event
+}
+
+// NewSliceStream returns a stream of events held in memory, ordered by timestamp
+func NewSliceStream(events []Event) EventStream {
+	return &sliceStream{events: events}
+}
+
+func (s *sliceStream) Next() (Event, bool) {
+	if len(s.events) == 0 {
+		return Event{}, false
+	}
+	e := s.events[0]
+	s.events = s.events[1:]
+	return e, true
+}
+
+func (s *sliceStream) Err() error {
+	return nil
+}
+
+// Merges streams of events ordered by timestamp into a single stream, events of the same time are
+// taken in the order of their streams. Each stream is read one event ahead
+type eventMerger struct {
+	streams []EventStream
+	// Next event of each stream
+	peeked []Event
+	// Streams with events left, ordered by the timestamp of their next event
+	heads []int
+}
+
+func newEventMerger(streams []EventStream) *eventMerger {
+	m := &eventMerger{streams: streams, peeked: make([]Event, len(streams))}
+	for i, stream := range streams {
+		if e, ok := stream.Next(); ok {
+			m.peeked[i] = e
+			m.heads = append(m.heads, i)
+		}
+	}
+	heap.Init(m)
+	return m
+}
+
+// Next returns the next event, false once all streams are exhausted
+func (m *eventMerger) next() (Event, bool) {
+	if len(m.heads) == 0 {
+		return Event{}, false
+	}
+	i := m.heads[0]
+	e := m.peeked[i]
+	if next, ok := m.streams[i].Next(); ok {
+		m.peeked[i] = next
+		heap.Fix(m, 0)
+	} else {
+		m.peeked[i] = Event{}
+		heap.Pop(m)
+	}
+	return e, true
+}
+
+// Err returns the first error that ended a stream early
+func (m *eventMerger) err() error {
+	for _, stream := range m.streams {
+		if err := stream.Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *eventMerger) Len() int {
+	return len(m.heads)
+}
+
+func (m *eventMerger) Less(i, j int) bool {
+	a, b := m.peeked[m.heads[i]].Timestamp, m.peeked[m.heads[j]].Timestamp
+	if a != b {
+		return a < b
+	}
+	return m.heads[i] < m.heads[j]
+}
+
+func (m *eventMerger) Swap(i, j int) {
+	m.heads[i], m.heads[j] = m.heads[j], m.heads[i]
+}
+
+func (m *eventMerger) Push(x any) {
+	m.heads = append(m.heads, x.(int))
+}
+
+func (m *eventMerger) Pop() any {
+	last := m.heads[len(m.heads)-1]
+	m.heads = m.heads[:len(m.heads)-1]
+	return last
+}
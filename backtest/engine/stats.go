@@ -0,0 +1,81 @@
+package engine
+
+import (
+	"math"
+	"time"
+
+	"tradingplatform/shared/types"
+)
+
+const year = 365 * 24 * time.Hour
+
+func summarize(initialCash float64,
+	curve []types.EquityPoint,
+	fills []types.BacktestFill,
+	traded float64,
+	commission float64,
+	events int) types.BacktestSummary {
+	final := curve[len(curve)-1].Equity
+	summary := types.BacktestSummary{
+		InitialCash: initialCash,
+		FinalEquity: final,
+		TotalReturn: final/initialCash - 1,
+		Sharpe:      sharpe(curve),
+		MaxDrawdown: maxDrawdown(curve),
+		Fills:       len(fills),
+		Commission:  commission,
+		Events:      events,
+	}
+	var total float64
+	for _, point := range curve {
+		total += point.Equity
+	}
+	if average := total / float64(len(curve)); average > 0 {
+		summary.Turnover = traded / average
+	}
+	return summary
+}
+
+// Annualized Sharpe ratio of the returns between the points of the equity curve, the number of
+// periods in a year is derived from the average time between the points
+func sharpe(curve []types.EquityPoint) float64 {
+	var returns []float64
+	for i := 1; i < len(curve); i++ {
+		if curve[i-1].Equity <= 0 {
+			continue
+		}
+		returns = append(returns, curve[i].Equity/curve[i-1].Equity-1)
+	}
+	if len(returns) < 2 {
+		return 0
+	}
+	var mean float64
+	for _, r := range returns {
+		mean += r
+	}
+	mean /= float64(len(returns))
+	var variance float64
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+	}
+	std := math.Sqrt(variance / float64(len(returns)-1))
+	interval := float64(curve[len(curve)-1].Timestamp-curve[0].Timestamp) / float64(len(curve)-1)
+	if std == 0 || interval <= 0 {
+		return 0
+	}
+	return mean / std * math.Sqrt(float64(year)/interval)
+}
+
+// Largest decline of the equity from a previous peak, as a fraction of the peak
+func maxDrawdown(curve []types.EquityPoint) float64 {
+	var peak, drawdown float64
+	for _, point := range curve {
+		if point.Equity > peak {
+			peak = point.Equity
+		}
+		if peak > 0 {
+			drawdown = math.Max(drawdown, (peak-point.Equity)/peak)
+		}
+	}
+	return drawdown
+}
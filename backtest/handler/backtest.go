@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"tradingplatform/backtest/engine"
+	"tradingplatform/backtest/strategy"
+	"tradingplatform/shared/calendar"
+	"tradingplatform/shared/communication"
+	"tradingplatform/shared/entities"
+	"tradingplatform/shared/logging"
+	"tradingplatform/shared/requests"
+	"tradingplatform/shared/types"
+	"tradingplatform/shared/utils"
+)
+
+// HandleBacktestRequest runs the strategy of a request against the data of the datastorage
+func HandleBacktestRequest(ctx context.Context, req requests.BacktestRequest) types.BacktestResponse {
+	logging.Log().Debug().RawJSON("request", req.JSON()).Msg("handling backtest request")
+
+	s, err := strategy.New(req.Strategy)
+	if err != nil {
+		return types.NewBacktestError(fmt.Errorf("%v, registered strategies: %v", err, strategy.GetStrategies()))
+	}
+	// The streams are read until the backtest is over
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	streams, err := loadEvents(ctx, req)
+	if err != nil {
+		logging.Log().Error().Err(err).RawJSON("request", req.JSON()).Msg("loading backtest data")
+		return types.NewBacktestError(err)
+	}
+	result, err := engine.Run(ctx, s, req, streams)
+	if err == nil && result.Summary.Events == 0 {
+		err = fmt.Errorf("%w for the symbols %v in the datastorage", types.ErrNoData, req.Symbols)
+	}
+	if err != nil {
+		logging.Log().Error().Err(err).RawJSON("request", req.JSON()).Msg("running backtest")
+		return types.NewBacktestError(err)
+	}
+	logging.Log().Info().
+		Str("strategy", req.Strategy).
+		Int("events", result.Summary.Events).
+		Int("fills", result.Summary.Fills).
+		Float64("totalReturn", result.Summary.TotalReturn).
+		Msg("finished backtest")
+
+	return types.NewBacktestResponse(types.Success,
+		"Successfully ran backtest",
+		nil,
+		result.Summary,
+		result.EquityCurve,
+		result.Trades)
+}
+
+// Request the data of each symbol and data type of a backtest from the datastorage, each stream is
+// ordered by the time at which its data is known. The data queues are read as the backtest advances,
+// except for the news which are ordered by their last update once received. Data missing from the
+// datastorage leaves its stream empty
+func loadEvents(ctx context.Context, req requests.BacktestRequest) ([]engine.EventStream, error) {
+	cal := calendar.ForAssetClass(req.AssetClass)
+	var streams []engine.EventStream
+	for _, symbol := range req.Symbols {
+		for _, dtype := range req.DataTypes {
+			dataRequest := req.DataRequest(symbol, dtype)
+			var toEvent func(*entities.Message, func(engine.Event))
+			switch dtype {
+			case types.Bar:
+				toEvent = func(msg *entities.Message, emit func(engine.Event)) {
+					utils.HandleEntity(msg, &entities.Bar{}, func(bar *entities.Bar) error {
+						emit(engine.Event{Timestamp: barEnd(cal, req.TimeFrame, bar), Bar: bar})
+						return nil
+					})
+				}
+			case types.Trades:
+				toEvent = func(msg *entities.Message, emit func(engine.Event)) {
+					utils.HandleEntity(msg, &entities.Trade{}, func(trade *entities.Trade) error {
+						emit(engine.Event{Timestamp: trade.TimestampNs, Trade: trade})
+						return nil
+					})
+				}
+			case types.Quotes:
+				toEvent = func(msg *entities.Message, emit func(engine.Event)) {
+					utils.HandleEntity(msg, &entities.Quote{}, func(quote *entities.Quote) error {
+						emit(engine.Event{Timestamp: quote.TimestampNs, Quote: quote})
+						return nil
+					})
+				}
+			case types.RawText:
+				stream, err := loadNews(ctx, dataRequest)
+				if err != nil {
+					return nil, err
+				}
+				streams = append(streams, stream)
+				continue
+			default:
+				continue
+			}
+			streams = append(streams, newQueueStream(ctx, dataRequest, toEvent))
+		}
+	}
+	return streams, nil
+}
+
+// Request the news of a backtest, they are known from their last update on
+func loadNews(ctx context.Context, dataRequest requests.DataRequest) (engine.EventStream, error) {
+	var events []engine.Event
+	err := requests.RequestData(ctx, utils.NewCommandTopic(types.DataStorage), dataRequest, func(msg *entities.Message) {
+		utils.HandleEntity(msg, &entities.News{}, func(news *entities.News) error {
+			events = append(events, engine.Event{Timestamp: news.UpdatedAtNs, News: news})
+			return nil
+		})
+	})
+	if requests.IsNoDataError(err) {
+		logging.Log().Warn().
+			RawJSON("request", dataRequest.JSON()).
+			Msg("no data for backtest stream")
+		err = nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp < events[j].Timestamp
+	})
+	return engine.NewSliceStream(events), nil
+}
+
+// A stream of events read from a data queue of the datastorage as the backtest advances, the queue
+// is received in the background at most a window of messages ahead
+type queueStream struct {
+	events chan engine.Event
+	err    error
+}
+
+func newQueueStream(ctx context.Context, dataRequest requests.DataRequest, toEvent func(*entities.Message, func(engine.Event))) *queueStream {
+	s := &queueStream{events: make(chan engine.Event, communication.DEFAULT_QUEUE_WINDOW)}
+	go func() {
+		defer close(s.events)
+		err := requests.RequestData(ctx, utils.NewCommandTopic(types.DataStorage), dataRequest, func(msg *entities.Message) {
+			toEvent(msg, func(e engine.Event) {
+				select {
+				case s.events <- e:
+				case <-ctx.Done():
+				}
+			})
+		})
+		if requests.IsNoDataError(err) {
+			logging.Log().Warn().
+				RawJSON("request", dataRequest.JSON()).
+				Msg("no data for backtest stream")
+			return
+		}
+		// The error is read once the channel is closed
+		s.err = err
+	}()
+	return s
+}
+
+func (s *queueStream) Next() (engine.Event, bool) {
+	e, ok := <-s.events
+	return e, ok
+}
+
+func (s *queueStream) Err() error {
+	return s.err
+}
+
+// Time at which a bar is known, the end of its period. Bars of the timeframes of the brokers are
+// aligned to the clock and resampled bars to the trading sessions, the later end of both is used so
+// that a bar is never known before its period is over
+func barEnd(cal calendar.Calendar, timeFrame types.TimeFrame, bar *entities.Bar) int64 {
+	t := time.Unix(0, bar.TimestampNs)
+	end := bar.TimestampNs
+	if period, err := calendar.BarPeriod(cal, timeFrame, t); err == nil && period.End.UnixNano() > end {
+		end = period.End.UnixNano()
+	}
+	if period, err := calendar.SessionBarPeriod(cal, timeFrame, t); err == nil && period.End.UnixNano() > end {
+		end = period.End.UnixNano()
+	}
+	return end
+}
@@ -0,0 +1,139 @@
+package strategy
+
+import (
+	"fmt"
+	"math"
+
+	"tradingplatform/shared/entities"
+	"tradingplatform/shared/indicators"
+)
+
+// Register the strategies available to the backtest component
+func RegisterStrategies() {
+	Register("buy-and-hold", func() Strategy { return &buyAndHold{} })
+	Register("sma-crossover", func() Strategy { return &smaCrossover{} })
+	Register("news-sentiment", func() Strategy { return &newsSentiment{} })
+}
+
+// Buys each symbol on its first price and holds it. Parameters: quantity, the quantity bought of each
+// symbol, as much as the cash allows if it is not given
+type buyAndHold struct {
+	Base
+	quantity float64
+	bought   map[string]bool
+}
+
+func (s *buyAndHold) Init(broker Broker, parameters map[string]string) error {
+	s.Broker = broker
+	s.bought = make(map[string]bool)
+	var err error
+	s.quantity, err = FloatParameter(parameters, "quantity", 0)
+	return err
+}
+
+func (s *buyAndHold) buy(symbol string, price float64) {
+	if s.bought[symbol] || price <= 0 {
+		return
+	}
+	s.bought[symbol] = true
+	quantity := s.quantity
+	if quantity == 0 {
+		quantity = math.Floor(s.Broker.Cash() / price)
+	}
+	if quantity > 0 {
+		s.Broker.SubmitOrder(Order{Symbol: symbol, Side: Buy, Type: Market, Quantity: quantity})
+	}
+}
+
+func (s *buyAndHold) OnBar(bar *entities.Bar) {
+	s.buy(bar.Symbol, bar.Close)
+}
+
+func (s *buyAndHold) OnTrade(trade *entities.Trade) {
+	if trade.Update != "" {
+		return
+	}
+	s.buy(trade.Symbol, trade.Price)
+}
+
+func (s *buyAndHold) OnQuote(quote *entities.Quote) {
+	s.buy(quote.Symbol, quote.AskPrice)
+}
+
+// Holds a long position while the fast simple moving average of the closes of a symbol is above the
+// slow one. Parameters: fast (10), slow (30) and quantity (1)
+type smaCrossover struct {
+	Base
+	quantity float64
+	fast     indicators.Spec
+	slow     indicators.Spec
+	averages map[string][2]indicators.Indicator
+}
+
+func (s *smaCrossover) Init(broker Broker, parameters map[string]string) error {
+	s.Broker = broker
+	s.averages = make(map[string][2]indicators.Indicator)
+	fast, err := IntParameter(parameters, "fast", 10)
+	if err != nil {
+		return err
+	}
+	slow, err := IntParameter(parameters, "slow", 30)
+	if err != nil {
+		return err
+	}
+	if fast <= 0 || fast >= slow {
+		return fmt.Errorf("parameter fast (%d) must be positive and less than slow (%d)", fast, slow)
+	}
+	s.fast = indicators.Spec{Kind: indicators.SMA, Parameters: []int{fast}}
+	s.slow = indicators.Spec{Kind: indicators.SMA, Parameters: []int{slow}}
+	s.quantity, err = FloatParameter(parameters, "quantity", 1)
+	return err
+}
+
+func (s *smaCrossover) OnBar(bar *entities.Bar) {
+	averages, ok := s.averages[bar.Symbol]
+	if !ok {
+		averages = [2]indicators.Indicator{s.fast.New(), s.slow.New()}
+		s.averages[bar.Symbol] = averages
+	}
+	fast, _, fastOk := averages[0].Update(bar)
+	slow, _, slowOk := averages[1].Update(bar)
+	if !fastOk || !slowOk {
+		return
+	}
+	if fast > slow {
+		orderTarget(s.Broker, bar.Symbol, s.quantity)
+	} else {
+		orderTarget(s.Broker, bar.Symbol, 0)
+	}
+}
+
+// Holds a long position in a symbol after positive news about it until negative news. Parameters:
+// quantity (1) and llm, only the sentiments of this model are used if it is given
+type newsSentiment struct {
+	Base
+	quantity float64
+	llm      string
+}
+
+func (s *newsSentiment) Init(broker Broker, parameters map[string]string) error {
+	s.Broker = broker
+	s.llm = parameters["llm"]
+	var err error
+	s.quantity, err = FloatParameter(parameters, "quantity", 1)
+	return err
+}
+
+func (s *newsSentiment) OnNews(news *entities.News) {
+	for _, sentiment := range news.Sentiments {
+		if sentiment.Failed || (s.llm != "" && sentiment.LLM != s.llm) {
+			continue
+		}
+		switch sentiment.Sentiment {
+		case "positive":
+			orderTarget(s.Broker, sentiment.Symbol, s.quantity)
+		case "negative":
+			orderTarget(s.Broker, sentiment.Symbol, 0)
+		}
+	}
+}
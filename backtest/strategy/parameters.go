@@ -0,0 +1,46 @@
+package strategy
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Parameter of a strategy as a whole number, def if it is not given
+func IntParameter(parameters map[string]string, name string, def int) (int, error) {
+	value, ok := parameters[name]
+	if !ok || value == "" {
+		return def, nil
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("parameter %s must be a whole number, got %s", name, value)
+	}
+	return i, nil
+}
+
+// Parameter of a strategy as a number, def if it is not given
+func FloatParameter(parameters map[string]string, name string, def float64) (float64, error) {
+	value, ok := parameters[name]
+	if !ok || value == "" {
+		return def, nil
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parameter %s must be a number, got %s", name, value)
+	}
+	return f, nil
+}
+
+// Submit the market order bringing the position of a symbol to a target quantity
+func orderTarget(broker Broker, symbol string, target float64) {
+	delta := target - broker.Position(symbol)
+	if delta == 0 {
+		return
+	}
+	order := Order{Symbol: symbol, Side: Buy, Type: Market, Quantity: delta}
+	if delta < 0 {
+		order.Side = Sell
+		order.Quantity = -delta
+	}
+	broker.SubmitOrder(order)
+}
@@ -0,0 +1,111 @@
+package strategy
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"tradingplatform/shared/entities"
+)
+
+type Side string
+type OrderType string
+
+const (
+	Buy  Side = "buy"
+	Sell Side = "sell"
+
+	// Market orders are filled at the next price of their symbol
+	Market OrderType = "market"
+	// Limit orders are filled once the price of their symbol reaches their limit price
+	Limit OrderType = "limit"
+)
+
+type Order struct {
+	Symbol     string
+	Side       Side
+	Type       OrderType
+	Quantity   float64
+	LimitPrice float64
+}
+
+// Broker is the simulated account a strategy trades with during a backtest. Orders are filled on the
+// events following their submission so that a strategy never trades on the price it just received
+type Broker interface {
+	// Submit an order, returns the ID of the order
+	SubmitOrder(order Order) (int64, error)
+	// Cancel an order that is not filled yet, returns whether it was found
+	CancelOrder(id int64) bool
+	// Quantity held of a symbol, negative for short positions
+	Position(symbol string) float64
+	Cash() float64
+	// Cash plus the value of the positions at their last price
+	Equity() float64
+	// Time of the event being handled
+	Time() time.Time
+}
+
+// Strategy receives the market data of a backtest in time order and trades through its broker
+type Strategy interface {
+	// Init is called before the first event with the broker and the parameters of the request
+	Init(broker Broker, parameters map[string]string) error
+	OnBar(bar *entities.Bar)
+	// OnTrade is also called with the corrections and cancels of trades, their Update field is set
+	OnTrade(trade *entities.Trade)
+	OnQuote(quote *entities.Quote)
+	// OnNews receives the news of the symbols with their sentiments
+	OnNews(news *entities.News)
+}
+
+// Base implements the callbacks of a strategy as no-ops, strategies embed it and implement the
+// callbacks of the data they use
+type Base struct {
+	Broker Broker
+}
+
+func (b *Base) Init(broker Broker, parameters map[string]string) error {
+	b.Broker = broker
+	return nil
+}
+
+func (b *Base) OnBar(bar *entities.Bar) {}
+
+func (b *Base) OnTrade(trade *entities.Trade) {}
+
+func (b *Base) OnQuote(quote *entities.Quote) {}
+
+func (b *Base) OnNews(news *entities.News) {}
+
+var strategies = make(map[string]func() Strategy)
+var strategiesLock sync.RWMutex
+
+// Register a strategy under a name, a new strategy is created for each backtest
+func Register(name string, factory func() Strategy) {
+	strategiesLock.Lock()
+	defer strategiesLock.Unlock()
+	strategies[name] = factory
+}
+
+// New creates the strategy registered under a name
+func New(name string) (Strategy, error) {
+	strategiesLock.RLock()
+	defer strategiesLock.RUnlock()
+	factory, ok := strategies[name]
+	if !ok {
+		return nil, fmt.Errorf("strategy %s is not registered", name)
+	}
+	return factory(), nil
+}
+
+// GetStrategies returns the names of the registered strategies
+func GetStrategies() []string {
+	strategiesLock.RLock()
+	defer strategiesLock.RUnlock()
+	names := make([]string, 0, len(strategies))
+	for name := range strategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,155 @@
+// Package client talks to the components of the platform over NATS. It manages the streams of the
+// dataprovider and the topics stored by the datastorage, subscribes to streamed entities, reads
+// historical data and sentiments as typed entities and reports the failures of the components as
+// *CommandError values.
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"tradingplatform/shared/communication"
+	"tradingplatform/shared/communication/command"
+	"tradingplatform/shared/tracing"
+	"tradingplatform/shared/types"
+	"tradingplatform/shared/utils"
+
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
+)
+
+// Time given to a component to answer the cancel command sent when the context of a command is done
+var cancelTimeout = 5 * time.Second
+
+type Client struct {
+	nc *nats.Conn
+	// Whether the connection was opened by the client and is closed with it
+	ownsConn bool
+}
+
+// Connect to the NATS server of the platform, the default URL of the components is used without URL
+func Connect(url string, options ...nats.Option) (*Client, error) {
+	if url == "" {
+		url = communication.GetNatsURL()
+	}
+	nc, err := nats.Connect(url, options...)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to %s: %w", url, err)
+	}
+	return &Client{nc: nc, ownsConn: true}, nil
+}
+
+// New client using an open connection, the connection is left open when the client is closed
+func New(nc *nats.Conn) *Client {
+	return &Client{nc: nc}
+}
+
+// Conn returns the NATS connection of the client
+func (c *Client) Conn() *nats.Conn {
+	return c.nc
+}
+
+// Close the connection of the client if it opened it
+func (c *Client) Close() {
+	if c.ownsConn {
+		c.nc.Close()
+	}
+}
+
+// Send a JSON command to a component and decode its answer into response
+func (c *Client) command(ctx context.Context,
+	component types.Component,
+	operation command.JSONOperation,
+	request any,
+	response any) error {
+
+	jsonCommand, err := newJSONCommand(operation, request)
+	if err != nil {
+		return err
+	}
+	return c.send(ctx, component, jsonCommand, response)
+}
+
+// Send a JSON command with a cancel key to a component, the command is cancelled on the component
+// when the context is done before the answer
+func (c *Client) cancelableCommand(ctx context.Context,
+	component types.Component,
+	operation command.JSONOperation,
+	request any,
+	response any) error {
+
+	jsonCommand, err := newJSONCommand(operation, request)
+	if err != nil {
+		return err
+	}
+	jsonCommand.CancelKey = getCancelKey(ctx)
+	stop := context.AfterFunc(ctx, func() {
+		cancelCtx, cancel := context.WithTimeout(context.Background(), cancelTimeout)
+		defer cancel()
+		c.Cancel(cancelCtx, component, jsonCommand.CancelKey)
+	})
+	defer stop()
+	return c.send(ctx, component, jsonCommand, response)
+}
+
+type cancelKeyContextKey struct{}
+
+// WithCancelKey returns a context sending the cancelable commands with a cancel key, so that they can
+// also be cancelled with Cancel. A random key is used otherwise
+func WithCancelKey(ctx context.Context, cancelKey string) context.Context {
+	return context.WithValue(ctx, cancelKeyContextKey{}, cancelKey)
+}
+
+func getCancelKey(ctx context.Context) string {
+	if cancelKey, ok := ctx.Value(cancelKeyContextKey{}).(string); ok && cancelKey != "" {
+		return cancelKey
+	}
+	return uuid.New().String()
+}
+
+func newJSONCommand(operation command.JSONOperation, request any) (command.JSONCommand, error) {
+	jsonCommand := command.JSONCommand{RootOperation: operation}
+	if request == nil {
+		return jsonCommand, nil
+	}
+	raw, err := json.Marshal(request)
+	if err != nil {
+		return jsonCommand, fmt.Errorf("encoding %s request: %w", operation, err)
+	}
+	jsonCommand.Request = raw
+	return jsonCommand, nil
+}
+
+// Send a JSON command to a component with the trace context of the context
+func (c *Client) send(ctx context.Context, component types.Component, jsonCommand command.JSONCommand, response any) error {
+	msg := nats.NewMsg(utils.NewCommandTopic(component).Generate())
+	msg.Data = []byte(jsonCommand.JSONWithHeader())
+	tracing.Inject(ctx, msg)
+	reply, err := c.nc.RequestMsgWithContext(ctx, msg)
+	if err != nil {
+		return fmt.Errorf("sending %s command to %s: %w", jsonCommand.RootOperation, component, err)
+	}
+	return decodeResponse(component, jsonCommand.RootOperation, reply.Data, response)
+}
+
+// Cancel the command started with a cancel key on a component
+func (c *Client) Cancel(ctx context.Context, component types.Component, cancelKey string) error {
+	return c.send(ctx, component, command.JSONCommand{
+		RootOperation: command.JSONOperationCancel,
+		CancelKey:     cancelKey,
+	}, nil)
+}
+
+// Status returns the status of a component
+func (c *Client) Status(ctx context.Context, component types.Component) (types.StatusResponse, error) {
+	var response types.StatusResponse
+	err := c.command(ctx, component, command.JSONOperationStatus, nil, &response)
+	return response, err
+}
+
+// Quit asks a component to shut down gracefully
+func (c *Client) Quit(ctx context.Context, component types.Component) error {
+	return c.command(ctx, component, command.JSONOperationQuit, nil, nil)
+}
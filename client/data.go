@@ -0,0 +1,104 @@
+package client
+
+import (
+	"context"
+
+	"tradingplatform/shared/communication/command"
+	"tradingplatform/shared/communication/subscriber"
+	"tradingplatform/shared/entities"
+	"tradingplatform/shared/requests"
+	"tradingplatform/shared/types"
+)
+
+// Iterator over the entities of a data queue, the queue is received while the entities are read.
+// The queue is cancelled when the iterator is closed or its context is done
+type Iterator struct {
+	// Response topic of the queue
+	Topic string
+	// Number of entities announced by the queue, 0 when it was not known up front
+	Count int
+
+	events  chan Event
+	current Event
+	cancel  context.CancelFunc
+	err     error
+}
+
+// Receive the data queue sent on a response topic
+func (c *Client) receive(ctx context.Context, topic string) *Iterator {
+	ctx, cancel := context.WithCancel(ctx)
+	_, count := subscriber.GetQueueComponents(topic)
+	it := &Iterator{
+		Topic:  topic,
+		Count:  count,
+		events: make(chan Event),
+		cancel: cancel,
+	}
+	go func() {
+		defer close(it.events)
+		it.err = subscriber.ReceiveQueue(ctx, c.nc, topic, func(msg *entities.Message) {
+			select {
+			case it.events <- newEvent(msg):
+			case <-ctx.Done():
+			}
+		})
+	}()
+	return it
+}
+
+// Next waits for the next entity of the queue, returns false once all entities were read or the
+// queue failed
+func (it *Iterator) Next() bool {
+	event, ok := <-it.events
+	if !ok {
+		return false
+	}
+	it.current = event
+	return true
+}
+
+// Event returns the entity read by the last call to Next
+func (it *Iterator) Event() Event {
+	return it.current
+}
+
+// Err returns the error the queue failed with once Next returned false, nil when all its entities
+// were read
+func (it *Iterator) Err() error {
+	return it.err
+}
+
+// Close the iterator, the rest of the queue is cancelled
+func (it *Iterator) Close() {
+	it.cancel()
+	for range it.events {
+	}
+}
+
+// All reads the remaining entities of the queue and closes the iterator
+func (it *Iterator) All() ([]Event, error) {
+	defer it.Close()
+	events := make([]Event, 0, it.Count)
+	for it.Next() {
+		events = append(events, it.Event())
+	}
+	return events, it.Err()
+}
+
+// History requests the data matching a data request from the datastorage. Requests no stored data
+// matches fail with an error matching ErrNoData
+func (c *Client) History(ctx context.Context, req requests.DataRequest) (*Iterator, error) {
+	return c.HistoryFrom(ctx, types.DataStorage, req)
+}
+
+// HistoryFrom requests the data matching a data request from a component answering data requests,
+// the dataprovider fetches it from the source of the request
+func (c *Client) HistoryFrom(ctx context.Context, component types.Component, req requests.DataRequest) (*Iterator, error) {
+	// The queue is received with acknowledgements
+	req.NoConfirm = false
+	var response types.DataResponse
+	if err := c.command(ctx, component, command.JSONOperationData, &req, &response); err != nil {
+		return nil, err
+	}
+	return c.receive(ctx, response.ResponseTopic), nil
+}
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"tradingplatform/shared/communication/command"
+	"tradingplatform/shared/types"
+)
+
+// ErrNoData is matched with errors.Is by the failures of data requests no data matches
+var ErrNoData = types.ErrNoData
+
+// CommandError is a failure reported by a component in the response to a command
+type CommandError struct {
+	Component types.Component
+	Operation command.JSONOperation
+	// Err and Message of the response of the component
+	Err     string
+	Message string
+}
+
+func (e *CommandError) Error() string {
+	return fmt.Sprintf("%s %s: %s", e.Component, e.Operation, e.Err)
+}
+
+// Is matches ErrNoData for the failures of data requests no data matches
+func (e *CommandError) Is(target error) bool {
+	return target == ErrNoData && errors.Is(types.ResponseError(e.Err), ErrNoData)
+}
+
+// Decode the response of a component to a command into response, the fields of types.Response are
+// shared by the responses of all the commands
+func decodeResponse(component types.Component, operation command.JSONOperation, data []byte, response any) error {
+	var r types.Response
+	if err := json.Unmarshal(data, &r); err != nil {
+		return fmt.Errorf("decoding response of %s to %s: %w", component, operation, err)
+	}
+	if response != nil {
+		// Failed responses are decoded as well, some carry the partial result of the command
+		if err := json.Unmarshal(data, response); err != nil {
+			return fmt.Errorf("decoding response of %s to %s: %w", component, operation, err)
+		}
+	}
+	if r.Err != "" || r.Status == types.Failure {
+		return &CommandError{
+			Component: component,
+			Operation: operation,
+			Err:       r.Err,
+			Message:   r.Message,
+		}
+	}
+	return nil
+}
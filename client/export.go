@@ -0,0 +1,19 @@
+package client
+
+import (
+	"context"
+
+	"tradingplatform/shared/communication/command"
+	"tradingplatform/shared/requests"
+	"tradingplatform/shared/types"
+)
+
+// Export makes the datastorage write the stored data matching an export request into files under its
+// export directory and returns the manifest of the files written. When the export fails the response
+// lists the files written before the failure. The export is cancelled on the datastorage when the
+// context is done before it completed
+func (c *Client) Export(ctx context.Context, req requests.ExportRequest) (types.ExportResponse, error) {
+	var response types.ExportResponse
+	err := c.cancelableCommand(ctx, types.DataStorage, command.JSONOperationExport, &req, &response)
+	return response, err
+}
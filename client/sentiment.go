@@ -0,0 +1,21 @@
+package client
+
+import (
+	"context"
+
+	"tradingplatform/shared/communication/command"
+	"tradingplatform/shared/requests"
+	"tradingplatform/shared/types"
+)
+
+// AnalyzeSentiment makes the sentiment analyzer analyze the stored news matching a request and
+// returns an iterator over the news with their sentiments (*entities.News). The analysis is cancelled
+// on the sentiment analyzer when the context is done before it completed
+func (c *Client) AnalyzeSentiment(ctx context.Context, req requests.SentimentAnalysisRequest) (*Iterator, error) {
+	req.NoConfirm = false
+	var response types.DataResponse
+	if err := c.cancelableCommand(ctx, types.SentimentAnalyzer, command.JSONOperationData, &req, &response); err != nil {
+		return nil, err
+	}
+	return c.receive(ctx, response.ResponseTopic), nil
+}
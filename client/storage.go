@@ -0,0 +1,43 @@
+package client
+
+import (
+	"context"
+
+	"tradingplatform/shared/communication/command"
+	"tradingplatform/shared/requests"
+	"tradingplatform/shared/types"
+)
+
+// StorageSubscribe makes the datastorage store the entities of topics or topic patterns, each topic
+// being handled by a number of agents
+func (c *Client) StorageSubscribe(ctx context.Context, topics []string, agents int) (types.StreamResponse, error) {
+	return c.storageStream(ctx, types.StreamAddOp, topics, agents)
+}
+
+// StorageUnsubscribe stops the datastorage from storing the entities of topics
+func (c *Client) StorageUnsubscribe(ctx context.Context, topics []string) (types.StreamResponse, error) {
+	return c.storageStream(ctx, types.StreamRemoveOp, topics, 1)
+}
+
+// StorageTopics returns the topics the datastorage is subscribed to
+func (c *Client) StorageTopics(ctx context.Context) ([]string, error) {
+	status, err := c.Status(ctx, types.DataStorage)
+	return status.Streams, err
+}
+
+func (c *Client) storageStream(ctx context.Context,
+	operation types.StreamRequestOp,
+	topics []string,
+	agents int) (types.StreamResponse, error) {
+
+	req := requests.StreamSubscribeRequest{Operation: operation}
+	for _, topic := range topics {
+		req.StreamSubscribeWithAgents = append(req.StreamSubscribeWithAgents, requests.StreamSubscribeAgents{
+			AgentCount: agents,
+			Topic:      topic,
+		})
+	}
+	var response types.StreamResponse
+	err := c.command(ctx, types.DataStorage, command.JSONOperationStreamSubscribe, &req, &response)
+	return response, err
+}
@@ -0,0 +1,218 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"tradingplatform/shared/communication/command"
+	"tradingplatform/shared/entities"
+	"tradingplatform/shared/requests"
+	"tradingplatform/shared/types"
+	"tradingplatform/shared/utils"
+
+	"github.com/nats-io/nats.go"
+	"google.golang.org/protobuf/proto"
+)
+
+// Event is an entity received on a stream topic or a data queue
+type Event struct {
+	Topic    string
+	DataType types.DataType
+	// Sequence number of the entity in its data queue, 0 for streams
+	Sequence uint64
+	// Entity of the data type, e.g. *entities.Trade for trades. Nil for the data types without entity
+	// such as logs, whose payload is kept as is, or when the payload could not be decoded
+	Entity  proto.Message
+	Payload []byte
+	// Error decoding the payload
+	Err error
+}
+
+func newEvent(msg *entities.Message) Event {
+	event := Event{
+		Topic:    msg.Topic,
+		DataType: types.DataType(msg.DataType),
+		Sequence: msg.Sequence,
+		Payload:  msg.Payload,
+	}
+	if event.DataType == types.Log {
+		return event
+	}
+	event.Entity, event.Err = msg.Decode()
+	return event
+}
+
+// Handle adapts a function handling the entities of one type to a handler of events, the events of
+// other entities are skipped
+func Handle[T proto.Message](f func(T)) func(Event) {
+	return func(event Event) {
+		if entity, ok := event.Entity.(T); ok {
+			f(entity)
+		}
+	}
+}
+
+// Subscription to stream topics, stopped with Stop or when the context it was started with is done
+type Subscription struct {
+	subs []*nats.Subscription
+	once sync.Once
+	done chan struct{}
+	// Calls of the handler in progress, none is started once the subscription is stopped
+	lock     sync.Mutex
+	stopped  bool
+	handling sync.WaitGroup
+}
+
+// Stop the subscription, the handler is not called anymore once Stop returned. Stop waits for the
+// calls of the handler in progress, a handler stopping its own subscription calls Stop in a goroutine
+func (s *Subscription) Stop() {
+	s.once.Do(func() {
+		s.lock.Lock()
+		s.stopped = true
+		s.lock.Unlock()
+		for _, sub := range s.subs {
+			sub.Unsubscribe()
+		}
+		s.handling.Wait()
+		close(s.done)
+	})
+}
+
+// Call the handler unless the subscription is stopped
+func (s *Subscription) handle(f func()) {
+	s.lock.Lock()
+	if s.stopped {
+		s.lock.Unlock()
+		return
+	}
+	s.handling.Add(1)
+	s.lock.Unlock()
+	defer s.handling.Done()
+	f()
+}
+
+// Done returns a channel closed once the subscription is stopped
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+// Subscribe to the streams of the dataprovider of a source, asset class and data type for symbols, or
+// for all the symbols without symbol. The handler is called with the entities of each topic in the
+// order they are received
+func (c *Client) Subscribe(ctx context.Context,
+	source types.Source,
+	assetClass types.AssetClass,
+	dataType types.DataType,
+	symbols []string,
+	handler func(Event)) (*Subscription, error) {
+
+	if len(symbols) == 0 {
+		symbols = []string{"*"}
+	}
+	topics := make([]string, len(symbols))
+	for i, symbol := range symbols {
+		topics[i] = utils.NewStreamTopic(types.DataProvider, source, assetClass, dataType, symbol).Generate()
+	}
+	return c.SubscribeTopics(ctx, topics, handler)
+}
+
+// SubscribeTopics subscribes to topics or topic patterns (e.g. indicators.stream.>) and calls the
+// handler with their entities, batch messages are unpacked
+func (c *Client) SubscribeTopics(ctx context.Context, topics []string, handler func(Event)) (*Subscription, error) {
+	s := &Subscription{done: make(chan struct{})}
+	for _, topic := range topics {
+		sub, err := c.nc.Subscribe(topic, func(m *nats.Msg) {
+			s.handle(func() {
+				var msg entities.Message
+				if err := proto.Unmarshal(m.Data, &msg); err != nil {
+					handler(Event{Topic: m.Subject, Err: fmt.Errorf("decoding message: %w", err)})
+					return
+				}
+				for _, part := range msg.Unpack() {
+					handler(newEvent(part))
+				}
+			})
+		})
+		if err != nil {
+			s.Stop()
+			return nil, fmt.Errorf("subscribing to %s: %w", topic, err)
+		}
+		sub.SetPendingLimits(-1, -1)
+		s.subs = append(s.subs, sub)
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+			s.Stop()
+		case <-s.done:
+		}
+	}()
+	return s, nil
+}
+
+// StreamAdd starts the streams of the dataprovider of a stream request, all the data types of the
+// source are streamed when the request has none
+func (c *Client) StreamAdd(ctx context.Context, req requests.StreamRequest) (types.StreamResponse, error) {
+	req.Operation = types.StreamAddOp
+	return c.stream(ctx, req)
+}
+
+// StreamRemove stops the streams of the dataprovider of a stream request
+func (c *Client) StreamRemove(ctx context.Context, req requests.StreamRequest) (types.StreamResponse, error) {
+	req.Operation = types.StreamRemoveOp
+	return c.stream(ctx, req)
+}
+
+// StreamList returns the active streams of the dataprovider for a source, asset class and account
+func (c *Client) StreamList(ctx context.Context,
+	source types.Source,
+	assetClass types.AssetClass,
+	account requests.Account) (types.StreamResponse, error) {
+
+	return c.stream(ctx, requests.NewStreamRequest(source, assetClass, []string{}, types.StreamGetOp, nil, account))
+}
+
+// Stream of the dataprovider listed in the responses to stream requests
+type Stream struct {
+	DataSource types.Source
+	Account    requests.Account
+	DataType   types.DataType
+	AssetClass types.AssetClass
+	Symbol     string
+}
+
+// ParseStreams returns the active streams of the dataprovider listed in a response to a stream request
+func ParseStreams(response types.StreamResponse) ([]Stream, error) {
+	var streams []Stream
+	if response.Streams == "" {
+		return streams, nil
+	}
+	if err := json.Unmarshal([]byte(response.Streams), &streams); err != nil {
+		return nil, fmt.Errorf("decoding streams: %w", err)
+	}
+	return streams, nil
+}
+
+// ParseConnections returns the state of the connections of the clients streaming the data of a source
+// listed in a response to a stream request, for the sources keeping connections
+func ParseConnections(response types.StreamResponse) ([]*entities.ConnectionStatus, error) {
+	var connections []*entities.ConnectionStatus
+	if response.Connections == "" {
+		return connections, nil
+	}
+	if err := json.Unmarshal([]byte(response.Connections), &connections); err != nil {
+		return nil, fmt.Errorf("decoding connections: %w", err)
+	}
+	return connections, nil
+}
+
+func (c *Client) stream(ctx context.Context, req requests.StreamRequest) (types.StreamResponse, error) {
+	if req.Symbols == nil {
+		req.Symbols = []string{}
+	}
+	var response types.StreamResponse
+	err := c.command(ctx, types.DataProvider, command.JSONOperationStream, &req, &response)
+	return response, err
+}
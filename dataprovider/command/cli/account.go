@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"tradingplatform/dataprovider/handler"
+	"tradingplatform/shared/logging"
+	"tradingplatform/shared/requests"
+	"tradingplatform/shared/types"
+
+	"github.com/spf13/cobra"
+)
+
+// Add, remove and list the Alpaca accounts of the registry
+func NewAccountCmd() *cobra.Command {
+	accountCmd := cobra.Command{
+		Use:   "account",
+		Short: "Add, remove or list the Alpaca accounts used by streams and data requests",
+	}
+
+	accountCmd.AddCommand(NewAccountOperationCmd(types.AccountAddOp,
+		"Add an account with its credentials and the feed of its stock data subscription"))
+	accountCmd.AddCommand(NewAccountOperationCmd(types.AccountRemoveOp,
+		"Remove an account without active streams"))
+	accountCmd.AddCommand(NewAccountOperationCmd(types.AccountListOp,
+		"List the accounts without their secret"))
+
+	return &accountCmd
+}
+
+func NewAccountOperationCmd(operation types.AccountRequestOp, short string) *cobra.Command {
+	operationCmd := cobra.Command{
+		Use:   string(operation),
+		Short: short,
+		Run: func(cmd *cobra.Command, args []string) {
+			account, _ := cmd.Flags().GetString("account")
+			key, _ := cmd.Flags().GetString("key")
+			secret, _ := cmd.Flags().GetString("secret")
+			feed, _ := cmd.Flags().GetString("feed")
+			live, _ := cmd.Flags().GetBool("live")
+
+			req, err := requests.NewAccountRequestFromRaw(string(operation),
+				account,
+				key,
+				secret,
+				feed,
+				live,
+				requests.DefaultForEmptyAccountRequest)
+
+			logging.Log().Info().
+				RawJSON("accountRequest", req.JSON()).
+				Msg("receiving account request")
+
+			if err != nil {
+				cmd.Print(types.NewAccountError(err).Respond())
+				return
+			}
+
+			cmd.Print(handler.HandleAccountRequest(req).Respond())
+		},
+	}
+
+	switch operation {
+	case types.AccountAddOp:
+		operationCmd.Flags().StringP("account", "c", "",
+			"Name of the account")
+		operationCmd.MarkFlagRequired("account")
+		operationCmd.Flags().StringP("key", "k", "",
+			"API key")
+		operationCmd.MarkFlagRequired("key")
+		operationCmd.Flags().StringP("secret", "x", "",
+			"API secret")
+		operationCmd.MarkFlagRequired("secret")
+		operationCmd.Flags().StringP("feed", "f", "",
+			"Feed of the stock data subscription (iex or sip), iex by default")
+		operationCmd.Flags().BoolP("live", "l", false,
+			"Route the orders of the account to the live trading API instead of the paper one")
+	case types.AccountRemoveOp:
+		operationCmd.Flags().StringP("account", "c", "",
+			"Name of the account")
+		operationCmd.MarkFlagRequired("account")
+	}
+
+	return &operationCmd
+}
@@ -84,9 +84,9 @@ func NewDataGetCmd() *cobra.Command {
 	dataGetCmd.Flags().StringP("account", "c", "",
 		"Account to use for the stream")
 	dataGetCmd.Flags().Int64P("start-time", "b", 0,
-		"Start time for the data")
+		"Start time for the data (unix nanoseconds, seconds are also accepted)")
 	dataGetCmd.Flags().Int64P("end-time", "e", 0,
-		"End time for the data")
+		"End time for the data (unix nanoseconds, seconds are also accepted)")
 	dataGetCmd.Flags().StringP("time-frame", "f", "",
 		"Time frame (only available for bar data)")
 	dataGetCmd.Flags().BoolP("no-confirm", "o", false,
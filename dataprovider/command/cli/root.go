@@ -17,6 +17,7 @@ func NewRootCmd() *cobra.Command {
 	rootCmd.AddCommand(NewStreamCmd())
 	rootCmd.AddCommand(NewQuitCommand())
 	rootCmd.AddCommand(NewDataCmd())
+	rootCmd.AddCommand(NewAccountCmd())
 
 	return &rootCmd
 }
@@ -6,6 +6,7 @@ import (
 
 	"tradingplatform/shared/logging"
 	"tradingplatform/shared/requests"
+	"tradingplatform/shared/types"
 
 	"github.com/spf13/cobra"
 )
@@ -39,6 +40,10 @@ func NewStreamAddCmd() *cobra.Command {
 			operation := "add"
 			dataTypes, _ := cmd.Flags().GetStringArray("data-types")
 			account, _ := cmd.Flags().GetString("account")
+			replayStart, _ := cmd.Flags().GetInt64("replay-start")
+			replayEnd, _ := cmd.Flags().GetInt64("replay-end")
+			replaySpeed, _ := cmd.Flags().GetFloat64("replay-speed")
+			replaySource, _ := cmd.Flags().GetString("replay-source")
 
 			// Generate stream request from flags
 			streamRequest, err := requests.NewStreamRequestFromRaw(source,
@@ -46,7 +51,17 @@ func NewStreamAddCmd() *cobra.Command {
 				symbols,
 				operation,
 				dataTypes,
-				account, requests.DefaultForEmptyStreamAddDeleteRequest)
+				account, func(sr *requests.StreamRequest) {
+					if sr.Source == types.Replay {
+						sr.Replay = &requests.ReplayOptions{
+							StoredSource: types.Source(replaySource),
+							StartTime:    replayStart,
+							EndTime:      replayEnd,
+							Speed:        replaySpeed,
+						}
+					}
+					requests.DefaultForEmptyStreamAddDeleteRequest(sr)
+				})
 
 			logging.Log().Info().
 				RawJSON("streamRequest", streamRequest.JSON()).
@@ -72,6 +87,14 @@ func NewStreamAddCmd() *cobra.Command {
 		"Type of data (e.g. bar, trade...)")
 	streamAddCmd.Flags().StringP("account", "c", "",
 		"Account to use for the stream")
+	streamAddCmd.Flags().Int64("replay-start", 0,
+		"Start time (unix nanoseconds, seconds are also accepted) of the session replayed by the replay source")
+	streamAddCmd.Flags().Int64("replay-end", 0,
+		"End time (unix nanoseconds, seconds are also accepted, exclusive) of the session replayed by the replay source")
+	streamAddCmd.Flags().Float64("replay-speed", 1,
+		"Replay speed multiplier (e.g. 1, 10), 0 replays as fast as possible")
+	streamAddCmd.Flags().String("replay-source", string(types.Alpaca),
+		"Source of the stored data to replay")
 
 	return &streamAddCmd
 }
@@ -59,5 +59,18 @@ func HandleJSONCommand(ctx context.Context, jsonStr string) string {
 			return ""
 		}
 	}
+
+	if jsonCommand.RootOperation == shcommand.JSONOperationAccount {
+		var accountRequest requests.AccountRequest
+		err := JSON.Unmarshal(jsonCommand.Request, &accountRequest)
+		if err != nil {
+			return types.NewAccountError(err).Respond()
+		}
+		validAccountRequest, err := requests.NewAccountRequestFromExisting(&accountRequest, requests.DefaultForEmptyAccountRequest)
+		if err != nil {
+			return types.NewAccountError(err).Respond()
+		}
+		return handler.HandleAccountRequest(validAccountRequest).Respond()
+	}
 	return ""
 }
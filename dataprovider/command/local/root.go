@@ -8,9 +8,18 @@ import (
 	"tradingplatform/dataprovider/command/cli"
 	"tradingplatform/dataprovider/command/json"
 	"tradingplatform/dataprovider/data"
+	"tradingplatform/dataprovider/handler"
+	"tradingplatform/dataprovider/provider/aggregator"
+	"tradingplatform/dataprovider/provider/replay"
+	"tradingplatform/dataprovider/provider/synthetic"
+	"tradingplatform/shared/accounts"
+	"tradingplatform/shared/bars"
 	"tradingplatform/shared/communication"
 	"tradingplatform/shared/communication/command"
+	shdata "tradingplatform/shared/data"
 	"tradingplatform/shared/logging"
+	"tradingplatform/shared/metrics"
+	"tradingplatform/shared/tracing"
 	"tradingplatform/shared/types"
 	"tradingplatform/shared/utils"
 
@@ -25,6 +34,12 @@ func NewRootCmd() *cobra.Command {
 		Run: func(cmd *cobra.Command, args []string) {
 			natsURL, _ := cmd.Flags().GetString("nats-url")
 			communication.SetNatsURL(natsURL)
+			if err := communication.SetJetStreamConfigFromFlags(cmd.Flags()); err != nil {
+				panic(err)
+			}
+			if err := communication.SetBatchConfigFromFlags(cmd.Flags()); err != nil {
+				panic(err)
+			}
 			nc, err := nats.Connect(communication.GetNatsURL())
 			if err != nil {
 				panic(err)
@@ -34,9 +49,15 @@ func NewRootCmd() *cobra.Command {
 			mlLogger := logging.NewMultiLevelLogger(types.DataProvider,
 				os.Stdout, logging.NewNatsWriter(nc, loggingTopic))
 			logging.SetLogger(&mlLogger)
+			shutdownTracing, err := tracing.InitFromFlags(types.DataProvider, cmd.Flags())
+			if err != nil {
+				panic(err)
+			}
+			defer shutdownTracing()
 
 			logging.Log().Info().
 				Str("natsUrl", communication.GetNatsURL()).
+				Bool("jetstream", communication.IsJetStreamEnabled()).
 				Msg("starting dataprovider, remote logging enabled")
 
 			// Create a channel to receive OS signals
@@ -45,18 +66,69 @@ func NewRootCmd() *cobra.Command {
 			// Register the channel to receive SIGINT and SIGTERM signals
 			signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
 
-			cleanup := data.InitializeDataProviderLocalDatabase()
+			accountsPath, _ := cmd.Flags().GetString("accounts")
+			if err := accounts.Load(accountsPath); err != nil {
+				panic(err)
+			}
+
+			localDB, _ := cmd.Flags().GetString("local-db")
+			cleanup := data.InitializeDataProviderLocalDatabase(localDB)
 			defer cleanup()
+
+			aggregatorBars, _ := cmd.Flags().GetStringSlice("aggregator-bars")
+			aggregatorSpecs, err := bars.ParseSpecs(aggregatorBars)
+			if err != nil {
+				panic(err)
+			}
+			aggregatorGrace, _ := cmd.Flags().GetDuration("aggregator-grace")
+			aggregatorSource, _ := cmd.Flags().GetString("aggregator-trades-source")
+			aggregator.SetConfig(aggregator.Config{
+				Specs:        aggregatorSpecs,
+				Grace:        aggregatorGrace,
+				TradesSource: types.Source(aggregatorSource),
+			})
+			syntheticSeed, _ := cmd.Flags().GetInt64("synthetic-seed")
+			synthetic.SetConfig(synthetic.Config{
+				Seed: syntheticSeed,
+			})
+			handler.RegisterProviders()
+
+			replayDSN, _ := cmd.Flags().GetString("replay-dsn")
+			if replayDSN != "" {
+				replayCleanup := replay.InitializeReplayDatabase(replayDSN)
+				defer replayCleanup()
+			}
+			command.RegisterDatabase("local", shdata.PingLocalDatabase)
+			if replayDSN != "" {
+				command.RegisterDatabase("replay", replay.PingDatabase)
+			}
+			command.SetStreamsFunc(data.GetDataProviderStreamTopics)
+			command.SetFailedStreamsFunc(handler.GetFailedStreams)
 			command.StartCommandHandler(types.DataProvider, cli.NewRootCmd, json.HandleJSONCommand)
-			handler := command.GetCommandHandler()
+			commandHandler := command.GetCommandHandler()
+			command.StartHealthServerFromFlags(cmd.Flags())
+			metrics.StartServerFromFlags(commandHandler.Ctx(), cmd.Flags())
+			handler.RestoreStreams()
 			go func() {
 				<-sigs
-				handler.Cancel()
+				commandHandler.Cancel()
 			}()
-			<-handler.Ctx().Done()
-			handler.Wg.Wait()
+			<-commandHandler.Ctx().Done()
+			commandHandler.Wg.Wait()
 		},
 	}
 	rootCmd.Flags().StringP("nats-url", "n", communication.GetNatsURL(), "NATS server URL")
+	communication.AddJetStreamFlags(rootCmd.Flags())
+	communication.AddBatchFlags(rootCmd.Flags())
+	command.AddHealthFlags(rootCmd.Flags())
+	metrics.AddMetricsFlags(rootCmd.Flags())
+	tracing.AddTracingFlags(rootCmd.Flags())
+	rootCmd.Flags().String("local-db", "", "Path to the SQLite file of the active streams, restored on startup. Without file the streams are kept in memory")
+	rootCmd.Flags().String("accounts", "", "Path to the JSON file of the Alpaca accounts registry, the accounts added with the account command are saved to it. Without file the registry is kept in memory; the default account is the one of ALPACA_KEY, ALPACA_SECRET and ALPACA_FEED unless registered")
+	rootCmd.Flags().String("replay-dsn", "", "DSN of the datastorage database read by the replay source, the replayed session is part of each stream request")
+	rootCmd.Flags().Int64("synthetic-seed", 0, "Seed of the data generated by the synthetic source, combined with the symbol so that runs with the same seed generate the same prices")
+	rootCmd.Flags().StringSlice("aggregator-bars", aggregator.DefaultSpecs, "Bars built from trades by the aggregator source (e.g. 5sec, 500ms, 100tick, 1000volume, 1000000dollar)")
+	rootCmd.Flags().Duration("aggregator-grace", aggregator.GetConfig().Grace, "Time the aggregator waits for late trades and trade corrections before building bars")
+	rootCmd.Flags().String("aggregator-trades-source", string(aggregator.AnySource), "Source of the trades the aggregator builds bars from, * for all sources")
 	return &rootCmd
 }
@@ -2,9 +2,9 @@ package data
 
 import "tradingplatform/shared/data"
 
-// Initialize local database for the DataProvider
+// Initialize local database for the DataProvider, in memory without path
 // Returns cancel function
-func InitializeDataProviderLocalDatabase() func() {
-	_, cancel := data.InitializeLocalDatabase(&DataProviderStream{})
+func InitializeDataProviderLocalDatabase(path string) func() {
+	_, cancel := data.InitializeLocalDatabase(path, &DataProviderStream{})
 	return cancel
 }
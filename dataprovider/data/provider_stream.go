@@ -1,10 +1,13 @@
 package data
 
 import (
+	"sort"
+
 	"tradingplatform/shared/data"
 	"tradingplatform/shared/logging"
 	"tradingplatform/shared/requests"
 	"tradingplatform/shared/types"
+	"tradingplatform/shared/utils"
 
 	"gorm.io/gorm/clause"
 )
@@ -46,6 +49,64 @@ func GetDataProviderStreamsAssetClass(assetClass types.AssetClass) []DataProvide
 	return activeStreams
 }
 
+// Get all active streams of the dataprovider from local database for a given source and asset class
+func GetDataProviderStreamsSourceAssetClass(source types.Source, assetClass types.AssetClass) []DataProviderStream {
+	var activeStreams []DataProviderStream
+	data.LocalDBLock.Lock()
+	result := data.LocalDB.Where("data_source = ? AND asset_class = ?", source, assetClass).
+		Find(&activeStreams, DataProviderStream{})
+	data.LocalDBLock.Unlock()
+	if result.Error != nil {
+		logging.Log().Error().
+			Err(result.Error).
+			Msg("getting active streams for given source and asset class from local database")
+	}
+	return activeStreams
+}
+
+// Get all active streams of the dataprovider from local database for a given account
+func GetDataProviderStreamsAccount(account requests.Account) []DataProviderStream {
+	var activeStreams []DataProviderStream
+	data.LocalDBLock.Lock()
+	result := data.LocalDB.Where("account = ?", account).
+		Find(&activeStreams, DataProviderStream{})
+	data.LocalDBLock.Unlock()
+	if result.Error != nil {
+		logging.Log().Error().
+			Err(result.Error).
+			Msg("getting active streams for given account from local database")
+	}
+	return activeStreams
+}
+
+// Remove all active streams of the dataprovider of a given source from local database
+func RemoveDataProviderStreamsSource(source types.Source) {
+	data.LocalDBLock.Lock()
+	result := data.LocalDB.Where("data_source = ?", source).Delete(&DataProviderStream{})
+	data.LocalDBLock.Unlock()
+	if result.Error != nil {
+		logging.Log().Error().
+			Err(result.Error).
+			Str("source", string(source)).
+			Msg("removing active streams for given source from local database")
+	}
+}
+
+// Get the topics of the active streams of the dataprovider from local database
+func GetDataProviderStreamTopics() []string {
+	seen := make(map[string]bool)
+	topics := []string{}
+	for _, stream := range GetDataProviderStreams() {
+		topic := utils.NewStreamTopic(types.DataProvider, stream.DataSource, stream.AssetClass, stream.DataType, stream.Symbol).Generate()
+		if !seen[topic] {
+			seen[topic] = true
+			topics = append(topics, topic)
+		}
+	}
+	sort.Strings(topics)
+	return topics
+}
+
 // Add active stream to local database
 func AddDataProviderStreamForDType(req requests.StreamRequest, dataType types.DataType) {
 	data.LocalDBLock.Lock()
@@ -62,7 +123,7 @@ func AddDataProviderStreamForDType(req requests.StreamRequest, dataType types.Da
 	for _, symbol := range req.GetSymbol() {
 		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&DataProviderStream{
 			DataSource: req.GetSource(),
-			Account:    req.GetAccount(),
+			Account:    req.GetStreamAccount(),
 			AssetClass: req.GetAssetClass(),
 			DataType:   dataType,
 			Symbol:     symbol,
@@ -103,7 +164,7 @@ func RemoveDataProviderStreamForDType(req requests.StreamRequest, dataType types
 
 	if err := tx.Where("data_source = ? AND account = ? AND asset_class = ? AND data_type = ? AND symbol IN ?",
 		req.GetSource(),
-		req.GetAccount(),
+		req.GetStreamAccount(),
 		req.GetAssetClass(),
 		dataType,
 		req.GetSymbol()).
@@ -123,7 +184,6 @@ func RemoveDataProviderStreamForDType(req requests.StreamRequest, dataType types
 			Err(err).
 			RawJSON("request", req.JSON()).
 			Msg("committing transaction used to remove active stream to local database")
-		return
 	}
 	data.LocalDBLock.Unlock()
 }
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"fmt"
+	"tradingplatform/dataprovider/data"
+	"tradingplatform/dataprovider/provider/alpaca/stream"
+	"tradingplatform/shared/accounts"
+	"tradingplatform/shared/logging"
+	"tradingplatform/shared/requests"
+	"tradingplatform/shared/types"
+)
+
+// Handle an account request by adding the account to the registry, removing it or listing the accounts
+func HandleAccountRequest(req requests.AccountRequest) types.AccountResponse {
+	account := req.GetAccount()
+	// The clients of an account are closed when it is added (e.g. the default account of the
+	// environment added to the registry) or removed, which would stop its active streams
+	if req.GetOperation() != types.AccountListOp {
+		if streams := data.GetDataProviderStreamsAccount(account); len(streams) > 0 {
+			return types.NewAccountError(
+				fmt.Errorf("account %s has %d active streams, remove them first", account, len(streams)),
+			)
+		}
+	}
+	switch req.GetOperation() {
+	case types.AccountAddOp:
+		err := accounts.Add(string(account), accounts.Credentials{
+			Key:    req.Key,
+			Secret: req.Secret,
+			Feed:   req.Feed,
+			Live:   req.Live,
+		})
+		if err != nil {
+			logging.Log().Error().
+				Err(err).
+				RawJSON("request", req.JSON()).
+				Msg("adding account")
+			return types.NewAccountError(err)
+		}
+		stream.CloseAccountClients(account)
+		return types.NewAccountResponse(types.Success,
+			fmt.Sprintf("Successfully added account %s", account), nil, nil)
+	case types.AccountRemoveOp:
+		if err := accounts.Remove(string(account)); err != nil {
+			logging.Log().Error().
+				Err(err).
+				RawJSON("request", req.JSON()).
+				Msg("removing account")
+			return types.NewAccountError(err)
+		}
+		stream.CloseAccountClients(account)
+		return types.NewAccountResponse(types.Success,
+			fmt.Sprintf("Successfully removed account %s", account), nil, nil)
+	case types.AccountListOp:
+		return types.NewAccountResponse(types.Success,
+			"Successfully listed accounts", nil, accounts.List())
+	default:
+		return types.NewAccountError(
+			fmt.Errorf("operation %s not supported", req.GetOperation()),
+		)
+	}
+}
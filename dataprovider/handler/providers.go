@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"tradingplatform/dataprovider/provider"
+	"tradingplatform/dataprovider/provider/aggregator"
+	aggregatorStream "tradingplatform/dataprovider/provider/aggregator/stream"
+	"tradingplatform/dataprovider/provider/alpaca"
+	alpacaData "tradingplatform/dataprovider/provider/alpaca/data"
+	alpacaStream "tradingplatform/dataprovider/provider/alpaca/stream"
+	"tradingplatform/dataprovider/provider/binance"
+	binanceData "tradingplatform/dataprovider/provider/binance/data"
+	binanceStream "tradingplatform/dataprovider/provider/binance/stream"
+	"tradingplatform/dataprovider/provider/replay"
+	replayStream "tradingplatform/dataprovider/provider/replay/stream"
+	"tradingplatform/dataprovider/provider/synthetic"
+	syntheticData "tradingplatform/dataprovider/provider/synthetic/data"
+	syntheticStream "tradingplatform/dataprovider/provider/synthetic/stream"
+	"tradingplatform/shared/requests"
+	"tradingplatform/shared/types"
+)
+
+// Register the providers available to the dataprovider
+func RegisterProviders() {
+	provider.Register(alpacaProvider{})
+	provider.Register(replayProvider{})
+	provider.Register(syntheticProvider{})
+	provider.Register(binanceProvider{})
+	provider.Register(aggregatorProvider{})
+}
+
+// Provider streaming and fetching data from Alpaca
+type alpacaProvider struct{}
+
+func (alpacaProvider) Source() types.Source {
+	return types.Alpaca
+}
+
+func (alpacaProvider) SupportedDataTypes(assetClass types.AssetClass) map[types.DataType]types.DataType {
+	return alpaca.GetDataTypes(assetClass)
+}
+
+func (alpacaProvider) IsSymbolValid(symbol string, assetClass types.AssetClass) bool {
+	return alpaca.IsSymbolValid(symbol, assetClass)
+}
+
+func (alpacaProvider) AddStream(req requests.StreamRequest) types.StreamResponse {
+	return alpacaStream.HandleAlpacaStreamAddRequest(req)
+}
+
+func (alpacaProvider) RemoveStream(req requests.StreamRequest) types.StreamResponse {
+	return alpacaStream.HandleAlpacaStreamRemoveRequest(req)
+}
+
+func (alpacaProvider) GetStreams(req requests.StreamRequest) types.StreamResponse {
+	return alpacaStream.HandleAlpacaStreamGetRequest(req)
+}
+
+func (alpacaProvider) FetchData(req requests.DataRequest) types.DataResponse {
+	return alpacaData.HandleAlpacaDataRequest(req)
+}
+
+// Provider replaying data stored by the datastorage, historical fetches are not supported
+type replayProvider struct{}
+
+func (replayProvider) Source() types.Source {
+	return types.Replay
+}
+
+func (replayProvider) SupportedDataTypes(assetClass types.AssetClass) map[types.DataType]types.DataType {
+	return replay.GetDataTypes(assetClass)
+}
+
+func (replayProvider) IsSymbolValid(symbol string, assetClass types.AssetClass) bool {
+	return symbol != ""
+}
+
+func (replayProvider) AddStream(req requests.StreamRequest) types.StreamResponse {
+	return replayStream.HandleReplayStreamAddRequest(req)
+}
+
+func (replayProvider) RemoveStream(req requests.StreamRequest) types.StreamResponse {
+	return replayStream.HandleReplayStreamRemoveRequest(req)
+}
+
+func (replayProvider) GetStreams(req requests.StreamRequest) types.StreamResponse {
+	return replayStream.HandleReplayStreamGetRequest(req)
+}
+
+func (replayProvider) FetchData(req requests.DataRequest) types.DataResponse {
+	return newUnsupportedDataError(req)
+}
+
+// Provider generating synthetic market data
+type syntheticProvider struct{}
+
+func (syntheticProvider) Source() types.Source {
+	return types.Synthetic
+}
+
+func (syntheticProvider) SupportedDataTypes(assetClass types.AssetClass) map[types.DataType]types.DataType {
+	return synthetic.GetDataTypes(assetClass)
+}
+
+func (syntheticProvider) IsSymbolValid(symbol string, assetClass types.AssetClass) bool {
+	return symbol != ""
+}
+
+func (syntheticProvider) AddStream(req requests.StreamRequest) types.StreamResponse {
+	return syntheticStream.HandleSyntheticStreamAddRequest(req)
+}
+
+func (syntheticProvider) RemoveStream(req requests.StreamRequest) types.StreamResponse {
+	return syntheticStream.HandleSyntheticStreamRemoveRequest(req)
+}
+
+func (syntheticProvider) GetStreams(req requests.StreamRequest) types.StreamResponse {
+	return syntheticStream.HandleSyntheticStreamGetRequest(req)
+}
+
+func (syntheticProvider) FetchData(req requests.DataRequest) types.DataResponse {
+	return syntheticData.HandleSyntheticDataRequest(req)
+}
+
+// Provider streaming and fetching public market data from Binance
+type binanceProvider struct{}
+
+func (binanceProvider) Source() types.Source {
+	return types.Binance
+}
+
+func (binanceProvider) SupportedDataTypes(assetClass types.AssetClass) map[types.DataType]types.DataType {
+	return binance.GetDataTypes(assetClass)
+}
+
+func (binanceProvider) IsSymbolValid(symbol string, assetClass types.AssetClass) bool {
+	return binance.IsSymbolValid(symbol, assetClass)
+}
+
+func (binanceProvider) AddStream(req requests.StreamRequest) types.StreamResponse {
+	return binanceStream.HandleBinanceStreamAddRequest(req)
+}
+
+func (binanceProvider) RemoveStream(req requests.StreamRequest) types.StreamResponse {
+	return binanceStream.HandleBinanceStreamRemoveRequest(req)
+}
+
+func (binanceProvider) GetStreams(req requests.StreamRequest) types.StreamResponse {
+	return binanceStream.HandleBinanceStreamGetRequest(req)
+}
+
+func (binanceProvider) FetchData(req requests.DataRequest) types.DataResponse {
+	return binanceData.HandleBinanceDataRequest(req)
+}
+
+// Provider building bars from the trades streamed by the other providers, historical fetches are
+// not supported
+type aggregatorProvider struct{}
+
+func (aggregatorProvider) Source() types.Source {
+	return types.Aggregator
+}
+
+func (aggregatorProvider) SupportedDataTypes(assetClass types.AssetClass) map[types.DataType]types.DataType {
+	return aggregator.GetDataTypes(assetClass)
+}
+
+func (aggregatorProvider) IsSymbolValid(symbol string, assetClass types.AssetClass) bool {
+	return symbol != ""
+}
+
+func (aggregatorProvider) AddStream(req requests.StreamRequest) types.StreamResponse {
+	return aggregatorStream.HandleAggregatorStreamAddRequest(req)
+}
+
+func (aggregatorProvider) RemoveStream(req requests.StreamRequest) types.StreamResponse {
+	return aggregatorStream.HandleAggregatorStreamRemoveRequest(req)
+}
+
+func (aggregatorProvider) GetStreams(req requests.StreamRequest) types.StreamResponse {
+	return aggregatorStream.HandleAggregatorStreamGetRequest(req)
+}
+
+func (aggregatorProvider) FetchData(req requests.DataRequest) types.DataResponse {
+	return newUnsupportedDataError(req)
+}
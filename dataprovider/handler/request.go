@@ -3,35 +3,81 @@ package handler
 import (
 	"fmt"
 	"tradingplatform/dataprovider/provider"
-	"tradingplatform/dataprovider/provider/alpaca/data"
-	alpacaStream "tradingplatform/dataprovider/provider/alpaca/stream"
+	"tradingplatform/shared/accounts"
+	"tradingplatform/shared/logging"
 	"tradingplatform/shared/requests"
 	"tradingplatform/shared/types"
 )
 
-// Handle a stream request by delegating to function based on the data provider
+// Handle a stream request by delegating to the provider registered for its source
 func HandleStreamRequest(req requests.StreamRequest) string {
 	source := req.GetSource()
 
-	switch source {
-	case types.Alpaca:
-		return alpacaStream.HandleAlpacaStreamRequest(req).Respond()
-	default:
+	p, ok := provider.GetProvider(source)
+	if !ok {
 		invalidSourceError := provider.NewStreamError(
 			fmt.Errorf("invalid source %s", source),
 		)
 		return invalidSourceError.Respond()
 	}
+
+	if err := checkAccount(req.GetStreamAccount()); err != nil {
+		return provider.NewStreamError(err).Respond()
+	}
+
+	// Validate symbols
+	for _, symbol := range req.GetSymbol() {
+		if !p.IsSymbolValid(symbol, req.GetAssetClass()) {
+			return provider.NewStreamError(
+				fmt.Errorf("symbol %s not valid for asset class %s", symbol, req.GetAssetClass()),
+			).Respond()
+		}
+	}
+
+	switch req.GetOperation() {
+	case types.StreamAddOp:
+		return p.AddStream(req).Respond()
+	case types.StreamRemoveOp:
+		return p.RemoveStream(req).Respond()
+	case types.StreamGetOp:
+		return p.GetStreams(req).Respond()
+	default:
+		err := fmt.Errorf("operation %s not supported", req.GetOperation())
+		logging.Log().Error().
+			Err(err).
+			RawJSON("request", req.JSON()).
+			Msg("handling stream request")
+		return provider.NewStreamError(err).Respond()
+	}
 }
 
-// Handle a data request by delegating to function based on the data provider
+// Handle a data request by delegating to the provider registered for its source
 func HandleDataRequest(dataRequest requests.DataRequest, och chan types.DataResponse) {
-	switch dataRequest.GetSource() {
-	case types.Alpaca:
-		och <- data.HandleAlpacaDataRequest(dataRequest)
-	default:
+	p, ok := provider.GetProvider(dataRequest.GetSource())
+	if !ok {
 		och <- types.NewDataError(
 			fmt.Errorf("invalid source %s", dataRequest.GetSource()),
 		)
+		return
+	}
+	if err := checkAccount(requests.ResolveAccount(dataRequest.GetAccount())); err != nil {
+		och <- types.NewDataError(err)
+		return
 	}
+	och <- p.FetchData(dataRequest)
+}
+
+// The account of a request must be registered, the requests only check the name of the account
+func checkAccount(account requests.Account) error {
+	if !accounts.Exists(string(account)) {
+		return fmt.Errorf("account %s not registered", account)
+	}
+	return nil
+}
+
+// New error for a data request a provider does not support
+func newUnsupportedDataError(req requests.DataRequest) types.DataResponse {
+	return types.NewDataError(
+		fmt.Errorf("data requests are not supported by source %s", req.GetSource()),
+	)
 }
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"tradingplatform/dataprovider/data"
+	"tradingplatform/dataprovider/provider"
+	"tradingplatform/shared/logging"
+	"tradingplatform/shared/requests"
+	"tradingplatform/shared/types"
+	"tradingplatform/shared/utils"
+)
+
+// Streams of the local database added together, the symbols of a source, account, asset class and
+// data type
+type restoredStreams struct {
+	source     types.Source
+	account    requests.Account
+	assetClass types.AssetClass
+	dataType   types.DataType
+	symbols    []string
+}
+
+// Streams that could not be restored, with their error
+var failedStreams []string
+var failedStreamsLock sync.RWMutex
+
+// GetFailedStreams returns the topics of the streams that could not be restored on startup with their error
+func GetFailedStreams() []string {
+	failedStreamsLock.RLock()
+	defer failedStreamsLock.RUnlock()
+	return append([]string{}, failedStreams...)
+}
+
+// Subscribe again to the streams of the local database left by a previous run. The streams that cannot
+// be added are reported and kept in the local database to be restored again on the next startup,
+// returns the number of failed streams. Replay streams belong to the session of the previous run, they
+// are removed
+func RestoreStreams() int {
+	data.RemoveDataProviderStreamsSource(types.Replay)
+	groups := make(map[string]*restoredStreams)
+	for _, stream := range data.GetDataProviderStreams() {
+		key := fmt.Sprintf("%s.%s.%s.%s", stream.DataSource, stream.Account, stream.AssetClass, stream.DataType)
+		group, ok := groups[key]
+		if !ok {
+			group = &restoredStreams{
+				source:     stream.DataSource,
+				account:    stream.Account,
+				assetClass: stream.AssetClass,
+				dataType:   stream.DataType,
+			}
+			groups[key] = group
+		}
+		group.symbols = append(group.symbols, stream.Symbol)
+	}
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	restored := 0
+	var failed []string
+	for _, key := range keys {
+		group := groups[key]
+		req, err := requests.NewStreamRequestFromRaw(string(group.source),
+			string(group.assetClass),
+			group.symbols,
+			string(types.StreamAddOp),
+			[]string{string(group.dataType)},
+			string(group.account), requests.DefaultForEmptyStreamAddDeleteRequest)
+		if err == nil {
+			err = restoreStreams(req)
+		}
+		if err != nil {
+			logging.Log().Error().
+				Err(err).
+				RawJSON("request", req.JSON()).
+				Msg("restoring streams of the local database")
+			for _, symbol := range group.symbols {
+				topic := utils.NewStreamTopic(types.DataProvider, group.source, group.assetClass, group.dataType, symbol).Generate()
+				failed = append(failed, fmt.Sprintf("%s: %v", topic, err))
+			}
+			continue
+		}
+		restored += len(group.symbols)
+	}
+	if restored+len(failed) > 0 {
+		logging.Log().Info().
+			Int("restored", restored).
+			Int("failed", len(failed)).
+			Msg("restored streams of the local database")
+	}
+	failedStreamsLock.Lock()
+	failedStreams = failed
+	failedStreamsLock.Unlock()
+	return len(failed)
+}
+
+func restoreStreams(req requests.StreamRequest) error {
+	p, ok := provider.GetProvider(req.GetSource())
+	if !ok {
+		return fmt.Errorf("invalid source %s", req.GetSource())
+	}
+	response := p.AddStream(req)
+	if response.Err != "" {
+		return errors.New(response.Err)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package aggregator
+
+import (
+	"sync"
+	"time"
+
+	"tradingplatform/shared/bars"
+	"tradingplatform/shared/types"
+)
+
+// Any source of trades
+const AnySource types.Source = "*"
+
+type Config struct {
+	// Bars that can be streamed, built from the trades streamed by the dataprovider
+	Specs []bars.Spec
+	// Time trades are held back for late trades and trade updates before their bars are built
+	Grace time.Duration
+	// Source of the trades bars are built from, AnySource builds separate bars for every source
+	TradesSource types.Source
+}
+
+var DefaultSpecs = []string{"5sec", "10sec"}
+
+var config = Config{
+	Specs:        mustParseSpecs(DefaultSpecs),
+	Grace:        2 * time.Second,
+	TradesSource: AnySource,
+}
+var configLock sync.RWMutex
+
+func mustParseSpecs(names []string) []bars.Spec {
+	specs, err := bars.ParseSpecs(names)
+	if err != nil {
+		panic(err)
+	}
+	return specs
+}
+
+// SetConfig sets the configuration used by new aggregator streams
+func SetConfig(c Config) {
+	configLock.Lock()
+	defer configLock.Unlock()
+	config = c
+}
+
+// GetConfig returns the configuration used by new aggregator streams
+func GetConfig() Config {
+	configLock.RLock()
+	defer configLock.RUnlock()
+	return config
+}
+
+// Data types of the bars that can be streamed, the same for every asset class with trades
+func GetDataTypes(assetClass types.AssetClass) map[types.DataType]types.DataType {
+	switch assetClass {
+	case types.Stock, types.Crypto:
+		dtypes := map[types.DataType]types.DataType{}
+		for _, spec := range GetConfig().Specs {
+			dtypes[spec.DataType()] = spec.DataType()
+		}
+		return dtypes
+	default:
+		return nil
+	}
+}
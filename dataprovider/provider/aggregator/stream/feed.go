@@ -0,0 +1,172 @@
+package stream
+
+import (
+	"context"
+	"sync"
+	"time"
+	"tradingplatform/dataprovider/provider/aggregator"
+	"tradingplatform/shared/bars"
+	"tradingplatform/shared/communication"
+	"tradingplatform/shared/communication/producer"
+	sharedent "tradingplatform/shared/entities"
+	"tradingplatform/shared/logging"
+	"tradingplatform/shared/types"
+	"tradingplatform/shared/utils"
+
+	"github.com/nats-io/nats.go"
+	"google.golang.org/protobuf/proto"
+)
+
+// Interval at which the bars of a feed are completed while no trades arrive
+var ADVANCE_INTERVAL = 100 * time.Millisecond
+
+// A feed builds the bars of a symbol from the trades streamed by the dataprovider, bars are built
+// separately for each source of the trades
+type feed struct {
+	assetClass types.AssetClass
+	symbol     string
+	cfg        aggregator.Config
+	cancel     context.CancelFunc
+	specs      map[string]bars.Spec
+	builders   map[types.Source]*bars.LiveBuilder
+	lock       sync.Mutex
+}
+
+var feeds = make(map[string]*feed)
+var feedsLock sync.Mutex
+
+func feedKey(assetClass types.AssetClass, symbol string) string {
+	return string(assetClass) + "." + symbol
+}
+
+// Subscribe the bars of a spec of a symbol, starting the feed of the symbol if needed
+func subscribe(assetClass types.AssetClass, symbol string, spec bars.Spec) error {
+	feedsLock.Lock()
+	defer feedsLock.Unlock()
+
+	key := feedKey(assetClass, symbol)
+	f, ok := feeds[key]
+	if !ok {
+		f = &feed{
+			assetClass: assetClass,
+			symbol:     symbol,
+			cfg:        aggregator.GetConfig(),
+			specs:      make(map[string]bars.Spec),
+			builders:   make(map[types.Source]*bars.LiveBuilder),
+		}
+		nc, err := nats.Connect(communication.GetNatsURL())
+		if err != nil {
+			return err
+		}
+		// Corrections and cancels arrive on their own topic and are applied to the same bars
+		for _, topic := range []string{
+			aggregator.NewTradesStreamTopic(f.cfg.TradesSource, assetClass, symbol).Generate(),
+			aggregator.NewTradeUpdatesStreamTopic(f.cfg.TradesSource, assetClass, symbol).Generate(),
+		} {
+			sub, err := nc.Subscribe(topic, f.handleMessage)
+			if err != nil {
+				nc.Close()
+				return err
+			}
+			sub.SetPendingLimits(-1, -1)
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		f.cancel = cancel
+		feeds[key] = f
+		go f.run(ctx, nc)
+	}
+	f.lock.Lock()
+	f.specs[spec.Name] = spec
+	for _, builder := range f.builders {
+		builder.AddSpec(spec)
+	}
+	f.lock.Unlock()
+	return nil
+}
+
+// Unsubscribe the bars of a spec of a symbol, stopping the feed of the symbol if nothing is left
+func unsubscribe(assetClass types.AssetClass, symbol string, spec bars.Spec) {
+	feedsLock.Lock()
+	defer feedsLock.Unlock()
+
+	key := feedKey(assetClass, symbol)
+	f, ok := feeds[key]
+	if !ok {
+		return
+	}
+	f.lock.Lock()
+	delete(f.specs, spec.Name)
+	for _, builder := range f.builders {
+		builder.RemoveSpec(spec.Name)
+	}
+	empty := len(f.specs) == 0
+	f.lock.Unlock()
+	if empty {
+		f.cancel()
+		delete(feeds, key)
+	}
+}
+
+// Add the trades or trade updates of a message to the bars of their source
+func (f *feed) handleMessage(m *nats.Msg) {
+	var msg sharedent.Message
+	if err := proto.Unmarshal(m.Data, &msg); err != nil {
+		logging.Log().Error().Err(err).Str("topic", m.Subject).Msg("unmarshalling trade message")
+		return
+	}
+	now := time.Now()
+	utils.HandleEntity(&msg, &sharedent.Trade{}, func(trade *sharedent.Trade) error {
+		f.lock.Lock()
+		defer f.lock.Unlock()
+		source := types.Source(trade.Source)
+		builder, ok := f.builders[source]
+		if !ok {
+			builder = bars.NewLiveBuilder(f.cfg.Grace)
+			for _, spec := range f.specs {
+				builder.AddSpec(spec)
+			}
+			f.builders[source] = builder
+		}
+		builder.AddTrade(trade, now)
+		return nil
+	})
+}
+
+// Publish the completed bars until the context is cancelled, the subscription to the trades is
+// closed with the connection
+func (f *feed) run(ctx context.Context, nc *nats.Conn) {
+	defer nc.Close()
+	ticker := time.NewTicker(ADVANCE_INTERVAL)
+	defer ticker.Stop()
+	for {
+		var now time.Time
+		select {
+		case <-ctx.Done():
+			return
+		case now = <-ticker.C:
+		}
+
+		f.lock.Lock()
+		var completed []*sharedent.Bar
+		for source, builder := range f.builders {
+			completed = append(completed, builder.Advance(now)...)
+			if dropped := builder.Dropped(); dropped > 0 {
+				logging.Log().Debug().
+					Str("source", string(source)).
+					Str("assetClass", string(f.assetClass)).
+					Str("symbol", f.symbol).
+					Dur("grace", f.cfg.Grace).
+					Int("dropped", dropped).
+					Msg("dropping trades arriving after the grace window")
+			}
+		}
+		f.lock.Unlock()
+
+		for _, bar := range completed {
+			dtype := bars.Spec{Name: bar.Timeframe}.DataType()
+			topic := aggregator.NewStreamTopic(f.assetClass, dtype, f.symbol).Generate()
+			msg := sharedent.GenerateMessage(bar, types.Bar, topic)
+			producer.GetStreamHandler(msg.Topic).Ch <- msg
+		}
+	}
+}
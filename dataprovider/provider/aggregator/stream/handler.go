@@ -0,0 +1,106 @@
+package stream
+
+import (
+	"tradingplatform/dataprovider/data"
+	"tradingplatform/dataprovider/provider"
+	"tradingplatform/dataprovider/provider/aggregator"
+	"tradingplatform/shared/bars"
+	"tradingplatform/shared/communication/producer"
+	"tradingplatform/shared/logging"
+	"tradingplatform/shared/requests"
+	"tradingplatform/shared/types"
+)
+
+// Handle an aggregator stream add request, the trades of the symbols must be streamed separately
+func HandleAggregatorStreamAddRequest(req requests.StreamRequest) types.StreamResponse {
+	logging.Log().Debug().RawJSON("request", req.JSON()).Msg("adding aggregator stream")
+
+	dtypesHandled := []types.DataType{}
+	for _, dtype := range req.GetDataType() {
+		spec, err := bars.ParseSpecDataType(dtype)
+		if err != nil {
+			logging.Log().Error().Err(err).RawJSON("request", req.JSON()).Msg("adding aggregator stream")
+			return provider.NewStreamError(err)
+		}
+		for _, symbol := range req.GetSymbol() {
+			if err := subscribe(req.GetAssetClass(), symbol, spec); err != nil {
+				logging.Log().Error().
+					Err(err).
+					Str("symbol", symbol).
+					Str("dtype", string(dtype)).
+					Msg("subscribing to trades")
+				return provider.NewStreamError(err)
+			}
+		}
+		dtypesHandled = append(dtypesHandled, dtype)
+		data.AddDataProviderStreamForDType(req, dtype)
+	}
+
+	return provider.NewStreamResponseSourceAssetClass(
+		types.Success,
+		"Successfully added aggregator stream",
+		aggregator.GenerateJSONStreamTopicDict(req.GetAssetClass(), dtypesHandled, req.GetSymbol()),
+		nil,
+		types.Aggregator,
+		req.GetAssetClass(),
+	)
+}
+
+// Handle an aggregator stream remove request
+func HandleAggregatorStreamRemoveRequest(req requests.StreamRequest) types.StreamResponse {
+	logging.Log().Info().RawJSON("request", req.JSON()).Msg("removing aggregator stream")
+
+	dtypesHandled := []types.DataType{}
+	for _, dtype := range req.GetDataType() {
+		spec, err := bars.ParseSpecDataType(dtype)
+		if err != nil {
+			logging.Log().Error().Err(err).RawJSON("request", req.JSON()).Msg("removing aggregator stream")
+			return provider.NewStreamError(err)
+		}
+		for _, symbol := range req.GetSymbol() {
+			unsubscribe(req.GetAssetClass(), symbol, spec)
+			tTopic := aggregator.NewStreamTopic(req.GetAssetClass(), dtype, symbol).Generate()
+			producer.StopTopicHandler(tTopic)
+		}
+		dtypesHandled = append(dtypesHandled, dtype)
+		data.RemoveDataProviderStreamForDType(req, dtype)
+	}
+
+	return provider.NewStreamResponseSourceAssetClass(
+		types.Success,
+		"Successfully removed aggregator stream",
+		aggregator.GenerateJSONStreamTopicDict(req.GetAssetClass(), dtypesHandled, req.GetSymbol()),
+		nil,
+		types.Aggregator,
+		req.GetAssetClass(),
+	)
+}
+
+// Provide a response with the active aggregator streams
+func HandleAggregatorStreamGetRequest(req requests.StreamRequest) types.StreamResponse {
+	assetClass := req.GetAssetClass()
+	streams := data.GetDataProviderStreamsSourceAssetClass(types.Aggregator, assetClass)
+	dtypes := map[types.DataType]struct{}{}
+	symbols := map[string]struct{}{}
+	for _, stream := range streams {
+		dtypes[stream.DataType] = struct{}{}
+		symbols[stream.Symbol] = struct{}{}
+	}
+	dtypesSlice := make([]types.DataType, 0, len(dtypes))
+	for dtype := range dtypes {
+		dtypesSlice = append(dtypesSlice, dtype)
+	}
+	symbolsSlice := make([]string, 0, len(symbols))
+	for symbol := range symbols {
+		symbolsSlice = append(symbolsSlice, symbol)
+	}
+
+	return provider.NewStreamResponseSourceAssetClass(
+		types.Success,
+		"Successfully retrieved streams",
+		aggregator.GenerateJSONStreamTopicDict(assetClass, dtypesSlice, symbolsSlice),
+		nil,
+		types.Aggregator,
+		assetClass,
+	)
+}
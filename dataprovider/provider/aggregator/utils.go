@@ -0,0 +1,39 @@
+package aggregator
+
+import (
+	"encoding/json"
+	"tradingplatform/shared/logging"
+	"tradingplatform/shared/types"
+	"tradingplatform/shared/utils"
+)
+
+func NewStreamTopic(assetClass types.AssetClass, dataType types.DataType, symbol string) utils.Topic {
+	return utils.NewStreamTopic(types.DataProvider, types.Aggregator, assetClass, dataType, symbol)
+}
+
+// Topic of the trades bars of a symbol are built from
+func NewTradesStreamTopic(source types.Source, assetClass types.AssetClass, symbol string) utils.Topic {
+	return utils.NewStreamTopic(types.DataProvider, source, assetClass, types.Trades, symbol)
+}
+
+// Topic of the corrections and cancels of the trades bars of a symbol are built from
+func NewTradeUpdatesStreamTopic(source types.Source, assetClass types.AssetClass, symbol string) utils.Topic {
+	return utils.NewStreamTopic(types.DataProvider, source, assetClass, types.TradeUpdates, symbol)
+}
+
+func GenerateJSONStreamTopicDict(assetClass types.AssetClass, dataTypes []types.DataType, symbols []string) string {
+	tmap := map[types.DataType][]string{}
+	for _, dataType := range dataTypes {
+		value := tmap[dataType]
+		for _, symbol := range symbols {
+			value = append(value, NewStreamTopic(assetClass, dataType, symbol).Generate())
+		}
+		tmap[dataType] = value
+	}
+	json, err := json.Marshal(tmap)
+	if err != nil {
+		logging.Log().Error().Str("assetClass", string(assetClass)).Strs("symbols", symbols).Err(err).Msg("generating topics")
+		return ""
+	}
+	return string(json)
+}
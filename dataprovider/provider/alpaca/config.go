@@ -1,4 +1,27 @@
 package alpaca
 
+import (
+	"tradingplatform/shared/types"
+
+	"github.com/alpacahq/alpaca-trade-api-go/v3/marketdata"
+)
+
 var DEFAULT_EXCHANGE_STOCK = "SIP"
 var DEFAULT_EXCHANGE_CRYPTO = "US"
+
+// Feed of the stock streams of an account, IEX when the account does not set one
+func GetStreamFeed(feed types.Feed) marketdata.Feed {
+	if feed == types.SIPFeed {
+		return marketdata.SIP
+	}
+	return marketdata.IEX
+}
+
+// Feed of the historical stock data of an account, SIP when the account does not set one as the
+// historical SIP data older than 15 minutes is available to all accounts
+func GetDataFeed(feed types.Feed) marketdata.Feed {
+	if feed == types.IEXFeed {
+		return marketdata.IEX
+	}
+	return marketdata.SIP
+}
@@ -23,29 +23,33 @@ func handleAlpacaCryptoDataRequest(req requests.DataRequest) types.DataResponse
 	logging.Log().Debug().RawJSON("request", req.JSON()).Msg("handling alpaca crypto data request")
 	switch dtype {
 	case types.Bar:
+		timeFrame, err := alpaca.GetAlpacaTimeFrame(req.GetTimeFrame())
+		if err != nil {
+			return types.NewDataError(err)
+		}
 		messages, response = handleDataFetch[marketdata.GetCryptoBarsRequest,
 			marketdata.CryptoBar,
 			*sharedent.Bar](marketdata.GetCryptoBars, symbol, marketdata.GetCryptoBarsRequest{
-			TimeFrame: alpaca.GetAlpacaTimeFrame(req.GetTimeFrame()),
+			TimeFrame: timeFrame,
 			PageLimit: 10000,
-			Start:     time.Unix(req.GetStartTime(), 0),
-			End:       time.Unix(req.GetEndTime(), 0),
+			Start:     time.Unix(0, req.GetStartTime()),
+			End:       time.Unix(0, req.GetEndTime()),
 		}, types.Bar, types.Crypto, req.GetTimeFrame())
 	case types.Trades:
 		messages, response = handleDataFetch[marketdata.GetCryptoTradesRequest,
 			marketdata.CryptoTrade,
 			*sharedent.Trade](marketdata.GetCryptoTrades, symbol, marketdata.GetCryptoTradesRequest{
 			PageLimit: 10000,
-			Start:     time.Unix(req.GetStartTime(), 0),
-			End:       time.Unix(req.GetEndTime(), 0),
+			Start:     time.Unix(0, req.GetStartTime()),
+			End:       time.Unix(0, req.GetEndTime()),
 		}, types.Trades, types.Crypto, req.GetTimeFrame())
 	case types.Quotes:
 		messages, response = handleDataFetch[marketdata.GetCryptoQuotesRequest,
 			marketdata.CryptoQuote,
 			*sharedent.Quote](marketdata.GetCryptoQuotes, symbol, marketdata.GetCryptoQuotesRequest{
 			PageLimit: 10000,
-			Start:     time.Unix(req.GetStartTime(), 0),
-			End:       time.Unix(req.GetEndTime(), 0),
+			Start:     time.Unix(0, req.GetStartTime()),
+			End:       time.Unix(0, req.GetEndTime()),
 		}, types.Quotes, types.Crypto, req.GetTimeFrame())
 	default:
 		return types.NewDataError(
@@ -57,7 +61,7 @@ func handleAlpacaCryptoDataRequest(req requests.DataRequest) types.DataResponse
 	}
 	if len(*messages) == 0 {
 		return types.NewDataError(
-			fmt.Errorf("no data found for %s", symbol),
+			types.NewNoDataError(symbol),
 		)
 	}
 	responseTopic := (*messages)[0].Topic
@@ -65,7 +69,7 @@ func handleAlpacaCryptoDataRequest(req requests.DataRequest) types.DataResponse
 	if handlerResponse.Err != "" {
 		return handlerResponse
 	}
-	handler.Ch <- messages
+	handler.Ch <- producer.NewQueue(responseTopic, *messages)
 
 	return response
 }
@@ -3,6 +3,7 @@ package data
 import (
 	"fmt"
 	"tradingplatform/dataprovider/provider/alpaca"
+	"tradingplatform/shared/accounts"
 	"tradingplatform/shared/communication/producer"
 	sharedent "tradingplatform/shared/entities"
 	"tradingplatform/shared/logging"
@@ -125,3 +126,13 @@ func HandleAlpacaDataRequest(dataRequest requests.DataRequest) types.DataRespons
 		)
 	}
 }
+
+// Get the account of a data request from the registry, any stands for the default account
+func getAccountCredentials(req requests.DataRequest) (accounts.Credentials, error) {
+	account := requests.ResolveAccount(req.GetAccount())
+	credentials, ok := accounts.Get(string(account))
+	if !ok {
+		return credentials, fmt.Errorf("account %s not registered", account)
+	}
+	return credentials, nil
+}
@@ -2,7 +2,6 @@ package data
 
 import (
 	"fmt"
-	"os"
 	"time"
 	"tradingplatform/shared/communication/producer"
 	sharedent "tradingplatform/shared/entities"
@@ -33,18 +32,22 @@ func handleAlpacaNewsDataRequest(req requests.DataRequest) types.DataResponse {
 	var response types.DataResponse
 	var messages *[]*sharedent.Message
 	logging.Log().Debug().RawJSON("request", req.JSON()).Msg("handling alpaca news data request")
+	credentials, err := getAccountCredentials(req)
+	if err != nil {
+		return types.NewDataError(err)
+	}
 	client := ClientWrapper{
 		client: marketdata.NewClient(marketdata.ClientOpts{
-			APIKey:    os.Getenv("ALPACA_KEY"),
-			APISecret: os.Getenv("ALPACA_SECRET"),
+			APIKey:    credentials.Key,
+			APISecret: credentials.Secret,
 		})}
 	symbol := req.GetSymbol()
 
 	messages, response = handleDataFetch[marketdata.GetNewsRequest,
 		marketdata.News, *sharedent.News](client.getNewsWrapper, symbol, marketdata.GetNewsRequest{
 		PageLimit: 10000,
-		Start:     time.Unix(req.GetStartTime(), 0),
-		End:       time.Unix(req.GetEndTime(), 0),
+		Start:     time.Unix(0, req.GetStartTime()),
+		End:       time.Unix(0, req.GetEndTime()),
 	}, types.RawText, types.News, req.GetTimeFrame())
 	if response.Err != "" {
 		return response
@@ -59,7 +62,7 @@ func handleAlpacaNewsDataRequest(req requests.DataRequest) types.DataResponse {
 	if handlerResponse.Err != "" {
 		return handlerResponse
 	}
-	handler.Ch <- messages
+	handler.Ch <- producer.NewQueue(responseTopic, *messages)
 
 	return response
 }
@@ -2,7 +2,6 @@ package data
 
 import (
 	"fmt"
-	"os"
 	"time"
 	"tradingplatform/dataprovider/provider/alpaca"
 	"tradingplatform/shared/communication/producer"
@@ -18,22 +17,30 @@ import (
 func handleAlpacaStockDataRequest(req requests.DataRequest) types.DataResponse {
 	var response types.DataResponse
 	var messages *[]*sharedent.Message
+	credentials, err := getAccountCredentials(req)
+	if err != nil {
+		return types.NewDataError(err)
+	}
 	client := marketdata.NewClient(marketdata.ClientOpts{
-		APIKey:    os.Getenv("ALPACA_KEY"),
-		APISecret: os.Getenv("ALPACA_SECRET"),
-		Feed:      marketdata.SIP,
+		APIKey:    credentials.Key,
+		APISecret: credentials.Secret,
+		Feed:      alpaca.GetDataFeed(credentials.Feed),
 	})
 	symbol := req.GetSymbol()
 	dtype := req.GetDataType()
 	logging.Log().Debug().RawJSON("request", req.JSON()).Msg("handling alpaca stock data request")
 	switch dtype {
 	case types.Bar:
+		timeFrame, err := alpaca.GetAlpacaTimeFrame(req.GetTimeFrame())
+		if err != nil {
+			return types.NewDataError(err)
+		}
 		messages, response = handleDataFetch[marketdata.GetBarsRequest,
 			marketdata.Bar, *sharedent.Bar](client.GetBars, symbol, marketdata.GetBarsRequest{
-			TimeFrame:  alpaca.GetAlpacaTimeFrame(req.GetTimeFrame()),
+			TimeFrame:  timeFrame,
 			PageLimit:  10000,
-			Start:      time.Unix(req.GetStartTime(), 0),
-			End:        time.Unix(req.GetEndTime(), 0),
+			Start:      time.Unix(0, req.GetStartTime()),
+			End:        time.Unix(0, req.GetEndTime()),
 			Adjustment: marketdata.All,
 		}, types.Bar, types.Stock, req.GetTimeFrame())
 
@@ -41,15 +48,15 @@ func handleAlpacaStockDataRequest(req requests.DataRequest) types.DataResponse {
 		messages, response = handleDataFetch[marketdata.GetTradesRequest,
 			marketdata.Trade, *sharedent.Trade](client.GetTrades, symbol, marketdata.GetTradesRequest{
 			PageLimit: 10000,
-			Start:     time.Unix(req.GetStartTime(), 0),
-			End:       time.Unix(req.GetEndTime(), 0),
+			Start:     time.Unix(0, req.GetStartTime()),
+			End:       time.Unix(0, req.GetEndTime()),
 		}, types.Trades, types.Stock, req.GetTimeFrame())
 	case types.Quotes:
 		messages, response = handleDataFetch[marketdata.GetQuotesRequest,
 			marketdata.Quote, *sharedent.Quote](client.GetQuotes, symbol, marketdata.GetQuotesRequest{
 			PageLimit: 10000,
-			Start:     time.Unix(req.GetStartTime(), 0),
-			End:       time.Unix(req.GetEndTime(), 0),
+			Start:     time.Unix(0, req.GetStartTime()),
+			End:       time.Unix(0, req.GetEndTime()),
 		}, types.Quotes, types.Stock, req.GetTimeFrame())
 
 	default:
@@ -62,7 +69,7 @@ func handleAlpacaStockDataRequest(req requests.DataRequest) types.DataResponse {
 	}
 	if len(*messages) == 0 {
 		return types.NewDataError(
-			fmt.Errorf("no data found for %s", symbol),
+			types.NewNoDataError(symbol),
 		)
 	}
 	responseTopic := (*messages)[0].Topic
@@ -70,7 +77,7 @@ func handleAlpacaStockDataRequest(req requests.DataRequest) types.DataResponse {
 	if handlerResponse.Err != "" {
 		return handlerResponse
 	}
-	handler.Ch <- messages
+	handler.Ch <- producer.NewQueue(responseTopic, *messages)
 
 	return response
 }
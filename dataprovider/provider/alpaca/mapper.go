@@ -1,6 +1,7 @@
 package alpaca
 
 import (
+	"fmt"
 	sharedent "tradingplatform/shared/entities"
 	"tradingplatform/shared/types"
 
@@ -105,16 +106,16 @@ func MapEntityWithReturnEntity(i interface{}, symbol string) interface{} {
 
 func MapCryptoBar(cb astream.CryptoBar) (*sharedent.Bar, types.DataType) {
 	newBar := sharedent.Bar{
-		Symbol:     cb.Symbol,
-		Open:       cb.Open,
-		High:       cb.High,
-		Low:        cb.Low,
-		Close:      cb.Close,
-		Volume:     cb.Volume,
-		Timestamp:  cb.Timestamp.Unix(),
-		Exchange:   cb.Exchange,
-		VWAP:       cb.VWAP,
-		AssetClass: string(types.Crypto),
+		Symbol:      cb.Symbol,
+		Open:        cb.Open,
+		High:        cb.High,
+		Low:         cb.Low,
+		Close:       cb.Close,
+		Volume:      cb.Volume,
+		TimestampNs: cb.Timestamp.UnixNano(),
+		Exchange:    cb.Exchange,
+		VWAP:        cb.VWAP,
+		AssetClass:  string(types.Crypto),
 	}
 
 	newBar.SetFingerprint()
@@ -123,15 +124,15 @@ func MapCryptoBar(cb astream.CryptoBar) (*sharedent.Bar, types.DataType) {
 
 func MapMarketCryptoBar(cb marketdata.CryptoBar, symbol string) (*sharedent.Bar, types.DataType) {
 	newBar := sharedent.Bar{
-		Symbol:     symbol,
-		Open:       cb.Open,
-		High:       cb.High,
-		Low:        cb.Low,
-		Close:      cb.Close,
-		Volume:     cb.Volume,
-		Timestamp:  cb.Timestamp.Unix(),
-		VWAP:       cb.VWAP,
-		AssetClass: string(types.Crypto),
+		Symbol:      symbol,
+		Open:        cb.Open,
+		High:        cb.High,
+		Low:         cb.Low,
+		Close:       cb.Close,
+		Volume:      cb.Volume,
+		TimestampNs: cb.Timestamp.UnixNano(),
+		VWAP:        cb.VWAP,
+		AssetClass:  string(types.Crypto),
 	}
 
 	newBar.SetFingerprint()
@@ -140,10 +141,10 @@ func MapMarketCryptoBar(cb marketdata.CryptoBar, symbol string) (*sharedent.Bar,
 
 func MapCryptoOrderbook(ob astream.CryptoOrderbook) (*sharedent.Orderbook, types.DataType) {
 	newOrderbook := sharedent.Orderbook{
-		Symbol:     ob.Symbol,
-		Exchange:   ob.Exchange,
-		Timestamp:  ob.Timestamp.Unix(),
-		AssetClass: string(types.Crypto),
+		Symbol:      ob.Symbol,
+		Exchange:    ob.Exchange,
+		TimestampNs: ob.Timestamp.UnixNano(),
+		AssetClass:  string(types.Crypto),
 	}
 	for _, ask := range ob.Asks {
 		newOrderbook.Asks = append(newOrderbook.Asks, &sharedent.OrderbookEntry{
@@ -165,14 +166,14 @@ func MapCryptoOrderbook(ob astream.CryptoOrderbook) (*sharedent.Orderbook, types
 
 func MapCryptoQuote(q astream.CryptoQuote) (*sharedent.Quote, types.DataType) {
 	newQuote := sharedent.Quote{
-		Symbol:     q.Symbol,
-		Exchange:   q.Exchange,
-		Timestamp:  q.Timestamp.Unix(),
-		AskPrice:   q.AskPrice,
-		AskSize:    q.AskSize,
-		BidPrice:   q.BidPrice,
-		BidSize:    q.BidSize,
-		AssetClass: string(types.Crypto),
+		Symbol:      q.Symbol,
+		Exchange:    q.Exchange,
+		TimestampNs: q.Timestamp.UnixNano(),
+		AskPrice:    q.AskPrice,
+		AskSize:     q.AskSize,
+		BidPrice:    q.BidPrice,
+		BidSize:     q.BidSize,
+		AssetClass:  string(types.Crypto),
 	}
 
 	newQuote.SetFingerprint()
@@ -181,13 +182,13 @@ func MapCryptoQuote(q astream.CryptoQuote) (*sharedent.Quote, types.DataType) {
 
 func MapMarketCryptoQuote(q marketdata.CryptoQuote, symbol string) (*sharedent.Quote, types.DataType) {
 	newQuote := sharedent.Quote{
-		Symbol:     symbol,
-		Timestamp:  q.Timestamp.Unix(),
-		AskPrice:   q.AskPrice,
-		AskSize:    q.AskSize,
-		BidPrice:   q.BidPrice,
-		BidSize:    q.BidSize,
-		AssetClass: string(types.Crypto),
+		Symbol:      symbol,
+		TimestampNs: q.Timestamp.UnixNano(),
+		AskPrice:    q.AskPrice,
+		AskSize:     q.AskSize,
+		BidPrice:    q.BidPrice,
+		BidSize:     q.BidSize,
+		AssetClass:  string(types.Crypto),
 	}
 
 	newQuote.SetFingerprint()
@@ -196,14 +197,14 @@ func MapMarketCryptoQuote(q marketdata.CryptoQuote, symbol string) (*sharedent.Q
 
 func MapCryptoTrade(t astream.CryptoTrade) (*sharedent.Trade, types.DataType) {
 	newTrade := sharedent.Trade{
-		ID:         t.ID,
-		Symbol:     t.Symbol,
-		Exchange:   t.Exchange,
-		Timestamp:  t.Timestamp.Unix(),
-		Price:      t.Price,
-		Size:       t.Size,
-		TakerSide:  string(t.TakerSide),
-		AssetClass: string(types.Crypto),
+		ID:          t.ID,
+		Symbol:      t.Symbol,
+		Exchange:    t.Exchange,
+		TimestampNs: t.Timestamp.UnixNano(),
+		Price:       t.Price,
+		Size:        t.Size,
+		TakerSide:   string(t.TakerSide),
+		AssetClass:  string(types.Crypto),
 	}
 
 	newTrade.SetFingerprint()
@@ -212,13 +213,13 @@ func MapCryptoTrade(t astream.CryptoTrade) (*sharedent.Trade, types.DataType) {
 
 func MapMarketCryptoTrade(t marketdata.CryptoTrade, symbol string) (*sharedent.Trade, types.DataType) {
 	newTrade := sharedent.Trade{
-		ID:         t.ID,
-		Symbol:     symbol,
-		Timestamp:  t.Timestamp.Unix(),
-		Price:      t.Price,
-		Size:       t.Size,
-		TakerSide:  string(t.TakerSide),
-		AssetClass: string(types.Crypto),
+		ID:          t.ID,
+		Symbol:      symbol,
+		TimestampNs: t.Timestamp.UnixNano(),
+		Price:       t.Price,
+		Size:        t.Size,
+		TakerSide:   string(t.TakerSide),
+		AssetClass:  string(types.Crypto),
 	}
 
 	newTrade.SetFingerprint()
@@ -227,15 +228,15 @@ func MapMarketCryptoTrade(t marketdata.CryptoTrade, symbol string) (*sharedent.T
 
 func MapStockBar(b astream.Bar) (*sharedent.Bar, types.DataType) {
 	newBar := sharedent.Bar{
-		Symbol:     b.Symbol,
-		Open:       b.Open,
-		High:       b.High,
-		Low:        b.Low,
-		Close:      b.Close,
-		Volume:     float64(b.Volume),
-		Timestamp:  b.Timestamp.Unix(),
-		VWAP:       b.VWAP,
-		AssetClass: string(types.Stock),
+		Symbol:      b.Symbol,
+		Open:        b.Open,
+		High:        b.High,
+		Low:         b.Low,
+		Close:       b.Close,
+		Volume:      float64(b.Volume),
+		TimestampNs: b.Timestamp.UnixNano(),
+		VWAP:        b.VWAP,
+		AssetClass:  string(types.Stock),
 	}
 
 	newBar.SetFingerprint()
@@ -244,16 +245,16 @@ func MapStockBar(b astream.Bar) (*sharedent.Bar, types.DataType) {
 
 func MapMarketStockBar(b marketdata.Bar, symbol string) (*sharedent.Bar, types.DataType) {
 	newBar := sharedent.Bar{
-		Symbol:     symbol,
-		Open:       b.Open,
-		High:       b.High,
-		Low:        b.Low,
-		Close:      b.Close,
-		Volume:     float64(b.Volume),
-		TradeCount: b.TradeCount,
-		Timestamp:  b.Timestamp.Unix(),
-		VWAP:       b.VWAP,
-		AssetClass: string(types.Stock),
+		Symbol:      symbol,
+		Open:        b.Open,
+		High:        b.High,
+		Low:         b.Low,
+		Close:       b.Close,
+		Volume:      float64(b.Volume),
+		TradeCount:  b.TradeCount,
+		TimestampNs: b.Timestamp.UnixNano(),
+		VWAP:        b.VWAP,
+		AssetClass:  string(types.Stock),
 	}
 
 	newBar.SetFingerprint()
@@ -262,15 +263,15 @@ func MapMarketStockBar(b marketdata.Bar, symbol string) (*sharedent.Bar, types.D
 
 func MapStockTrade(t astream.Trade) (*sharedent.Trade, types.DataType) {
 	newTrade := sharedent.Trade{
-		ID:         t.ID,
-		Symbol:     t.Symbol,
-		Exchange:   t.Exchange,
-		Price:      t.Price,
-		Size:       float64(t.Size),
-		Timestamp:  t.Timestamp.Unix(),
-		Conditions: t.Conditions,
-		Tape:       t.Tape,
-		AssetClass: string(types.Stock),
+		ID:          t.ID,
+		Symbol:      t.Symbol,
+		Exchange:    t.Exchange,
+		Price:       t.Price,
+		Size:        float64(t.Size),
+		TimestampNs: t.Timestamp.UnixNano(),
+		Conditions:  t.Conditions,
+		Tape:        t.Tape,
+		AssetClass:  string(types.Stock),
 	}
 
 	newTrade.SetFingerprint()
@@ -279,22 +280,77 @@ func MapStockTrade(t astream.Trade) (*sharedent.Trade, types.DataType) {
 
 func MapMarketStockTrade(t marketdata.Trade, symbol string) (*sharedent.Trade, types.DataType) {
 	newTrade := sharedent.Trade{
-		ID:         t.ID,
-		Symbol:     symbol,
-		Exchange:   t.Exchange,
-		Update:     t.Update,
-		Price:      t.Price,
-		Size:       float64(t.Size),
-		Timestamp:  t.Timestamp.Unix(),
-		Conditions: t.Conditions,
-		Tape:       t.Tape,
-		AssetClass: string(types.Stock),
+		ID:          t.ID,
+		Symbol:      symbol,
+		Exchange:    t.Exchange,
+		Update:      t.Update,
+		Price:       t.Price,
+		Size:        float64(t.Size),
+		TimestampNs: t.Timestamp.UnixNano(),
+		Conditions:  t.Conditions,
+		Tape:        t.Tape,
+		AssetClass:  string(types.Stock),
 	}
 
 	newTrade.SetFingerprint()
 	return &newTrade, types.Trades
 }
 
+// MapStockTradeCorrection maps a trade correction to the original trade, marked as incorrect, and
+// the trade correcting it
+func MapStockTradeCorrection(c astream.TradeCorrection) []*sharedent.Trade {
+	original := sharedent.Trade{
+		ID:          c.OriginalID,
+		Symbol:      c.Symbol,
+		Exchange:    c.Exchange,
+		Update:      types.TradeIncorrect,
+		Price:       c.OriginalPrice,
+		Size:        float64(c.OriginalSize),
+		TimestampNs: c.Timestamp.UnixNano(),
+		Conditions:  c.OriginalConditions,
+		Tape:        c.Tape,
+		AssetClass:  string(types.Stock),
+	}
+	corrected := sharedent.Trade{
+		ID:          c.CorrectedID,
+		Symbol:      c.Symbol,
+		Exchange:    c.Exchange,
+		Update:      types.TradeCorrected,
+		Price:       c.CorrectedPrice,
+		Size:        float64(c.CorrectedSize),
+		TimestampNs: c.Timestamp.UnixNano(),
+		Conditions:  c.CorrectedConditions,
+		Tape:        c.Tape,
+		AssetClass:  string(types.Stock),
+	}
+
+	original.SetFingerprint()
+	corrected.SetFingerprint()
+	return []*sharedent.Trade{&original, &corrected}
+}
+
+// MapStockTradeCancelError maps a trade cancel or error to the trade it removes
+func MapStockTradeCancelError(e astream.TradeCancelError) *sharedent.Trade {
+	update := types.TradeCanceled
+	if e.CancelErrorAction == "E" {
+		update = types.TradeIncorrect
+	}
+	newTrade := sharedent.Trade{
+		ID:          e.ID,
+		Symbol:      e.Symbol,
+		Exchange:    e.Exchange,
+		Update:      update,
+		Price:       e.Price,
+		Size:        float64(e.Size),
+		TimestampNs: e.Timestamp.UnixNano(),
+		Tape:        e.Tape,
+		AssetClass:  string(types.Stock),
+	}
+
+	newTrade.SetFingerprint()
+	return &newTrade
+}
+
 func MapStockQuote(q astream.Quote) (*sharedent.Quote, types.DataType) {
 	newQuote := sharedent.Quote{
 		Symbol:      q.Symbol,
@@ -304,7 +360,7 @@ func MapStockQuote(q astream.Quote) (*sharedent.Quote, types.DataType) {
 		AskExchange: q.AskExchange,
 		AskPrice:    q.AskPrice,
 		AskSize:     float64(q.AskSize),
-		Timestamp:   q.Timestamp.Unix(),
+		TimestampNs: q.Timestamp.UnixNano(),
 		Conditions:  q.Conditions,
 		Tape:        q.Tape,
 		AssetClass:  string(types.Stock),
@@ -323,7 +379,7 @@ func MapMarketStockQuote(q marketdata.Quote, symbol string) (*sharedent.Quote, t
 		AskExchange: q.AskExchange,
 		AskPrice:    q.AskPrice,
 		AskSize:     float64(q.AskSize),
-		Timestamp:   q.Timestamp.Unix(),
+		TimestampNs: q.Timestamp.UnixNano(),
 		Conditions:  q.Conditions,
 		Tape:        q.Tape,
 		AssetClass:  string(types.Stock),
@@ -339,7 +395,7 @@ func MapStockLULD(luld astream.LULD) (*sharedent.LULD, types.DataType) {
 		LimitUpPrice:   luld.LimitUpPrice,
 		LimitDownPrice: luld.LimitDownPrice,
 		Indicator:      luld.Indicator,
-		Timestamp:      luld.Timestamp.Unix(),
+		TimestampNs:    luld.Timestamp.UnixNano(),
 		Tape:           luld.Tape,
 		AssetClass:     string(types.Stock),
 	}
@@ -350,14 +406,14 @@ func MapStockLULD(luld astream.LULD) (*sharedent.LULD, types.DataType) {
 
 func MapStockTradingStatus(status astream.TradingStatus) (*sharedent.TradingStatus, types.DataType) {
 	newStatus := sharedent.TradingStatus{
-		Symbol:     status.Symbol,
-		StatusCode: status.StatusCode,
-		StatusMsg:  status.StatusMsg,
-		ReasonCode: status.ReasonCode,
-		ReasonMsg:  status.ReasonMsg,
-		Timestamp:  status.Timestamp.Unix(),
-		Tape:       status.Tape,
-		AssetClass: string(types.Stock),
+		Symbol:      status.Symbol,
+		StatusCode:  status.StatusCode,
+		StatusMsg:   status.StatusMsg,
+		ReasonCode:  status.ReasonCode,
+		ReasonMsg:   status.ReasonMsg,
+		TimestampNs: status.Timestamp.UnixNano(),
+		Tape:        status.Tape,
+		AssetClass:  string(types.Stock),
 	}
 
 	newStatus.SetFingerprint()
@@ -366,15 +422,15 @@ func MapStockTradingStatus(status astream.TradingStatus) (*sharedent.TradingStat
 
 func MapNews(news astream.News) (*sharedent.News, types.DataType) {
 	newNews := sharedent.News{
-		Id:        int64(news.ID),
-		Author:    news.Author,
-		CreatedAt: news.CreatedAt.Unix(),
-		UpdatedAt: news.UpdatedAt.Unix(),
-		Headline:  news.Headline,
-		Summary:   news.Summary,
-		Content:   news.Content,
-		URL:       news.URL,
-		Symbols:   news.Symbols,
+		Id:          int64(news.ID),
+		Author:      news.Author,
+		CreatedAtNs: news.CreatedAt.UnixNano(),
+		UpdatedAtNs: news.UpdatedAt.UnixNano(),
+		Headline:    news.Headline,
+		Summary:     news.Summary,
+		Content:     news.Content,
+		URL:         news.URL,
+		Symbols:     news.Symbols,
 	}
 
 	newNews.SetFingerprint()
@@ -383,30 +439,39 @@ func MapNews(news astream.News) (*sharedent.News, types.DataType) {
 
 func MapMarketNews(news marketdata.News) (*sharedent.News, types.DataType) {
 	newNews := sharedent.News{
-		Id:        int64(news.ID),
-		Author:    news.Author,
-		CreatedAt: news.CreatedAt.Unix(),
-		UpdatedAt: news.UpdatedAt.Unix(),
-		Headline:  news.Headline,
-		Summary:   news.Summary,
-		Content:   news.Content,
-		URL:       news.URL,
-		Symbols:   news.Symbols,
+		Id:          int64(news.ID),
+		Author:      news.Author,
+		CreatedAtNs: news.CreatedAt.UnixNano(),
+		UpdatedAtNs: news.UpdatedAt.UnixNano(),
+		Headline:    news.Headline,
+		Summary:     news.Summary,
+		Content:     news.Content,
+		URL:         news.URL,
+		Symbols:     news.Symbols,
 	}
 
 	newNews.SetFingerprint()
 	return &newNews, types.RawText
 }
 
-func GetAlpacaTimeFrame(timeFrame types.TimeFrame) marketdata.TimeFrame {
-	m := map[types.TimeFrame]marketdata.TimeFrame{
-		types.OneMin:   marketdata.OneMin,
-		types.OneHour:  marketdata.OneHour,
-		types.OneDay:   marketdata.OneDay,
-		types.OneWeek:  marketdata.OneWeek,
-		types.OneMonth: marketdata.OneMonth,
+// GetAlpacaTimeFrame maps a timeframe to the Alpaca one, it fails for timeframes Alpaca has no
+// bars for
+func GetAlpacaTimeFrame(timeFrame types.TimeFrame) (marketdata.TimeFrame, error) {
+	m := map[types.TimeFrame]marketdata.TimeFrameUnit{
+		types.OneMin:   marketdata.Min,
+		types.OneHour:  marketdata.Hour,
+		types.OneDay:   marketdata.Day,
+		types.OneWeek:  marketdata.Week,
+		types.OneMonth: marketdata.Month,
 	}
 
-	return m[timeFrame]
-
+	count, unit, ok := types.ParseTimeFrame(timeFrame)
+	if !ok {
+		return marketdata.TimeFrame{}, fmt.Errorf("unsupported timeframe %s", timeFrame)
+	}
+	alpacaUnit, ok := m[unit]
+	if !ok {
+		return marketdata.TimeFrame{}, fmt.Errorf("unsupported timeframe %s", timeFrame)
+	}
+	return marketdata.NewTimeFrame(count, alpacaUnit), nil
 }
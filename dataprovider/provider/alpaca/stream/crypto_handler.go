@@ -3,11 +3,11 @@ package stream
 import (
 	"context"
 	"fmt"
-	"os"
 	"sync"
 	"tradingplatform/dataprovider/data"
 	"tradingplatform/dataprovider/provider"
 	"tradingplatform/dataprovider/provider/alpaca"
+	"tradingplatform/shared/accounts"
 	"tradingplatform/shared/communication/producer"
 	sharedent "tradingplatform/shared/entities"
 	"tradingplatform/shared/logging"
@@ -18,80 +18,104 @@ import (
 	astream "github.com/alpacahq/alpaca-trade-api-go/v3/marketdata/stream"
 )
 
-// Handle a crypto stream request for Alpaca and delegate based on operation
-func handleAlpacaCryptoStreamRequest(req requests.StreamRequest) types.StreamResponse {
-	account := req.GetAccount()
-	// Only default account is supported for now
-	// TODO: Allow account separation
-	if account != requests.DefaultAccount && account != requests.AnyAccount {
-		err := fmt.Errorf(
-			"account %s not supported, only default and any account options are supported",
-			account,
-		)
+// Get the crypto client of the account of a stream request and its lock, a client is connected if the
+// account has none
+func getCryptoClient(req requests.StreamRequest) (*astream.CryptoClient, *sync.RWMutex, error) {
+	account, credentials, err := getAccountCredentials(req)
+	if err != nil {
 		logging.Log().Error().
 			Err(err).
+			Str("account", string(account)).
 			RawJSON("request", req.JSON()).
 			Msg("handling Alpaca crypto stream request")
-		return provider.NewStreamError(
-			err,
-		)
+		return nil, nil, err
 	}
 
 	// Get the client for the account and client lock
 	alpacaCryptoMapLock.RLock()
-	clientLock, okLock := alpacaCryptoMapOfLocks[requests.DefaultAccount]
-	client, ok := alpacaCryptoClientMap[requests.DefaultAccount]
+	clientLock, okLock := alpacaCryptoMapOfLocks[account]
+	client, ok := alpacaCryptoClientMap[account]
 	alpacaCryptoMapLock.RUnlock()
 
 	if !okLock {
 		logging.Log().Debug().
-			Str("account", string(requests.DefaultAccount)).
+			Str("account", string(account)).
 			RawJSON("request", req.JSON()).
 			Msg("ceating new Alpaca crypto client lock")
 		clientLock = &sync.RWMutex{}
 		alpacaCryptoMapLock.Lock()
-		alpacaCryptoMapOfLocks[requests.DefaultAccount] = clientLock
+		alpacaCryptoMapOfLocks[account] = clientLock
 		alpacaCryptoMapLock.Unlock()
 	}
 
 	if !ok {
 		logging.Log().Debug().
-			Str("account", string(requests.DefaultAccount)).
+			Str("account", string(account)).
 			RawJSON("request", req.JSON()).
 			Msg("creating new Alpaca crypto client")
-		client = astream.NewCryptoClient(marketdata.US,
-			astream.WithCredentials(os.Getenv("ALPACA_KEY"), os.Getenv("ALPACA_SECRET")),
-		)
-		err := client.Connect(context.TODO())
+		ctx := getAccountContext(account)
+		client, err = newCryptoClient(ctx, account, credentials)
 		if err != nil {
 			logging.Log().Error().
 				Err(err).
 				RawJSON("request", req.JSON()).
 				Msg("connecting to Alpaca using crypto client")
-			return provider.NewStreamError(err)
+			return nil, nil, err
 		}
 		alpacaCryptoMapLock.Lock()
-		alpacaCryptoClientMap[requests.DefaultAccount] = client
+		alpacaCryptoClientMap[account] = client
 		alpacaCryptoMapLock.Unlock()
+		go supervise(ctx, account, types.Crypto, client.Terminated(), reconnectCrypto(account))
 	}
+	return client, clientLock, nil
+}
 
-	// Handle request operation
-	switch req.GetOperation() {
-	case types.StreamAddOp:
-		return handleAlpacaCryptoStreamAddRequest(client, clientLock, req)
-	case types.StreamGetOp:
-		return handleAlpacaStreamGetRequest(req, types.Crypto)
-	case types.StreamRemoveOp:
-		return handleAlpacaCryptoStreamRemoveRequest(client, clientLock, req)
-	default:
-		err := fmt.Errorf("operation %s not supported", req.GetOperation())
-		logging.Log().Error().
-			Err(err).
-			RawJSON("request", req.JSON()).
-			Msg("handling Alpaca crypto stream request")
-		return provider.NewStreamError(
-			err,
-		)
+// Get the connected crypto client of an account and its lock
+func getConnectedCryptoClient(account requests.Account) (*astream.CryptoClient, *sync.RWMutex, bool) {
+	alpacaCryptoMapLock.RLock()
+	defer alpacaCryptoMapLock.RUnlock()
+	client, ok := alpacaCryptoClientMap[account]
+	clientLock, okLock := alpacaCryptoMapOfLocks[account]
+	return client, clientLock, ok && okLock
+}
+
+// Create a crypto client of an account and connect it, the transitions of its connection are published
+func newCryptoClient(ctx context.Context, account requests.Account,
+	credentials accounts.Credentials) (*astream.CryptoClient, error) {
+
+	client := astream.NewCryptoClient(marketdata.US,
+		astream.WithCredentials(credentials.Key, credentials.Secret),
+		astream.WithConnectCallback(func() {
+			setConnectionState(account, types.Crypto, types.Connected, nil)
+		}),
+		astream.WithDisconnectCallback(func() {
+			setConnectionState(account, types.Crypto, types.Disconnected, nil)
+		}),
+	)
+	return client, client.Connect(ctx)
+}
+
+// Replace the crypto client of an account by a new client, subscribed to the crypto streams of the account
+// by the returned function
+func reconnectCrypto(account requests.Account) reconnectFunc {
+	return func(ctx context.Context) (<-chan error, func() error, error) {
+		credentials, ok := accounts.Get(string(account))
+		if !ok {
+			return nil, nil, fmt.Errorf("account %s not registered", account)
+		}
+		client, err := newCryptoClient(ctx, account, credentials)
+		if err != nil {
+			return nil, nil, err
+		}
+		clientLock := getClientLock(&alpacaCryptoMapLock, alpacaCryptoMapOfLocks, account)
+		alpacaCryptoMapLock.Lock()
+		alpacaCryptoClientMap[account] = client
+		alpacaCryptoMapLock.Unlock()
+		return client.Terminated(), func() error {
+			return resubscribe(account, types.Crypto, func(req requests.StreamRequest) types.StreamResponse {
+				return handleAlpacaCryptoStreamAddRequest(client, clientLock, req)
+			})
+		}, nil
 	}
 }
 
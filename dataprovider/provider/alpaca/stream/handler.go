@@ -1,14 +1,18 @@
 package stream
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"sync"
 	"tradingplatform/dataprovider/data"
 	"tradingplatform/dataprovider/provider"
 	"tradingplatform/dataprovider/provider/alpaca"
+	"tradingplatform/shared/accounts"
 	sharedent "tradingplatform/shared/entities"
 	"tradingplatform/shared/logging"
+	"tradingplatform/shared/metrics"
 	"tradingplatform/shared/requests"
 	"tradingplatform/shared/types"
 
@@ -27,6 +31,58 @@ var alpacaCryptoMapOfLocks map[requests.Account]*sync.RWMutex = make(map[request
 var alpacaStockMapOfLocks map[requests.Account]*sync.RWMutex = make(map[requests.Account]*sync.RWMutex)
 var alpacaNewsMapOfLocks map[requests.Account]*sync.RWMutex = make(map[requests.Account]*sync.RWMutex)
 
+// Context the clients of an account are connected with, cancelled to close them
+var alpacaAccountContexts map[requests.Account]context.Context = make(map[requests.Account]context.Context)
+var alpacaAccountCancels map[requests.Account]context.CancelFunc = make(map[requests.Account]context.CancelFunc)
+var alpacaAccountLock sync.Mutex
+
+// Get the account of a stream request from the registry, any stands for the default account
+func getAccountCredentials(req requests.StreamRequest) (requests.Account, accounts.Credentials, error) {
+	account := req.GetStreamAccount()
+	credentials, ok := accounts.Get(string(account))
+	if !ok {
+		return account, credentials, fmt.Errorf("account %s not registered", account)
+	}
+	return account, credentials, nil
+}
+
+// Get the context the clients of an account are connected with
+func getAccountContext(account requests.Account) context.Context {
+	alpacaAccountLock.Lock()
+	defer alpacaAccountLock.Unlock()
+	ctx, ok := alpacaAccountContexts[account]
+	if !ok {
+		ctx, alpacaAccountCancels[account] = context.WithCancel(context.Background())
+		alpacaAccountContexts[account] = ctx
+	}
+	return ctx
+}
+
+// Close the stock, crypto and news clients of an account, the next stream request of the account
+// connects new clients with the credentials of the registry
+func CloseAccountClients(account requests.Account) {
+	alpacaAccountLock.Lock()
+	if cancel, ok := alpacaAccountCancels[account]; ok {
+		cancel()
+	}
+	delete(alpacaAccountCancels, account)
+	delete(alpacaAccountContexts, account)
+	alpacaAccountLock.Unlock()
+
+	alpacaStocksMapLock.Lock()
+	delete(alpacaStocksClientMap, account)
+	delete(alpacaStockMapOfLocks, account)
+	alpacaStocksMapLock.Unlock()
+	alpacaCryptoMapLock.Lock()
+	delete(alpacaCryptoClientMap, account)
+	delete(alpacaCryptoMapOfLocks, account)
+	alpacaCryptoMapLock.Unlock()
+	alpacaNewsMapLock.Lock()
+	delete(alpacaNewsClientMap, account)
+	delete(alpacaNewsMapOfLocks, account)
+	alpacaNewsMapLock.Unlock()
+}
+
 // handleOnStreamData is a function that handles streaming data for a given entity.
 // It maps the entity with the provided symbol, sets the source and exchange based on the asset class,
 // generates the topic based on the asset class and data type, and returns a generated message.
@@ -45,7 +101,10 @@ func handleOnStreamData[T any,
 	V sharedent.Payloader](entity T,
 	assetClass types.AssetClass,
 	dtype types.DataType,
-	symbol string) (*sharedent.Message, error) {
+	symbol string) (msg *sharedent.Message, err error) {
+	defer func() {
+		metrics.ObserveAlpacaEvent(string(assetClass), string(dtype), err)
+	}()
 
 	newEntity := alpaca.MapEntityWithReturnEntity(entity, symbol)
 	if newEntity == nil {
@@ -111,13 +170,17 @@ func handleOnStreamData[T any,
 
 }
 
-// Provide a response with the active streams
+// Provide a response with the active streams of the account of the request and the state of the
+// connections of its clients, of all accounts for any
 func handleAlpacaStreamGetRequest(req requests.StreamRequest, assetClass types.AssetClass) types.StreamResponse {
-	streams := data.GetDataProviderStreamsAssetClass(assetClass)
+	streams := data.GetDataProviderStreamsSourceAssetClass(types.Alpaca, assetClass)
 	// Create a hash map of the streams
 	dtypes := map[types.DataType]struct{}{}
 	symbols := map[string]struct{}{}
 	for _, stream := range streams {
+		if req.GetAccount() != requests.AnyAccount && stream.Account != req.GetAccount() {
+			continue
+		}
 		dtypes[stream.DataType] = struct{}{}
 		symbols[stream.Symbol] = struct{}{}
 	}
@@ -130,36 +193,83 @@ func handleAlpacaStreamGetRequest(req requests.StreamRequest, assetClass types.A
 		symbolsSlice = append(symbolsSlice, symbol)
 	}
 
-	return provider.NewStreamResponseAssetClass(
+	response := provider.NewStreamResponseSourceAssetClass(
 		types.Success,
 		"Successfully retrieved streams",
 		alpaca.GenerateJSONStreamTopicDict(assetClass, dtypesSlice, symbolsSlice),
-		nil, assetClass)
+		nil, types.Alpaca, assetClass)
+	connectionsJSON, _ := json.Marshal(getConnections(assetClass, req.GetAccount()))
+	response.Connections = string(connectionsJSON)
+	return response
+}
+
+// Provide a response with the active Alpaca streams of the requested asset class
+func HandleAlpacaStreamGetRequest(req requests.StreamRequest) types.StreamResponse {
+	return handleAlpacaStreamGetRequest(req, req.GetAssetClass())
 }
 
-// Handle a stream request for Alpaca and delegate to the appropriate handler based on asset class
-func HandleAlpacaStreamRequest(req requests.StreamRequest) types.StreamResponse {
-	var response types.StreamResponse
-	// Validate symbols
-	for _, symbol := range req.GetSymbol() {
-		if !alpaca.IsSymbolValid(symbol, req.GetAssetClass()) {
-			return provider.NewStreamError(
-				fmt.Errorf("symbol %s not valid for asset class %s", symbol, req.GetAssetClass()),
-			)
+// Handle an Alpaca stream add request, subscribing the client of the asset class of the account of the
+// request, connected if the account has none
+func HandleAlpacaStreamAddRequest(req requests.StreamRequest) types.StreamResponse {
+	switch req.GetAssetClass() {
+	case types.Stock:
+		client, clientLock, err := getStocksClient(req)
+		if err != nil {
+			return provider.NewStreamError(err)
 		}
+		return handleAlpacaStockStreamAddRequest(client, clientLock, req)
+	case types.Crypto:
+		client, clientLock, err := getCryptoClient(req)
+		if err != nil {
+			return provider.NewStreamError(err)
+		}
+		return handleAlpacaCryptoStreamAddRequest(client, clientLock, req)
+	case types.News:
+		client, clientLock, err := getNewsClient(req)
+		if err != nil {
+			return provider.NewStreamError(err)
+		}
+		return handleAlpacaNewsStreamAddRequest(client, clientLock, req)
+	default:
+		return provider.NewStreamError(
+			errors.New("invalid asset class"),
+		)
 	}
+}
 
+// Handle an Alpaca stream remove request, unsubscribing the client of the asset class of the account of
+// the request. Without client nothing is streamed, only the streams of the local database are removed
+func HandleAlpacaStreamRemoveRequest(req requests.StreamRequest) types.StreamResponse {
+	account := req.GetStreamAccount()
 	switch req.GetAssetClass() {
 	case types.Stock:
-		response = handleAlpacaStockStreamRequest(req)
+		if client, clientLock, ok := getConnectedStocksClient(account); ok {
+			return handleAlpacaStockStreamRemoveRequest(client, clientLock, req)
+		}
 	case types.Crypto:
-		response = handleAlpacaCryptoStreamRequest(req)
+		if client, clientLock, ok := getConnectedCryptoClient(account); ok {
+			return handleAlpacaCryptoStreamRemoveRequest(client, clientLock, req)
+		}
 	case types.News:
-		response = handleAlpacaNewsStreamRequest(req)
+		if client, clientLock, ok := getConnectedNewsClient(account); ok {
+			return handleAlpacaNewsStreamRemoveRequest(client, clientLock, req)
+		}
 	default:
-		response = provider.NewStreamError(
+		return provider.NewStreamError(
 			errors.New("invalid asset class"),
 		)
 	}
-	return response
+
+	logging.Log().Info().RawJSON("request", req.JSON()).Msg("removing Alpaca streams without client")
+	for _, dtype := range req.GetDataType() {
+		data.RemoveDataProviderStreamForDType(req, dtype)
+	}
+	return provider.NewStreamResponseSourceAssetClass(
+		types.Success,
+		"Successfully removed streams",
+		alpaca.GenerateJSONStreamTopicDict(req.GetAssetClass(), req.GetDataType(), req.GetSymbol()),
+		nil,
+		types.Alpaca,
+		req.GetAssetClass(),
+	)
 }
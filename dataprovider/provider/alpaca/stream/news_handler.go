@@ -3,13 +3,13 @@ package stream
 import (
 	"context"
 	"fmt"
-	"os"
 	"strings"
 	"sync"
 	"tradingplatform/dataprovider/data"
 	"tradingplatform/dataprovider/provider/alpaca"
 
 	"tradingplatform/dataprovider/provider"
+	"tradingplatform/shared/accounts"
 	"tradingplatform/shared/communication/producer"
 	sharedent "tradingplatform/shared/entities"
 	"tradingplatform/shared/logging"
@@ -19,26 +19,23 @@ import (
 	astream "github.com/alpacahq/alpaca-trade-api-go/v3/marketdata/stream"
 )
 
-// Handle a news stream request for Alpaca
-func handleAlpacaNewsStreamRequest(req requests.StreamRequest) types.StreamResponse {
-	account := req.GetAccount()
-	// Only default account is supported for now
-	// TODO: Allow account separation
-	if account != requests.DefaultAccount && account != requests.AnyAccount {
-		err := fmt.Errorf("account %s not supported", account)
+// Get the news client of the account of a stream request and its lock, a client is connected if the
+// account has none
+func getNewsClient(req requests.StreamRequest) (*astream.NewsClient, *sync.RWMutex, error) {
+	account, credentials, err := getAccountCredentials(req)
+	if err != nil {
 		logging.Log().Error().
 			Err(err).
+			Str("account", string(account)).
 			RawJSON("request", req.JSON()).
 			Msg("handling Alpaca news stream request")
-		return provider.NewStreamError(
-			err,
-		)
+		return nil, nil, err
 	}
 
 	// Get the client for the account and client lock
 	alpacaNewsMapLock.RLock()
-	clientLock, okLock := alpacaNewsMapOfLocks[requests.DefaultAccount]
-	client, ok := alpacaNewsClientMap[requests.DefaultAccount]
+	clientLock, okLock := alpacaNewsMapOfLocks[account]
+	client, ok := alpacaNewsClientMap[account]
 	alpacaNewsMapLock.RUnlock()
 
 	if !okLock {
@@ -47,7 +44,7 @@ func handleAlpacaNewsStreamRequest(req requests.StreamRequest) types.StreamRespo
 			Msg("creating new Alpaca news client lock")
 		clientLock = &sync.RWMutex{}
 		alpacaNewsMapLock.Lock()
-		alpacaNewsMapOfLocks[requests.DefaultAccount] = clientLock
+		alpacaNewsMapOfLocks[account] = clientLock
 		alpacaNewsMapLock.Unlock()
 	}
 
@@ -55,39 +52,69 @@ func handleAlpacaNewsStreamRequest(req requests.StreamRequest) types.StreamRespo
 		logging.Log().Debug().
 			RawJSON("request", req.JSON()).
 			Msg("creating new Alpaca news client")
-		client = astream.NewNewsClient(
-			astream.WithCredentials(os.Getenv("ALPACA_KEY"), os.Getenv("ALPACA_SECRET")),
-		)
-		err := client.Connect(context.TODO())
+		ctx := getAccountContext(account)
+		client, err = newNewsClient(ctx, account, credentials)
 		if err != nil {
 			logging.Log().Error().
 				Err(err).
 				RawJSON("request", req.JSON()).
 				Msg("connecting to Alpaca news stream")
-			return provider.NewStreamError(err)
+			return nil, nil, err
 		}
 		alpacaNewsMapLock.Lock()
-		alpacaNewsClientMap[requests.DefaultAccount] = client
+		alpacaNewsClientMap[account] = client
 		alpacaNewsMapLock.Unlock()
+		go supervise(ctx, account, types.News, client.Terminated(), reconnectNews(account))
 	}
+	return client, clientLock, nil
+}
 
-	// Handle request operation
-	switch req.GetOperation() {
-	case types.StreamAddOp:
-		return handleAlpacaNewsStreamAddRequest(client, clientLock, req)
-	case types.StreamGetOp:
-		return handleAlpacaStreamGetRequest(req, types.News)
-	case types.StreamRemoveOp:
-		return handleAlpacaNewsStreamRemoveRequest(client, clientLock, req)
-	default:
-		err := fmt.Errorf("operation %s not supported", req.GetOperation())
-		logging.Log().Error().
-			Err(err).
-			RawJSON("request", req.JSON()).
-			Msg("handling Alpaca news stream request")
-		return provider.NewStreamError(
-			err,
-		)
+// Get the connected news client of an account and its lock
+func getConnectedNewsClient(account requests.Account) (*astream.NewsClient, *sync.RWMutex, bool) {
+	alpacaNewsMapLock.RLock()
+	defer alpacaNewsMapLock.RUnlock()
+	client, ok := alpacaNewsClientMap[account]
+	clientLock, okLock := alpacaNewsMapOfLocks[account]
+	return client, clientLock, ok && okLock
+}
+
+// Create a news client of an account and connect it, the transitions of its connection are published
+func newNewsClient(ctx context.Context, account requests.Account,
+	credentials accounts.Credentials) (*astream.NewsClient, error) {
+
+	client := astream.NewNewsClient(
+		astream.WithCredentials(credentials.Key, credentials.Secret),
+		astream.WithConnectCallback(func() {
+			setConnectionState(account, types.News, types.Connected, nil)
+		}),
+		astream.WithDisconnectCallback(func() {
+			setConnectionState(account, types.News, types.Disconnected, nil)
+		}),
+	)
+	return client, client.Connect(ctx)
+}
+
+// Replace the news client of an account by a new client, subscribed to the news streams of the account
+// by the returned function
+func reconnectNews(account requests.Account) reconnectFunc {
+	return func(ctx context.Context) (<-chan error, func() error, error) {
+		credentials, ok := accounts.Get(string(account))
+		if !ok {
+			return nil, nil, fmt.Errorf("account %s not registered", account)
+		}
+		client, err := newNewsClient(ctx, account, credentials)
+		if err != nil {
+			return nil, nil, err
+		}
+		clientLock := getClientLock(&alpacaNewsMapLock, alpacaNewsMapOfLocks, account)
+		alpacaNewsMapLock.Lock()
+		alpacaNewsClientMap[account] = client
+		alpacaNewsMapLock.Unlock()
+		return client.Terminated(), func() error {
+			return resubscribe(account, types.News, func(req requests.StreamRequest) types.StreamResponse {
+				return handleAlpacaNewsStreamAddRequest(client, clientLock, req)
+			})
+		}, nil
 	}
 }
 
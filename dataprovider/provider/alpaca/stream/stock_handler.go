@@ -3,7 +3,6 @@ package stream
 import (
 	"context"
 	"fmt"
-	"os"
 	"sync"
 	"tradingplatform/shared/communication/producer"
 	"tradingplatform/shared/logging"
@@ -11,90 +10,137 @@ import (
 	"tradingplatform/dataprovider/data"
 	"tradingplatform/dataprovider/provider"
 	"tradingplatform/dataprovider/provider/alpaca"
+	"tradingplatform/shared/accounts"
 	sharedent "tradingplatform/shared/entities"
 	"tradingplatform/shared/requests"
 	"tradingplatform/shared/types"
 
-	"github.com/alpacahq/alpaca-trade-api-go/v3/marketdata"
 	astream "github.com/alpacahq/alpaca-trade-api-go/v3/marketdata/stream"
 )
 
-// Handle a stock stream request for Alpaca and delegate based on operation
-func handleAlpacaStockStreamRequest(req requests.StreamRequest) types.StreamResponse {
-	account := req.GetAccount()
-
-	// Only default account is supported for now
-	// TODO: Allow account separation
-	if account != requests.DefaultAccount && account != requests.AnyAccount {
-		err := fmt.Errorf("account %s not supported", account)
+// Get the stocks client of the account of a stream request and its lock, a client is connected if the
+// account has none
+func getStocksClient(req requests.StreamRequest) (*astream.StocksClient, *sync.RWMutex, error) {
+	account, credentials, err := getAccountCredentials(req)
+	if err != nil {
 		logging.Log().Error().
 			Err(err).
 			Str("account", string(account)).
 			RawJSON("request", req.JSON()).
 			Msg("handling Alpaca stock stream request")
 
-		return provider.NewStreamError(
-			err,
-		)
+		return nil, nil, err
 	}
 
 	// Get the client for the account and client lock
 	alpacaStocksMapLock.RLock()
-	clientLock, okLock := alpacaStockMapOfLocks[requests.DefaultAccount]
-	client, ok := alpacaStocksClientMap[requests.DefaultAccount]
+	clientLock, okLock := alpacaStockMapOfLocks[account]
+	client, ok := alpacaStocksClientMap[account]
 	alpacaStocksMapLock.RUnlock()
 
 	if !okLock {
 		logging.Log().Debug().
-			Str("account", string(requests.DefaultAccount)).
+			Str("account", string(account)).
 			RawJSON("request", req.JSON()).
 			Msg("creating new Alpaca stocks client lock")
 		clientLock = &sync.RWMutex{}
 		alpacaStocksMapLock.Lock()
-		alpacaStockMapOfLocks[requests.DefaultAccount] = clientLock
+		alpacaStockMapOfLocks[account] = clientLock
 		alpacaStocksMapLock.Unlock()
 	}
 
 	if !ok {
 		logging.Log().Debug().
-			Str("account", string(requests.DefaultAccount)).
+			Str("account", string(account)).
 			RawJSON("request", req.JSON()).
 			Msg("creating new Alpaca stocks client")
-		client = astream.NewStocksClient(
-			marketdata.IEX,
-			astream.WithCredentials(
-				os.Getenv("ALPACA_KEY"),
-				os.Getenv("ALPACA_SECRET"),
-			),
-		)
-		err := client.Connect(context.TODO())
+		ctx := getAccountContext(account)
+		client, err = newStocksClient(ctx, account, credentials)
 		if err != nil {
 			logging.Log().Error().
 				Err(err).
 				RawJSON("request", req.JSON()).
 				Msg("connecting to Alpaca stocks stream")
-			return provider.NewStreamError(err)
+			return nil, nil, err
 		}
 		alpacaStocksMapLock.Lock()
-		alpacaStocksClientMap[requests.DefaultAccount] = client
+		alpacaStocksClientMap[account] = client
 		alpacaStocksMapLock.Unlock()
+		go supervise(ctx, account, types.Stock, client.Terminated(), reconnectStocks(account))
 	}
+	return client, clientLock, nil
+}
 
-	// Handle the request
-	switch req.GetOperation() {
-	case types.StreamAddOp:
-		return handleAlpacaStockStreamAddRequest(client, clientLock, req)
-	case types.StreamGetOp:
-		return handleAlpacaStreamGetRequest(req, types.Stock)
-	case types.StreamRemoveOp:
-		return handleAlpacaStockStreamRemoveRequest(client, clientLock, req)
-	default:
-		err := fmt.Errorf("request type %s not supported", req.GetOperation())
-		logging.Log().Error().
-			Err(err).
-			RawJSON("request", req.JSON()).
-			Msg("handling Alpaca stocks stream request")
-		return provider.NewStreamError(err)
+// Get the connected stocks client of an account and its lock
+func getConnectedStocksClient(account requests.Account) (*astream.StocksClient, *sync.RWMutex, bool) {
+	alpacaStocksMapLock.RLock()
+	defer alpacaStocksMapLock.RUnlock()
+	client, ok := alpacaStocksClientMap[account]
+	clientLock, okLock := alpacaStockMapOfLocks[account]
+	return client, clientLock, ok && okLock
+}
+
+// Create a stocks client of an account and connect it, the transitions of its connection are published
+func newStocksClient(ctx context.Context, account requests.Account,
+	credentials accounts.Credentials) (*astream.StocksClient, error) {
+
+	client := astream.NewStocksClient(
+		alpaca.GetStreamFeed(credentials.Feed),
+		astream.WithCredentials(
+			credentials.Key,
+			credentials.Secret,
+		),
+		// Corrections and cancels are sent for the symbols whose trades are subscribed
+		astream.WithCorrections(func(c astream.TradeCorrection) {
+			publishStockTradeUpdates(alpaca.MapStockTradeCorrection(c)...)
+		}),
+		astream.WithCancelErrors(func(e astream.TradeCancelError) {
+			publishStockTradeUpdates(alpaca.MapStockTradeCancelError(e))
+		}),
+		astream.WithConnectCallback(func() {
+			setConnectionState(account, types.Stock, types.Connected, nil)
+		}),
+		astream.WithDisconnectCallback(func() {
+			setConnectionState(account, types.Stock, types.Disconnected, nil)
+		}),
+	)
+	return client, client.Connect(ctx)
+}
+
+// Replace the stocks client of an account by a new client, subscribed to the stock streams of the account
+// by the returned function
+func reconnectStocks(account requests.Account) reconnectFunc {
+	return func(ctx context.Context) (<-chan error, func() error, error) {
+		credentials, ok := accounts.Get(string(account))
+		if !ok {
+			return nil, nil, fmt.Errorf("account %s not registered", account)
+		}
+		client, err := newStocksClient(ctx, account, credentials)
+		if err != nil {
+			return nil, nil, err
+		}
+		clientLock := getClientLock(&alpacaStocksMapLock, alpacaStockMapOfLocks, account)
+		alpacaStocksMapLock.Lock()
+		alpacaStocksClientMap[account] = client
+		alpacaStocksMapLock.Unlock()
+		return client.Terminated(), func() error {
+			return resubscribe(account, types.Stock, func(req requests.StreamRequest) types.StreamResponse {
+				return handleAlpacaStockStreamAddRequest(client, clientLock, req)
+			})
+		}, nil
+	}
+}
+
+// Publish trade updates on the trade updates stream of their symbol, apart from the trades so that
+// consumers of the trades never take them for trades. The Update field of the trades tells which
+// trades sent before are corrected or cancelled
+func publishStockTradeUpdates(trades ...*sharedent.Trade) {
+	for _, trade := range trades {
+		trade.SetSource(string(types.Alpaca))
+		trade.SetExchange(alpaca.DEFAULT_EXCHANGE_STOCK)
+		topic := alpaca.NewStockStreamTopic(types.TradeUpdates, trade.Symbol).Generate()
+		msg := sharedent.GenerateMessage(trade, types.TradeUpdates, topic)
+		producer.GetStreamHandler(msg.Topic).Ch <- msg
 	}
 }
 
@@ -0,0 +1,195 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+	"tradingplatform/dataprovider/data"
+	"tradingplatform/dataprovider/provider/alpaca"
+	"tradingplatform/shared/communication/producer"
+	sharedent "tradingplatform/shared/entities"
+	"tradingplatform/shared/logging"
+	"tradingplatform/shared/requests"
+	"tradingplatform/shared/types"
+)
+
+// State of the connections of the clients by account and asset class
+var connections map[string]*sharedent.ConnectionStatus = make(map[string]*sharedent.ConnectionStatus)
+var connectionsLock sync.RWMutex
+
+var maxReconnectBackoff = time.Minute
+
+func connectionKey(account requests.Account, assetClass types.AssetClass) string {
+	return string(account) + "." + string(assetClass)
+}
+
+// Record a transition of the connection of the client of an account and publish it on the connection
+// topic of the account
+func setConnectionState(account requests.Account, assetClass types.AssetClass, state types.ConnectionState, err error) {
+	status := &sharedent.ConnectionStatus{
+		Source:     string(types.Alpaca),
+		Account:    string(account),
+		AssetClass: string(assetClass),
+		State:      string(state),
+		Timestamp:  time.Now().UnixNano(),
+	}
+	if err != nil {
+		status.Error = err.Error()
+	}
+	connectionsLock.Lock()
+	if state == types.Closed {
+		delete(connections, connectionKey(account, assetClass))
+	} else {
+		connections[connectionKey(account, assetClass)] = status
+	}
+	connectionsLock.Unlock()
+
+	logging.Log().Info().
+		Err(err).
+		Str("account", string(account)).
+		Str("assetClass", string(assetClass)).
+		Str("state", string(state)).
+		Msg("Alpaca stream connection")
+	status.SetFingerprint()
+	topic := alpaca.NewConnectionTopic(assetClass, string(account)).Generate()
+	producer.GetStreamHandler(topic).Ch <- sharedent.GenerateMessage(status, types.Connection, topic)
+}
+
+// Get the state of the connections of the clients of an asset class, of all accounts for any
+func getConnections(assetClass types.AssetClass, account requests.Account) []*sharedent.ConnectionStatus {
+	connectionsLock.RLock()
+	defer connectionsLock.RUnlock()
+	var result []*sharedent.ConnectionStatus
+	for _, status := range connections {
+		if status.AssetClass != string(assetClass) {
+			continue
+		}
+		if account != requests.AnyAccount && status.Account != string(account) {
+			continue
+		}
+		result = append(result, status)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Account < result[j].Account
+	})
+	return result
+}
+
+// Connect a new client of an account, returns the channel the termination of the client is sent on
+// and a function subscribing the client to the streams of the account
+type reconnectFunc func(context.Context) (terminated <-chan error, resubscribe func() error, err error)
+
+// Watch the client of an account streaming an asset class until the clients of the account are closed.
+// The client reconnects by itself when its connection drops, once it gives up a new client is connected
+// with backoff by reconnect and takes over the streams of the client. The streams that cannot be
+// subscribed again are kept and subscribed again on the next cycle of the backoff
+func supervise(ctx context.Context, account requests.Account, assetClass types.AssetClass,
+	terminated <-chan error, reconnect reconnectFunc) {
+
+	for {
+		err := <-terminated
+		if ctx.Err() != nil {
+			setConnectionState(account, assetClass, types.Closed, nil)
+			return
+		}
+		if err == nil {
+			err = errors.New("connection terminated")
+		}
+		setConnectionState(account, assetClass, types.Reconnecting, err)
+
+		backoff := time.Second
+		var resubscribe func() error
+		for {
+			select {
+			case <-ctx.Done():
+				setConnectionState(account, assetClass, types.Closed, nil)
+				return
+			case <-time.After(backoff):
+			}
+			// The new client may have given up while waiting, a new one is connected
+			if resubscribe != nil {
+				select {
+				case <-terminated:
+					resubscribe = nil
+				default:
+				}
+			}
+			if resubscribe == nil {
+				terminated, resubscribe, err = reconnect(ctx)
+				if err != nil {
+					resubscribe = nil
+					logging.Log().Warn().
+						Err(err).
+						Str("account", string(account)).
+						Str("assetClass", string(assetClass)).
+						Dur("backoff", backoff).
+						Msg("reconnecting Alpaca stream client")
+					backoff = min(2*backoff, maxReconnectBackoff)
+					continue
+				}
+			}
+			err = resubscribe()
+			if err == nil {
+				break
+			}
+			logging.Log().Warn().
+				Err(err).
+				Str("account", string(account)).
+				Str("assetClass", string(assetClass)).
+				Dur("backoff", backoff).
+				Msg("resubscribing Alpaca stream client, retrying")
+			backoff = min(2*backoff, maxReconnectBackoff)
+		}
+	}
+}
+
+// Subscribe a new client of an account to the streams of the asset class recorded in the local
+// database. The streams that cannot be subscribed are reported and kept in the local database, the
+// error of the last of them is returned
+func resubscribe(account requests.Account, assetClass types.AssetClass,
+	add func(requests.StreamRequest) types.StreamResponse) error {
+
+	symbols := make(map[types.DataType][]string)
+	for _, stream := range data.GetDataProviderStreamsSourceAssetClass(types.Alpaca, assetClass) {
+		if stream.Account == account {
+			symbols[stream.DataType] = append(symbols[stream.DataType], stream.Symbol)
+		}
+	}
+	dtypes := make([]types.DataType, 0, len(symbols))
+	for dtype := range symbols {
+		dtypes = append(dtypes, dtype)
+	}
+	sort.Slice(dtypes, func(i, j int) bool {
+		return dtypes[i] < dtypes[j]
+	})
+
+	var err error
+	for _, dtype := range dtypes {
+		req := requests.NewStreamRequest(types.Alpaca, assetClass, symbols[dtype],
+			types.StreamAddOp, []types.DataType{dtype}, account)
+		if response := add(req); response.Err != "" {
+			err = errors.New(response.Err)
+			logging.Log().Error().
+				Err(err).
+				RawJSON("request", req.JSON()).
+				Msg("resubscribing Alpaca stream after reconnecting")
+		}
+	}
+	return err
+}
+
+// Get the lock of the client of an account in a map of locks, creating it if needed
+func getClientLock(mapLock *sync.RWMutex, locks map[requests.Account]*sync.RWMutex,
+	account requests.Account) *sync.RWMutex {
+
+	mapLock.Lock()
+	defer mapLock.Unlock()
+	clientLock, ok := locks[account]
+	if !ok {
+		clientLock = &sync.RWMutex{}
+		locks[account] = clientLock
+	}
+	return clientLock
+}
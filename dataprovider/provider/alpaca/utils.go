@@ -63,3 +63,8 @@ func GenerateJSONStreamTopicDict(assetClass types.AssetClass, dataTypes []types.
 	}
 	return string(json)
 }
+
+// Topic of the transitions of the connection of the client of an account streaming an asset class
+func NewConnectionTopic(assetClass types.AssetClass, account string) utils.Topic {
+	return NewStreamTopic(assetClass, types.Connection, account)
+}
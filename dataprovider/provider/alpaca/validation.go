@@ -1,8 +1,7 @@
 package alpaca
 
 import (
-	"os"
-
+	"tradingplatform/shared/accounts"
 	"tradingplatform/shared/logging"
 	"tradingplatform/shared/types"
 
@@ -10,10 +9,12 @@ import (
 )
 
 // Utility to verify whether a symbol is valid for a given asset class
+// with the credentials of the default account
 func IsSymbolValid(symbol string, assetClass types.AssetClass) bool {
+	credentials, _ := accounts.Get(accounts.Default)
 	client := astream.NewClient(astream.ClientOpts{
-		APIKey:    os.Getenv("ALPACA_KEY"),
-		APISecret: os.Getenv("ALPACA_SECRET"),
+		APIKey:    credentials.Key,
+		APISecret: credentials.Secret,
 		BaseURL:   "https://paper-api.alpaca.markets",
 	})
 
@@ -35,3 +36,34 @@ func IsSymbolValid(symbol string, assetClass types.AssetClass) bool {
 
 	return false
 }
+
+// Data types streamed and fetched from Alpaca for each asset class
+func GetDataTypes(assetClass types.AssetClass) map[types.DataType]types.DataType {
+	switch assetClass {
+	case types.Stock:
+		return map[types.DataType]types.DataType{
+			"bar":          types.Bar,
+			"daily-bars":   types.DailyBars,
+			"quotes":       types.Quotes,
+			"trades":       types.Trades,
+			"updated-bars": types.UpdatedBars,
+			"luld":         types.LULD,
+			"status":       types.Status,
+		}
+	case types.Crypto:
+		return map[types.DataType]types.DataType{
+			"bar":          types.Bar,
+			"orderbook":    types.Orderbook,
+			"daily-bars":   types.DailyBars,
+			"quotes":       types.Quotes,
+			"trades":       types.Trades,
+			"updated-bars": types.UpdatedBars,
+		}
+	case types.News:
+		return map[types.DataType]types.DataType{
+			"raw-text": types.RawText,
+		}
+	default:
+		return nil
+	}
+}
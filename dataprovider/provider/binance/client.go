@@ -0,0 +1,154 @@
+package binance
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Maximum number of elements returned by a single REST call
+var PAGE_LIMIT = 1000
+
+// The aggTrades endpoint only accepts windows shorter than one hour
+var AGG_TRADES_WINDOW = time.Hour
+
+// Depth of the orderbook snapshots
+var DEPTH_LIMIT = 100
+
+// Client of the Binance public market data REST API
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// Create a new client using the configured REST URL
+func NewClient() *Client {
+	return &Client{
+		baseURL:    GetRESTURL(),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Perform a GET request and decode the JSON response in out
+func (c *Client) get(path string, params url.Values, out any) error {
+	resp, err := c.httpClient.Get(c.baseURL + path + "?" + params.Encode())
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("binance %s returned status %d: %s", path, resp.StatusCode, string(body))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// Get the klines of a symbol in [start, end)
+func (c *Client) GetKlines(symbol string, interval string, start time.Time, end time.Time) ([]Kline, error) {
+	var klines []Kline
+	startMs := start.UnixMilli()
+	endMs := end.UnixMilli() - 1
+	for startMs <= endMs {
+		var page []Kline
+		err := c.get("/api/v3/klines", url.Values{
+			"symbol":    {symbol},
+			"interval":  {interval},
+			"startTime": {strconv.FormatInt(startMs, 10)},
+			"endTime":   {strconv.FormatInt(endMs, 10)},
+			"limit":     {strconv.Itoa(PAGE_LIMIT)},
+		}, &page)
+		if err != nil {
+			return nil, err
+		}
+		klines = append(klines, page...)
+		if len(page) < PAGE_LIMIT {
+			break
+		}
+		startMs = page[len(page)-1].OpenTime + 1
+	}
+	return klines, nil
+}
+
+// Get the aggregated trades of a symbol in [start, end)
+func (c *Client) GetAggTrades(symbol string, start time.Time, end time.Time) ([]AggTrade, error) {
+	var trades []AggTrade
+	lastID := int64(-1)
+	for windowStart := start; windowStart.Before(end); windowStart = windowStart.Add(AGG_TRADES_WINDOW) {
+		windowEnd := windowStart.Add(AGG_TRADES_WINDOW)
+		if windowEnd.After(end) {
+			windowEnd = end
+		}
+		startMs := windowStart.UnixMilli()
+		endMs := windowEnd.UnixMilli() - 1
+		for startMs <= endMs {
+			var page []AggTrade
+			err := c.get("/api/v3/aggTrades", url.Values{
+				"symbol":    {symbol},
+				"startTime": {strconv.FormatInt(startMs, 10)},
+				"endTime":   {strconv.FormatInt(endMs, 10)},
+				"limit":     {strconv.Itoa(PAGE_LIMIT)},
+			}, &page)
+			if err != nil {
+				return nil, err
+			}
+			for _, trade := range page {
+				// Pages overlap on the millisecond they are split on
+				if trade.ID > lastID {
+					trades = append(trades, trade)
+					lastID = trade.ID
+				}
+			}
+			if len(page) < PAGE_LIMIT {
+				break
+			}
+			next := page[len(page)-1].Time
+			if next <= startMs {
+				// More than a page of trades on the same millisecond, move past it
+				next = startMs + 1
+			}
+			startMs = next
+		}
+	}
+	return trades, nil
+}
+
+// Get a snapshot of the orderbook of a symbol
+func (c *Client) GetDepth(symbol string) (Depth, error) {
+	var depth Depth
+	err := c.get("/api/v3/depth", url.Values{
+		"symbol": {symbol},
+		"limit":  {strconv.Itoa(DEPTH_LIMIT)},
+	}, &depth)
+	return depth, err
+}
+
+// Get the current best bid/ask of a symbol
+func (c *Client) GetBookTicker(symbol string) (RESTBookTicker, error) {
+	var ticker RESTBookTicker
+	err := c.get("/api/v3/ticker/bookTicker", url.Values{
+		"symbol": {symbol},
+	}, &ticker)
+	return ticker, err
+}
+
+// Exchange information about the symbols traded on Binance
+type ExchangeInfo struct {
+	Symbols []struct {
+		Symbol string `json:"symbol"`
+		Status string `json:"status"`
+	} `json:"symbols"`
+}
+
+// Get the exchange information of a symbol
+func (c *Client) GetExchangeInfo(symbol string) (ExchangeInfo, error) {
+	var info ExchangeInfo
+	err := c.get("/api/v3/exchangeInfo", url.Values{
+		"symbol": {symbol},
+	}, &info)
+	return info, err
+}
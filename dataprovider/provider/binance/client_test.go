@@ -0,0 +1,210 @@
+package binance
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+	"tradingplatform/shared/logging"
+	"tradingplatform/shared/types"
+
+	"github.com/rs/zerolog"
+)
+
+func TestMain(m *testing.M) {
+	logger := zerolog.Nop()
+	logging.SetLogger(&logger)
+	os.Exit(m.Run())
+}
+
+// Stand-in of the REST API serving the fixtures and recording the query of each path
+func newStandIn(t *testing.T) map[string][]string {
+	var lock sync.Mutex
+	queries := map[string][]string{}
+	fixtures := map[string]string{
+		"/api/v3/klines":            "klines.json",
+		"/api/v3/aggTrades":         "aggTrades.json",
+		"/api/v3/ticker/bookTicker": "bookTicker.json",
+		"/api/v3/depth":             "depth.json",
+		"/api/v3/exchangeInfo":      "exchangeInfo.json",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, ok := fixtures[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		lock.Lock()
+		queries[r.URL.Path] = append(queries[r.URL.Path], r.URL.RawQuery)
+		lock.Unlock()
+		if r.URL.Path == "/api/v3/klines" {
+			serveKlines(w, r)
+			return
+		}
+		http.ServeFile(w, r, filepath.Join("testdata", name))
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("BINANCE_REST_URL", server.URL)
+	return queries
+}
+
+// Serve the klines of the fixture opening in the requested window, up to the limit
+func serveKlines(w http.ResponseWriter, r *http.Request) {
+	b, err := os.ReadFile(filepath.Join("testdata", "klines.json"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	var klines [][]any
+	if err := json.Unmarshal(b, &klines); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	query := r.URL.Query()
+	start, _ := strconv.ParseInt(query.Get("startTime"), 10, 64)
+	end, _ := strconv.ParseInt(query.Get("endTime"), 10, 64)
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	page := [][]any{}
+	for _, kline := range klines {
+		openTime := int64(kline[0].(float64))
+		if openTime >= start && openTime <= end && len(page) < limit {
+			page = append(page, kline)
+		}
+	}
+	json.NewEncoder(w).Encode(page)
+}
+
+func TestGetKlines(t *testing.T) {
+	newStandIn(t)
+	start := time.UnixMilli(1704067200000)
+	klines, err := NewClient().GetKlines("BTCUSDT", "1m", start, start.Add(3*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(klines) != 3 {
+		t.Fatalf("expected 3 klines, got %d", len(klines))
+	}
+
+	bar := MapKline(klines[0], "BTC/USDT", types.OneMin)
+	if bar.Open != 42283.58 || bar.High != 42298.62 || bar.Low != 42261.02 || bar.Close != 42298.61 ||
+		bar.Volume != 35.92724 || bar.TradeCount != 1327 {
+		t.Fatalf("unexpected bar %+v", bar)
+	}
+	if bar.TimestampNs != start.UnixNano() || bar.Timeframe != string(types.OneMin) {
+		t.Fatalf("unexpected timestamp %d or timeframe %s", bar.TimestampNs, bar.Timeframe)
+	}
+	if quoteVolume, volume := 1519339.6955474, 35.92724; bar.VWAP != quoteVolume/volume {
+		t.Fatalf("unexpected VWAP %f", bar.VWAP)
+	}
+}
+
+func TestGetKlinesPages(t *testing.T) {
+	queries := newStandIn(t)
+	limit := PAGE_LIMIT
+	PAGE_LIMIT = 2
+	t.Cleanup(func() { PAGE_LIMIT = limit })
+
+	// The next page starts after the last kline of a full page
+	start := time.UnixMilli(1704067200000)
+	klines, err := NewClient().GetKlines("BTCUSDT", "1m", start, start.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(klines) != 3 || klines[2].OpenTime != 1704067320000 || len(queries["/api/v3/klines"]) != 2 {
+		t.Fatalf("expected 2 pages, got %d klines in %d requests", len(klines), len(queries["/api/v3/klines"]))
+	}
+}
+
+func TestGetAggTrades(t *testing.T) {
+	newStandIn(t)
+	start := time.UnixMilli(1704067200000)
+	trades, err := NewClient().GetAggTrades("BTCUSDT", start, start.Add(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trades) != 3 {
+		t.Fatalf("expected 3 trades, got %d", len(trades))
+	}
+
+	trade := MapAggTrade(trades[1], "BTC/USDT")
+	if trade.ID != 2808412386 || trade.Price != 42283.57 || trade.Size != 0.02516 || trade.TakerSide != "S" {
+		t.Fatalf("unexpected trade %+v", trade)
+	}
+	if trade.TimestampNs != time.UnixMilli(1704067200035).UnixNano() || trade.Timestamp != 1704067200 {
+		t.Fatalf("unexpected timestamp %d", trade.TimestampNs)
+	}
+	if taker := MapAggTrade(trades[0], "BTC/USDT").TakerSide; taker != "B" {
+		t.Fatalf("expected the buyer to be the taker, got %s", taker)
+	}
+}
+
+func TestGetAggTradesSkipsOverlappingTrades(t *testing.T) {
+	newStandIn(t)
+	window := AGG_TRADES_WINDOW
+	AGG_TRADES_WINDOW = time.Second
+	t.Cleanup(func() { AGG_TRADES_WINDOW = window })
+
+	// Both windows are answered with the same trades, they are only kept once
+	start := time.UnixMilli(1704067200000)
+	trades, err := NewClient().GetAggTrades("BTCUSDT", start, start.Add(2*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trades) != 3 {
+		t.Fatalf("expected 3 trades, got %d", len(trades))
+	}
+}
+
+func TestGetBookTickerAndDepth(t *testing.T) {
+	newStandIn(t)
+	now := time.Now()
+
+	ticker, err := NewClient().GetBookTicker("BTCUSDT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	quote := MapRESTBookTicker(ticker, "BTC/USDT", now)
+	if quote.BidPrice != 42283.57 || quote.BidSize != 3.71622 || quote.AskPrice != 42283.58 ||
+		quote.AskSize != 6.62213 || quote.TimestampNs != now.UnixNano() || quote.Timestamp != now.Unix() {
+		t.Fatalf("unexpected quote %+v", quote)
+	}
+
+	depth, err := NewClient().GetDepth("BTCUSDT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	orderbook := MapDepth(depth, "BTC/USDT", now)
+	if len(orderbook.Bids) != 3 || len(orderbook.Asks) != 3 || !orderbook.Reset_ {
+		t.Fatalf("unexpected orderbook %+v", orderbook)
+	}
+	if orderbook.Asks[0].Price != 42283.58 || orderbook.Asks[0].Size != 6.62213 {
+		t.Fatalf("unexpected best ask %+v", orderbook.Asks[0])
+	}
+}
+
+func TestIsSymbolValid(t *testing.T) {
+	newStandIn(t)
+	if !IsSymbolValid("BTC/USDT", types.Crypto) {
+		t.Fatal("expected BTC/USDT to be valid")
+	}
+	if IsSymbolValid("DOGE/USDT", types.Crypto) {
+		t.Fatal("expected DOGE/USDT to be invalid")
+	}
+	if IsSymbolValid("AAPL", types.Stock) {
+		t.Fatal("expected stocks to be invalid")
+	}
+}
+
+func TestGetErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(server.Close)
+	t.Setenv("BINANCE_REST_URL", server.URL)
+	if _, err := NewClient().GetDepth("BTCUSDT"); err == nil {
+		t.Fatal("expected an error for a failed request")
+	}
+}
@@ -0,0 +1,46 @@
+package binance
+
+import (
+	"os"
+	"tradingplatform/shared/types"
+)
+
+var DEFAULT_EXCHANGE = "BINANCE"
+
+var DEFAULT_REST_URL = "https://api.binance.com"
+var DEFAULT_WS_URL = "wss://stream.binance.com:9443"
+
+// Base URL of the Binance REST API, can be overridden (e.g. to point to a local stand-in)
+// with the BINANCE_REST_URL environment variable
+func GetRESTURL() string {
+	if url := os.Getenv("BINANCE_REST_URL"); url != "" {
+		return url
+	}
+	return DEFAULT_REST_URL
+}
+
+// Base URL of the Binance websocket streams, can be overridden (e.g. to point to a local
+// stand-in) with the BINANCE_WS_URL environment variable
+func GetWSURL() string {
+	if url := os.Getenv("BINANCE_WS_URL"); url != "" {
+		return url
+	}
+	return DEFAULT_WS_URL
+}
+
+// Convert a timeframe to a Binance kline interval
+func GetBinanceInterval(timeFrame types.TimeFrame) (string, bool) {
+	m := map[types.TimeFrame]string{
+		types.OneMin:     "1m",
+		types.FiveMin:    "5m",
+		types.FifteenMin: "15m",
+		types.ThirtyMin:  "30m",
+		types.OneHour:    "1h",
+		types.FourHour:   "4h",
+		types.OneDay:     "1d",
+		types.OneWeek:    "1w",
+		types.OneMonth:   "1M",
+	}
+	interval, ok := m[timeFrame]
+	return interval, ok
+}
@@ -0,0 +1,106 @@
+package data
+
+import (
+	"fmt"
+	"time"
+	"tradingplatform/dataprovider/provider/binance"
+	"tradingplatform/shared/communication/producer"
+	sharedent "tradingplatform/shared/entities"
+	"tradingplatform/shared/logging"
+	"tradingplatform/shared/requests"
+	"tradingplatform/shared/types"
+)
+
+// Handle a data request for Binance. Bars and trades are fetched for the requested window,
+// orderbooks and quotes are only available as a snapshot of the current state
+func HandleBinanceDataRequest(req requests.DataRequest) types.DataResponse {
+	logging.Log().Debug().RawJSON("request", req.JSON()).Msg("handling binance data request")
+
+	if req.GetAssetClass() != types.Crypto {
+		return types.NewDataError(
+			fmt.Errorf("invalid asset type %s", req.GetAssetClass()),
+		)
+	}
+
+	client := binance.NewClient()
+	symbol := req.GetSymbol()
+	binanceSymbol := binance.GetBinanceSymbol(symbol)
+	start := time.Unix(0, req.GetStartTime())
+	end := time.Unix(0, req.GetEndTime())
+	dtype := req.GetDataType()
+
+	var payloads []sharedent.Payloader
+	var err error
+	switch dtype {
+	case types.Bar:
+		interval, ok := binance.GetBinanceInterval(req.GetTimeFrame())
+		if !ok {
+			err = fmt.Errorf("invalid timeframe %s", req.GetTimeFrame())
+			break
+		}
+		var klines []binance.Kline
+		klines, err = client.GetKlines(binanceSymbol, interval, start, end)
+		for _, kline := range klines {
+			payloads = append(payloads, binance.MapKline(kline, symbol, req.GetTimeFrame()))
+		}
+	case types.Trades:
+		var trades []binance.AggTrade
+		trades, err = client.GetAggTrades(binanceSymbol, start, end)
+		for _, trade := range trades {
+			payloads = append(payloads, binance.MapAggTrade(trade, symbol))
+		}
+	case types.Quotes:
+		var ticker binance.RESTBookTicker
+		ticker, err = client.GetBookTicker(binanceSymbol)
+		if err == nil {
+			payloads = append(payloads, binance.MapRESTBookTicker(ticker, symbol, time.Now()))
+		}
+	case types.Orderbook:
+		var depth binance.Depth
+		depth, err = client.GetDepth(binanceSymbol)
+		if err == nil {
+			payloads = append(payloads, binance.MapDepth(depth, symbol, time.Now()))
+		}
+	default:
+		return types.NewDataError(
+			fmt.Errorf("invalid data type %s", dtype),
+		)
+	}
+	if err != nil {
+		logging.Log().Error().
+			Err(err).
+			RawJSON("request", req.JSON()).
+			Msg("getting data from binance")
+		return types.NewDataError(err)
+	}
+	if len(payloads) == 0 {
+		return types.NewDataError(
+			types.NewNoDataError(symbol),
+		)
+	}
+
+	queueID := producer.GenerateQueueID()
+	var responseTopic string
+	if dtype == types.Bar {
+		responseTopic = binance.NewBarDataTopic(req.GetTimeFrame(), symbol, queueID, len(payloads)).Generate()
+	} else {
+		responseTopic = binance.NewDataTopic(dtype, symbol, queueID, len(payloads)).Generate()
+	}
+	messages := make([]*sharedent.Message, 0, len(payloads))
+	for _, p := range payloads {
+		messages = append(messages, sharedent.GenerateMessage(p, dtype, responseTopic))
+	}
+
+	handler, handlerResponse := producer.GetQueueHandler(responseTopic, req.GetNoConfirm())
+	if handlerResponse.Err != "" {
+		return handlerResponse
+	}
+	handler.Ch <- producer.NewQueue(responseTopic, messages)
+
+	return types.NewDataResponse(
+		types.Success,
+		"Successfully added data",
+		nil,
+		responseTopic,
+	)
+}
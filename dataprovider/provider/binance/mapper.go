@@ -0,0 +1,261 @@
+package binance
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+	sharedent "tradingplatform/shared/entities"
+	"tradingplatform/shared/types"
+)
+
+// Kline (candlestick) as returned by the REST API, encoded as an array
+type Kline struct {
+	OpenTime    int64
+	Open        float64
+	High        float64
+	Low         float64
+	Close       float64
+	Volume      float64
+	QuoteVolume float64
+	TradeCount  uint64
+}
+
+func (k *Kline) UnmarshalJSON(b []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if len(raw) < 9 {
+		return fmt.Errorf("kline has %d elements, expected at least 9", len(raw))
+	}
+	if err := json.Unmarshal(raw[0], &k.OpenTime); err != nil {
+		return err
+	}
+	var err error
+	for i, field := range []*float64{&k.Open, &k.High, &k.Low, &k.Close, &k.Volume} {
+		if *field, err = parseRawFloat(raw[i+1]); err != nil {
+			return err
+		}
+	}
+	if k.QuoteVolume, err = parseRawFloat(raw[7]); err != nil {
+		return err
+	}
+	return json.Unmarshal(raw[8], &k.TradeCount)
+}
+
+// Aggregated trade as returned by the REST API and the aggTrade stream. encoding/json matches keys
+// case-insensitively, keys differing from a field only by case must have their own field
+type AggTrade struct {
+	ID           int64  `json:"a"`
+	Price        string `json:"p"`
+	Quantity     string `json:"q"`
+	Time         int64  `json:"T"`
+	BuyerIsMaker bool   `json:"m"`
+	BestMatch    bool   `json:"M"`
+}
+
+// Orderbook snapshot as returned by the REST API and the partial depth stream
+type Depth struct {
+	LastUpdateID int64       `json:"lastUpdateId"`
+	Bids         [][2]string `json:"bids"`
+	Asks         [][2]string `json:"asks"`
+}
+
+// Best bid/ask as returned by the bookTicker stream
+type BookTicker struct {
+	UpdateID int64  `json:"u"`
+	Symbol   string `json:"s"`
+	BidPrice string `json:"b"`
+	BidQty   string `json:"B"`
+	AskPrice string `json:"a"`
+	AskQty   string `json:"A"`
+}
+
+// Best bid/ask as returned by the REST API
+type RESTBookTicker struct {
+	Symbol   string `json:"symbol"`
+	BidPrice string `json:"bidPrice"`
+	BidQty   string `json:"bidQty"`
+	AskPrice string `json:"askPrice"`
+	AskQty   string `json:"askQty"`
+}
+
+// Kline event of the kline stream, see AggTrade for the fields differing only by case
+type KlineEvent struct {
+	EventType string `json:"e"`
+	EventTime int64  `json:"E"`
+	Symbol    string `json:"s"`
+	Kline     struct {
+		StartTime           int64  `json:"t"`
+		CloseTime           int64  `json:"T"`
+		Interval            string `json:"i"`
+		Open                string `json:"o"`
+		Close               string `json:"c"`
+		High                string `json:"h"`
+		Low                 string `json:"l"`
+		LastTradeID         int64  `json:"L"`
+		Volume              string `json:"v"`
+		TakerBuyVolume      string `json:"V"`
+		QuoteVolume         string `json:"q"`
+		TakerBuyQuoteVolume string `json:"Q"`
+		TradeCount          uint64 `json:"n"`
+		Closed              bool   `json:"x"`
+	} `json:"k"`
+}
+
+// Aggregated trade event of the aggTrade stream
+type AggTradeEvent struct {
+	AggTrade
+	EventType string `json:"e"`
+	EventTime int64  `json:"E"`
+	Symbol    string `json:"s"`
+}
+
+func parseRawFloat(raw json.RawMessage) (float64, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+func parseFloat(s string) float64 {
+	f, _ := strconv.ParseFloat(s, 64)
+	return f
+}
+
+// Convert a Binance timestamp (milliseconds) to the timestamp used by the entities
+func msToTimestamp(ms int64) int64 {
+	return time.UnixMilli(ms).UnixNano()
+}
+
+func vwap(quoteVolume float64, volume float64) float64 {
+	if volume == 0 {
+		return 0
+	}
+	return quoteVolume / volume
+}
+
+// Map a REST kline to a bar
+func MapKline(k Kline, symbol string, timeFrame types.TimeFrame) *sharedent.Bar {
+	bar := sharedent.Bar{
+		Symbol:      symbol,
+		Exchange:    DEFAULT_EXCHANGE,
+		Open:        k.Open,
+		High:        k.High,
+		Low:         k.Low,
+		Close:       k.Close,
+		Volume:      k.Volume,
+		VWAP:        vwap(k.QuoteVolume, k.Volume),
+		TimestampNs: msToTimestamp(k.OpenTime),
+		TradeCount:  k.TradeCount,
+		Source:      string(types.Binance),
+		AssetClass:  string(types.Crypto),
+		Timeframe:   string(timeFrame),
+	}
+	bar.SetFingerprint()
+	return &bar
+}
+
+// Map a kline stream event to a bar
+func MapKlineEvent(e KlineEvent, symbol string) *sharedent.Bar {
+	volume := parseFloat(e.Kline.Volume)
+	bar := sharedent.Bar{
+		Symbol:      symbol,
+		Exchange:    DEFAULT_EXCHANGE,
+		Open:        parseFloat(e.Kline.Open),
+		High:        parseFloat(e.Kline.High),
+		Low:         parseFloat(e.Kline.Low),
+		Close:       parseFloat(e.Kline.Close),
+		Volume:      volume,
+		VWAP:        vwap(parseFloat(e.Kline.QuoteVolume), volume),
+		TimestampNs: msToTimestamp(e.Kline.StartTime),
+		TradeCount:  e.Kline.TradeCount,
+		Source:      string(types.Binance),
+		AssetClass:  string(types.Crypto),
+		Timeframe:   string(types.OneMin),
+	}
+	bar.SetFingerprint()
+	return &bar
+}
+
+// Map an aggregated trade to a trade
+func MapAggTrade(t AggTrade, symbol string) *sharedent.Trade {
+	// The taker is the seller if the buyer is the maker
+	takerSide := "B"
+	if t.BuyerIsMaker {
+		takerSide = "S"
+	}
+	trade := sharedent.Trade{
+		ID:          t.ID,
+		Symbol:      symbol,
+		Exchange:    DEFAULT_EXCHANGE,
+		Price:       parseFloat(t.Price),
+		Size:        parseFloat(t.Quantity),
+		TimestampNs: msToTimestamp(t.Time),
+		TakerSide:   takerSide,
+		Source:      string(types.Binance),
+		AssetClass:  string(types.Crypto),
+	}
+	trade.SetFingerprint()
+	return &trade
+}
+
+// Map a book ticker to a quote, book tickers carry no timestamp so the time of reception is used
+func MapBookTicker(b BookTicker, symbol string, timestamp time.Time) *sharedent.Quote {
+	quote := sharedent.Quote{
+		Symbol:      symbol,
+		Exchange:    DEFAULT_EXCHANGE,
+		BidExchange: DEFAULT_EXCHANGE,
+		AskExchange: DEFAULT_EXCHANGE,
+		BidPrice:    parseFloat(b.BidPrice),
+		BidSize:     parseFloat(b.BidQty),
+		AskPrice:    parseFloat(b.AskPrice),
+		AskSize:     parseFloat(b.AskQty),
+		TimestampNs: timestamp.UnixNano(),
+		Source:      string(types.Binance),
+		AssetClass:  string(types.Crypto),
+	}
+	quote.SetFingerprint()
+	return &quote
+}
+
+// Map a REST book ticker to a quote
+func MapRESTBookTicker(b RESTBookTicker, symbol string, timestamp time.Time) *sharedent.Quote {
+	return MapBookTicker(BookTicker{
+		Symbol:   b.Symbol,
+		BidPrice: b.BidPrice,
+		BidQty:   b.BidQty,
+		AskPrice: b.AskPrice,
+		AskQty:   b.AskQty,
+	}, symbol, timestamp)
+}
+
+func mapDepthEntries(entries [][2]string) []*sharedent.OrderbookEntry {
+	mapped := make([]*sharedent.OrderbookEntry, 0, len(entries))
+	for _, entry := range entries {
+		mapped = append(mapped, &sharedent.OrderbookEntry{
+			Price:  parseFloat(entry[0]),
+			Size:   parseFloat(entry[1]),
+			Source: string(types.Binance),
+		})
+	}
+	return mapped
+}
+
+// Map a depth snapshot to an orderbook, snapshots carry no timestamp so the time of reception is used
+func MapDepth(d Depth, symbol string, timestamp time.Time) *sharedent.Orderbook {
+	orderbook := sharedent.Orderbook{
+		Symbol:      symbol,
+		Exchange:    DEFAULT_EXCHANGE,
+		TimestampNs: timestamp.UnixNano(),
+		Asks:        mapDepthEntries(d.Asks),
+		Bids:        mapDepthEntries(d.Bids),
+		Reset_:      true,
+		Source:      string(types.Binance),
+		AssetClass:  string(types.Crypto),
+	}
+	orderbook.SetFingerprint()
+	return &orderbook
+}
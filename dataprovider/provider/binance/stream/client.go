@@ -0,0 +1,351 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"tradingplatform/dataprovider/provider/binance"
+	"tradingplatform/shared/communication/producer"
+	sharedent "tradingplatform/shared/entities"
+	"tradingplatform/shared/logging"
+	"tradingplatform/shared/types"
+
+	"nhooyr.io/websocket"
+)
+
+// Delay before trying to reconnect after the websocket connection is lost
+var RECONNECT_DELAY = 5 * time.Second
+
+// Maximum size of a message received on the websocket
+var READ_LIMIT int64 = 1 << 20
+
+// A subscribed stream of the combined websocket connection
+type subscription struct {
+	dtype  types.DataType
+	symbol string
+}
+
+// A message received on the combined stream, replies to (un)subscribe requests have no stream
+type combinedMessage struct {
+	Stream string          `json:"stream"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// A (un)subscribe request sent on the combined stream
+type subscriptionRequest struct {
+	Method string   `json:"method"`
+	Params []string `json:"params"`
+	ID     int64    `json:"id"`
+}
+
+// Client of the combined websocket streams, a single connection is shared by all subscriptions
+type streamClient struct {
+	lock          sync.Mutex
+	conn          *websocket.Conn
+	cancel        context.CancelFunc
+	subscriptions map[string]subscription
+	requestID     int64
+}
+
+var client = &streamClient{subscriptions: make(map[string]subscription)}
+
+// Get the name of the Binance stream providing a data type for a symbol
+func streamName(dtype types.DataType, symbol string) (string, error) {
+	s := strings.ToLower(binance.GetBinanceSymbol(symbol))
+	switch dtype {
+	case types.Bar:
+		return s + "@kline_1m", nil
+	case types.Trades:
+		return s + "@aggTrade", nil
+	case types.Quotes:
+		return s + "@bookTicker", nil
+	case types.Orderbook:
+		return s + "@depth20@100ms", nil
+	default:
+		return "", fmt.Errorf("data type %s not supported yet", dtype)
+	}
+}
+
+// Get the streams of a data type for the given symbols that are not subscribed yet, must be called with
+// the lock held. Symbols written differently (e.g. BTC/USDT and BTCUSDT) share the same stream, a
+// symbol whose stream is subscribed for another symbol is rejected
+func (c *streamClient) pending(dtype types.DataType, symbols []string) (map[string]subscription, error) {
+	pending := make(map[string]subscription)
+	for _, symbol := range symbols {
+		name, err := streamName(dtype, symbol)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.checkSymbol(name, symbol); err != nil {
+			return nil, err
+		}
+		if other, ok := pending[name]; ok && other.symbol != symbol {
+			return nil, fmt.Errorf("symbols %s and %s share the binance stream %s", other.symbol, symbol, name)
+		}
+		if _, ok := c.subscriptions[name]; !ok {
+			pending[name] = subscription{dtype: dtype, symbol: symbol}
+		}
+	}
+	return pending, nil
+}
+
+// Check that a stream is not subscribed for another symbol, must be called with the lock held
+func (c *streamClient) checkSymbol(name string, symbol string) error {
+	if sub, ok := c.subscriptions[name]; ok && sub.symbol != symbol {
+		return fmt.Errorf("binance stream %s of symbol %s is already subscribed for symbol %s", name, symbol, sub.symbol)
+	}
+	return nil
+}
+
+// Subscribe to a data type for the given symbols, connecting if needed. The streams are recorded once the
+// subscribe request is sent, the connection is dialed without the lock held
+func (c *streamClient) subscribe(dtype types.DataType, symbols []string) error {
+	c.lock.Lock()
+	pending, err := c.pending(dtype, symbols)
+	connected := c.conn != nil
+	c.lock.Unlock()
+	if err != nil || len(pending) == 0 {
+		return err
+	}
+	if !connected {
+		if err := c.connect(); err != nil {
+			return err
+		}
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.conn == nil {
+		return errors.New("binance stream connection lost while subscribing")
+	}
+	names := []string{}
+	for name, sub := range pending {
+		// Another request may have subscribed the stream meanwhile
+		if err := c.checkSymbol(name, sub.symbol); err != nil {
+			return err
+		}
+		if _, ok := c.subscriptions[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	sort.Strings(names)
+	if err := c.send("SUBSCRIBE", names); err != nil {
+		if len(c.subscriptions) == 0 {
+			c.disconnect()
+		}
+		return err
+	}
+	for _, name := range names {
+		c.subscriptions[name] = pending[name]
+	}
+	return nil
+}
+
+// Unsubscribe from a data type for the given symbols, disconnecting if nothing is left. A stream
+// subscribed for another symbol is left subscribed
+func (c *streamClient) unsubscribe(dtype types.DataType, symbols []string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	names := []string{}
+	for _, symbol := range symbols {
+		name, err := streamName(dtype, symbol)
+		if err != nil {
+			return err
+		}
+		if sub, ok := c.subscriptions[name]; !ok || sub.symbol != symbol {
+			continue
+		}
+		delete(c.subscriptions, name)
+		names = append(names, name)
+	}
+	if c.conn == nil || len(names) == 0 {
+		return nil
+	}
+	if len(c.subscriptions) == 0 {
+		c.disconnect()
+		return nil
+	}
+	return c.send("UNSUBSCRIBE", names)
+}
+
+// Send a (un)subscribe request, must be called with the lock held
+func (c *streamClient) send(method string, names []string) error {
+	c.requestID++
+	req, err := json.Marshal(subscriptionRequest{Method: method, Params: names, ID: c.requestID})
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return c.conn.Write(ctx, websocket.MessageText, req)
+}
+
+// Dial a connection and subscribe it to the recorded streams, must be called without the lock held.
+// The connection is dropped if another one was connected meanwhile
+func (c *streamClient) connect() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	dialCtx, dialCancel := context.WithTimeout(ctx, 30*time.Second)
+	defer dialCancel()
+	conn, _, err := websocket.Dial(dialCtx, binance.GetWSURL()+"/stream", nil)
+	if err != nil {
+		cancel()
+		return err
+	}
+	conn.SetReadLimit(READ_LIMIT)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.conn != nil {
+		cancel()
+		conn.Close(websocket.StatusNormalClosure, "")
+		return nil
+	}
+	c.conn = conn
+	c.cancel = cancel
+
+	if len(c.subscriptions) > 0 {
+		names := make([]string, 0, len(c.subscriptions))
+		for name := range c.subscriptions {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		if err := c.send("SUBSCRIBE", names); err != nil {
+			c.disconnect()
+			return err
+		}
+	}
+	go c.read(ctx, conn)
+	return nil
+}
+
+// Close the connection, must be called with the lock held
+func (c *streamClient) disconnect() {
+	c.cancel()
+	c.conn.Close(websocket.StatusNormalClosure, "")
+	c.conn = nil
+	c.cancel = nil
+}
+
+// Read messages until the connection is closed, reconnecting if it was lost
+func (c *streamClient) read(ctx context.Context, conn *websocket.Conn) {
+	for {
+		_, msg, err := conn.Read(ctx)
+		if err != nil {
+			if errors.Is(ctx.Err(), context.Canceled) {
+				return
+			}
+			logging.Log().Error().Err(err).Msg("reading from binance stream")
+			c.reconnect(conn)
+			return
+		}
+		c.handleMessage(msg)
+	}
+}
+
+// Replace a lost connection, retrying until it succeeds or no subscription is left
+func (c *streamClient) reconnect(lost *websocket.Conn) {
+	c.lock.Lock()
+	if c.conn != lost {
+		c.lock.Unlock()
+		return
+	}
+	c.disconnect()
+	c.lock.Unlock()
+
+	for {
+		time.Sleep(RECONNECT_DELAY)
+		c.lock.Lock()
+		done := c.conn != nil || len(c.subscriptions) == 0
+		c.lock.Unlock()
+		if done {
+			return
+		}
+		err := c.connect()
+		if err == nil {
+			logging.Log().Info().Msg("reconnected to binance stream")
+			return
+		}
+		logging.Log().Error().Err(err).Msg("reconnecting to binance stream")
+	}
+}
+
+// Map a message of the combined stream and publish it on the stream topic
+func (c *streamClient) handleMessage(msg []byte) {
+	var combined combinedMessage
+	if err := json.Unmarshal(msg, &combined); err != nil {
+		logging.Log().Error().Err(err).Bytes("raw", msg).Msg("unmarshalling binance stream message")
+		return
+	}
+	if combined.Stream == "" {
+		// Reply to a (un)subscribe request
+		logging.Log().Debug().RawJSON("reply", msg).Msg("received binance stream reply")
+		return
+	}
+
+	c.lock.Lock()
+	sub, ok := c.subscriptions[combined.Stream]
+	c.lock.Unlock()
+	if !ok {
+		return
+	}
+
+	payload, err := mapStreamData(sub, combined.Data)
+	if err != nil {
+		logging.Log().Error().
+			Err(err).
+			Str("stream", combined.Stream).
+			Msg("mapping binance stream data")
+		return
+	}
+	if payload == nil {
+		return
+	}
+	topic := binance.NewStreamTopic(sub.dtype, sub.symbol).Generate()
+	message := sharedent.GenerateMessage(payload, sub.dtype, topic)
+	producer.GetStreamHandler(message.Topic).Ch <- message
+}
+
+// Map the data of a stream message to an entity, nil if there is nothing to publish yet
+func mapStreamData(sub subscription, data json.RawMessage) (sharedent.Payloader, error) {
+	switch sub.dtype {
+	case types.Bar:
+		var event binance.KlineEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			return nil, err
+		}
+		// Only publish complete bars
+		if !event.Kline.Closed {
+			return nil, nil
+		}
+		return binance.MapKlineEvent(event, sub.symbol), nil
+	case types.Trades:
+		var event binance.AggTradeEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			return nil, err
+		}
+		return binance.MapAggTrade(event.AggTrade, sub.symbol), nil
+	case types.Quotes:
+		var ticker binance.BookTicker
+		if err := json.Unmarshal(data, &ticker); err != nil {
+			return nil, err
+		}
+		return binance.MapBookTicker(ticker, sub.symbol, time.Now()), nil
+	case types.Orderbook:
+		var depth binance.Depth
+		if err := json.Unmarshal(data, &depth); err != nil {
+			return nil, err
+		}
+		return binance.MapDepth(depth, sub.symbol, time.Now()), nil
+	default:
+		return nil, fmt.Errorf("data type %s not supported yet", sub.dtype)
+	}
+}
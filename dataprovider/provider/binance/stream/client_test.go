@@ -0,0 +1,326 @@
+package stream
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"tradingplatform/dataprovider/provider/binance"
+	"tradingplatform/shared/communication"
+	sharedent "tradingplatform/shared/entities"
+	"tradingplatform/shared/logging"
+	"tradingplatform/shared/types"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog"
+	"google.golang.org/protobuf/proto"
+	"nhooyr.io/websocket"
+)
+
+func TestMain(m *testing.M) {
+	logger := zerolog.Nop()
+	logging.SetLogger(&logger)
+	os.Exit(m.Run())
+}
+
+func readFixture(t *testing.T, name string) json.RawMessage {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join("..", "testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// Stand-in of the combined stream endpoint recording the (un)subscribe requests and answering each
+// subscribed stream with the event of its fixture
+type standIn struct {
+	t        *testing.T
+	server   *httptest.Server
+	lock     sync.Mutex
+	requests []subscriptionRequest
+	dials    int
+}
+
+func newStandIn(t *testing.T) *standIn {
+	s := &standIn{t: t}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.server.Close)
+	t.Setenv("BINANCE_WS_URL", "ws"+strings.TrimPrefix(s.server.URL, "http"))
+	return s
+}
+
+func (s *standIn) serve(w http.ResponseWriter, r *http.Request) {
+	conn, err := websocket.Accept(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close(websocket.StatusNormalClosure, "")
+	s.lock.Lock()
+	s.dials++
+	s.lock.Unlock()
+
+	ctx := r.Context()
+	for {
+		_, msg, err := conn.Read(ctx)
+		if err != nil {
+			return
+		}
+		var req subscriptionRequest
+		if err := json.Unmarshal(msg, &req); err != nil {
+			return
+		}
+		s.lock.Lock()
+		s.requests = append(s.requests, req)
+		s.lock.Unlock()
+
+		reply, _ := json.Marshal(map[string]any{"result": nil, "id": req.ID})
+		conn.Write(ctx, websocket.MessageText, reply)
+		if req.Method != "SUBSCRIBE" {
+			continue
+		}
+		for _, name := range req.Params {
+			_, kind, _ := strings.Cut(name, "@")
+			kind, _, _ = strings.Cut(kind, "_")
+			kind, _, _ = strings.Cut(kind, "@")
+			data, err := os.ReadFile(filepath.Join("..", "testdata", "stream_"+strings.TrimSuffix(kind, "20")+".json"))
+			if err != nil {
+				return
+			}
+			event, _ := json.Marshal(combinedMessage{Stream: name, Data: data})
+			conn.Write(ctx, websocket.MessageText, event)
+		}
+	}
+}
+
+func (s *standIn) received() ([]subscriptionRequest, int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]subscriptionRequest{}, s.requests...), s.dials
+}
+
+// Wait until the stand-in received n requests
+func (s *standIn) waitRequests(n int) []subscriptionRequest {
+	s.t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if requests, _ := s.received(); len(requests) >= n {
+			return requests
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	requests, _ := s.received()
+	s.t.Fatalf("expected %d requests, got %v", n, requests)
+	return nil
+}
+
+func newTestClient(t *testing.T) *streamClient {
+	c := &streamClient{subscriptions: make(map[string]subscription)}
+	t.Cleanup(func() {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+		if c.conn != nil {
+			c.disconnect()
+		}
+	})
+	return c
+}
+
+func startNats(t *testing.T) *nats.Conn {
+	ns, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ns.Start()
+	if !ns.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats-server not ready")
+	}
+	t.Cleanup(ns.Shutdown)
+	communication.SetNatsURL(ns.ClientURL())
+	nc, err := nats.Connect(ns.ClientURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(nc.Close)
+	return nc
+}
+
+func TestSubscribePublishesStreamEvents(t *testing.T) {
+	nc := startNats(t)
+	standIn := newStandIn(t)
+	c := newTestClient(t)
+
+	topic := binance.NewStreamTopic(types.Trades, "BTC/USDT").Generate()
+	sub, err := nc.SubscribeSync(topic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.subscribe(types.Trades, []string{"BTC/USDT"}); err != nil {
+		t.Fatal(err)
+	}
+
+	requests := standIn.waitRequests(1)
+	if requests[0].Method != "SUBSCRIBE" || len(requests[0].Params) != 1 || requests[0].Params[0] != "btcusdt@aggTrade" {
+		t.Fatalf("unexpected subscribe request %+v", requests[0])
+	}
+
+	natsMsg, err := sub.NextMsg(5 * time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var msg sharedent.Message
+	if err := proto.Unmarshal(natsMsg.Data, &msg); err != nil {
+		t.Fatal(err)
+	}
+	entity, err := msg.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	trade, ok := entity.(*sharedent.Trade)
+	if !ok {
+		t.Fatalf("expected a trade, got %T", entity)
+	}
+	if trade.Price != 42283.58 || trade.Symbol != "BTC/USDT" || trade.ID != 2808412385 {
+		t.Fatalf("unexpected trade %+v", trade)
+	}
+	if trade.TimestampNs != time.UnixMilli(1704067200021).UnixNano() {
+		t.Fatalf("unexpected timestamp %d", trade.TimestampNs)
+	}
+}
+
+func TestSubscribeSharesConnectionAndSkipsSubscribedStreams(t *testing.T) {
+	startNats(t)
+	standIn := newStandIn(t)
+	c := newTestClient(t)
+
+	if err := c.subscribe(types.Quotes, []string{"BTC/USDT"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.subscribe(types.Quotes, []string{"BTC/USDT", "ETH/USDT"}); err != nil {
+		t.Fatal(err)
+	}
+	requests := standIn.waitRequests(2)
+	if got := requests[1].Params; len(got) != 1 || got[0] != "ethusdt@bookTicker" {
+		t.Fatalf("expected only the new stream to be subscribed, got %v", got)
+	}
+	if _, dials := standIn.received(); dials != 1 {
+		t.Fatalf("expected a single connection, got %d", dials)
+	}
+
+	if err := c.unsubscribe(types.Quotes, []string{"BTC/USDT"}); err != nil {
+		t.Fatal(err)
+	}
+	requests = standIn.waitRequests(3)
+	if requests[2].Method != "UNSUBSCRIBE" || requests[2].Params[0] != "btcusdt@bookTicker" {
+		t.Fatalf("unexpected unsubscribe request %+v", requests[2])
+	}
+
+	// The last stream closes the connection
+	if err := c.unsubscribe(types.Quotes, []string{"ETH/USDT"}); err != nil {
+		t.Fatal(err)
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.conn != nil || len(c.subscriptions) != 0 {
+		t.Fatalf("expected the client to be disconnected, subscriptions %v", c.subscriptions)
+	}
+}
+
+func TestSubscribeRejectsSymbolsSharingAStream(t *testing.T) {
+	startNats(t)
+	standIn := newStandIn(t)
+	c := newTestClient(t)
+
+	if err := c.subscribe(types.Trades, []string{"BTC/USDT", "BTCUSDT"}); err == nil {
+		t.Fatal("expected symbols sharing a stream in a request to be rejected")
+	}
+	if err := c.subscribe(types.Trades, []string{"BTC/USDT"}); err != nil {
+		t.Fatal(err)
+	}
+	standIn.waitRequests(1)
+	if err := c.subscribe(types.Trades, []string{"BTCUSDT"}); err == nil {
+		t.Fatal("expected a symbol sharing the stream of another symbol to be rejected")
+	}
+
+	// Removing the other symbol keeps the stream of the subscribed one
+	if err := c.unsubscribe(types.Trades, []string{"BTCUSDT"}); err != nil {
+		t.Fatal(err)
+	}
+	c.lock.Lock()
+	sub, ok := c.subscriptions["btcusdt@aggTrade"]
+	c.lock.Unlock()
+	if !ok || sub.symbol != "BTC/USDT" {
+		t.Fatalf("expected the stream to stay subscribed for BTC/USDT, got %v", c.subscriptions)
+	}
+	if requests, _ := standIn.received(); len(requests) != 1 {
+		t.Fatalf("expected no other request, got %+v", requests)
+	}
+}
+
+func TestSubscribeFailureRecordsNothing(t *testing.T) {
+	standIn := newStandIn(t)
+	standIn.server.Close()
+	c := newTestClient(t)
+
+	if err := c.subscribe(types.Trades, []string{"BTC/USDT"}); err == nil {
+		t.Fatal("expected the dial to fail")
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.conn != nil || len(c.subscriptions) != 0 {
+		t.Fatalf("expected no connection and no subscription, got %v", c.subscriptions)
+	}
+}
+
+func TestSubscribeUnsupportedDataType(t *testing.T) {
+	c := newTestClient(t)
+	if err := c.subscribe(types.RawText, []string{"BTC/USDT"}); err == nil {
+		t.Fatal("expected an error for an unsupported data type")
+	}
+	if len(c.subscriptions) != 0 {
+		t.Fatalf("expected no subscription, got %v", c.subscriptions)
+	}
+}
+
+func TestMapStreamData(t *testing.T) {
+	bar, err := mapStreamData(subscription{dtype: types.Bar, symbol: "BTC/USDT"}, readFixture(t, "stream_kline.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := bar.(*sharedent.Bar); b.Open != 42283.58 || b.Close != 42298.61 || b.TradeCount != 1327 ||
+		b.TimestampNs != time.UnixMilli(1704067200000).UnixNano() {
+		t.Fatalf("unexpected bar %+v", b)
+	}
+
+	quote, err := mapStreamData(subscription{dtype: types.Quotes, symbol: "BTC/USDT"}, readFixture(t, "stream_bookTicker.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q := quote.(*sharedent.Quote); q.BidPrice != 42283.57 || q.AskPrice != 42283.58 || q.AskSize != 6.62213 {
+		t.Fatalf("unexpected quote %+v", q)
+	}
+
+	orderbook, err := mapStreamData(subscription{dtype: types.Orderbook, symbol: "BTC/USDT"}, readFixture(t, "stream_depth.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o := orderbook.(*sharedent.Orderbook); len(o.Asks) != 3 || len(o.Bids) != 3 || o.Bids[0].Price != 42283.57 {
+		t.Fatalf("unexpected orderbook %+v", o)
+	}
+
+	// Bars are only published once closed
+	var event map[string]any
+	json.Unmarshal(readFixture(t, "stream_kline.json"), &event)
+	event["k"].(map[string]any)["x"] = false
+	open, _ := json.Marshal(event)
+	payload, err := mapStreamData(subscription{dtype: types.Bar, symbol: "BTC/USDT"}, open)
+	if err != nil || payload != nil {
+		t.Fatalf("expected nothing to publish for an open bar, got %v, %v", payload, err)
+	}
+}
@@ -0,0 +1,92 @@
+package stream
+
+import (
+	"tradingplatform/dataprovider/data"
+	"tradingplatform/dataprovider/provider"
+	"tradingplatform/dataprovider/provider/binance"
+	"tradingplatform/shared/logging"
+	"tradingplatform/shared/requests"
+	"tradingplatform/shared/types"
+)
+
+// Handle a Binance stream add request
+func HandleBinanceStreamAddRequest(req requests.StreamRequest) types.StreamResponse {
+	logging.Log().Debug().RawJSON("request", req.JSON()).Msg("adding binance stream")
+
+	dtypesHandled := []types.DataType{}
+	for _, dtype := range req.GetDataType() {
+		if err := client.subscribe(dtype, req.GetSymbol()); err != nil {
+			logging.Log().Error().
+				Err(err).
+				RawJSON("request", req.JSON()).
+				Msg("adding binance stream")
+			return provider.NewStreamError(err)
+		}
+		dtypesHandled = append(dtypesHandled, dtype)
+		data.AddDataProviderStreamForDType(req, dtype)
+	}
+
+	return provider.NewStreamResponseSourceAssetClass(
+		types.Success,
+		"Successfully added binance stream",
+		binance.GenerateJSONStreamTopicDict(dtypesHandled, req.GetSymbol()),
+		nil,
+		types.Binance,
+		types.Crypto,
+	)
+}
+
+// Handle a Binance stream remove request
+func HandleBinanceStreamRemoveRequest(req requests.StreamRequest) types.StreamResponse {
+	logging.Log().Info().RawJSON("request", req.JSON()).Msg("removing binance stream")
+
+	dtypesHandled := []types.DataType{}
+	for _, dtype := range req.GetDataType() {
+		if err := client.unsubscribe(dtype, req.GetSymbol()); err != nil {
+			logging.Log().Error().
+				Err(err).
+				RawJSON("request", req.JSON()).
+				Msg("removing binance stream")
+			return provider.NewStreamError(err)
+		}
+		dtypesHandled = append(dtypesHandled, dtype)
+		data.RemoveDataProviderStreamForDType(req, dtype)
+	}
+
+	return provider.NewStreamResponseSourceAssetClass(
+		types.Success,
+		"Successfully removed binance stream",
+		binance.GenerateJSONStreamTopicDict(dtypesHandled, req.GetSymbol()),
+		nil,
+		types.Binance,
+		types.Crypto,
+	)
+}
+
+// Provide a response with the active Binance streams
+func HandleBinanceStreamGetRequest(req requests.StreamRequest) types.StreamResponse {
+	streams := data.GetDataProviderStreamsSourceAssetClass(types.Binance, types.Crypto)
+	dtypes := map[types.DataType]struct{}{}
+	symbols := map[string]struct{}{}
+	for _, stream := range streams {
+		dtypes[stream.DataType] = struct{}{}
+		symbols[stream.Symbol] = struct{}{}
+	}
+	dtypesSlice := make([]types.DataType, 0, len(dtypes))
+	for dtype := range dtypes {
+		dtypesSlice = append(dtypesSlice, dtype)
+	}
+	symbolsSlice := make([]string, 0, len(symbols))
+	for symbol := range symbols {
+		symbolsSlice = append(symbolsSlice, symbol)
+	}
+
+	return provider.NewStreamResponseSourceAssetClass(
+		types.Success,
+		"Successfully retrieved streams",
+		binance.GenerateJSONStreamTopicDict(dtypesSlice, symbolsSlice),
+		nil,
+		types.Binance,
+		types.Crypto,
+	)
+}
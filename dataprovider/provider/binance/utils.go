@@ -0,0 +1,43 @@
+package binance
+
+import (
+	"encoding/json"
+	"strings"
+	"tradingplatform/shared/logging"
+	"tradingplatform/shared/types"
+	"tradingplatform/shared/utils"
+)
+
+func NewStreamTopic(dataType types.DataType, symbol string) utils.Topic {
+	return utils.NewStreamTopic(types.DataProvider, types.Binance, types.Crypto, dataType, symbol)
+}
+
+func NewDataTopic(dataType types.DataType, symbol string, queueID string, queueCount int) utils.Topic {
+	return utils.NewDataTopic(types.DataProvider, types.Binance, types.Crypto, dataType, symbol, queueID, queueCount)
+}
+
+func NewBarDataTopic(timeFrame types.TimeFrame, symbol string, queueID string, queueCount int) utils.Topic {
+	return utils.NewBarDataTopic(types.DataProvider, types.Binance, types.Crypto, timeFrame, symbol, queueID, queueCount)
+}
+
+func GenerateJSONStreamTopicDict(dataTypes []types.DataType, symbols []string) string {
+	tmap := map[types.DataType][]string{}
+	for _, dataType := range dataTypes {
+		value := tmap[dataType]
+		for _, symbol := range symbols {
+			value = append(value, NewStreamTopic(dataType, symbol).Generate())
+		}
+		tmap[dataType] = value
+	}
+	json, err := json.Marshal(tmap)
+	if err != nil {
+		logging.Log().Error().Strs("symbols", symbols).Err(err).Msg("generating topics")
+		return ""
+	}
+	return string(json)
+}
+
+// Convert a symbol (e.g. BTC/USDT or btcusdt) to the Binance format (e.g. BTCUSDT)
+func GetBinanceSymbol(symbol string) string {
+	return strings.ToUpper(strings.ReplaceAll(symbol, "/", ""))
+}
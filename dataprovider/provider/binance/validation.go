@@ -0,0 +1,41 @@
+package binance
+
+import (
+	"tradingplatform/shared/logging"
+	"tradingplatform/shared/types"
+)
+
+// Utility to verify whether a symbol is traded on Binance, only crypto is available
+func IsSymbolValid(symbol string, assetClass types.AssetClass) bool {
+	if assetClass != types.Crypto {
+		return false
+	}
+	info, err := NewClient().GetExchangeInfo(GetBinanceSymbol(symbol))
+	if err != nil {
+		logging.Log().Error().
+			Err(err).
+			Msg("getting exchange info during symbol validation")
+		return false
+	}
+	for _, s := range info.Symbols {
+		if s.Symbol == GetBinanceSymbol(symbol) && s.Status == "TRADING" {
+			return true
+		}
+	}
+	return false
+}
+
+// Data types available from the Binance public market data
+func GetDataTypes(assetClass types.AssetClass) map[types.DataType]types.DataType {
+	switch assetClass {
+	case types.Crypto:
+		return map[types.DataType]types.DataType{
+			"bar":       types.Bar,
+			"orderbook": types.Orderbook,
+			"quotes":    types.Quotes,
+			"trades":    types.Trades,
+		}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,60 @@
+package provider
+
+import (
+	"sync"
+	"tradingplatform/shared/requests"
+	"tradingplatform/shared/types"
+)
+
+// A source of market data that the dataprovider can stream from and fetch historical data from
+type Provider interface {
+	// The source handled by the provider
+	Source() types.Source
+	// The data types supported by the provider for a given asset class
+	SupportedDataTypes(assetClass types.AssetClass) map[types.DataType]types.DataType
+	// Whether a symbol is valid for a given asset class
+	IsSymbolValid(symbol string, assetClass types.AssetClass) bool
+	// Start streaming the requested data types and symbols
+	AddStream(req requests.StreamRequest) types.StreamResponse
+	// Stop streaming the requested data types and symbols
+	RemoveStream(req requests.StreamRequest) types.StreamResponse
+	// Get the active streams of the provider
+	GetStreams(req requests.StreamRequest) types.StreamResponse
+	// Fetch historical data and publish it on a data topic
+	FetchData(req requests.DataRequest) types.DataResponse
+}
+
+var providers = make(map[types.Source]Provider)
+var providersLock sync.RWMutex
+
+// Register a provider, the data types it supports are registered as a source so that
+// requests referring to it pass validation
+func Register(p Provider) {
+	providersLock.Lock()
+	providers[p.Source()] = p
+	providersLock.Unlock()
+
+	requests.RegisterSource(requests.SourceDescriptor{
+		Source:    p.Source(),
+		DataTypes: p.SupportedDataTypes,
+	})
+}
+
+// Get the provider registered for a source
+func GetProvider(source types.Source) (Provider, bool) {
+	providersLock.RLock()
+	defer providersLock.RUnlock()
+	p, ok := providers[source]
+	return p, ok
+}
+
+// Get all registered providers
+func GetProviders() []Provider {
+	providersLock.RLock()
+	defer providersLock.RUnlock()
+	ps := make([]Provider, 0, len(providers))
+	for _, p := range providers {
+		ps = append(ps, p)
+	}
+	return ps
+}
@@ -0,0 +1,11 @@
+package replay
+
+import (
+	"time"
+)
+
+// AsFastAsPossible disables pacing, stored events are published back to back
+const AsFastAsPossible float64 = 0
+
+// Size of the time window loaded from the database at once
+const DefaultWindow = time.Hour
@@ -0,0 +1,74 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"time"
+
+	"tradingplatform/shared/logging"
+	"tradingplatform/shared/tracing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Read-only connection to the datastorage database the replayed data is read from
+var db *gorm.DB
+
+// InitializeReplayDatabase opens a read-only connection to the datastorage database the replayed
+// data is read from. Returns cleanup function
+func InitializeReplayDatabase(dsn string) func() {
+	config, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		logging.Log().Error().Err(err).Msg("parsing the DSN of the replay database")
+		panic(err)
+	}
+	// The replay never writes to the datastorage tables, every transaction of the connection is read-only
+	config.RuntimeParams["default_transaction_read_only"] = "on"
+	sqlDB := stdlib.OpenDB(*config)
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{
+		Logger: logger.New(
+			log.New(os.Stdout, "\r\n", log.LstdFlags),
+			logger.Config{
+				SlowThreshold: time.Second,
+				LogLevel:      logger.Error,
+				Colorful:      false,
+			},
+		),
+	})
+	if err != nil {
+		logging.Log().Error().Err(err).Msg("failed to connect to the replay database")
+		panic(err)
+	}
+	if err := gormDB.Use(tracing.GormPlugin()); err != nil {
+		logging.Log().Error().Err(err).Msg("failed to register the tracing of the replay database queries")
+		panic(err)
+	}
+	db = gormDB
+	return func() {
+		sqlDB.Close()
+	}
+}
+
+// IsDatabaseInitialized returns whether a datastorage database is available for replay
+func IsDatabaseInitialized() bool {
+	return db != nil
+}
+
+// PingDatabase pings the datastorage database used for replay
+func PingDatabase(ctx context.Context) error {
+	if db == nil {
+		return errors.New("replay database is not initialized")
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
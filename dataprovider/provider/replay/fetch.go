@@ -0,0 +1,332 @@
+package replay
+
+import (
+	"time"
+
+	sharedent "tradingplatform/shared/entities"
+	"tradingplatform/shared/types"
+)
+
+// Rows of the datastorage tables read by the replay, only the columns needed to rebuild the entities
+// are mapped
+
+type storedBar struct {
+	Symbol      string
+	Exchange    string
+	Source      string
+	Open        float64
+	High        float64
+	Low         float64
+	Close       float64
+	Volume      float64
+	VWAP        float64
+	TimestampNs int64
+	TradeCount  uint64
+	Fingerprint string `gorm:"primaryKey"`
+	AssetClass  string
+	Timeframe   string
+}
+
+func (storedBar) TableName() string { return "bars" }
+
+type storedCondition struct {
+	Condition        string
+	TradeFingerprint string
+}
+
+type storedTradeCondition storedCondition
+
+func (storedTradeCondition) TableName() string { return "trade_conditions" }
+
+type storedTrade struct {
+	Symbol      string
+	Exchange    string
+	Price       float64
+	Size        float64
+	TimestampNs int64
+	TakerSide   string
+	Conditions  []storedTradeCondition `gorm:"foreignKey:TradeFingerprint"`
+	Tape        string
+	Fingerprint string `gorm:"primaryKey"`
+	Update      string
+	Source      string
+	AssetClass  string
+}
+
+func (storedTrade) TableName() string { return "trades" }
+
+type storedQuoteCondition storedCondition
+
+func (storedQuoteCondition) TableName() string { return "quote_conditions" }
+
+type storedQuote struct {
+	Symbol      string
+	BidExchange string
+	Exchange    string
+	BidPrice    float64
+	BidSize     float64
+	AskExchange string
+	AskPrice    float64
+	AskSize     float64
+	TimestampNs int64
+	Conditions  []storedQuoteCondition `gorm:"foreignKey:TradeFingerprint"`
+	Tape        string
+	Fingerprint string `gorm:"primaryKey"`
+	Source      string
+	AssetClass  string
+}
+
+func (storedQuote) TableName() string { return "quotes" }
+
+type storedOrderbookEntry struct {
+	Price                float64
+	Size                 float64
+	Source               string
+	OrderbookFingerprint string
+}
+
+type storedAsk storedOrderbookEntry
+
+func (storedAsk) TableName() string { return "asks_orderbook_entries" }
+
+type storedBid storedOrderbookEntry
+
+func (storedBid) TableName() string { return "bids_orderbook_entries" }
+
+type storedOrderbook struct {
+	Symbol      string
+	Exchange    string
+	TimestampNs int64
+	Asks        []storedAsk `gorm:"foreignKey:OrderbookFingerprint"`
+	Bids        []storedBid `gorm:"foreignKey:OrderbookFingerprint"`
+	Reset_      bool
+	Fingerprint string `gorm:"primaryKey"`
+	Source      string
+	AssetClass  string
+}
+
+func (storedOrderbook) TableName() string { return "orderbooks" }
+
+type storedNewsSymbol struct {
+	Symbol          string
+	NewsFingerprint string
+}
+
+func (storedNewsSymbol) TableName() string { return "news_symbols" }
+
+type storedNews struct {
+	Id                 int64
+	Author             string
+	CreatedAtTimestamp time.Time
+	UpdatedAtTimestamp time.Time
+	Headline           string
+	Summary            string
+	Content            string
+	URL                string
+	Symbols            []storedNewsSymbol `gorm:"foreignKey:NewsFingerprint"`
+	Fingerprint        string             `gorm:"primaryKey"`
+	Source             string
+}
+
+func (storedNews) TableName() string { return "news" }
+
+// A stored entity together with its original timestamp
+type event struct {
+	timestamp int64
+	entity    sharedent.Payloader
+}
+
+// Fetcher loads the stored entities of a symbol within [from, to) ordered by timestamp
+type Fetcher func(source types.Source, assetClass types.AssetClass, symbol string, from time.Time, to time.Time) ([]event, error)
+
+// GetFetcher returns the fetcher for a given data type
+func GetFetcher(dtype types.DataType) (Fetcher, bool) {
+	fetchers := map[types.DataType]Fetcher{
+		types.Bar:       fetchBars,
+		types.Trades:    fetchTrades,
+		types.Quotes:    fetchQuotes,
+		types.Orderbook: fetchOrderbooks,
+		types.RawText:   fetchNews,
+	}
+	fetcher, ok := fetchers[dtype]
+	return fetcher, ok
+}
+
+func fetchBars(source types.Source, assetClass types.AssetClass, symbol string, from time.Time, to time.Time) ([]event, error) {
+	var bars []storedBar
+	// Bars coming from streams are stored without timeframe
+	tx := db.Where("source = ? AND symbol = ? AND asset_class = ? AND timestamp_ns >= ? AND timestamp_ns < ? AND timeframe IN ?",
+		source,
+		symbol,
+		assetClass,
+		from.UnixNano(),
+		to.UnixNano(),
+		[]string{"", string(types.OneMin)}).Order("timestamp_ns").Find(&bars)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	events := make([]event, len(bars))
+	for i, bar := range bars {
+		events[i] = event{timestamp: bar.TimestampNs, entity: &sharedent.Bar{
+			Symbol:      bar.Symbol,
+			Exchange:    bar.Exchange,
+			Source:      bar.Source,
+			Open:        bar.Open,
+			High:        bar.High,
+			Low:         bar.Low,
+			Close:       bar.Close,
+			Volume:      bar.Volume,
+			VWAP:        bar.VWAP,
+			TimestampNs: bar.TimestampNs,
+			TradeCount:  bar.TradeCount,
+			Fingerprint: bar.Fingerprint,
+			AssetClass:  bar.AssetClass,
+			Timeframe:   bar.Timeframe,
+		}}
+	}
+	return events, nil
+}
+
+func fetchTrades(source types.Source, assetClass types.AssetClass, symbol string, from time.Time, to time.Time) ([]event, error) {
+	var trades []storedTrade
+	tx := db.Preload("Conditions").Where("source = ? AND symbol = ? AND asset_class = ? AND timestamp_ns >= ? AND timestamp_ns < ?",
+		source,
+		symbol,
+		assetClass,
+		from.UnixNano(),
+		to.UnixNano()).Order("timestamp_ns").Find(&trades)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	events := make([]event, len(trades))
+	for i, trade := range trades {
+		conditions := make([]string, len(trade.Conditions))
+		for j, c := range trade.Conditions {
+			conditions[j] = c.Condition
+		}
+		events[i] = event{timestamp: trade.TimestampNs, entity: &sharedent.Trade{
+			Symbol:      trade.Symbol,
+			Exchange:    trade.Exchange,
+			Price:       trade.Price,
+			Size:        trade.Size,
+			TimestampNs: trade.TimestampNs,
+			TakerSide:   trade.TakerSide,
+			Conditions:  conditions,
+			Tape:        trade.Tape,
+			Fingerprint: trade.Fingerprint,
+			Update:      trade.Update,
+			Source:      trade.Source,
+			AssetClass:  trade.AssetClass,
+		}}
+	}
+	return events, nil
+}
+
+func fetchQuotes(source types.Source, assetClass types.AssetClass, symbol string, from time.Time, to time.Time) ([]event, error) {
+	var quotes []storedQuote
+	tx := db.Preload("Conditions").Where("source = ? AND symbol = ? AND asset_class = ? AND timestamp_ns >= ? AND timestamp_ns < ?",
+		source,
+		symbol,
+		assetClass,
+		from.UnixNano(),
+		to.UnixNano()).Order("timestamp_ns").Find(&quotes)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	events := make([]event, len(quotes))
+	for i, quote := range quotes {
+		conditions := make([]string, len(quote.Conditions))
+		for j, c := range quote.Conditions {
+			conditions[j] = c.Condition
+		}
+		events[i] = event{timestamp: quote.TimestampNs, entity: &sharedent.Quote{
+			Symbol:      quote.Symbol,
+			BidExchange: quote.BidExchange,
+			Exchange:    quote.Exchange,
+			BidPrice:    quote.BidPrice,
+			BidSize:     quote.BidSize,
+			AskExchange: quote.AskExchange,
+			AskPrice:    quote.AskPrice,
+			AskSize:     quote.AskSize,
+			TimestampNs: quote.TimestampNs,
+			Conditions:  conditions,
+			Tape:        quote.Tape,
+			Fingerprint: quote.Fingerprint,
+			Source:      quote.Source,
+			AssetClass:  quote.AssetClass,
+		}}
+	}
+	return events, nil
+}
+
+func fetchOrderbooks(source types.Source, assetClass types.AssetClass, symbol string, from time.Time, to time.Time) ([]event, error) {
+	var orderbooks []storedOrderbook
+	tx := db.Preload("Asks").Preload("Bids").Where("source = ? AND symbol = ? AND asset_class = ? AND timestamp_ns >= ? AND timestamp_ns < ?",
+		source,
+		symbol,
+		assetClass,
+		from.UnixNano(),
+		to.UnixNano()).Order("timestamp_ns").Find(&orderbooks)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	events := make([]event, len(orderbooks))
+	for i, orderbook := range orderbooks {
+		asks := make([]*sharedent.OrderbookEntry, len(orderbook.Asks))
+		for j, a := range orderbook.Asks {
+			asks[j] = &sharedent.OrderbookEntry{Price: a.Price, Size: a.Size, Source: a.Source}
+		}
+		bids := make([]*sharedent.OrderbookEntry, len(orderbook.Bids))
+		for j, b := range orderbook.Bids {
+			bids[j] = &sharedent.OrderbookEntry{Price: b.Price, Size: b.Size, Source: b.Source}
+		}
+		events[i] = event{timestamp: orderbook.TimestampNs, entity: &sharedent.Orderbook{
+			Symbol:      orderbook.Symbol,
+			Exchange:    orderbook.Exchange,
+			TimestampNs: orderbook.TimestampNs,
+			Asks:        asks,
+			Bids:        bids,
+			Reset_:      orderbook.Reset_,
+			Fingerprint: orderbook.Fingerprint,
+			Source:      orderbook.Source,
+			AssetClass:  orderbook.AssetClass,
+		}}
+	}
+	return events, nil
+}
+
+func fetchNews(source types.Source, _ types.AssetClass, symbol string, from time.Time, to time.Time) ([]event, error) {
+	var news []storedNews
+	tx := db.Preload("Symbols").
+		Joins("JOIN news_symbols ON news_symbols.news_fingerprint = news.fingerprint").
+		Where("source = ? AND news_symbols.symbol = ? AND created_at_timestamp >= ? AND created_at_timestamp < ?",
+			source,
+			symbol,
+			from,
+			to).Order("created_at_timestamp").Find(&news)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	events := make([]event, len(news))
+	for i, n := range news {
+		symbols := make([]string, len(n.Symbols))
+		for j, s := range n.Symbols {
+			symbols[j] = s.Symbol
+		}
+		createdAt := n.CreatedAtTimestamp.UnixNano()
+		events[i] = event{timestamp: createdAt, entity: &sharedent.News{
+			Id:          n.Id,
+			Author:      n.Author,
+			CreatedAtNs: createdAt,
+			UpdatedAtNs: n.UpdatedAtTimestamp.UnixNano(),
+			Headline:    n.Headline,
+			Summary:     n.Summary,
+			Content:     n.Content,
+			URL:         n.URL,
+			Symbols:     symbols,
+			Fingerprint: n.Fingerprint,
+			Source:      n.Source,
+		}}
+	}
+	return events, nil
+}
@@ -0,0 +1,89 @@
+package replay
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	sharedent "tradingplatform/shared/entities"
+	"tradingplatform/shared/logging"
+	"tradingplatform/shared/requests"
+	"tradingplatform/shared/types"
+)
+
+// A stream of a replay session, the stored entities of a data type of a symbol
+type Stream struct {
+	DataType types.DataType
+	Symbol   string
+	Fetch    Fetcher
+	Publish  func(sharedent.Payloader)
+}
+
+// Replay loads the stored entities of the streams of a session window by window and passes them to
+// the publish function of their stream. The entities of all the streams are merged on a single clock,
+// so that they are published in their original order across symbols and data types. Delays between
+// entities are the original ones divided by the speed of the session, a speed of AsFastAsPossible
+// disables pacing.
+func Replay(ctx context.Context,
+	session requests.ReplayOptions,
+	assetClass types.AssetClass,
+	streams []Stream) error {
+
+	start := time.Unix(0, session.StartTime)
+	end := time.Unix(0, session.EndTime)
+	wallStart := time.Now()
+	published := 0
+
+	// Entity of a window together with the stream it is published on
+	type streamEvent struct {
+		event
+		stream int
+	}
+	for from := start; from.Before(end); from = from.Add(DefaultWindow) {
+		to := from.Add(DefaultWindow)
+		if to.After(end) {
+			to = end
+		}
+		var events []streamEvent
+		for i, stream := range streams {
+			fetched, err := stream.Fetch(session.StoredSource, assetClass, stream.Symbol, from, to)
+			if err != nil {
+				return err
+			}
+			for _, e := range fetched {
+				events = append(events, streamEvent{event: e, stream: i})
+			}
+		}
+		// The entities of each stream are already ordered, a stable sort keeps their order on equal timestamps
+		sort.SliceStable(events, func(i, j int) bool {
+			return events[i].timestamp < events[j].timestamp
+		})
+
+		for _, e := range events {
+			if session.Speed > AsFastAsPossible {
+				offset := time.Duration(float64(time.Unix(0, e.timestamp).Sub(start)) / session.Speed)
+				if wait := time.Until(wallStart.Add(offset)); wait > 0 {
+					timer := time.NewTimer(wait)
+					select {
+					case <-timer.C:
+					case <-ctx.Done():
+						timer.Stop()
+						return ctx.Err()
+					}
+				}
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			streams[e.stream].Publish(e.entity)
+			published++
+		}
+	}
+
+	logging.Log().Info().
+		Str("assetClass", string(assetClass)).
+		Int("streams", len(streams)).
+		Int("published", published).
+		Msg("replay finished")
+	return nil
+}
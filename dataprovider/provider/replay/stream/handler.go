@@ -0,0 +1,236 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"tradingplatform/dataprovider/data"
+	"tradingplatform/dataprovider/provider"
+	"tradingplatform/dataprovider/provider/replay"
+	"tradingplatform/shared/communication/producer"
+	sharedent "tradingplatform/shared/entities"
+	"tradingplatform/shared/logging"
+	"tradingplatform/shared/requests"
+	"tradingplatform/shared/types"
+)
+
+// A running replay session, the topics of a stream add request replayed on a single clock
+type session struct {
+	cancel context.CancelFunc
+	// Number of topics still replayed by the session
+	topics int
+}
+
+// Sessions replaying each topic
+var activeReplays = make(map[string]*session)
+var activeReplaysLock sync.Mutex
+
+// Whether a topic is still replayed by a session
+func isReplaying(topic string, s *session) bool {
+	activeReplaysLock.Lock()
+	defer activeReplaysLock.Unlock()
+	return activeReplays[topic] == s
+}
+
+// Start a session replaying the data types and symbols of a request, the topics already replayed by
+// another session are left to it. Once the session is finished the streams of its remaining topics
+// are removed
+func startReplay(req requests.StreamRequest, fetchers map[types.DataType]replay.Fetcher) {
+	assetClass := req.GetAssetClass()
+	ctx, cancel := context.WithCancel(context.Background())
+	current := &session{cancel: cancel}
+
+	var streams []replay.Stream
+	activeReplaysLock.Lock()
+	for _, dtype := range req.GetDataType() {
+		for _, symbol := range req.GetSymbol() {
+			dtype, symbol, topic := dtype, symbol, replay.NewStreamTopic(assetClass, dtype, symbol).Generate()
+			if _, ok := activeReplays[topic]; ok {
+				continue
+			}
+			activeReplays[topic] = current
+			current.topics++
+			streams = append(streams, replay.Stream{
+				DataType: dtype,
+				Symbol:   symbol,
+				Fetch:    fetchers[dtype],
+				Publish: func(p sharedent.Payloader) {
+					// The topic may have been removed from the session while it is running
+					if !isReplaying(topic, current) {
+						return
+					}
+					msg := sharedent.GenerateMessage(p, dtype, topic)
+					// Corrections and cancels of trades are published on their own topic as when streamed
+					if trade, ok := p.(*sharedent.Trade); ok && trade.Update != "" {
+						msg = sharedent.GenerateMessage(p, types.TradeUpdates,
+							replay.NewStreamTopic(assetClass, types.TradeUpdates, symbol).Generate())
+					}
+					producer.GetStreamHandler(msg.Topic).Ch <- msg
+				},
+			})
+		}
+	}
+	activeReplaysLock.Unlock()
+	if len(streams) == 0 {
+		cancel()
+		return
+	}
+
+	go func() {
+		err := replay.Replay(ctx, *req.GetReplay(), assetClass, streams)
+		if err != nil && !errors.Is(err, context.Canceled) {
+			logging.Log().Error().
+				Err(err).
+				RawJSON("request", req.JSON()).
+				Msg("replaying stored data")
+		}
+
+		// Remove the streams of the topics still replayed by the session
+		finished := make(map[types.DataType][]string)
+		activeReplaysLock.Lock()
+		for _, stream := range streams {
+			topic := replay.NewStreamTopic(assetClass, stream.DataType, stream.Symbol).Generate()
+			if activeReplays[topic] == current {
+				delete(activeReplays, topic)
+				finished[stream.DataType] = append(finished[stream.DataType], stream.Symbol)
+			}
+		}
+		activeReplaysLock.Unlock()
+		cancel()
+		for dtype, symbols := range finished {
+			for _, symbol := range symbols {
+				stopTopicHandlers(assetClass, dtype, symbol)
+			}
+			data.RemoveDataProviderStreamForDType(requests.NewStreamRequest(types.Replay,
+				assetClass,
+				symbols,
+				types.StreamRemoveOp,
+				[]types.DataType{dtype},
+				req.GetAccount()), dtype)
+		}
+	}()
+}
+
+// Stop replaying a topic, the session is stopped once it has no topic left
+func stopReplay(topic string) {
+	activeReplaysLock.Lock()
+	defer activeReplaysLock.Unlock()
+	current, ok := activeReplays[topic]
+	if !ok {
+		return
+	}
+	delete(activeReplays, topic)
+	current.topics--
+	if current.topics == 0 {
+		current.cancel()
+	}
+}
+
+// Handle a replay stream add request
+func HandleReplayStreamAddRequest(req requests.StreamRequest) types.StreamResponse {
+	logging.Log().Debug().RawJSON("request", req.JSON()).Msg("adding replay stream")
+	if !replay.IsDatabaseInitialized() {
+		return provider.NewStreamError(
+			errors.New("replay source is not configured, start the dataprovider with a replay DSN"),
+		)
+	}
+	session := req.GetReplay()
+	if session == nil {
+		return provider.NewStreamError(errors.New("replay stream request without replayed session"))
+	}
+	if session.EndTime <= session.StartTime {
+		return provider.NewStreamError(
+			fmt.Errorf("invalid replay window [%d, %d)", session.StartTime, session.EndTime),
+		)
+	}
+
+	fetchers := make(map[types.DataType]replay.Fetcher)
+	for _, dtype := range req.GetDataType() {
+		fetch, ok := replay.GetFetcher(dtype)
+		if !ok {
+			err := fmt.Errorf("data type %s not supported yet", dtype)
+			logging.Log().Error().
+				Err(err).
+				RawJSON("request", req.JSON()).
+				Msg("adding replay stream")
+			return provider.NewStreamError(err)
+		}
+		fetchers[dtype] = fetch
+	}
+	for _, dtype := range req.GetDataType() {
+		data.AddDataProviderStreamForDType(req, dtype)
+	}
+	startReplay(req, fetchers)
+
+	return provider.NewStreamResponseSourceAssetClass(
+		types.Success,
+		"Successfully added replay stream",
+		replay.GenerateJSONStreamTopicDict(req.GetAssetClass(), req.GetDataType(), req.GetSymbol()),
+		nil,
+		types.Replay,
+		req.GetAssetClass(),
+	)
+}
+
+// Handle a replay stream remove request
+func HandleReplayStreamRemoveRequest(req requests.StreamRequest) types.StreamResponse {
+	logging.Log().Info().RawJSON("request", req.JSON()).Msg("removing replay stream")
+
+	dtypesHandled := []types.DataType{}
+	for _, dtype := range req.GetDataType() {
+		for _, symbol := range req.GetSymbol() {
+			tTopic := replay.NewStreamTopic(req.GetAssetClass(), dtype, symbol).Generate()
+			stopReplay(tTopic)
+			stopTopicHandlers(req.GetAssetClass(), dtype, symbol)
+		}
+		dtypesHandled = append(dtypesHandled, dtype)
+		data.RemoveDataProviderStreamForDType(req, dtype)
+	}
+
+	return provider.NewStreamResponseSourceAssetClass(
+		types.Success,
+		"Successfully removed replay stream",
+		replay.GenerateJSONStreamTopicDict(req.GetAssetClass(), dtypesHandled, req.GetSymbol()),
+		nil,
+		types.Replay,
+		req.GetAssetClass(),
+	)
+}
+
+// Provide a response with the active replay streams
+func HandleReplayStreamGetRequest(req requests.StreamRequest) types.StreamResponse {
+	assetClass := req.GetAssetClass()
+	streams := data.GetDataProviderStreamsSourceAssetClass(types.Replay, assetClass)
+	dtypes := map[types.DataType]struct{}{}
+	symbols := map[string]struct{}{}
+	for _, stream := range streams {
+		dtypes[stream.DataType] = struct{}{}
+		symbols[stream.Symbol] = struct{}{}
+	}
+	dtypesSlice := make([]types.DataType, 0, len(dtypes))
+	for dtype := range dtypes {
+		dtypesSlice = append(dtypesSlice, dtype)
+	}
+	symbolsSlice := make([]string, 0, len(symbols))
+	for symbol := range symbols {
+		symbolsSlice = append(symbolsSlice, symbol)
+	}
+
+	return provider.NewStreamResponseSourceAssetClass(
+		types.Success,
+		"Successfully retrieved streams",
+		replay.GenerateJSONStreamTopicDict(assetClass, dtypesSlice, symbolsSlice),
+		nil,
+		types.Replay,
+		assetClass,
+	)
+}
+
+// Stop the producers of the topic of a data type of a symbol, and of its trade updates for trades
+func stopTopicHandlers(assetClass types.AssetClass, dtype types.DataType, symbol string) {
+	producer.StopTopicHandler(replay.NewStreamTopic(assetClass, dtype, symbol).Generate())
+	if dtype == types.Trades {
+		producer.StopTopicHandler(replay.NewStreamTopic(assetClass, types.TradeUpdates, symbol).Generate())
+	}
+}
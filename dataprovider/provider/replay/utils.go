@@ -0,0 +1,54 @@
+package replay
+
+import (
+	"encoding/json"
+	"tradingplatform/shared/logging"
+	"tradingplatform/shared/types"
+	"tradingplatform/shared/utils"
+)
+
+func NewStreamTopic(assetClass types.AssetClass, dataType types.DataType, symbol string) utils.Topic {
+	return utils.NewStreamTopic(types.DataProvider, types.Replay, assetClass, dataType, symbol)
+}
+
+func GenerateJSONStreamTopicDict(assetClass types.AssetClass, dataTypes []types.DataType, symbols []string) string {
+	tmap := map[types.DataType][]string{}
+	for _, dataType := range dataTypes {
+		value := tmap[dataType]
+		for _, symbol := range symbols {
+			value = append(value, NewStreamTopic(assetClass, dataType, symbol).Generate())
+		}
+		tmap[dataType] = value
+	}
+	json, err := json.Marshal(tmap)
+	if err != nil {
+		logging.Log().Error().Str("assetClass", string(assetClass)).Strs("symbols", symbols).Err(err).Msg("generating topics")
+		return ""
+	}
+	return string(json)
+}
+
+// Data types that can be replayed from the datastorage tables
+func GetDataTypes(assetClass types.AssetClass) map[types.DataType]types.DataType {
+	switch assetClass {
+	case types.Stock:
+		return map[types.DataType]types.DataType{
+			"bar":    types.Bar,
+			"quotes": types.Quotes,
+			"trades": types.Trades,
+		}
+	case types.Crypto:
+		return map[types.DataType]types.DataType{
+			"bar":       types.Bar,
+			"orderbook": types.Orderbook,
+			"quotes":    types.Quotes,
+			"trades":    types.Trades,
+		}
+	case types.News:
+		return map[types.DataType]types.DataType{
+			"raw-text": types.RawText,
+		}
+	default:
+		return nil
+	}
+}
@@ -26,3 +26,12 @@ func NewStreamResponseAssetClass(status types.OpStatus, message string, topics s
 	streamsJSON, _ := json.Marshal(streams)
 	return types.NewStreamResponse(status, message, err, string(streamsJSON), topics)
 }
+
+// New dataprovider-specific stream response for a given source and asset class
+func NewStreamResponseSourceAssetClass(status types.OpStatus, message string, topics string, err error,
+	source types.Source, assetClass types.AssetClass) types.StreamResponse {
+
+	streams := data.GetDataProviderStreamsSourceAssetClass(source, assetClass)
+	streamsJSON, _ := json.Marshal(streams)
+	return types.NewStreamResponse(status, message, err, string(streamsJSON), topics)
+}
@@ -0,0 +1,37 @@
+package synthetic
+
+import (
+	"strconv"
+	"sync"
+
+	"tradingplatform/shared/types"
+)
+
+type Config struct {
+	// Seed of the generated data, combined with the asset class and symbol of each stream or data request
+	// so that runs with the same seed generate the same prices
+	Seed int64
+}
+
+var config = Config{}
+var configLock sync.RWMutex
+
+// SetConfig sets the configuration used by new synthetic streams and data requests
+func SetConfig(c Config) {
+	configLock.Lock()
+	defer configLock.Unlock()
+	config = c
+}
+
+// GetConfig returns the configuration used by new synthetic streams and data requests
+func GetConfig() Config {
+	configLock.RLock()
+	defer configLock.RUnlock()
+	return config
+}
+
+// SymbolSeed derives the seed of the data of a symbol from the configured seed and the given parts
+// (e.g. fields of a request)
+func SymbolSeed(assetClass types.AssetClass, symbol string, parts ...string) int64 {
+	return Seed(append([]string{strconv.FormatInt(GetConfig().Seed, 10), string(assetClass), symbol}, parts...)...)
+}
@@ -0,0 +1,164 @@
+package data
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+	"tradingplatform/dataprovider/provider/synthetic"
+	"tradingplatform/shared/calendar"
+	"tradingplatform/shared/communication/producer"
+	sharedent "tradingplatform/shared/entities"
+	"tradingplatform/shared/logging"
+	"tradingplatform/shared/requests"
+	"tradingplatform/shared/types"
+)
+
+// Maximum number of elements generated for a single data request
+var MAX_ELEMENTS = 10000
+
+// Number of trades used to build each bar
+var TRADES_PER_BAR = 60
+
+// Handle a data request for the synthetic source
+func HandleSyntheticDataRequest(req requests.DataRequest) types.DataResponse {
+	logging.Log().Debug().RawJSON("request", req.JSON()).Msg("handling synthetic data request")
+
+	assetClass := req.GetAssetClass()
+	if assetClass != types.Stock && assetClass != types.Crypto {
+		return types.NewDataError(
+			fmt.Errorf("invalid asset type %s", assetClass),
+		)
+	}
+	start := time.Unix(0, req.GetStartTime())
+	end := time.Unix(0, req.GetEndTime())
+	if !end.After(start) {
+		return types.NewDataError(
+			fmt.Errorf("invalid time window [%d, %d)", req.GetStartTime(), req.GetEndTime()),
+		)
+	}
+
+	symbol := req.GetSymbol()
+	dtype := req.GetDataType()
+	// The same request always generates the same data
+	market := synthetic.NewMarket(symbol, assetClass, synthetic.SymbolSeed(
+		assetClass,
+		symbol,
+		strconv.FormatInt(req.GetStartTime(), 10),
+		strconv.FormatInt(req.GetEndTime(), 10),
+	))
+
+	var payloads []sharedent.Payloader
+	var err error
+	switch dtype {
+	case types.Bar:
+		payloads, err = generateBars(market, symbol, assetClass, req.GetTimeFrame(), start, end)
+	case types.Trades:
+		payloads = generateTicks(start, end, func(ts time.Time) sharedent.Payloader {
+			return market.Trade(ts)
+		}, market)
+	case types.Quotes:
+		payloads = generateTicks(start, end, func(ts time.Time) sharedent.Payloader {
+			return market.Quote(ts)
+		}, market)
+	case types.Orderbook:
+		if assetClass != types.Crypto {
+			err = fmt.Errorf("invalid data type %s for asset class %s", dtype, assetClass)
+			break
+		}
+		payloads = generateTicks(start, end, func(ts time.Time) sharedent.Payloader {
+			return market.Orderbook(ts)
+		}, market)
+	default:
+		err = fmt.Errorf("invalid data type %s", dtype)
+	}
+	if err != nil {
+		logging.Log().Error().
+			Err(err).
+			RawJSON("request", req.JSON()).
+			Msg("generating synthetic data")
+		return types.NewDataError(err)
+	}
+	if len(payloads) == 0 {
+		return types.NewDataError(
+			types.NewNoDataError(symbol),
+		)
+	}
+
+	queueID := producer.GenerateQueueID()
+	var responseTopic string
+	if dtype == types.Bar {
+		responseTopic = synthetic.NewBarDataTopic(assetClass, req.GetTimeFrame(), symbol,
+			queueID, len(payloads)).Generate()
+	} else {
+		responseTopic = synthetic.NewDataTopic(assetClass, dtype, symbol, queueID,
+			len(payloads)).Generate()
+	}
+	messages := make([]*sharedent.Message, 0, len(payloads))
+	for _, p := range payloads {
+		messages = append(messages, sharedent.GenerateMessage(p, dtype, responseTopic))
+	}
+
+	handler, handlerResponse := producer.GetQueueHandler(responseTopic, req.GetNoConfirm())
+	if handlerResponse.Err != "" {
+		return handlerResponse
+	}
+	handler.Ch <- producer.NewQueue(responseTopic, messages)
+
+	return types.NewDataResponse(
+		types.Success,
+		"Successfully added data",
+		nil,
+		responseTopic,
+	)
+}
+
+// Generate one element per tick in [start, end), the tick is at least one second and grows
+// with the window so that no more than MAX_ELEMENTS are generated
+func generateTicks(start time.Time,
+	end time.Time,
+	gen func(time.Time) sharedent.Payloader,
+	market *synthetic.Market) []sharedent.Payloader {
+
+	tick := time.Second
+	if window := end.Sub(start); window/tick > time.Duration(MAX_ELEMENTS) {
+		tick = window / time.Duration(MAX_ELEMENTS)
+	}
+	var payloads []sharedent.Payloader
+	for ts := start; ts.Before(end) && len(payloads) < MAX_ELEMENTS; ts = ts.Add(tick) {
+		market.Step(tick)
+		payloads = append(payloads, gen(ts))
+	}
+	return payloads
+}
+
+// Generate bars in [start, end) aligned to the timeframe in the trading sessions of the asset
+// class, each bar is derived from the trades generated within it
+func generateBars(market *synthetic.Market,
+	symbol string,
+	assetClass types.AssetClass,
+	timeFrame types.TimeFrame,
+	start time.Time,
+	end time.Time) ([]sharedent.Payloader, error) {
+
+	cal := calendar.ForAssetClass(assetClass)
+	period, err := calendar.SessionBarPeriod(cal, timeFrame, start)
+	if err != nil {
+		return nil, err
+	}
+
+	var payloads []sharedent.Payloader
+	for ; period.Start.Before(end) && len(payloads) < MAX_ELEMENTS; period, _ = calendar.SessionBarPeriod(cal, timeFrame, period.End) {
+		// Months and bars cut at the open or close of a session have their own length
+		tick := period.End.Sub(period.Start) / time.Duration(TRADES_PER_BAR)
+		builder := synthetic.NewBarBuilder(symbol, assetClass, timeFrame, period.Start)
+		for i := 0; i < TRADES_PER_BAR; i++ {
+			market.Step(tick)
+			builder.Add(market.Trade(period.Start.Add(time.Duration(i) * tick)))
+		}
+		if period.Start.Before(start) {
+			continue
+		}
+		payloads = append(payloads, builder.Build())
+	}
+	return payloads, nil
+}
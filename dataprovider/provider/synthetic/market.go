@@ -0,0 +1,208 @@
+package synthetic
+
+import (
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"time"
+
+	sharedent "tradingplatform/shared/entities"
+	"tradingplatform/shared/types"
+)
+
+var DEFAULT_EXCHANGE = "SYNTHETIC"
+
+// Annualized drift and volatility of the generated price paths
+var DEFAULT_DRIFT = 0.05
+var DEFAULT_VOLATILITY = 0.4
+
+// Number of levels on each side of generated orderbooks
+var DEFAULT_ORDERBOOK_DEPTH = 10
+
+const secondsPerYear = 365 * 24 * 60 * 60
+
+// Seed derives a deterministic seed from the given parts (e.g. fields of a request)
+func Seed(parts ...string) int64 {
+	h := fnv.New64a()
+	for _, part := range parts {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return int64(h.Sum64())
+}
+
+// Market generates a geometric Brownian motion price path for a symbol together with
+// trades, quotes and orderbooks consistent with it
+type Market struct {
+	symbol     string
+	assetClass types.AssetClass
+	rng        *rand.Rand
+	price      float64
+	drift      float64
+	volatility float64
+	tradeID    int64
+}
+
+// NewMarket creates a new market for a symbol, the same seed always produces the same data
+func NewMarket(symbol string, assetClass types.AssetClass, seed int64) *Market {
+	rng := rand.New(rand.NewSource(seed))
+	// Pick a starting price in a range that is plausible for the asset class
+	price := 10 + rng.Float64()*490
+	if assetClass == types.Crypto {
+		price = 100 + rng.Float64()*59900
+	}
+	return &Market{
+		symbol:     symbol,
+		assetClass: assetClass,
+		rng:        rng,
+		price:      price,
+		drift:      DEFAULT_DRIFT,
+		volatility: DEFAULT_VOLATILITY,
+	}
+}
+
+// Price returns the current price of the market
+func (m *Market) Price() float64 {
+	return m.price
+}
+
+// Step advances the price path by dt
+func (m *Market) Step(dt time.Duration) float64 {
+	t := dt.Seconds() / secondsPerYear
+	z := m.rng.NormFloat64()
+	m.price *= math.Exp((m.drift-m.volatility*m.volatility/2)*t + m.volatility*math.Sqrt(t)*z)
+	return m.price
+}
+
+func (m *Market) size() float64 {
+	if m.assetClass == types.Crypto {
+		return math.Round(m.rng.ExpFloat64()*0.5*1e4) / 1e4
+	}
+	return float64(1 + m.rng.Intn(500))
+}
+
+// Half of the bid/ask spread around the current price
+func (m *Market) halfSpread() float64 {
+	return m.price * (0.0001 + m.rng.Float64()*0.0004)
+}
+
+// Trade generates a trade at the current price
+func (m *Market) Trade(timestamp time.Time) *sharedent.Trade {
+	m.tradeID++
+	trade := sharedent.Trade{
+		ID:          m.tradeID,
+		Symbol:      m.symbol,
+		Exchange:    DEFAULT_EXCHANGE,
+		Price:       m.price,
+		Size:        m.size(),
+		TimestampNs: timestamp.UnixNano(),
+		Source:      string(types.Synthetic),
+		AssetClass:  string(m.assetClass),
+	}
+	if m.assetClass == types.Crypto {
+		trade.TakerSide = "B"
+		if m.rng.Intn(2) == 0 {
+			trade.TakerSide = "S"
+		}
+	}
+	trade.SetFingerprint()
+	return &trade
+}
+
+// Quote generates a quote around the current price
+func (m *Market) Quote(timestamp time.Time) *sharedent.Quote {
+	halfSpread := m.halfSpread()
+	quote := sharedent.Quote{
+		Symbol:      m.symbol,
+		Exchange:    DEFAULT_EXCHANGE,
+		BidExchange: DEFAULT_EXCHANGE,
+		AskExchange: DEFAULT_EXCHANGE,
+		BidPrice:    m.price - halfSpread,
+		BidSize:     m.size(),
+		AskPrice:    m.price + halfSpread,
+		AskSize:     m.size(),
+		TimestampNs: timestamp.UnixNano(),
+		Source:      string(types.Synthetic),
+		AssetClass:  string(m.assetClass),
+	}
+	quote.SetFingerprint()
+	return &quote
+}
+
+// Orderbook generates a random orderbook snapshot around the current price
+func (m *Market) Orderbook(timestamp time.Time) *sharedent.Orderbook {
+	halfSpread := m.halfSpread()
+	tick := m.price * 0.0001
+	orderbook := sharedent.Orderbook{
+		Symbol:      m.symbol,
+		Exchange:    DEFAULT_EXCHANGE,
+		TimestampNs: timestamp.UnixNano(),
+		Reset_:      true,
+		Source:      string(types.Synthetic),
+		AssetClass:  string(m.assetClass),
+	}
+	for i := 0; i < DEFAULT_ORDERBOOK_DEPTH; i++ {
+		offset := halfSpread + float64(i)*tick*(1+m.rng.Float64())
+		orderbook.Bids = append(orderbook.Bids, &sharedent.OrderbookEntry{
+			Price:  m.price - offset,
+			Size:   m.size(),
+			Source: string(types.Synthetic),
+		})
+		orderbook.Asks = append(orderbook.Asks, &sharedent.OrderbookEntry{
+			Price:  m.price + offset,
+			Size:   m.size(),
+			Source: string(types.Synthetic),
+		})
+	}
+	orderbook.SetFingerprint()
+	return &orderbook
+}
+
+// BarBuilder aggregates trades into a bar
+type BarBuilder struct {
+	bar      *sharedent.Bar
+	notional float64
+}
+
+// NewBarBuilder creates a bar builder for a bar starting at the given time
+func NewBarBuilder(symbol string, assetClass types.AssetClass, timeFrame types.TimeFrame, start time.Time) *BarBuilder {
+	return &BarBuilder{
+		bar: &sharedent.Bar{
+			Symbol:      symbol,
+			Exchange:    DEFAULT_EXCHANGE,
+			TimestampNs: start.UnixNano(),
+			Source:      string(types.Synthetic),
+			AssetClass:  string(assetClass),
+			Timeframe:   string(timeFrame),
+		},
+	}
+}
+
+// Add adds a trade to the bar
+func (b *BarBuilder) Add(trade *sharedent.Trade) {
+	if b.bar.TradeCount == 0 {
+		b.bar.Open = trade.Price
+		b.bar.High = trade.Price
+		b.bar.Low = trade.Price
+	}
+	b.bar.High = math.Max(b.bar.High, trade.Price)
+	b.bar.Low = math.Min(b.bar.Low, trade.Price)
+	b.bar.Close = trade.Price
+	b.bar.Volume += trade.Size
+	b.bar.TradeCount++
+	b.notional += trade.Price * trade.Size
+}
+
+// Empty returns whether no trade was added to the bar
+func (b *BarBuilder) Empty() bool {
+	return b.bar.TradeCount == 0
+}
+
+// Build finalizes the bar
+func (b *BarBuilder) Build() *sharedent.Bar {
+	if b.bar.Volume > 0 {
+		b.bar.VWAP = b.notional / b.bar.Volume
+	}
+	b.bar.SetFingerprint()
+	return b.bar
+}
@@ -0,0 +1,127 @@
+package stream
+
+import (
+	"context"
+	"sync"
+	"time"
+	"tradingplatform/dataprovider/provider/synthetic"
+	"tradingplatform/shared/communication/producer"
+	sharedent "tradingplatform/shared/entities"
+	"tradingplatform/shared/types"
+)
+
+// Interval between two generated ticks of a feed
+var TICK_INTERVAL = 250 * time.Millisecond
+
+// A feed generates the stream data of a single symbol, all data types of a symbol are derived from
+// the same price path so that they stay consistent with each other
+type feed struct {
+	assetClass types.AssetClass
+	symbol     string
+	cancel     context.CancelFunc
+	dtypes     map[types.DataType]struct{}
+	lock       sync.Mutex
+}
+
+var feeds = make(map[string]*feed)
+var feedsLock sync.Mutex
+
+func feedKey(assetClass types.AssetClass, symbol string) string {
+	return string(assetClass) + "." + symbol
+}
+
+// Subscribe a data type of a symbol, starting the feed of the symbol if needed
+func subscribe(assetClass types.AssetClass, symbol string, dtype types.DataType) {
+	feedsLock.Lock()
+	defer feedsLock.Unlock()
+
+	key := feedKey(assetClass, symbol)
+	f, ok := feeds[key]
+	if !ok {
+		ctx, cancel := context.WithCancel(context.Background())
+		f = &feed{
+			assetClass: assetClass,
+			symbol:     symbol,
+			cancel:     cancel,
+			dtypes:     make(map[types.DataType]struct{}),
+		}
+		feeds[key] = f
+		go f.run(ctx)
+	}
+	f.lock.Lock()
+	f.dtypes[dtype] = struct{}{}
+	f.lock.Unlock()
+}
+
+// Unsubscribe a data type of a symbol, stopping the feed of the symbol if nothing is left
+func unsubscribe(assetClass types.AssetClass, symbol string, dtype types.DataType) {
+	feedsLock.Lock()
+	defer feedsLock.Unlock()
+
+	key := feedKey(assetClass, symbol)
+	f, ok := feeds[key]
+	if !ok {
+		return
+	}
+	f.lock.Lock()
+	delete(f.dtypes, dtype)
+	empty := len(f.dtypes) == 0
+	f.lock.Unlock()
+	if empty {
+		f.cancel()
+		delete(feeds, key)
+	}
+}
+
+func (f *feed) subscribed(dtype types.DataType) bool {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	_, ok := f.dtypes[dtype]
+	return ok
+}
+
+func (f *feed) publish(p sharedent.Payloader, dtype types.DataType) {
+	topic := synthetic.NewStreamTopic(f.assetClass, dtype, f.symbol).Generate()
+	msg := sharedent.GenerateMessage(p, dtype, topic)
+	producer.GetStreamHandler(msg.Topic).Ch <- msg
+}
+
+// Generate ticks until the context is cancelled, minute bars are published once they are complete
+func (f *feed) run(ctx context.Context) {
+	now := time.Now()
+	// The prices only depend on the configured seed and the symbol, the timestamps on the wall clock
+	market := synthetic.NewMarket(f.symbol, f.assetClass, synthetic.SymbolSeed(f.assetClass, f.symbol))
+	barStart := now.Truncate(time.Minute)
+	bar := synthetic.NewBarBuilder(f.symbol, f.assetClass, types.OneMin, barStart)
+
+	ticker := time.NewTicker(TICK_INTERVAL)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now = <-ticker.C:
+		}
+
+		if currentStart := now.Truncate(time.Minute); currentStart.After(barStart) {
+			if !bar.Empty() && f.subscribed(types.Bar) {
+				f.publish(bar.Build(), types.Bar)
+			}
+			barStart = currentStart
+			bar = synthetic.NewBarBuilder(f.symbol, f.assetClass, types.OneMin, barStart)
+		}
+
+		market.Step(TICK_INTERVAL)
+		trade := market.Trade(now)
+		bar.Add(trade)
+		if f.subscribed(types.Trades) {
+			f.publish(trade, types.Trades)
+		}
+		if f.subscribed(types.Quotes) {
+			f.publish(market.Quote(now), types.Quotes)
+		}
+		if f.assetClass == types.Crypto && f.subscribed(types.Orderbook) {
+			f.publish(market.Orderbook(now), types.Orderbook)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package stream
+
+import (
+	"tradingplatform/dataprovider/data"
+	"tradingplatform/dataprovider/provider"
+	"tradingplatform/dataprovider/provider/synthetic"
+	"tradingplatform/shared/communication/producer"
+	"tradingplatform/shared/logging"
+	"tradingplatform/shared/requests"
+	"tradingplatform/shared/types"
+)
+
+// Handle a synthetic stream add request
+func HandleSyntheticStreamAddRequest(req requests.StreamRequest) types.StreamResponse {
+	logging.Log().Debug().RawJSON("request", req.JSON()).Msg("adding synthetic stream")
+
+	dtypesHandled := []types.DataType{}
+	for _, dtype := range req.GetDataType() {
+		for _, symbol := range req.GetSymbol() {
+			subscribe(req.GetAssetClass(), symbol, dtype)
+		}
+		dtypesHandled = append(dtypesHandled, dtype)
+		data.AddDataProviderStreamForDType(req, dtype)
+	}
+
+	return provider.NewStreamResponseSourceAssetClass(
+		types.Success,
+		"Successfully added synthetic stream",
+		synthetic.GenerateJSONStreamTopicDict(req.GetAssetClass(), dtypesHandled, req.GetSymbol()),
+		nil,
+		types.Synthetic,
+		req.GetAssetClass(),
+	)
+}
+
+// Handle a synthetic stream remove request
+func HandleSyntheticStreamRemoveRequest(req requests.StreamRequest) types.StreamResponse {
+	logging.Log().Info().RawJSON("request", req.JSON()).Msg("removing synthetic stream")
+
+	dtypesHandled := []types.DataType{}
+	for _, dtype := range req.GetDataType() {
+		for _, symbol := range req.GetSymbol() {
+			unsubscribe(req.GetAssetClass(), symbol, dtype)
+			tTopic := synthetic.NewStreamTopic(req.GetAssetClass(), dtype, symbol).Generate()
+			producer.StopTopicHandler(tTopic)
+		}
+		dtypesHandled = append(dtypesHandled, dtype)
+		data.RemoveDataProviderStreamForDType(req, dtype)
+	}
+
+	return provider.NewStreamResponseSourceAssetClass(
+		types.Success,
+		"Successfully removed synthetic stream",
+		synthetic.GenerateJSONStreamTopicDict(req.GetAssetClass(), dtypesHandled, req.GetSymbol()),
+		nil,
+		types.Synthetic,
+		req.GetAssetClass(),
+	)
+}
+
+// Provide a response with the active synthetic streams
+func HandleSyntheticStreamGetRequest(req requests.StreamRequest) types.StreamResponse {
+	assetClass := req.GetAssetClass()
+	streams := data.GetDataProviderStreamsSourceAssetClass(types.Synthetic, assetClass)
+	dtypes := map[types.DataType]struct{}{}
+	symbols := map[string]struct{}{}
+	for _, stream := range streams {
+		dtypes[stream.DataType] = struct{}{}
+		symbols[stream.Symbol] = struct{}{}
+	}
+	dtypesSlice := make([]types.DataType, 0, len(dtypes))
+	for dtype := range dtypes {
+		dtypesSlice = append(dtypesSlice, dtype)
+	}
+	symbolsSlice := make([]string, 0, len(symbols))
+	for symbol := range symbols {
+		symbolsSlice = append(symbolsSlice, symbol)
+	}
+
+	return provider.NewStreamResponseSourceAssetClass(
+		types.Success,
+		"Successfully retrieved streams",
+		synthetic.GenerateJSONStreamTopicDict(assetClass, dtypesSlice, symbolsSlice),
+		nil,
+		types.Synthetic,
+		assetClass,
+	)
+}
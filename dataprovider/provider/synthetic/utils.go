@@ -0,0 +1,58 @@
+package synthetic
+
+import (
+	"encoding/json"
+	"tradingplatform/shared/logging"
+	"tradingplatform/shared/types"
+	"tradingplatform/shared/utils"
+)
+
+func NewStreamTopic(assetClass types.AssetClass, dataType types.DataType, symbol string) utils.Topic {
+	return utils.NewStreamTopic(types.DataProvider, types.Synthetic, assetClass, dataType, symbol)
+}
+
+func NewDataTopic(assetClass types.AssetClass, dataType types.DataType, symbol string, queueID string, queueCount int) utils.Topic {
+	return utils.NewDataTopic(types.DataProvider, types.Synthetic, assetClass, dataType, symbol, queueID, queueCount)
+}
+
+func NewBarDataTopic(assetClass types.AssetClass, timeFrame types.TimeFrame, symbol string, queueID string, queueCount int) utils.Topic {
+	return utils.NewBarDataTopic(types.DataProvider, types.Synthetic, assetClass, timeFrame, symbol, queueID, queueCount)
+}
+
+func GenerateJSONStreamTopicDict(assetClass types.AssetClass, dataTypes []types.DataType, symbols []string) string {
+	tmap := map[types.DataType][]string{}
+	for _, dataType := range dataTypes {
+		value := tmap[dataType]
+		for _, symbol := range symbols {
+			value = append(value, NewStreamTopic(assetClass, dataType, symbol).Generate())
+		}
+		tmap[dataType] = value
+	}
+	json, err := json.Marshal(tmap)
+	if err != nil {
+		logging.Log().Error().Str("assetClass", string(assetClass)).Strs("symbols", symbols).Err(err).Msg("generating topics")
+		return ""
+	}
+	return string(json)
+}
+
+// Data types that can be generated by the synthetic source
+func GetDataTypes(assetClass types.AssetClass) map[types.DataType]types.DataType {
+	switch assetClass {
+	case types.Stock:
+		return map[types.DataType]types.DataType{
+			"bar":    types.Bar,
+			"quotes": types.Quotes,
+			"trades": types.Trades,
+		}
+	case types.Crypto:
+		return map[types.DataType]types.DataType{
+			"bar":       types.Bar,
+			"orderbook": types.Orderbook,
+			"quotes":    types.Quotes,
+			"trades":    types.Trades,
+		}
+	default:
+		return nil
+	}
+}
@@ -17,6 +17,7 @@ func NewDataCmd() *cobra.Command {
 	}
 
 	dataCmd.AddCommand(NewDataGetCmd())
+	dataCmd.AddCommand(NewDataGapsCmd())
 
 	return &dataCmd
 }
@@ -58,7 +59,7 @@ func NewDataGetCmd() *cobra.Command {
 				return
 			}
 			var och chan types.DataResponse = make(chan types.DataResponse)
-			go handler.HandleDataRequest(dataRequest, och)
+			go handler.HandleDataRequest(cmd.Context(), dataRequest, och)
 
 			select {
 			case response := <-och:
@@ -81,9 +82,9 @@ func NewDataGetCmd() *cobra.Command {
 	dataGetCmd.Flags().StringP("account", "c", "",
 		"Account (not doing anything currently)")
 	dataGetCmd.Flags().Int64P("start-time", "b", 0,
-		"Start time for the data")
+		"Start time for the data (unix nanoseconds, seconds are also accepted)")
 	dataGetCmd.Flags().Int64P("end-time", "e", 0,
-		"End time for the data")
+		"End time for the data (unix nanoseconds, seconds are also accepted)")
 	dataGetCmd.Flags().StringP("time-frame", "f", "",
 		"Time frame (only for bar data)")
 	dataGetCmd.Flags().BoolP("no-confirm", "o", false,
@@ -91,3 +92,63 @@ func NewDataGetCmd() *cobra.Command {
 
 	return &dataGetCmd
 }
+
+// Find (and fill) missing bars
+func NewDataGapsCmd() *cobra.Command {
+	dataGapsCmd := cobra.Command{
+		Use:   "gaps",
+		Short: "Find bars missing from datastorage, and optionally fill them from the dataprovider.",
+
+		Run: func(cmd *cobra.Command, args []string) {
+			// Get flags
+			source, _ := cmd.Flags().GetString("source")
+			assetClass, _ := cmd.Flags().GetString("asset-class")
+			symbol, _ := cmd.Flags().GetString("symbol")
+			dataType, _ := cmd.Flags().GetString("data-type")
+			startTime, _ := cmd.Flags().GetInt64("start-time")
+			endTime, _ := cmd.Flags().GetInt64("end-time")
+			timeFrame, _ := cmd.Flags().GetString("time-frame")
+			cal, _ := cmd.Flags().GetString("calendar")
+			fill, _ := cmd.Flags().GetBool("fill")
+
+			// Generate data gaps request from flags
+			gapsRequest, err := requests.NewDataGapsRequestFromRaw(source,
+				assetClass,
+				symbol,
+				dataType,
+				startTime,
+				endTime,
+				timeFrame,
+				cal,
+				fill,
+				requests.DefaultForEmptyDataGapsRequest)
+
+			if err != nil {
+				cmd.Print(types.NewDataGapsError(err).Respond())
+				return
+			}
+			cmd.Print(handler.HandleDataGapsRequest(cmd.Context(), gapsRequest).Respond())
+		},
+	}
+
+	dataGapsCmd.Flags().StringP("source", "s", "",
+		"Source of the data")
+	dataGapsCmd.Flags().StringP("symbol", "y", "",
+		"Symbol")
+	dataGapsCmd.Flags().StringP("asset-class", "a", "",
+		"Asset class")
+	dataGapsCmd.Flags().StringP("data-type", "t", "",
+		"Type of data (bar or daily-bars)")
+	dataGapsCmd.Flags().Int64P("start-time", "b", 0,
+		"Start time of the range to scan (unix nanoseconds, seconds are also accepted)")
+	dataGapsCmd.Flags().Int64P("end-time", "e", 0,
+		"End time of the range to scan (unix nanoseconds, seconds are also accepted, exclusive)")
+	dataGapsCmd.Flags().StringP("time-frame", "f", "",
+		"Time frame of the bars")
+	dataGapsCmd.Flags().StringP("calendar", "l", "",
+		"Trading calendar (nyse, always), defaults to the calendar of the asset class")
+	dataGapsCmd.Flags().BoolP("fill", "i", false,
+		"Request the missing bars from the dataprovider and store them")
+
+	return &dataGapsCmd
+}
@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"tradingplatform/datastorage/handler"
+
+	"tradingplatform/shared/requests"
+	"tradingplatform/shared/types"
+
+	"github.com/spf13/cobra"
+)
+
+// Export stored data to files
+func NewExportCmd() *cobra.Command {
+	exportCmd := cobra.Command{
+		Use:   "export",
+		Short: "Export stored data to Parquet or CSV files.",
+		Long: `Write the stored data of symbols into one file per data type, symbol and day
+(<dataType>/<symbol>/<date>.parquet) under a directory of the export directory, along
+with a manifest of the files written.`,
+
+		Run: func(cmd *cobra.Command, args []string) {
+			// Get flags
+			source, _ := cmd.Flags().GetString("source")
+			assetClass, _ := cmd.Flags().GetString("asset-class")
+			symbols, _ := cmd.Flags().GetStringArray("symbols")
+			dataTypes, _ := cmd.Flags().GetStringArray("data-types")
+			startTime, _ := cmd.Flags().GetInt64("start-time")
+			endTime, _ := cmd.Flags().GetInt64("end-time")
+			timeFrame, _ := cmd.Flags().GetString("time-frame")
+			format, _ := cmd.Flags().GetString("format")
+			directory, _ := cmd.Flags().GetString("directory")
+
+			// Generate export request from flags
+			exportRequest, err := requests.NewExportRequestFromRaw(source,
+				assetClass,
+				symbols,
+				dataTypes,
+				startTime,
+				endTime,
+				timeFrame,
+				format,
+				directory,
+				requests.DefaultForEmptyExportRequest)
+
+			if err != nil {
+				cmd.Print(types.NewExportError(err).Respond())
+				return
+			}
+			cmd.Print(handler.HandleExportRequest(cmd.Context(), exportRequest).Respond())
+		},
+	}
+
+	exportCmd.Flags().StringP("source", "s", "",
+		"Source of the data")
+	exportCmd.Flags().StringArrayP("symbols", "y", []string{},
+		"Symbols")
+	exportCmd.Flags().StringP("asset-class", "a", "",
+		"Asset class")
+	exportCmd.Flags().StringArrayP("data-types", "t", []string{},
+		"Types of data (bar, daily-bars, trades, quotes, orderbook, raw-text or sentiment)")
+	exportCmd.Flags().Int64P("start-time", "b", 0,
+		"Start time of the data (unix nanoseconds, seconds are also accepted)")
+	exportCmd.Flags().Int64P("end-time", "e", 0,
+		"End time of the data (unix nanoseconds, seconds are also accepted)")
+	exportCmd.Flags().StringP("time-frame", "f", "",
+		"Time frame (only for bar data)")
+	exportCmd.Flags().String("format", "",
+		"Format of the files (parquet, csv or jsonl), parquet by default")
+	exportCmd.Flags().StringP("directory", "d", "",
+		"Directory the files are written to, relative to the export directory")
+
+	return &exportCmd
+}
@@ -16,6 +16,7 @@ func NewRootCmd() *cobra.Command {
 	rootCmd.AddCommand(NewQuitCommand())
 	rootCmd.AddCommand(NewStreamCommand())
 	rootCmd.AddCommand(NewDataCmd())
+	rootCmd.AddCommand(NewExportCmd())
 
 	return &rootCmd
 }
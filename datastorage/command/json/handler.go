@@ -7,6 +7,7 @@ import (
 
 	"tradingplatform/datastorage/handler"
 	"tradingplatform/shared/communication/command"
+	"tradingplatform/shared/logging"
 	"tradingplatform/shared/requests"
 	"tradingplatform/shared/types"
 )
@@ -19,6 +20,35 @@ func HandleJSONCommand(ctx context.Context, jsonStr string) string {
 		return types.NewError(err).Respond()
 	}
 
+	// Register cancel function
+	if jsonCommand.CancelKey != "" && jsonCommand.RootOperation != command.JSONOperationCancel {
+		cancelKey := jsonCommand.CancelKey
+		err := command.AddCancelFunc(cancelKey, ctx.Value(command.CancelKey{}).(context.CancelFunc))
+		if err != nil {
+			logging.Log().Error().Str("key", cancelKey).Err(err).Msg("adding cancel function")
+			return types.NewError(err).Respond()
+		}
+		logging.Log().Info().Str("key", cancelKey).Msg("added cancel function")
+		defer command.RemoveCancelFunc(cancelKey)
+	}
+
+	if jsonCommand.RootOperation == command.JSONOperationCancel {
+		cancelFunc, found := command.GetCancelFunc(jsonCommand.CancelKey)
+		cancelKey := jsonCommand.CancelKey
+		if !found {
+			err := fmt.Errorf("cancel function not found for key %s", cancelKey)
+			logging.Log().Error().Str("key", cancelKey).Err(err).Msg("getting cancel function")
+			return types.NewError(err).Respond()
+		}
+		cancelFunc()
+		logging.Log().Info().Str("key", cancelKey).Msg("called cancel function")
+		return types.NewResponse(
+			types.Success,
+			"Cancelled operation",
+			nil,
+		).Respond()
+	}
+
 	if jsonCommand.RootOperation == command.JSONOperationQuit {
 		command.GetCommandHandler().Cancel()
 		return types.NewResponse(
@@ -57,7 +87,7 @@ func HandleJSONCommand(ctx context.Context, jsonStr string) string {
 			return types.NewError(err).Respond()
 		}
 		var och chan types.DataResponse = make(chan types.DataResponse)
-		go handler.HandleDataRequest(validatedDataRequest, och)
+		go handler.HandleDataRequest(ctx, validatedDataRequest, och)
 
 		select {
 		case response := <-och:
@@ -66,5 +96,29 @@ func HandleJSONCommand(ctx context.Context, jsonStr string) string {
 			return ""
 		}
 	}
+	if jsonCommand.RootOperation == command.JSONOperationDataGaps {
+		var gapsRequest requests.DataGapsRequest
+		err := JSON.Unmarshal(jsonCommand.Request, &gapsRequest)
+		if err != nil {
+			return types.NewDataGapsError(err).Respond()
+		}
+		validatedGapsRequest, err := requests.NewDataGapsRequestFromExisting(&gapsRequest, requests.DefaultForEmptyDataGapsRequest)
+		if err != nil {
+			return types.NewDataGapsError(err).Respond()
+		}
+		return handler.HandleDataGapsRequest(ctx, validatedGapsRequest).Respond()
+	}
+	if jsonCommand.RootOperation == command.JSONOperationExport {
+		var exportRequest requests.ExportRequest
+		err := JSON.Unmarshal(jsonCommand.Request, &exportRequest)
+		if err != nil {
+			return types.NewExportError(err).Respond()
+		}
+		validatedExportRequest, err := requests.NewExportRequestFromExisting(&exportRequest, requests.DefaultForEmptyExportRequest)
+		if err != nil {
+			return types.NewExportError(err).Respond()
+		}
+		return handler.HandleExportRequest(ctx, validatedExportRequest).Respond()
+	}
 	return ""
 }
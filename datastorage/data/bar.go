@@ -21,6 +21,7 @@ type Bar struct {
 	Volume      float64
 	VWAP        float64
 	Timestamp   time.Time `gorm:"index"`
+	TimestampNs int64     `gorm:"index"`
 	TradeCount  uint64
 	Fingerprint string `gorm:"primaryKey"`
 	AssetClass  string `gorm:"not null"`
@@ -38,7 +39,8 @@ func BarFromEntity(entity *entities.Bar) Bar {
 		Close:       entity.Close,
 		Volume:      entity.Volume,
 		VWAP:        entity.VWAP,
-		Timestamp:   time.Unix(entity.Timestamp, 0),
+		Timestamp:   time.Unix(0, entity.TimestampNs),
+		TimestampNs: entity.TimestampNs,
 		TradeCount:  entity.TradeCount,
 		Fingerprint: entity.Fingerprint,
 		AssetClass:  entity.AssetClass,
@@ -57,7 +59,7 @@ func BarToEntity(bar Bar) *entities.Bar {
 		Close:       bar.Close,
 		Volume:      bar.Volume,
 		VWAP:        bar.VWAP,
-		Timestamp:   bar.Timestamp.Unix(),
+		TimestampNs: bar.TimestampNs,
 		TradeCount:  bar.TradeCount,
 		Fingerprint: bar.Fingerprint,
 		AssetClass:  bar.AssetClass,
@@ -81,7 +83,7 @@ func BarsToEntities(bars []Bar) []*entities.Bar {
 	return entities
 }
 
-func InsertBar(bar *entities.Bar) {
+func InsertBar(bar *entities.Bar) error {
 	dbBar := BarFromEntity(bar)
 	tx := DB.Clauses(clause.OnConflict{
 		DoNothing: true,
@@ -92,42 +94,39 @@ func InsertBar(bar *entities.Bar) {
 			RawJSON("bar", entities.GenerateJson(bar)).
 			Msg("inserting bar")
 	}
+	return tx.Error
 }
 
-func GetBarsFromRequest(symbol string, req requests.DataRequest) ([]*entities.Bar, error) {
-	return GetBars(string(req.GetSource()),
+// Key of a row of the bars in the order of their queries
+func barPageKey(bar Bar) PageKey {
+	return PageKey{Value: bar.TimestampNs, Fingerprint: bar.Fingerprint}
+}
+
+func GetBarsQueryFromRequest(symbol string, req requests.DataRequest) DataQuery[Bar, *entities.Bar] {
+	return GetBarsQuery(string(req.GetSource()),
 		symbol,
 		string(req.AssetClass),
 		req.GetStartTime(),
 		req.GetEndTime(),
-		string(req.TimeFrame)), nil
+		string(req.TimeFrame))
 }
 
-func GetBars(source string,
+func GetBarsQuery(source string,
 	symbol string,
 	assetClass string,
 	startTime int64,
 	endTime int64,
-	timeframe string) []*entities.Bar {
-	var bars []Bar
-	tx := DB.Where("source = ? AND symbol = ? AND timestamp >= ? AND timestamp <= ? AND asset_class = ? AND timeframe = ?",
-		source,
-		symbol,
-		time.Unix(startTime, 0),
-		time.Unix(endTime, 0),
-		assetClass,
-		timeframe).Order("timestamp").Find(&bars)
-	if tx.Error != nil {
-		logging.Log().Error().
-			Err(tx.Error).
-			Str("source", source).
-			Str("symbol", symbol).
-			Int64("startTime", startTime).
-			Int64("endTime", endTime).
-			Str("assetClass", assetClass).
-			Str("timeframe", timeframe).
-			Msg("getting bars")
+	timeframe string) DataQuery[Bar, *entities.Bar] {
+	return DataQuery[Bar, *entities.Bar]{
+		Query: DB.Model(&Bar{}).Where("source = ? AND symbol = ? AND timestamp_ns >= ? AND timestamp_ns <= ? AND asset_class = ? AND timeframe = ?",
+			source,
+			symbol,
+			startTime,
+			endTime,
+			assetClass,
+			timeframe),
+		OrderBy:    "timestamp_ns",
+		Key:        barPageKey,
+		ToEntities: BarsToEntities,
 	}
-
-	return BarsToEntities(bars)
 }
@@ -26,7 +26,8 @@ func DailyBarFromEntity(entity *entities.Bar) DailyBar {
 			Close:       entity.Close,
 			Volume:      entity.Volume,
 			VWAP:        entity.VWAP,
-			Timestamp:   time.Unix(entity.Timestamp, 0),
+			Timestamp:   time.Unix(0, entity.TimestampNs),
+			TimestampNs: entity.TimestampNs,
 			TradeCount:  entity.TradeCount,
 			Fingerprint: entity.Fingerprint,
 			AssetClass:  entity.AssetClass,
@@ -46,7 +47,7 @@ func DailyBarToEntity(bar DailyBar) *entities.Bar {
 		Close:       bar.Close,
 		Volume:      bar.Volume,
 		VWAP:        bar.VWAP,
-		Timestamp:   bar.Timestamp.Unix(),
+		TimestampNs: bar.TimestampNs,
 		TradeCount:  bar.TradeCount,
 		Fingerprint: bar.Fingerprint,
 		AssetClass:  bar.AssetClass,
@@ -70,7 +71,7 @@ func DailyBarsToEntities(bars []DailyBar) []*entities.Bar {
 	return entities
 }
 
-func InsertDailyBar(bar *entities.Bar) {
+func InsertDailyBar(bar *entities.Bar) error {
 	dbBar := DailyBarFromEntity(bar)
 	tx := DB.Clauses(clause.OnConflict{
 		DoNothing: true,
@@ -81,36 +82,39 @@ func InsertDailyBar(bar *entities.Bar) {
 			RawJSON("daily-bar", entities.GenerateJson(bar)).
 			Msg("inserting daily-bar")
 	}
+	return tx.Error
 }
 
-func GetDailyBarsFromRequest(symbol string, req requests.DataRequest) ([]*entities.Bar, error) {
-	return GetDailyBars(string(req.GetSource()),
+// Key of a row of the daily bars in the order of their queries
+func dailyBarPageKey(bar DailyBar) PageKey {
+	return PageKey{Value: bar.TimestampNs, Fingerprint: bar.Fingerprint}
+}
+
+func GetDailyBarsQueryFromRequest(symbol string, req requests.DataRequest) DataQuery[DailyBar, *entities.Bar] {
+	return GetDailyBarsQuery(string(req.GetSource()),
 		symbol,
 		string(req.AssetClass),
 		req.GetStartTime(),
 		req.GetEndTime(),
-		string(req.TimeFrame)), nil
+		string(req.TimeFrame))
 }
 
-func GetDailyBars(source string,
+func GetDailyBarsQuery(source string,
 	symbol string,
 	assetClass string,
 	startTime int64,
 	endTime int64,
-	timeframe string) []*entities.Bar {
-	var dBars []DailyBar
-	tx := DB.Where("source = ? AND symbol = ? AND timestamp >= ? AND timestamp <= ? AND asset_class = ? AND timeframe = ?",
-		source,
-		symbol,
-		time.Unix(startTime, 0),
-		time.Unix(endTime, 0),
-		assetClass,
-		timeframe).Order("timestamp").Find(&dBars)
-	if tx.Error != nil {
-		logging.Log().Error().
-			Err(tx.Error).
-			Msg("getting bars")
+	timeframe string) DataQuery[DailyBar, *entities.Bar] {
+	return DataQuery[DailyBar, *entities.Bar]{
+		Query: DB.Model(&DailyBar{}).Where("source = ? AND symbol = ? AND timestamp_ns >= ? AND timestamp_ns <= ? AND asset_class = ? AND timeframe = ?",
+			source,
+			symbol,
+			startTime,
+			endTime,
+			assetClass,
+			timeframe),
+		OrderBy:    "timestamp_ns",
+		Key:        dailyBarPageKey,
+		ToEntities: DailyBarsToEntities,
 	}
-
-	return DailyBarsToEntities(dBars)
 }
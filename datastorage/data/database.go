@@ -1,12 +1,14 @@
 package data
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
 	"strings"
 	"time"
 	"tradingplatform/shared/logging"
+	"tradingplatform/shared/tracing"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -120,6 +122,10 @@ func InitializeDatabase() (*gorm.DB, func()) {
 		panic(err)
 	}
 	logging.Log().Debug().Str("dsn", dsn).Msg("connected to database successfully")
+	if err := db.Use(tracing.GormPlugin()); err != nil {
+		logging.Log().Error().Err(err).Msg("failed to register the tracing of the database queries")
+		panic(err)
+	}
 
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -150,14 +156,25 @@ func InitializeDatabase() (*gorm.DB, func()) {
 		&DailyBar{},
 		&LLM{},
 		&Sentiment{},
+		&Indicator{},
+		&Order{},
+		&Fill{},
+		&Position{},
+		&SchemaVersion{},
+		&MigrationStep{},
+		&MigrationFingerprint{},
 	)
+	if err := migrate(db); err != nil {
+		logging.Log().Error().Err(err).Msg("failed to migrate the stored data")
+		panic(err)
+	}
 	DB = db
 
 	return db, cleanup
 }
 
 // Generic function to insert entities into the database in batches
-func InsertBatchEntity[I any](entities []I) {
+func InsertBatchEntity[I any](entities []I) error {
 	logging.Log().Debug().
 		Int("count", len(entities)).
 		Type("entity", entities[0]).
@@ -168,28 +185,23 @@ func InsertBatchEntity[I any](entities []I) {
 
 	if tx.Error != nil {
 		logging.Log().Error().Err(tx.Error).Msg("failed to insert batch of entities to db")
-		return
+		return tx.Error
 	}
 	logging.Log().Debug().
 		Int("count", len(entities)).
 		Type("entity", entities[0]).
 		Msg("finished inserting batch of entities to db")
+	return nil
 }
 
-func PaginateRequest[T any](tx *gorm.DB, _ T) []T {
-	pageSize := 65000
-	page := 1
-	ents := make([]T, 0)
-	for {
-		var batch []T
-		tx.Offset((page - 1) * pageSize).Limit(pageSize).Find(&batch)
-		ents = append(ents, batch...)
-		if len(batch) < pageSize {
-			break
-		}
-		page++
+// Ping the database, fails when the database is not initialized or does not answer
+func Ping(ctx context.Context) error {
+	if DB == nil {
+		return fmt.Errorf("database not initialized")
 	}
-
-	return ents
-
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
 }
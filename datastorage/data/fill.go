@@ -0,0 +1,90 @@
+package data
+
+import (
+	"time"
+
+	"tradingplatform/shared/entities"
+	"tradingplatform/shared/logging"
+)
+
+type Fill struct {
+	ID          string
+	OrderID     string `gorm:"index"`
+	Account     string `gorm:"index"`
+	Symbol      string
+	Source      string
+	AssetClass  string `gorm:"not null"`
+	Side        string
+	Quantity    float64
+	Price       float64
+	Commission  float64
+	Timestamp   time.Time `gorm:"index"`
+	TimestampNs int64     `gorm:"index"`
+	Fingerprint string    `gorm:"primaryKey"`
+	Broker      string
+}
+
+func FillFromEntity(entity *entities.Fill) Fill {
+	return Fill{
+		ID:          entity.ID,
+		OrderID:     entity.OrderID,
+		Account:     entity.Account,
+		Symbol:      entity.Symbol,
+		Source:      entity.Source,
+		AssetClass:  entity.AssetClass,
+		Side:        entity.Side,
+		Quantity:    entity.Quantity,
+		Price:       entity.Price,
+		Commission:  entity.Commission,
+		Timestamp:   time.Unix(0, entity.Timestamp),
+		TimestampNs: entity.Timestamp,
+		Fingerprint: entity.Fingerprint,
+		Broker:      entity.Broker,
+	}
+}
+
+func FillToEntity(fill Fill) *entities.Fill {
+	return &entities.Fill{
+		ID:          fill.ID,
+		OrderID:     fill.OrderID,
+		Account:     fill.Account,
+		Symbol:      fill.Symbol,
+		Source:      fill.Source,
+		AssetClass:  fill.AssetClass,
+		Side:        fill.Side,
+		Quantity:    fill.Quantity,
+		Price:       fill.Price,
+		Commission:  fill.Commission,
+		Timestamp:   fill.TimestampNs,
+		Fingerprint: fill.Fingerprint,
+		Broker:      fill.Broker,
+	}
+}
+
+func FillsToEntities(fills []Fill) []*entities.Fill {
+	entities := make([]*entities.Fill, len(fills))
+	for i, fill := range fills {
+		entities[i] = FillToEntity(fill)
+	}
+	return entities
+}
+
+func FillsFromEntities(entities []*entities.Fill) []Fill {
+	fills := make([]Fill, len(entities))
+	for i, entity := range entities {
+		fills[i] = FillFromEntity(entity)
+	}
+	return fills
+}
+
+func InsertFill(fill *entities.Fill) error {
+	dbFill := FillFromEntity(fill)
+	tx := DB.Create(dbFill)
+	if tx.Error != nil {
+		logging.Log().Error().
+			Err(tx.Error).
+			RawJSON("fill", entities.GenerateJson(fill)).
+			Msg("inserting fill")
+	}
+	return tx.Error
+}
@@ -0,0 +1,104 @@
+package data
+
+import (
+	"fmt"
+	"time"
+
+	"tradingplatform/shared/calendar"
+	"tradingplatform/shared/types"
+)
+
+// BarGap is a range of consecutive expected bars missing from a bar table
+type BarGap struct {
+	Start time.Time
+	End   time.Time
+	// Periods of the missing bars
+	Missing []calendar.Period
+}
+
+// GetBarTimestamps returns the timestamps of the bars of a table (bar or daily-bars) starting
+// between startTime (inclusive) and endTime (exclusive). Bars stored from the streams have no
+// timeframe, they are included for minute bars and daily bars
+func GetBarTimestamps(dtype types.DataType,
+	source string,
+	symbol string,
+	assetClass string,
+	startTime int64,
+	endTime int64,
+	timeframe string) ([]int64, error) {
+
+	var model any
+	timeframes := []string{timeframe}
+	switch dtype {
+	case types.Bar:
+		model = &Bar{}
+		if timeframe == string(types.OneMin) {
+			timeframes = append(timeframes, "")
+		}
+	case types.DailyBars:
+		model = &DailyBar{}
+		timeframes = append(timeframes, "")
+	default:
+		return nil, fmt.Errorf("invalid bar data type %s", dtype)
+	}
+
+	var timestamps []int64
+	tx := DB.Model(model).Where("source = ? AND symbol = ? AND timestamp_ns >= ? AND timestamp_ns < ? AND asset_class = ? AND timeframe IN ?",
+		source,
+		symbol,
+		startTime,
+		endTime,
+		assetClass,
+		timeframes).Order("timestamp_ns").Pluck("timestamp_ns", &timestamps)
+	return timestamps, tx.Error
+}
+
+// GetBarPeriod returns the period of the bar of a timeframe containing t. Bars of a single unit are
+// aligned as the providers align them, bars of several units as the resampled ones
+func GetBarPeriod(cal calendar.Calendar, timeFrame types.TimeFrame, t time.Time) (calendar.Period, error) {
+	if count, _, ok := types.ParseTimeFrame(timeFrame); ok && count > 1 {
+		return calendar.SessionBarPeriod(cal, timeFrame, t)
+	}
+	return calendar.BarPeriod(cal, timeFrame, t)
+}
+
+// FindBarGaps compares the timestamps of stored bars to the bars expected by a trading calendar
+// between start and end, and returns the ranges of consecutive missing bars
+func FindBarGaps(cal calendar.Calendar,
+	timeFrame types.TimeFrame,
+	start time.Time,
+	end time.Time,
+	timestamps []int64) ([]BarGap, error) {
+
+	var expected []calendar.Period
+	var err error
+	if count, _, ok := types.ParseTimeFrame(timeFrame); ok && count > 1 {
+		expected, err = calendar.ExpectedSessionBars(cal, timeFrame, start, end)
+	} else {
+		expected, err = calendar.ExpectedBars(cal, timeFrame, start, end)
+	}
+	if err != nil {
+		return nil, err
+	}
+	stored := make(map[int64]struct{}, len(timestamps))
+	for _, ts := range timestamps {
+		p, _ := GetBarPeriod(cal, timeFrame, time.Unix(0, ts))
+		stored[p.Start.UnixNano()] = struct{}{}
+	}
+
+	var gaps []BarGap
+	var current *BarGap
+	for _, p := range expected {
+		if _, ok := stored[p.Start.UnixNano()]; ok {
+			current = nil
+			continue
+		}
+		if current == nil {
+			gaps = append(gaps, BarGap{Start: p.Start})
+			current = &gaps[len(gaps)-1]
+		}
+		current.End = p.End
+		current.Missing = append(current.Missing, p)
+	}
+	return gaps, nil
+}
@@ -1,56 +1,209 @@
 package data
 
 import (
+	"context"
+
 	"tradingplatform/datastorage/utils"
+	"tradingplatform/shared/communication"
 	"tradingplatform/shared/communication/producer"
 	"tradingplatform/shared/entities"
 	"tradingplatform/shared/logging"
 	"tradingplatform/shared/types"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-// HandleDataFetch in a generic way to handle data fetches from the database
-func HandleDataFetch[T any,
+// DataQuery selects the rows of a table matching a data request. The rows are read in pages ordered by
+// a column and their fingerprint, each page continuing after the last row of the previous one. The
+// preloads are only applied when reading the rows
+type DataQuery[M any, V entities.FingerprintablePayloader] struct {
+	Query *gorm.DB
+	// Column the rows are ordered by, rows with the same value are ordered by fingerprint
+	OrderBy string
+	// Whether the rows are read in descending order
+	Descending bool
+	// Key of a row in the order of the query
+	Key        func(M) PageKey
+	Preload    func(*gorm.DB) *gorm.DB
+	ToEntities func([]M) []V
+}
+
+// Position of a row in the order of a query: the value of its order column and its fingerprint
+type PageKey struct {
+	Value       any
+	Fingerprint string
+}
+
+// WithContext returns the query run with a context, its spans are children of the span of the context
+func (q DataQuery[M, V]) WithContext(ctx context.Context) DataQuery[M, V] {
+	q.Query = q.Query.WithContext(ctx)
+	return q
+}
+
+// Exists returns whether the query selects any row
+func (q DataQuery[M, V]) Exists() (bool, error) {
+	var rows []M
+	tx := q.Query.Session(&gorm.Session{}).Limit(1).Find(&rows)
+	return len(rows) > 0, tx.Error
+}
+
+// Read a page of at most limit rows following the row with the key after, the first page if after is
+// nil. Returns the key of the last row of the page to read the next one
+func (q DataQuery[M, V]) Page(after *PageKey, limit int) ([]V, *PageKey, error) {
+	fingerprint := clause.Column{Table: clause.CurrentTable, Name: "fingerprint"}
+	tx := q.Query.Session(&gorm.Session{})
+	if after != nil {
+		op := ">"
+		if q.Descending {
+			op = "<"
+		}
+		tx = tx.Where("("+q.OrderBy+", ?) "+op+" (?, ?)", fingerprint, after.Value, after.Fingerprint)
+	}
+	if q.Preload != nil {
+		tx = tx.Scopes(q.Preload)
+	}
+	var rows []M
+	tx = tx.Order(clause.OrderByColumn{Column: clause.Column{Name: q.OrderBy, Raw: true}, Desc: q.Descending}).
+		Order(clause.OrderByColumn{Column: fingerprint, Desc: q.Descending}).
+		Limit(limit).
+		Find(&rows)
+	if tx.Error != nil {
+		return nil, nil, tx.Error
+	}
+	if len(rows) == 0 {
+		return nil, after, nil
+	}
+	key := q.Key(rows[len(rows)-1])
+	return q.ToEntities(rows), &key, nil
+}
+
+// Call f for each row selected by the query, reading pages of pageSize rows
+func (q DataQuery[M, V]) ForEach(pageSize int, f func(V)) error {
+	var key *PageKey
+	for {
+		page, next, err := q.Page(key, pageSize)
+		if err != nil {
+			return err
+		}
+		for _, entity := range page {
+			f(entity)
+		}
+		if len(page) < pageSize {
+			return nil
+		}
+		key = next
+	}
+}
+
+// Error of the requests no data matches
+func noDataError(symbol string) error {
+	return types.NewNoDataError(symbol)
+}
+
+// Generate the topic of the data queue answering a data request
+func newResponseTopic(symbol string,
+	dtype types.DataType, assetClass types.AssetClass,
+	timeFrame types.TimeFrame, count int) string {
+
+	queueID := producer.GenerateQueueID()
+	if dtype == types.Bar {
+		return utils.NewBarDataTopic(assetClass,
+			timeFrame, symbol, queueID, count).Generate()
+	}
+	return utils.NewDataTopic(assetClass,
+		dtype, symbol, queueID, count).Generate()
+}
+
+// HandleEntities returns a queue with entities that are already in memory, or an error if there is none
+func HandleEntities[V entities.Payloader](entitiesToSend []V,
+	symbol string,
+	dtype types.DataType, assetClass types.AssetClass,
+	timeFrame types.TimeFrame) (*producer.Queue, types.DataResponse) {
+
+	if len(entitiesToSend) == 0 {
+		return nil, types.NewDataError(noDataError(symbol))
+	}
+	responseTopic := newResponseTopic(symbol, dtype, assetClass, timeFrame, len(entitiesToSend))
+	messages := make([]*entities.Message, 0, len(entitiesToSend))
+	for _, entity := range entitiesToSend {
+		messages = append(messages, entities.GenerateMessage(entity, dtype, responseTopic))
+	}
+	return producer.NewQueue(responseTopic, messages), types.NewDataResponse(
+		types.Success,
+		"Successfully retrieved data",
+		nil,
+		responseTopic,
+	)
+}
+
+// HandleDataQuery returns a queue reading the rows selected by a query page by page while it is sent,
+// or an error if the query selects no row. The rows are not counted up front, the number of messages
+// in the response topic is only set when the first page holds all of them
+func HandleDataQuery[M any,
 	V entities.FingerprintablePayloader](
-	fun func(string, T) ([]V, error),
+	ctx context.Context,
+	query DataQuery[M, V],
 	symbol string,
-	req T,
 	dtype types.DataType, assetClass types.AssetClass,
-	timeFrame types.TimeFrame) (*[]*entities.Message, types.DataResponse) {
+	timeFrame types.TimeFrame) (*producer.Queue, types.DataResponse) {
 
-	result, err := fun(symbol, req)
+	log := logging.Log().With().
+		Str("symbol", symbol).
+		Str("dtype", string(dtype)).
+		Str("assetClass", string(assetClass)).
+		Str("timeFrame", string(timeFrame)).
+		Logger()
+	query = query.WithContext(ctx)
+	pageSize := communication.DEFAULT_QUEUE_PAGE_SIZE
+	pending, key, err := query.Page(nil, pageSize)
 	if err != nil {
-		logging.Log().Error().
-			Err(err).
-			Str("symbol", symbol).
-			Str("dtype", string(dtype)).
-			Str("assetClass", string(assetClass)).
-			Str("timeFrame", string(timeFrame)).
-			Msg("getting data from database")
+		log.Error().Err(err).Msg("getting data from database")
 		return nil, types.NewDataError(err)
 	}
-	var responseTopic = ""
-	var queueID = ""
-
-	var messages []*entities.Message
-	queueID = producer.GenerateQueueID()
-	for _, entity := range result {
-		messages = append(messages,
-			entities.GenerateMessage(entity,
-				dtype,
-				responseTopic))
+	if len(pending) == 0 {
+		return nil, types.NewDataError(noDataError(symbol))
 	}
+	done := len(pending) < pageSize
+	count := 0
+	if done {
+		count = len(pending)
+	}
+	responseTopic := newResponseTopic(symbol, dtype, assetClass, timeFrame, count)
 
-	if dtype == types.Bar {
-		responseTopic = utils.NewBarDataTopic(assetClass,
-			timeFrame, symbol, queueID, len(messages)).Generate()
-	} else {
-		responseTopic = utils.NewDataTopic(assetClass,
-			dtype, symbol, queueID, len(messages)).Generate()
+	toMessages := func(page []V) []*entities.Message {
+		messages := make([]*entities.Message, 0, len(page))
+		for _, entity := range page {
+			messages = append(messages, entities.GenerateMessage(entity, dtype, responseTopic))
+		}
+		return messages
 	}
-	for _, message := range messages {
-		message.Topic = responseTopic
+	queue := &producer.Queue{
+		Topic: responseTopic,
+		Count: count,
+		NextPage: func(limit int) ([]*entities.Message, error) {
+			if len(pending) > 0 {
+				if limit > len(pending) {
+					limit = len(pending)
+				}
+				page := pending[:limit]
+				pending = pending[limit:]
+				return toMessages(page), nil
+			}
+			if done {
+				return nil, nil
+			}
+			page, next, err := query.Page(key, limit)
+			if err != nil {
+				log.Error().Err(err).Str("topic", responseTopic).Msg("getting data page from database")
+				return nil, err
+			}
+			key = next
+			done = len(page) < limit
+			return toMessages(page), nil
+		},
 	}
-	return &messages, types.NewDataResponse(
+	return queue, types.NewDataResponse(
 		types.Success,
 		"Successfully retrieved data",
 		nil,
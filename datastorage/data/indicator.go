@@ -0,0 +1,78 @@
+package data
+
+import (
+	"time"
+
+	"tradingplatform/shared/entities"
+	"tradingplatform/shared/logging"
+)
+
+type Indicator struct {
+	Symbol      string
+	Source      string
+	Name        string `gorm:"index"`
+	Value       float64
+	Components  map[string]float64 `gorm:"serializer:json"`
+	Timestamp   time.Time          `gorm:"index"`
+	TimestampNs int64              `gorm:"index"`
+	Fingerprint string             `gorm:"primaryKey"`
+	AssetClass  string             `gorm:"not null"`
+	Timeframe   string             `gorm:"not null"`
+}
+
+func IndicatorFromEntity(entity *entities.Indicator) Indicator {
+	return Indicator{
+		Symbol:      entity.Symbol,
+		Source:      entity.Source,
+		Name:        entity.Name,
+		Value:       entity.Value,
+		Components:  entity.Components,
+		Timestamp:   time.Unix(0, entity.Timestamp),
+		TimestampNs: entity.Timestamp,
+		Fingerprint: entity.Fingerprint,
+		AssetClass:  entity.AssetClass,
+		Timeframe:   entity.Timeframe,
+	}
+}
+
+func IndicatorToEntity(indicator Indicator) *entities.Indicator {
+	return &entities.Indicator{
+		Symbol:      indicator.Symbol,
+		Source:      indicator.Source,
+		Name:        indicator.Name,
+		Value:       indicator.Value,
+		Components:  indicator.Components,
+		Timestamp:   indicator.TimestampNs,
+		Fingerprint: indicator.Fingerprint,
+		AssetClass:  indicator.AssetClass,
+		Timeframe:   indicator.Timeframe,
+	}
+}
+
+func IndicatorsToEntities(indicators []Indicator) []*entities.Indicator {
+	entities := make([]*entities.Indicator, len(indicators))
+	for i, indicator := range indicators {
+		entities[i] = IndicatorToEntity(indicator)
+	}
+	return entities
+}
+
+func IndicatorsFromEntities(entities []*entities.Indicator) []Indicator {
+	indicators := make([]Indicator, len(entities))
+	for i, entity := range entities {
+		indicators[i] = IndicatorFromEntity(entity)
+	}
+	return indicators
+}
+
+func InsertIndicator(indicator *entities.Indicator) error {
+	dbIndicator := IndicatorFromEntity(indicator)
+	tx := DB.Create(dbIndicator)
+	if tx.Error != nil {
+		logging.Log().Error().
+			Err(tx.Error).
+			RawJSON("indicator", entities.GenerateJson(indicator)).
+			Msg("inserting indicator")
+	}
+	return tx.Error
+}
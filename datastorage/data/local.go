@@ -4,8 +4,9 @@ import (
 	"tradingplatform/shared/data"
 )
 
-// InitializeDataStorageLocalDatabase initializes the local database and returns a function to close the database.
-func InitializeDataStorageLocalDatabase() func() {
-	_, cancel := data.InitializeLocalDatabase(&data.SubscribedTopic{})
+// InitializeDataStorageLocalDatabase initializes the local database, in memory without path, and returns a
+// function to close the database.
+func InitializeDataStorageLocalDatabase(path string) func() {
+	_, cancel := data.InitializeLocalDatabase(path, &data.SubscribedTopic{})
 	return cancel
 }
@@ -5,9 +5,9 @@ type Log struct {
 }
 
 // TODO: Handle errors on insertions
-func InsertLog(message []byte) {
+func InsertLog(message []byte) error {
 	data := Log{
 		Message: string(message),
 	}
-	DB.Create(&data).Commit()
+	return DB.Create(&data).Error
 }
@@ -13,6 +13,7 @@ type LULD struct {
 	LimitDownPrice float64
 	Indicator      string
 	Timestamp      time.Time `gorm:"index"`
+	TimestampNs    int64     `gorm:"index"`
 	Tape           string
 	Fingerprint    string
 	Source         string
@@ -25,7 +26,8 @@ func LULDFromEntity(entity *entities.LULD) LULD {
 		LimitUpPrice:   entity.LimitUpPrice,
 		LimitDownPrice: entity.LimitDownPrice,
 		Indicator:      entity.Indicator,
-		Timestamp:      time.Unix(entity.Timestamp, 0),
+		Timestamp:      time.Unix(0, entity.TimestampNs),
+		TimestampNs:    entity.TimestampNs,
 		Tape:           entity.Tape,
 		Fingerprint:    entity.Fingerprint,
 		Source:         entity.Source,
@@ -39,7 +41,7 @@ func LULDToEntity(luld LULD) *entities.LULD {
 		LimitUpPrice:   luld.LimitUpPrice,
 		LimitDownPrice: luld.LimitDownPrice,
 		Indicator:      luld.Indicator,
-		Timestamp:      luld.Timestamp.Unix(),
+		TimestampNs:    luld.TimestampNs,
 		Tape:           luld.Tape,
 		Fingerprint:    luld.Fingerprint,
 		Source:         luld.Source,
@@ -63,15 +65,20 @@ func LULDsFromEntities(entities []*entities.LULD) []LULD {
 	return lulds
 }
 
-func GetLULDFromRequest(symbol string, req requests.DataRequest) ([]*entities.LULD, error) {
-	return GetLULD(string(req.GetSource()),
+// Key of a row of the LULDs in the order of their queries
+func luldPageKey(luld LULD) PageKey {
+	return PageKey{Value: luld.TimestampNs, Fingerprint: luld.Fingerprint}
+}
+
+func GetLULDQueryFromRequest(symbol string, req requests.DataRequest) DataQuery[LULD, *entities.LULD] {
+	return GetLULDQuery(string(req.GetSource()),
 		symbol,
 		string(req.AssetClass),
 		req.GetStartTime(),
-		req.GetEndTime()), nil
+		req.GetEndTime())
 }
 
-func InsertLULD(luld *entities.LULD) {
+func InsertLULD(luld *entities.LULD) error {
 	dbLULD := LULDFromEntity(luld)
 	tx := DB.Create(dbLULD)
 	if tx.Error != nil {
@@ -80,33 +87,24 @@ func InsertLULD(luld *entities.LULD) {
 			RawJSON("luld", entities.GenerateJson(luld)).
 			Msg("inserting LULD")
 	}
+	return tx.Error
 }
 
-func GetLULD(source string,
+func GetLULDQuery(source string,
 	symbol string,
 	assetClass string,
 	startTime int64,
-	endTime int64) []*entities.LULD {
-
-	var lulds []LULD
-	tx := DB.Where("source = ? AND symbol = ? AND timestamp >= ? AND timestamp <= ? AND asset_class = ?",
-		source,
-		symbol,
-		time.Unix(startTime, 0),
-		time.Unix(endTime, 0),
-		assetClass,
-	).Find(&lulds)
-
-	if tx.Error != nil {
-		logging.Log().Error().
-			Err(tx.Error).
-			Str("source", source).
-			Str("symbol", symbol).
-			Int64("startTime", startTime).
-			Int64("endTime", endTime).
-			Str("assetClass", assetClass).
-			Msg("getting LULD from database")
-		return nil
+	endTime int64) DataQuery[LULD, *entities.LULD] {
+	return DataQuery[LULD, *entities.LULD]{
+		Query: DB.Model(&LULD{}).Where("source = ? AND symbol = ? AND timestamp_ns >= ? AND timestamp_ns <= ? AND asset_class = ?",
+			source,
+			symbol,
+			startTime,
+			endTime,
+			assetClass,
+		),
+		OrderBy:    "timestamp_ns",
+		Key:        luldPageKey,
+		ToEntities: LULDsToEntities,
 	}
-	return LULDsToEntities(lulds)
 }
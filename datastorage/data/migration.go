@@ -0,0 +1,333 @@
+package data
+
+import (
+	"fmt"
+	"time"
+	"tradingplatform/shared/logging"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Version of the stored data, a row is recorded for each migration applied to it
+type SchemaVersion struct {
+	Version   int `gorm:"primaryKey;autoIncrement:false"`
+	AppliedAt time.Time
+}
+
+// A fingerprint of a row left to migrate by a step of a migration. The fingerprints of a table are
+// staged before its rows are migrated, rows stored again under a new fingerprint are not migrated twice
+type MigrationFingerprint struct {
+	Step        string `gorm:"primaryKey"`
+	Fingerprint string `gorm:"primaryKey"`
+}
+
+// A step of the migration being applied whose fingerprints were staged, the step is done once none
+// of its fingerprints is left. The steps are deleted once the migration is recorded
+type MigrationStep struct {
+	Step string `gorm:"primaryKey"`
+}
+
+// Migrations of the stored data in the order they are applied, the version of a migration is its
+// position starting at 1. Migrations are only ever appended. A migration commits its progress batch
+// by batch and continues where it stopped when it is applied again after an interruption
+var migrations = []func(tx *gorm.DB) error{
+	migrateTimestampsToNanoseconds,
+}
+
+// Key of the advisory lock held while migrating, datastorages starting together migrate one at a time
+const migrationLockKey = 7166101
+
+// Number of rows read at once by the migrations
+var migrationBatchSize = 1000
+
+// Apply the migrations that were not applied to the stored data yet and record their versions. The
+// advisory lock is held on a single connection for the whole migration
+func migrate(db *gorm.DB) error {
+	return db.Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", migrationLockKey).Error; err != nil {
+			return err
+		}
+		defer conn.Exec("SELECT pg_advisory_unlock(?)", migrationLockKey)
+		var current int
+		if err := conn.Model(&SchemaVersion{}).Select("COALESCE(MAX(version), 0)").Scan(&current).Error; err != nil {
+			return err
+		}
+		for i := current; i < len(migrations); i++ {
+			version := i + 1
+			logging.Log().Info().Int("version", version).Msg("migrating stored data")
+			if err := migrations[i](conn); err != nil {
+				return fmt.Errorf("migrating stored data to version %d: %w", version, err)
+			}
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Where("1 = 1").Delete(&MigrationStep{}).Error; err != nil {
+					return err
+				}
+				return tx.Create(&SchemaVersion{Version: version, AppliedAt: time.Now()}).Error
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// A table referencing the fingerprints of the rows of another table
+type childTable struct {
+	model  any
+	column string
+	// The fingerprint is part of the primary key of the children, the children of a row that was
+	// stored again are dropped with the old row rather than moved
+	keyed bool
+}
+
+// Name of the table of a model
+func tableName(tx *gorm.DB, model any) (string, error) {
+	stmt := &gorm.Statement{DB: tx}
+	if err := stmt.Parse(model); err != nil {
+		return "", err
+	}
+	return stmt.Schema.Table, nil
+}
+
+// Stage the fingerprints of the rows of a table for a step of a migration, unless they were staged by
+// an interrupted run of the migration
+func stageFingerprints(db *gorm.DB, step string, table string) error {
+	staging, err := tableName(db, &MigrationFingerprint{})
+	if err != nil {
+		return err
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		var staged int64
+		if err := tx.Model(&MigrationStep{}).Where("step = ?", step).Count(&staged).Error; err != nil {
+			return err
+		}
+		if staged > 0 {
+			return nil
+		}
+		err := tx.Exec("INSERT INTO "+staging+" (step, fingerprint) SELECT DISTINCT CAST(? AS TEXT), fingerprint FROM "+
+			table+" WHERE fingerprint IS NOT NULL", step).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(&MigrationStep{Step: step}).Error
+	})
+}
+
+// Migrate the rows of a table batch by batch, each batch is committed with the removal of its staged
+// fingerprints. Only the rows staged before the first batch are migrated
+func migrateInBatches[R any](db *gorm.DB, preloads []string, migrateRow func(tx *gorm.DB, table string, row *R) error) error {
+	table, err := tableName(db, new(R))
+	if err != nil {
+		return err
+	}
+	step := "fingerprints:" + table
+	if err := stageFingerprints(db, step, table); err != nil {
+		return err
+	}
+	var migrated int
+	for {
+		var fingerprints []string
+		err := db.Model(&MigrationFingerprint{}).
+			Where("step = ?", step).
+			Order("fingerprint").
+			Limit(migrationBatchSize).
+			Pluck("fingerprint", &fingerprints).Error
+		if err != nil {
+			return err
+		}
+		if len(fingerprints) == 0 {
+			break
+		}
+		err = db.Transaction(func(tx *gorm.DB) error {
+			query := tx
+			for _, preload := range preloads {
+				query = query.Preload(preload)
+			}
+			var rows []R
+			if err := query.Where("fingerprint IN ?", fingerprints).Find(&rows).Error; err != nil {
+				return err
+			}
+			for i := range rows {
+				if err := migrateRow(tx, table, &rows[i]); err != nil {
+					return err
+				}
+			}
+			migrated += len(rows)
+			return tx.Where("step = ? AND fingerprint IN ?", step, fingerprints).Delete(&MigrationFingerprint{}).Error
+		})
+		if err != nil {
+			return err
+		}
+		logging.Log().Debug().Str("table", table).Int("rows", migrated).Msg("recomputing fingerprints")
+	}
+	logging.Log().Info().Str("table", table).Int("rows", migrated).Msg("recomputed fingerprints")
+	return nil
+}
+
+// Recompute the fingerprints of the rows of a table, rekey sets the fingerprint (and the nanosecond
+// timestamp) of a row and returns its previous and new fingerprints
+func recomputeFingerprints[R any](db *gorm.DB, preloads []string, children []childTable, rekey func(row *R) (string, string)) error {
+	return migrateInBatches(db, preloads, func(tx *gorm.DB, table string, row *R) error {
+		oldFingerprint, newFingerprint := rekey(row)
+		return moveRow(tx, table, row, oldFingerprint, newFingerprint, children)
+	})
+}
+
+// Store a row under its recomputed fingerprint with its children and delete it under the old one. A row
+// stored again with the new fingerprint is kept as is
+func moveRow(tx *gorm.DB, table string, row any, oldFingerprint string, newFingerprint string, children []childTable) error {
+	if newFingerprint == oldFingerprint {
+		return tx.Omit(clause.Associations).Save(row).Error
+	}
+	var stored int64
+	if err := tx.Table(table).Where("fingerprint = ?", newFingerprint).Count(&stored).Error; err != nil {
+		return err
+	}
+	if stored == 0 {
+		if err := tx.Omit(clause.Associations).Create(row).Error; err != nil {
+			return err
+		}
+	}
+	for _, child := range children {
+		childName, err := tableName(tx, child.model)
+		if err != nil {
+			return err
+		}
+		if stored > 0 && child.keyed {
+			err = tx.Exec("DELETE FROM "+childName+" WHERE "+child.column+" = ?", oldFingerprint).Error
+		} else {
+			err = tx.Exec("UPDATE "+childName+" SET "+child.column+" = ? WHERE "+child.column+" = ?",
+				newFingerprint, oldFingerprint).Error
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Exec("DELETE FROM "+table+" WHERE fingerprint = ?", oldFingerprint).Error
+}
+
+// Recompute the fingerprints of the market data and news stored before the timestamps were carried
+// in nanoseconds, and fill the nanosecond timestamps of the rows that only have second precision. The
+// fingerprint of a row is the one of the entity it is read as
+func migrateTimestampsToNanoseconds(db *gorm.DB) error {
+	nanoseconds := func(timestampNs *int64, timestamp time.Time) {
+		if *timestampNs == 0 {
+			*timestampNs = timestamp.UnixNano()
+		}
+	}
+	err := recomputeFingerprints(db, nil, nil, func(bar *Bar) (string, string) {
+		old := bar.Fingerprint
+		nanoseconds(&bar.TimestampNs, bar.Timestamp)
+		entity := BarToEntity(*bar)
+		entity.Fingerprint = ""
+		entity.SetFingerprint()
+		bar.Fingerprint = entity.Fingerprint
+		return old, bar.Fingerprint
+	})
+	if err != nil {
+		return err
+	}
+	err = recomputeFingerprints(db, nil, nil, func(bar *DailyBar) (string, string) {
+		old := bar.Fingerprint
+		nanoseconds(&bar.TimestampNs, bar.Timestamp)
+		entity := DailyBarToEntity(*bar)
+		entity.Fingerprint = ""
+		entity.SetFingerprint()
+		bar.Fingerprint = entity.Fingerprint
+		return old, bar.Fingerprint
+	})
+	if err != nil {
+		return err
+	}
+	err = recomputeFingerprints(db, []string{"Conditions"},
+		[]childTable{{model: &TradeCondition{}, column: "trade_fingerprint", keyed: true}},
+		func(trade *Trade) (string, string) {
+			old := trade.Fingerprint
+			nanoseconds(&trade.TimestampNs, trade.Timestamp)
+			entity := TradeToEntity(*trade)
+			entity.Fingerprint = ""
+			entity.SetFingerprint()
+			trade.Fingerprint = entity.Fingerprint
+			return old, trade.Fingerprint
+		})
+	if err != nil {
+		return err
+	}
+	err = recomputeFingerprints(db, []string{"Conditions"},
+		[]childTable{{model: &QuoteCondition{}, column: "trade_fingerprint", keyed: true}},
+		func(quote *Quote) (string, string) {
+			old := quote.Fingerprint
+			nanoseconds(&quote.TimestampNs, quote.Timestamp)
+			entity := QuoteToEntity(*quote)
+			entity.Fingerprint = ""
+			entity.SetFingerprint()
+			quote.Fingerprint = entity.Fingerprint
+			return old, quote.Fingerprint
+		})
+	if err != nil {
+		return err
+	}
+	err = recomputeFingerprints(db, []string{"Asks", "Bids"},
+		[]childTable{
+			{model: &AsksOrderbookEntry{}, column: "orderbook_fingerprint", keyed: true},
+			{model: &BidsOrderbookEntry{}, column: "orderbook_fingerprint", keyed: true},
+		},
+		func(orderbook *Orderbook) (string, string) {
+			old := orderbook.Fingerprint
+			nanoseconds(&orderbook.TimestampNs, orderbook.Timestamp)
+			entity := OrderbookToEntity(*orderbook)
+			entity.Fingerprint = ""
+			entity.SetFingerprint()
+			orderbook.Fingerprint = entity.Fingerprint
+			return old, orderbook.Fingerprint
+		})
+	if err != nil {
+		return err
+	}
+	err = recomputeFingerprints(db, nil, nil, func(status *TradingStatus) (string, string) {
+		old := status.Fingerprint
+		nanoseconds(&status.TimestampNs, status.Timestamp)
+		entity := TradingStatusToEntity(*status)
+		entity.Fingerprint = ""
+		entity.SetFingerprint()
+		status.Fingerprint = entity.Fingerprint
+		return old, status.Fingerprint
+	})
+	if err != nil {
+		return err
+	}
+	// The sentiments of news are not part of their fingerprint
+	err = recomputeFingerprints(db, []string{"Symbols"},
+		[]childTable{
+			{model: &NewsSymbol{}, column: "news_fingerprint", keyed: true},
+			{model: &Sentiment{}, column: "news_fingerprint"},
+		},
+		func(news *News) (string, string) {
+			old := news.Fingerprint
+			entity := NewsToEntity(*news)
+			entity.Fingerprint = ""
+			entity.SetFingerprint()
+			news.Fingerprint = entity.Fingerprint
+			return old, news.Fingerprint
+		})
+	if err != nil {
+		return err
+	}
+	return migrateLULDs(db, nanoseconds)
+}
+
+// LULDs have no primary key, their rows are updated in place
+func migrateLULDs(db *gorm.DB, nanoseconds func(*int64, time.Time)) error {
+	return migrateInBatches(db, nil, func(tx *gorm.DB, _ string, luld *LULD) error {
+		old := luld.Fingerprint
+		nanoseconds(&luld.TimestampNs, luld.Timestamp)
+		entity := LULDToEntity(*luld)
+		entity.Fingerprint = ""
+		entity.SetFingerprint()
+		return tx.Model(&LULD{}).
+			Where("fingerprint = ? AND timestamp = ?", old, luld.Timestamp).
+			Updates(map[string]any{"fingerprint": entity.Fingerprint, "timestamp_ns": luld.TimestampNs}).Error
+	})
+}
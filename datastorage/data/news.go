@@ -52,7 +52,7 @@ type LLM struct {
 
 func SentimentFromEntity(entity *entities.NewsSentiment) Sentiment {
 	return Sentiment{
-		Timestamp:                time.Unix(entity.Timestamp, 0),
+		Timestamp:                time.Unix(0, entity.TimestampNs),
 		Sentiment:                entity.Sentiment,
 		SentimentAnalysisProcess: entity.SentimentAnalysisProcess,
 		Fingerprint:              entity.Fingerprint,
@@ -78,8 +78,8 @@ func NewsFromEntity(entity *entities.News) News {
 	return News{
 		Id:                 entity.Id,
 		Author:             entity.Author,
-		CreatedAtTimestamp: time.Unix(entity.CreatedAt, 0),
-		UpdatedAtTimestamp: time.Unix(entity.UpdatedAt, 0),
+		CreatedAtTimestamp: time.Unix(0, entity.CreatedAtNs),
+		UpdatedAtTimestamp: time.Unix(0, entity.UpdatedAtNs),
 		Headline:           entity.Headline,
 		Summary:            entity.Summary,
 		Content:            entity.Content,
@@ -93,7 +93,7 @@ func NewsFromEntity(entity *entities.News) News {
 
 func SentimentToEntity(sentiment Sentiment) *entities.NewsSentiment {
 	return &entities.NewsSentiment{
-		Timestamp:                sentiment.Timestamp.Unix(),
+		TimestampNs:              sentiment.Timestamp.UnixNano(),
 		Sentiment:                sentiment.Sentiment,
 		SentimentAnalysisProcess: sentiment.SentimentAnalysisProcess,
 		Fingerprint:              sentiment.Fingerprint,
@@ -119,8 +119,8 @@ func NewsToEntity(news News) *entities.News {
 	return &entities.News{
 		Id:          news.Id,
 		Author:      news.Author,
-		CreatedAt:   news.CreatedAtTimestamp.Unix(),
-		UpdatedAt:   news.UpdatedAtTimestamp.Unix(),
+		CreatedAtNs: news.CreatedAtTimestamp.UnixNano(),
+		UpdatedAtNs: news.UpdatedAtTimestamp.UnixNano(),
 		Headline:    news.Headline,
 		Summary:     news.Summary,
 		Content:     news.Content,
@@ -157,23 +157,25 @@ func newsContainsSentiment(news News, sentiment Sentiment) bool {
 	return false
 }
 
-func InsertNewsWithSentiment(news *entities.News) {
+func InsertNewsWithSentiment(news *entities.News) error {
 	dbNews := NewsFromEntity(news)
 
 	var existingNews News
 	if err := DB.Where("fingerprint = ?", dbNews.Fingerprint).Preload("Sentiment").First(&existingNews).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			InsertNews(news)
+			if err := InsertNews(news); err != nil {
+				return err
+			}
 		} else {
 			logging.Log().Error().
 				Err(err).
 				RawJSON("news", entities.GenerateJson(news)).
 				Msg("finding news")
-			return
+			return err
 		}
 	}
 
-	DB.Transaction(func(tx *gorm.DB) error {
+	return DB.Transaction(func(tx *gorm.DB) error {
 		for _, sentiment := range dbNews.Sentiment {
 			sentiment.NewsFingerprint = existingNews.Fingerprint
 			if err := tx.Clauses(clause.OnConflict{
@@ -196,17 +198,19 @@ func InsertNewsWithSentiment(news *entities.News) {
 	})
 }
 
-func InsertNews(news *entities.News) {
+func InsertNews(news *entities.News) error {
 	dbNews := NewsFromEntity(news)
 	if err := DB.Create(&dbNews).Error; err != nil {
 		logging.Log().Error().
 			Err(err).
 			RawJSON("news", entities.GenerateJson(news)).
 			Msg("inserting news")
+		return err
 	}
+	return nil
 }
 
-func InsertBatchNewsWithSentiment(news []News) {
+func InsertBatchNewsWithSentiment(news []News) error {
 	var nonExistingNews []News
 	log := logging.Log().With().Int("news", len(news)).Logger()
 	log.Debug().Msg("started inserting batch news with sentiment")
@@ -220,12 +224,15 @@ func InsertBatchNewsWithSentiment(news []News) {
 					Err(err).
 					RawJSON("news", entities.GenerateJson(NewsToEntity(n))).
 					Msg("finding news")
+				return err
 			}
 		}
 	}
 	// Insert all non-existing news
 	if len(nonExistingNews) > 0 {
-		InsertBatchNews(nonExistingNews)
+		if err := InsertBatchNews(nonExistingNews); err != nil {
+			return err
+		}
 	}
 
 	var allSentiments []Sentiment
@@ -239,7 +246,7 @@ func InsertBatchNewsWithSentiment(news []News) {
 		}
 	}
 
-	DB.Transaction(func(tx *gorm.DB) error {
+	err := DB.Transaction(func(tx *gorm.DB) error {
 		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(allLLMs, 3000).Error; err != nil {
 			logging.Log().Error().
 				Err(err).
@@ -254,11 +261,14 @@ func InsertBatchNewsWithSentiment(news []News) {
 		}
 		return nil
 	})
+	if err != nil {
+		return err
+	}
 	log.Debug().Msg("finished inserting batch news with sentiment")
-
+	return nil
 }
 
-func InsertBatchNews(news []News) {
+func InsertBatchNews(news []News) error {
 	logging.Log().Debug().Int("count", len(news)).Type("entity", news[0]).Msg("started inserting batch of news to db")
 	tx := DB.Clauses(clause.OnConflict{
 		DoNothing: true,
@@ -266,64 +276,81 @@ func InsertBatchNews(news []News) {
 
 	if tx.Error != nil {
 		logging.Log().Error().Err(tx.Error).Msg("failed to insert batch of entities to db")
-		return
+		return tx.Error
 	}
 	logging.Log().Debug().Int("count", len(news)).Type("entity", news[0]).Msg("finished inserting batch of news to db")
+	return nil
 }
 
-func GetNewsFromDataRequest(symbol string, req requests.DataRequest) ([]*entities.News, error) {
+func GetNewsQueryFromDataRequest(symbol string, req requests.DataRequest) DataQuery[News, *entities.News] {
 	fingerprint := req.GetFingerprint()
 	if fingerprint != "" {
-		return GetNewsFingerprint(fingerprint), nil
+		return GetNewsFingerprintQuery(fingerprint)
 	}
-	return GetNews(string(req.GetSource()),
+	return GetNewsQuery(string(req.GetSource()),
 		symbol,
 		req.GetStartTime(),
-		req.GetEndTime()), nil
+		req.GetEndTime())
 }
 
-func GetNewsFingerprint(fingerprint string) []*entities.News {
-	var news []News
-
-	tx := DB.Preload("Symbols").
-		Preload("Sentiment").
-		Preload("LLM").
-		Where("fingerprint = ?", fingerprint).Find(&news)
+func preloadNews(tx *gorm.DB) *gorm.DB {
+	return tx.Preload("Symbols").Preload("Sentiment")
+}
 
-	news = PaginateRequest(tx, News{})
+// Key of a row of the news in the order of their queries
+func newsPageKey(news News) PageKey {
+	return PageKey{Value: news.UpdatedAtTimestamp, Fingerprint: news.Fingerprint}
+}
 
-	if tx.Error != nil {
-		logging.Log().Error().
-			Err(tx.Error).
-			Str("fingerprint", fingerprint).
-			Msg("getting news from database")
+func GetNewsFingerprintQuery(fingerprint string) DataQuery[News, *entities.News] {
+	return DataQuery[News, *entities.News]{
+		Query:      DB.Model(&News{}).Where("fingerprint = ?", fingerprint),
+		OrderBy:    "news.updated_at_timestamp",
+		Key:        newsPageKey,
+		Preload:    preloadNews,
+		ToEntities: NewsToEntities,
 	}
-
-	return NewsToEntities(news)
 }
 
-func GetNews(source string, symbol string, startTime int64, endTime int64) []*entities.News {
-	var news []News
-	tx := DB.Preload("Symbols").
-		Joins("JOIN news_symbols ON news_symbols.news_fingerprint = news.fingerprint").
-		Preload("Sentiment").
-		Where("source = ? AND news_symbols.symbol = ? AND updated_at_timestamp >= ? AND updated_at_timestamp <= ?",
-			source,
-			symbol,
-			time.Unix(startTime, 0),
-			time.Unix(endTime, 0)).Order("updated_at_timestamp DESC")
-	news = PaginateRequest(tx, News{})
-	logging.Log().Debug().Int("count", len(news)).Msg("finished getting news from db")
+func GetNewsQuery(source string, symbol string, startTime int64, endTime int64) DataQuery[News, *entities.News] {
+	return DataQuery[News, *entities.News]{
+		Query: DB.Model(&News{}).
+			Joins("JOIN news_symbols ON news_symbols.news_fingerprint = news.fingerprint").
+			Where("source = ? AND news_symbols.symbol = ? AND updated_at_timestamp >= ? AND updated_at_timestamp <= ?",
+				source,
+				symbol,
+				time.Unix(0, startTime),
+				time.Unix(0, endTime)),
+		OrderBy:    "news.updated_at_timestamp",
+		Descending: true,
+		Key:        newsPageKey,
+		Preload:    preloadNews,
+		ToEntities: NewsToEntities,
+	}
+}
 
-	if tx.Error != nil {
-		logging.Log().Error().
-			Err(tx.Error).
-			Str("source", source).
-			Str("symbol", symbol).
-			Int64("startTime", startTime).
-			Int64("endTime", endTime).
-			Msg("getting news from database")
+func SentimentsToEntities(sentiments []Sentiment) []*entities.NewsSentiment {
+	entities := make([]*entities.NewsSentiment, len(sentiments))
+	for i, sentiment := range sentiments {
+		entities[i] = SentimentToEntity(sentiment)
 	}
+	return entities
+}
 
-	return NewsToEntities(news)
+// GetSentimentsQuery selects the sentiments of a symbol in the news of a source
+func GetSentimentsQuery(source string, symbol string, startTime int64, endTime int64) DataQuery[Sentiment, *entities.NewsSentiment] {
+	return DataQuery[Sentiment, *entities.NewsSentiment]{
+		Query: DB.Model(&Sentiment{}).
+			Joins("JOIN news ON news.fingerprint = sentiments.news_fingerprint").
+			Where("news.source = ? AND sentiments.symbol = ? AND sentiments.timestamp >= ? AND sentiments.timestamp <= ?",
+				source,
+				symbol,
+				time.Unix(0, startTime),
+				time.Unix(0, endTime)),
+		OrderBy: "sentiments.timestamp",
+		Key: func(sentiment Sentiment) PageKey {
+			return PageKey{Value: sentiment.Timestamp, Fingerprint: sentiment.Fingerprint}
+		},
+		ToEntities: SentimentsToEntities,
+	}
 }
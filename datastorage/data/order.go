@@ -0,0 +1,114 @@
+package data
+
+import (
+	"time"
+
+	"tradingplatform/shared/entities"
+	"tradingplatform/shared/logging"
+)
+
+// An order of the execution component, every change of the order is stored
+type Order struct {
+	ID                 string `gorm:"index"`
+	ClientOrderID      string
+	Account            string `gorm:"index"`
+	Symbol             string
+	Source             string
+	AssetClass         string `gorm:"not null"`
+	Side               string
+	Type               string
+	Quantity           float64
+	LimitPrice         float64
+	StopPrice          float64
+	Status             string
+	FilledQuantity     float64
+	AveragePrice       float64
+	Reason             string
+	CreatedAtTimestamp time.Time
+	CreatedAtNs        int64
+	UpdatedAtTimestamp time.Time `gorm:"index"`
+	UpdatedAtNs        int64     `gorm:"index"`
+	Fingerprint        string    `gorm:"primaryKey"`
+	Broker             string
+	Replaces           string
+}
+
+func OrderFromEntity(entity *entities.Order) Order {
+	return Order{
+		ID:                 entity.ID,
+		ClientOrderID:      entity.ClientOrderID,
+		Account:            entity.Account,
+		Symbol:             entity.Symbol,
+		Source:             entity.Source,
+		AssetClass:         entity.AssetClass,
+		Side:               entity.Side,
+		Type:               entity.Type,
+		Quantity:           entity.Quantity,
+		LimitPrice:         entity.LimitPrice,
+		StopPrice:          entity.StopPrice,
+		Status:             entity.Status,
+		FilledQuantity:     entity.FilledQuantity,
+		AveragePrice:       entity.AveragePrice,
+		Reason:             entity.Reason,
+		CreatedAtTimestamp: time.Unix(0, entity.CreatedAt),
+		CreatedAtNs:        entity.CreatedAt,
+		UpdatedAtTimestamp: time.Unix(0, entity.UpdatedAt),
+		UpdatedAtNs:        entity.UpdatedAt,
+		Fingerprint:        entity.Fingerprint,
+		Broker:             entity.Broker,
+		Replaces:           entity.Replaces,
+	}
+}
+
+func OrderToEntity(order Order) *entities.Order {
+	return &entities.Order{
+		ID:             order.ID,
+		ClientOrderID:  order.ClientOrderID,
+		Account:        order.Account,
+		Symbol:         order.Symbol,
+		Source:         order.Source,
+		AssetClass:     order.AssetClass,
+		Side:           order.Side,
+		Type:           order.Type,
+		Quantity:       order.Quantity,
+		LimitPrice:     order.LimitPrice,
+		StopPrice:      order.StopPrice,
+		Status:         order.Status,
+		FilledQuantity: order.FilledQuantity,
+		AveragePrice:   order.AveragePrice,
+		Reason:         order.Reason,
+		CreatedAt:      order.CreatedAtNs,
+		UpdatedAt:      order.UpdatedAtNs,
+		Fingerprint:    order.Fingerprint,
+		Broker:         order.Broker,
+		Replaces:       order.Replaces,
+	}
+}
+
+func OrdersToEntities(orders []Order) []*entities.Order {
+	entities := make([]*entities.Order, len(orders))
+	for i, order := range orders {
+		entities[i] = OrderToEntity(order)
+	}
+	return entities
+}
+
+func OrdersFromEntities(entities []*entities.Order) []Order {
+	orders := make([]Order, len(entities))
+	for i, entity := range entities {
+		orders[i] = OrderFromEntity(entity)
+	}
+	return orders
+}
+
+func InsertOrder(order *entities.Order) error {
+	dbOrder := OrderFromEntity(order)
+	tx := DB.Create(dbOrder)
+	if tx.Error != nil {
+		logging.Log().Error().
+			Err(tx.Error).
+			RawJSON("order", entities.GenerateJson(order)).
+			Msg("inserting order")
+	}
+	return tx.Error
+}
@@ -6,6 +6,7 @@ import (
 	"tradingplatform/shared/logging"
 	"tradingplatform/shared/requests"
 
+	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
@@ -29,6 +30,7 @@ type Orderbook struct {
 	Symbol      string
 	Exchange    string
 	Timestamp   time.Time            `gorm:"index"`
+	TimestampNs int64                `gorm:"index"`
 	Asks        []AsksOrderbookEntry `gorm:"foreignKey:OrderbookFingerprint"`
 	Bids        []BidsOrderbookEntry `gorm:"foreignKey:OrderbookFingerprint"`
 	Reset_      bool
@@ -62,7 +64,8 @@ func OrderbookFromEntity(entity *entities.Orderbook) Orderbook {
 	return Orderbook{
 		Symbol:      entity.Symbol,
 		Exchange:    entity.Exchange,
-		Timestamp:   time.Unix(entity.Timestamp, 0),
+		Timestamp:   time.Unix(0, entity.TimestampNs),
+		TimestampNs: entity.TimestampNs,
 		Asks:        asks,
 		Bids:        bids,
 		Reset_:      entity.Reset_,
@@ -92,7 +95,7 @@ func OrderbookToEntity(orderbook Orderbook) *entities.Orderbook {
 	return &entities.Orderbook{
 		Symbol:      orderbook.Symbol,
 		Exchange:    orderbook.Exchange,
-		Timestamp:   orderbook.Timestamp.Unix(),
+		TimestampNs: orderbook.TimestampNs,
 		Asks:        asks,
 		Bids:        bids,
 		Reset_:      orderbook.Reset_,
@@ -118,7 +121,7 @@ func OrderbooksToEntities(orderbooks []Orderbook) []*entities.Orderbook {
 	return entities
 }
 
-func InsertOrderbook(orderbook *entities.Orderbook) {
+func InsertOrderbook(orderbook *entities.Orderbook) error {
 	dbOrderbook := OrderbookFromEntity(orderbook)
 	tx := DB.Clauses(clause.OnConflict{
 		DoNothing: true,
@@ -129,25 +132,35 @@ func InsertOrderbook(orderbook *entities.Orderbook) {
 			RawJSON("orderbook", entities.GenerateJson(orderbook)).
 			Msg("inserting orderbook")
 	}
+	return tx.Error
 }
 
-func GetOrderbookFromRequest(symbol string, req requests.DataRequest) ([]*entities.Orderbook, error) {
-	return GetOrderbook(string(req.GetSource()),
+// Key of a row of the orderbooks in the order of their queries
+func orderbookPageKey(orderbook Orderbook) PageKey {
+	return PageKey{Value: orderbook.TimestampNs, Fingerprint: orderbook.Fingerprint}
+}
+
+func GetOrderbookQueryFromRequest(symbol string, req requests.DataRequest) DataQuery[Orderbook, *entities.Orderbook] {
+	return GetOrderbookQuery(string(req.GetSource()),
 		symbol,
 		string(req.AssetClass),
 		req.GetStartTime(),
-		req.GetEndTime()), nil
+		req.GetEndTime())
 }
 
-func GetOrderbook(source, symbol, assetClass string, startTime, endTime int64) []*entities.Orderbook {
-	var orderbooks []Orderbook
-
-	tx := DB.Preload("Asks").Preload("Bids").Where("source = ? AND symbol = ? AND asset_class = ? AND timestamp >= ? AND timestamp < ?",
-		source,
-		symbol,
-		assetClass,
-		time.Unix(startTime, 0),
-		time.Unix(endTime, 0))
-	orderbooks = PaginateRequest(tx, Orderbook{})
-	return OrderbooksToEntities(orderbooks)
+func GetOrderbookQuery(source, symbol, assetClass string, startTime, endTime int64) DataQuery[Orderbook, *entities.Orderbook] {
+	return DataQuery[Orderbook, *entities.Orderbook]{
+		Query: DB.Model(&Orderbook{}).Where("source = ? AND symbol = ? AND asset_class = ? AND timestamp_ns >= ? AND timestamp_ns < ?",
+			source,
+			symbol,
+			assetClass,
+			startTime,
+			endTime),
+		OrderBy: "timestamp_ns",
+		Key:     orderbookPageKey,
+		Preload: func(tx *gorm.DB) *gorm.DB {
+			return tx.Preload("Asks").Preload("Bids")
+		},
+		ToEntities: OrderbooksToEntities,
+	}
 }
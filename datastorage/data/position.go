@@ -0,0 +1,79 @@
+package data
+
+import (
+	"time"
+
+	"tradingplatform/shared/entities"
+	"tradingplatform/shared/logging"
+)
+
+// A position of an account after a fill, every change of the position is stored
+type Position struct {
+	Account      string `gorm:"index"`
+	Symbol       string `gorm:"index"`
+	Source       string
+	AssetClass   string `gorm:"not null"`
+	Quantity     float64
+	AveragePrice float64
+	RealizedPnL  float64
+	Timestamp    time.Time `gorm:"index"`
+	TimestampNs  int64     `gorm:"index"`
+	Fingerprint  string    `gorm:"primaryKey"`
+}
+
+func PositionFromEntity(entity *entities.Position) Position {
+	return Position{
+		Account:      entity.Account,
+		Symbol:       entity.Symbol,
+		Source:       entity.Source,
+		AssetClass:   entity.AssetClass,
+		Quantity:     entity.Quantity,
+		AveragePrice: entity.AveragePrice,
+		RealizedPnL:  entity.RealizedPnL,
+		Timestamp:    time.Unix(0, entity.Timestamp),
+		TimestampNs:  entity.Timestamp,
+		Fingerprint:  entity.Fingerprint,
+	}
+}
+
+func PositionToEntity(position Position) *entities.Position {
+	return &entities.Position{
+		Account:      position.Account,
+		Symbol:       position.Symbol,
+		Source:       position.Source,
+		AssetClass:   position.AssetClass,
+		Quantity:     position.Quantity,
+		AveragePrice: position.AveragePrice,
+		RealizedPnL:  position.RealizedPnL,
+		Timestamp:    position.TimestampNs,
+		Fingerprint:  position.Fingerprint,
+	}
+}
+
+func PositionsToEntities(positions []Position) []*entities.Position {
+	entities := make([]*entities.Position, len(positions))
+	for i, position := range positions {
+		entities[i] = PositionToEntity(position)
+	}
+	return entities
+}
+
+func PositionsFromEntities(entities []*entities.Position) []Position {
+	positions := make([]Position, len(entities))
+	for i, entity := range entities {
+		positions[i] = PositionFromEntity(entity)
+	}
+	return positions
+}
+
+func InsertPosition(position *entities.Position) error {
+	dbPosition := PositionFromEntity(position)
+	tx := DB.Create(dbPosition)
+	if tx.Error != nil {
+		logging.Log().Error().
+			Err(tx.Error).
+			RawJSON("position", entities.GenerateJson(position)).
+			Msg("inserting position")
+	}
+	return tx.Error
+}
@@ -6,6 +6,7 @@ import (
 	"tradingplatform/shared/logging"
 	"tradingplatform/shared/requests"
 
+	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
@@ -19,6 +20,7 @@ type Quote struct {
 	AskPrice    float64
 	AskSize     float64
 	Timestamp   time.Time        `gorm:"index"`
+	TimestampNs int64            `gorm:"index"`
 	Conditions  []QuoteCondition `gorm:"foreignKey:TradeFingerprint"`
 	Tape        string
 	Fingerprint string `gorm:"primaryKey"`
@@ -45,7 +47,8 @@ func QuoteFromEntity(entity *entities.Quote) Quote {
 		AskExchange: entity.AskExchange,
 		AskPrice:    entity.AskPrice,
 		AskSize:     entity.AskSize,
-		Timestamp:   time.Unix(entity.Timestamp, 0),
+		Timestamp:   time.Unix(0, entity.TimestampNs),
+		TimestampNs: entity.TimestampNs,
 		Conditions:  conditions,
 		Tape:        entity.Tape,
 		Fingerprint: entity.Fingerprint,
@@ -68,7 +71,7 @@ func QuoteToEntity(quote Quote) *entities.Quote {
 		AskExchange: quote.AskExchange,
 		AskPrice:    quote.AskPrice,
 		AskSize:     quote.AskSize,
-		Timestamp:   quote.Timestamp.Unix(),
+		TimestampNs: quote.TimestampNs,
 		Conditions:  conditions,
 		Tape:        quote.Tape,
 		Fingerprint: quote.Fingerprint,
@@ -93,7 +96,7 @@ func QuotesToEntities(quotes []Quote) []*entities.Quote {
 	return entities
 }
 
-func InsertQuote(quote *entities.Quote) {
+func InsertQuote(quote *entities.Quote) error {
 	dbQuote := QuoteFromEntity(quote)
 	tx := DB.Clauses(clause.OnConflict{
 		DoNothing: true,
@@ -104,24 +107,35 @@ func InsertQuote(quote *entities.Quote) {
 			RawJSON("quote", entities.GenerateJson(quote)).
 			Msg("inserting quote")
 	}
+	return tx.Error
 }
 
-func GetQuoteFromRequest(symbol string, req requests.DataRequest) ([]*entities.Quote, error) {
-	return GetQuote(string(req.GetSource()),
+// Key of a row of the quotes in the order of their queries
+func quotePageKey(quote Quote) PageKey {
+	return PageKey{Value: quote.TimestampNs, Fingerprint: quote.Fingerprint}
+}
+
+func GetQuoteQueryFromRequest(symbol string, req requests.DataRequest) DataQuery[Quote, *entities.Quote] {
+	return GetQuoteQuery(string(req.GetSource()),
 		symbol,
 		string(req.AssetClass),
 		req.GetStartTime(),
-		req.GetEndTime()), nil
+		req.GetEndTime())
 }
 
-func GetQuote(source, symbol, assetClass string, startTime, endTime int64) []*entities.Quote {
-	var quotes []Quote
-	tx := DB.Preload("Conditions").Where("source = ? AND symbol = ? AND asset_class = ? AND timestamp >= ? AND timestamp <= ?",
-		source,
-		symbol,
-		assetClass,
-		time.Unix(startTime, 0),
-		time.Unix(endTime, 0)).Order("Timestamp")
-	quotes = PaginateRequest(tx, Quote{})
-	return QuotesToEntities(quotes)
+func GetQuoteQuery(source, symbol, assetClass string, startTime, endTime int64) DataQuery[Quote, *entities.Quote] {
+	return DataQuery[Quote, *entities.Quote]{
+		Query: DB.Model(&Quote{}).Where("source = ? AND symbol = ? AND asset_class = ? AND timestamp_ns >= ? AND timestamp_ns <= ?",
+			source,
+			symbol,
+			assetClass,
+			startTime,
+			endTime),
+		OrderBy: "timestamp_ns",
+		Key:     quotePageKey,
+		Preload: func(tx *gorm.DB) *gorm.DB {
+			return tx.Preload("Conditions")
+		},
+		ToEntities: QuotesToEntities,
+	}
 }
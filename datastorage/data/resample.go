@@ -0,0 +1,139 @@
+package data
+
+import (
+	"tradingplatform/shared/bars"
+	"tradingplatform/shared/calendar"
+	"tradingplatform/shared/communication"
+	"tradingplatform/shared/entities"
+	"tradingplatform/shared/types"
+)
+
+// Approximate length in minutes of a single unit timeframe, used to compare timeframes
+var timeFrameUnitMinutes = map[types.TimeFrame]int{
+	types.OneMin:   1,
+	types.OneHour:  60,
+	types.OneDay:   24 * 60,
+	types.OneWeek:  7 * 24 * 60,
+	types.OneMonth: 31 * 24 * 60,
+}
+
+// Whether bars of a timeframe can be aggregated into bars of a coarser one without a finer bar
+// spanning two coarser ones
+func canResample(from types.TimeFrame, to types.TimeFrame) bool {
+	fromCount, fromUnit, ok := types.ParseTimeFrame(from)
+	if !ok {
+		return false
+	}
+	toCount, toUnit, ok := types.ParseTimeFrame(to)
+	if !ok {
+		return false
+	}
+	fromMinutes := fromCount * timeFrameUnitMinutes[fromUnit]
+	toMinutes := toCount * timeFrameUnitMinutes[toUnit]
+	if fromMinutes >= toMinutes {
+		return false
+	}
+	switch {
+	case fromUnit == types.OneMin || fromUnit == types.OneHour:
+		// Intraday bars fit in intraday bars of a multiple length and in any longer bar
+		return toUnit != types.OneMin && toUnit != types.OneHour || toMinutes%fromMinutes == 0
+	case fromUnit == toUnit:
+		return toCount%fromCount == 0
+	case fromUnit == types.OneDay:
+		return fromCount == 1
+	}
+	return false
+}
+
+// Get the finest stored timeframe that can be aggregated into a timeframe
+func finestTimeFrame(stored []types.TimeFrame, timeFrame types.TimeFrame) (types.TimeFrame, bool) {
+	var finest types.TimeFrame
+	finestMinutes := 0
+	for _, tf := range stored {
+		if !canResample(tf, timeFrame) {
+			continue
+		}
+		count, unit, _ := types.ParseTimeFrame(tf)
+		if minutes := count * timeFrameUnitMinutes[unit]; finest == "" || minutes < finestMinutes {
+			finest = tf
+			finestMinutes = minutes
+		}
+	}
+	return finest, finest != ""
+}
+
+// ResampleBars builds the bars of a timeframe from the finest stored bars that can be aggregated
+// into it, or from the stored trades when there are no such bars. Bars are aligned to the trading
+// sessions of the asset class
+func ResampleBars(source string,
+	symbol string,
+	assetClass string,
+	startTime int64,
+	endTime int64,
+	timeFrame types.TimeFrame) ([]*entities.Bar, error) {
+
+	aggregator, err := bars.NewAggregator(calendar.ForAssetClass(types.AssetClass(assetClass)), timeFrame)
+	if err != nil {
+		return nil, err
+	}
+
+	var storedTimeFrames []string
+	tx := DB.Model(&Bar{}).Where("source = ? AND symbol = ? AND timestamp_ns >= ? AND timestamp_ns <= ? AND asset_class = ?",
+		source,
+		symbol,
+		startTime,
+		endTime,
+		assetClass).Distinct().Pluck("timeframe", &storedTimeFrames)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	// Bars stored from the streams have no timeframe, they are minute bars
+	stored := make([]types.TimeFrame, 0, len(storedTimeFrames))
+	for _, tf := range storedTimeFrames {
+		if tf == "" {
+			tf = string(types.OneMin)
+		}
+		stored = append(stored, types.TimeFrame(tf))
+	}
+
+	from, ok := finestTimeFrame(stored, timeFrame)
+	if !ok {
+		query := GetTradesQuery(source, symbol, assetClass, startTime, endTime)
+		query.Preload = nil
+		err = query.ForEach(communication.DEFAULT_QUEUE_PAGE_SIZE, aggregator.AddTrade)
+		if err != nil {
+			return nil, err
+		}
+		return aggregator.Flush(), nil
+	}
+
+	timeFrames := []string{string(from)}
+	if from == types.OneMin {
+		timeFrames = append(timeFrames, "")
+	}
+	query := DataQuery[Bar, *entities.Bar]{
+		Query: DB.Model(&Bar{}).Where("source = ? AND symbol = ? AND timestamp_ns >= ? AND timestamp_ns <= ? AND asset_class = ? AND timeframe IN ?",
+			source,
+			symbol,
+			startTime,
+			endTime,
+			assetClass,
+			timeFrames),
+		OrderBy:    "timestamp_ns",
+		Key:        barPageKey,
+		ToEntities: BarsToEntities,
+	}
+	// A minute can be stored both from the streams and from a data request, it is counted once
+	var last int64
+	err = query.ForEach(communication.DEFAULT_QUEUE_PAGE_SIZE, func(bar *entities.Bar) {
+		if bar.TimestampNs == last {
+			return
+		}
+		last = bar.TimestampNs
+		aggregator.AddBar(bar)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return aggregator.Flush(), nil
+}
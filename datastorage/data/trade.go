@@ -6,6 +6,7 @@ import (
 	"tradingplatform/shared/logging"
 	"tradingplatform/shared/requests"
 
+	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
@@ -16,6 +17,7 @@ type Trade struct {
 	Price       float64
 	Size        float64
 	Timestamp   time.Time `gorm:"index"`
+	TimestampNs int64     `gorm:"index"`
 	TakerSide   string
 	Conditions  []TradeCondition `gorm:"foreignKey:TradeFingerprint"`
 	Tape        string
@@ -41,7 +43,8 @@ func TradeFromEntity(entity *entities.Trade) Trade {
 		Exchange:    entity.Exchange,
 		Price:       entity.Price,
 		Size:        entity.Size,
-		Timestamp:   time.Unix(entity.Timestamp, 0),
+		Timestamp:   time.Unix(0, entity.TimestampNs),
+		TimestampNs: entity.TimestampNs,
 		TakerSide:   entity.TakerSide,
 		Conditions:  conditions,
 		Tape:        entity.Tape,
@@ -62,7 +65,7 @@ func TradeToEntity(trade Trade) *entities.Trade {
 		Exchange:    trade.Exchange,
 		Price:       trade.Price,
 		Size:        trade.Size,
-		Timestamp:   trade.Timestamp.Unix(),
+		TimestampNs: trade.TimestampNs,
 		TakerSide:   trade.TakerSide,
 		Conditions:  conditions,
 		Tape:        trade.Tape,
@@ -89,7 +92,7 @@ func TradesToEntities(trades []Trade) []*entities.Trade {
 	return entities
 }
 
-func InsertTrade(trade *entities.Trade) {
+func InsertTrade(trade *entities.Trade) error {
 	dbTrade := TradeFromEntity(trade)
 	tx := DB.Clauses(clause.OnConflict{
 		DoNothing: true,
@@ -100,25 +103,35 @@ func InsertTrade(trade *entities.Trade) {
 			RawJSON("trade", entities.GenerateJson(trade)).
 			Msg("inserting trade")
 	}
+	return tx.Error
 }
 
-func GetTradesFromRequest(symbol string, req requests.DataRequest) ([]*entities.Trade, error) {
-	return GetTrades(string(req.GetSource()),
+// Key of a row of the trades in the order of their queries
+func tradePageKey(trade Trade) PageKey {
+	return PageKey{Value: trade.TimestampNs, Fingerprint: trade.Fingerprint}
+}
+
+func GetTradesQueryFromRequest(symbol string, req requests.DataRequest) DataQuery[Trade, *entities.Trade] {
+	return GetTradesQuery(string(req.GetSource()),
 		symbol,
 		string(req.AssetClass),
 		req.GetStartTime(),
-		req.GetEndTime()), nil
+		req.GetEndTime())
 }
 
-func GetTrades(source, symbol, assetClass string, startTime, endTime int64) []*entities.Trade {
-	var trades []Trade
-	tx := DB.Preload("Conditions").Where("source = ? AND symbol = ? AND asset_class = ? AND timestamp >= ? AND timestamp <= ?",
-		source,
-		symbol,
-		assetClass,
-		time.Unix(startTime, 0),
-		time.Unix(endTime, 0)).
-		Order("Timestamp")
-	trades = PaginateRequest(tx, Trade{})
-	return TradesToEntities(trades)
+func GetTradesQuery(source, symbol, assetClass string, startTime, endTime int64) DataQuery[Trade, *entities.Trade] {
+	return DataQuery[Trade, *entities.Trade]{
+		Query: DB.Model(&Trade{}).Where("source = ? AND symbol = ? AND asset_class = ? AND timestamp_ns >= ? AND timestamp_ns <= ?",
+			source,
+			symbol,
+			assetClass,
+			startTime,
+			endTime),
+		OrderBy: "timestamp_ns",
+		Key:     tradePageKey,
+		Preload: func(tx *gorm.DB) *gorm.DB {
+			return tx.Preload("Conditions")
+		},
+		ToEntities: TradesToEntities,
+	}
 }
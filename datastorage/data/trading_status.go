@@ -14,6 +14,7 @@ type TradingStatus struct {
 	ReasonCode  string
 	ReasonMsg   string
 	Timestamp   time.Time `gorm:"index"`
+	TimestampNs int64     `gorm:"index"`
 	Tape        string
 	Fingerprint string `gorm:"primaryKey"`
 	Source      string
@@ -27,7 +28,8 @@ func TradingStatusFromEntity(entity *entities.TradingStatus) TradingStatus {
 		StatusMsg:   entity.StatusMsg,
 		ReasonCode:  entity.ReasonCode,
 		ReasonMsg:   entity.ReasonMsg,
-		Timestamp:   time.Unix(entity.Timestamp, 0),
+		Timestamp:   time.Unix(0, entity.TimestampNs),
+		TimestampNs: entity.TimestampNs,
 		Tape:        entity.Tape,
 		Fingerprint: entity.Fingerprint,
 		Source:      entity.Source,
@@ -42,7 +44,7 @@ func TradingStatusToEntity(tradingStatus TradingStatus) *entities.TradingStatus
 		StatusMsg:   tradingStatus.StatusMsg,
 		ReasonCode:  tradingStatus.ReasonCode,
 		ReasonMsg:   tradingStatus.ReasonMsg,
-		Timestamp:   tradingStatus.Timestamp.Unix(),
+		TimestampNs: tradingStatus.TimestampNs,
 		Tape:        tradingStatus.Tape,
 		Fingerprint: tradingStatus.Fingerprint,
 		Source:      tradingStatus.Source,
@@ -66,7 +68,7 @@ func TradingStatusesFromEntities(entities []*entities.TradingStatus) []TradingSt
 	return tradingStatuses
 }
 
-func InsertTradingStatus(tradingStatus *entities.TradingStatus) {
+func InsertTradingStatus(tradingStatus *entities.TradingStatus) error {
 	dbTradingStatus := TradingStatusFromEntity(tradingStatus)
 	tx := DB.Create(dbTradingStatus)
 	if tx.Error != nil {
@@ -75,23 +77,32 @@ func InsertTradingStatus(tradingStatus *entities.TradingStatus) {
 			RawJSON("tradingStatus", entities.GenerateJson(tradingStatus)).
 			Msg("inserting tradingStatus")
 	}
+	return tx.Error
 }
 
-func GetTradingStatusesFromRequest(symbol string, req requests.DataRequest) ([]*entities.TradingStatus, error) {
-	return GetTradingStatuses(string(req.GetSource()),
+// Key of a row of the trading statuses in the order of their queries
+func tradingStatusPageKey(status TradingStatus) PageKey {
+	return PageKey{Value: status.TimestampNs, Fingerprint: status.Fingerprint}
+}
+
+func GetTradingStatusesQueryFromRequest(symbol string, req requests.DataRequest) DataQuery[TradingStatus, *entities.TradingStatus] {
+	return GetTradingStatusesQuery(string(req.GetSource()),
 		symbol,
 		string(req.AssetClass),
 		req.GetStartTime(),
-		req.GetEndTime()), nil
+		req.GetEndTime())
 }
 
-func GetTradingStatuses(source, symbol, assetClass string, startTime, endTime int64) []*entities.TradingStatus {
-	var ts []TradingStatus
-	DB.Where("source = ? AND symbol = ? AND asset_class = ? AND timestamp >= ? AND timestamp <= ?",
-		source,
-		symbol,
-		assetClass,
-		time.Unix(startTime, 0),
-		time.Unix(endTime, 0)).Find(&ts)
-	return TradingStatusesToEntities(ts)
+func GetTradingStatusesQuery(source, symbol, assetClass string, startTime, endTime int64) DataQuery[TradingStatus, *entities.TradingStatus] {
+	return DataQuery[TradingStatus, *entities.TradingStatus]{
+		Query: DB.Model(&TradingStatus{}).Where("source = ? AND symbol = ? AND asset_class = ? AND timestamp_ns >= ? AND timestamp_ns <= ?",
+			source,
+			symbol,
+			assetClass,
+			startTime,
+			endTime),
+		OrderBy:    "timestamp_ns",
+		Key:        tradingStatusPageKey,
+		ToEntities: TradingStatusesToEntities,
+	}
 }
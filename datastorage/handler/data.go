@@ -1,10 +1,13 @@
 package handler
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"tradingplatform/datastorage/data"
 
+	"tradingplatform/shared/calendar"
 	"tradingplatform/shared/communication/producer"
 	"tradingplatform/shared/entities"
 	"tradingplatform/shared/logging"
@@ -12,74 +15,58 @@ import (
 	"tradingplatform/shared/types"
 )
 
-func HandleDataRequest(dataRequest requests.DataRequest, och chan types.DataResponse) {
+// Handle a data request by sending the requested data on a data queue, the context carries the trace
+// of the request
+func HandleDataRequest(ctx context.Context, dataRequest requests.DataRequest, och chan types.DataResponse) {
+	// The queue is read after the command was answered, only the trace of the command is kept
+	ctx = context.WithoutCancel(ctx)
 	symbol := dataRequest.GetSymbol()
 	dtype := dataRequest.GetDataType()
-	var messages *[]*entities.Message
+	var queue *producer.Queue
 	var response types.DataResponse
 
 	logging.Log().Debug().RawJSON("dataRequest", dataRequest.JSON()).Msg("handling data request to db")
 
 	switch dtype {
 	case types.Bar:
-		messages, response = data.HandleDataFetch[requests.DataRequest,
-			*entities.Bar](data.GetBarsFromRequest,
-			symbol,
-			dataRequest,
-			types.Bar,
-			dataRequest.AssetClass,
-			dataRequest.GetTimeFrame())
+		queue, response = handleBarsRequest(ctx, symbol, dataRequest)
 	case types.DailyBars:
-		messages, response = data.HandleDataFetch[requests.DataRequest,
-			*entities.Bar](data.GetDailyBarsFromRequest,
+		queue, response = data.HandleDataQuery(ctx, data.GetDailyBarsQueryFromRequest(symbol, dataRequest),
 			symbol,
-			dataRequest,
 			types.DailyBars,
 			dataRequest.AssetClass,
 			dataRequest.GetTimeFrame())
 	case types.LULD:
-		messages, response = data.HandleDataFetch[requests.DataRequest,
-			*entities.LULD](data.GetLULDFromRequest,
+		queue, response = data.HandleDataQuery(ctx, data.GetLULDQueryFromRequest(symbol, dataRequest),
 			symbol,
-			dataRequest,
 			types.LULD,
 			dataRequest.AssetClass, "")
 	case types.RawText:
-		messages, response = data.HandleDataFetch[requests.DataRequest,
-			*entities.News](data.GetNewsFromDataRequest,
+		queue, response = data.HandleDataQuery(ctx, data.GetNewsQueryFromDataRequest(symbol, dataRequest),
 			symbol,
-			dataRequest,
 			types.RawText,
 			dataRequest.AssetClass, "")
 	case types.Orderbook:
-		messages, response = data.HandleDataFetch[requests.DataRequest,
-			*entities.Orderbook](data.GetOrderbookFromRequest,
+		queue, response = data.HandleDataQuery(ctx, data.GetOrderbookQueryFromRequest(symbol, dataRequest),
 			symbol,
-			dataRequest,
 			types.Orderbook,
 			dataRequest.AssetClass,
 			"")
 	case types.Trades:
-		messages, response = data.HandleDataFetch[requests.DataRequest,
-			*entities.Trade](data.GetTradesFromRequest,
+		queue, response = data.HandleDataQuery(ctx, data.GetTradesQueryFromRequest(symbol, dataRequest),
 			symbol,
-			dataRequest,
 			types.Trades,
 			dataRequest.AssetClass,
 			"")
 	case types.Status:
-		messages, response = data.HandleDataFetch[requests.DataRequest,
-			*entities.TradingStatus](data.GetTradingStatusesFromRequest,
+		queue, response = data.HandleDataQuery(ctx, data.GetTradingStatusesQueryFromRequest(symbol, dataRequest),
 			symbol,
-			dataRequest,
 			types.Status,
 			dataRequest.AssetClass,
 			"")
 	case types.Quotes:
-		messages, response = data.HandleDataFetch[requests.DataRequest,
-			*entities.Quote](data.GetQuoteFromRequest,
+		queue, response = data.HandleDataQuery(ctx, data.GetQuoteQueryFromRequest(symbol, dataRequest),
 			symbol,
-			dataRequest,
 			types.Quotes,
 			dataRequest.AssetClass,
 			"")
@@ -93,19 +80,96 @@ func HandleDataRequest(dataRequest requests.DataRequest, och chan types.DataResp
 		och <- response
 		return
 	}
-	if len(*messages) == 0 {
-		och <- types.NewDataError(
-			fmt.Errorf("no data found for %s", symbol),
-		)
-		return
-	}
-	responseTopic := (*messages)[0].Topic
-	handler, handlerResponse := producer.GetQueueHandler(responseTopic, dataRequest.GetNoConfirm())
+	handler, handlerResponse := producer.GetQueueHandler(queue.Topic, dataRequest.GetNoConfirm())
 	if handlerResponse.Err != "" {
 		och <- handlerResponse
 		return
 	}
-	handler.Ch <- messages
+	queue.Ctx = ctx
+	handler.Ch <- queue
 
 	och <- response
 }
+
+// Bars are returned as stored when the stored bars of the requested timeframe cover the trading
+// sessions of the range, otherwise they are built from finer bars or trades. When those do not
+// cover the range either the most complete of the stored and built bars are returned, the missing
+// bars are filled from the dataprovider with the gaps command
+func handleBarsRequest(ctx context.Context, symbol string, dataRequest requests.DataRequest) (*producer.Queue, types.DataResponse) {
+	query := data.GetBarsQueryFromRequest(symbol, dataRequest).WithContext(ctx)
+	storedQueue := func() (*producer.Queue, types.DataResponse) {
+		return data.HandleDataQuery(ctx, query,
+			symbol,
+			types.Bar,
+			dataRequest.AssetClass,
+			dataRequest.GetTimeFrame())
+	}
+	cal := calendar.ForAssetClass(dataRequest.AssetClass)
+	start := time.Unix(0, dataRequest.GetStartTime())
+	// The end of a data request is inclusive
+	end := time.Unix(0, dataRequest.GetEndTime()+1)
+
+	stored, err := data.GetBarTimestamps(types.Bar,
+		string(dataRequest.GetSource()),
+		symbol,
+		string(dataRequest.AssetClass),
+		start.UnixNano(),
+		end.UnixNano(),
+		string(dataRequest.GetTimeFrame()))
+	if err != nil {
+		logging.Log().Error().
+			Err(err).
+			RawJSON("dataRequest", dataRequest.JSON()).
+			Msg("getting stored bar timestamps")
+		return nil, types.NewDataError(err)
+	}
+	if len(stored) > 0 && barsComplete(cal, dataRequest.GetTimeFrame(), start, end, stored) {
+		return storedQueue()
+	}
+
+	bars, err := data.ResampleBars(string(dataRequest.GetSource()),
+		symbol,
+		string(dataRequest.AssetClass),
+		dataRequest.GetStartTime(),
+		dataRequest.GetEndTime(),
+		dataRequest.GetTimeFrame())
+	if err != nil {
+		logging.Log().Error().
+			Err(err).
+			RawJSON("dataRequest", dataRequest.JSON()).
+			Msg("resampling bars")
+		return nil, types.NewDataError(err)
+	}
+	logging.Log().Debug().
+		Int("count", len(bars)).
+		Int("stored", len(stored)).
+		RawJSON("dataRequest", dataRequest.JSON()).
+		Msg("resampled bars")
+	resampled := make([]int64, len(bars))
+	for i, bar := range bars {
+		resampled[i] = bar.TimestampNs
+	}
+	if len(bars) > 0 && barsComplete(cal, dataRequest.GetTimeFrame(), start, end, resampled) {
+		return entitiesQueue(bars, symbol, dataRequest)
+	}
+
+	if len(stored) >= len(bars) && len(stored) > 0 {
+		return storedQueue()
+	}
+	return entitiesQueue(bars, symbol, dataRequest)
+}
+
+func entitiesQueue(bars []*entities.Bar, symbol string, dataRequest requests.DataRequest) (*producer.Queue, types.DataResponse) {
+	return data.HandleEntities(bars,
+		symbol,
+		types.Bar,
+		dataRequest.AssetClass,
+		dataRequest.GetTimeFrame())
+}
+
+// Whether bars with the given timestamps cover all the bars the calendar expects in [start, end),
+// bars of a timeframe the calendar expects no bars for are complete as they are
+func barsComplete(cal calendar.Calendar, timeFrame types.TimeFrame, start time.Time, end time.Time, timestamps []int64) bool {
+	gaps, err := data.FindBarGaps(cal, timeFrame, start, end, timestamps)
+	return err != nil || len(gaps) == 0
+}
@@ -0,0 +1,285 @@
+package handler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"tradingplatform/datastorage/data"
+
+	"tradingplatform/shared/communication"
+	"tradingplatform/shared/entities"
+	"tradingplatform/shared/export"
+	"tradingplatform/shared/logging"
+	"tradingplatform/shared/requests"
+	"tradingplatform/shared/types"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Name of the manifest written in the directory of an export once all its files are written
+const manifestFile = "manifest.json"
+
+var exportDir = "exports"
+
+// Set the directory the export requests write their files into
+func SetExportDir(dir string) {
+	exportDir = dir
+}
+
+// HandleExportRequest writes the stored data of the symbols and data types of a request into files
+// partitioned by data type, symbol and day (<dataType>/<symbol>/<date>.<format>) under the directory
+// of the request, reading the rows page by page. The response is the manifest of the files written
+func HandleExportRequest(ctx context.Context, req requests.ExportRequest) types.ExportResponse {
+	logging.Log().Debug().RawJSON("exportRequest", req.JSON()).Msg("handling export request")
+
+	dir := filepath.Join(exportDir, req.Directory)
+	var files []types.ExportedFile
+	for _, dtype := range req.DataTypes {
+		for _, symbol := range req.Symbols {
+			written, err := exportSymbol(ctx, req, dtype, symbol, dir)
+			files = append(files, written...)
+			if err != nil {
+				logging.Log().Error().
+					Err(err).
+					Str("symbol", symbol).
+					Str("dtype", string(dtype)).
+					RawJSON("exportRequest", req.JSON()).
+					Msg("exporting data")
+				return types.NewExportResponse(types.Failure, "", fmt.Errorf("exporting %s of %s: %w", dtype, symbol, err), dir, files)
+			}
+		}
+	}
+	if err := writeManifest(dir, req, files); err != nil {
+		return types.NewExportResponse(types.Failure, "", err, dir, files)
+	}
+	response := types.NewExportResponse(types.Success, "", nil, dir, files)
+	response.Message = fmt.Sprintf("Exported %d rows to %d files", response.Rows, len(files))
+	logging.Log().Info().
+		Str("directory", dir).
+		Int("files", len(files)).
+		Int("rows", response.Rows).
+		Msg("exported data")
+	return response
+}
+
+// Export the rows of a data type of a symbol, returns the files written
+func exportSymbol(ctx context.Context,
+	req requests.ExportRequest,
+	dtype types.DataType,
+	symbol string,
+	dir string) ([]types.ExportedFile, error) {
+
+	sample, err := entities.NewEntity(dtype)
+	if err != nil {
+		return nil, err
+	}
+	w := &partitionWriter{
+		root:     dir,
+		dir:      filepath.Join(string(dtype), strings.ReplaceAll(symbol, "/", "-")),
+		format:   req.Format,
+		dataType: dtype,
+		symbol:   symbol,
+		sample:   sample,
+		exported: make(map[string]bool),
+	}
+	source := string(req.Source)
+	assetClass := string(req.AssetClass)
+	switch dtype {
+	case types.Bar:
+		err = exportQuery(ctx, data.GetBarsQuery(source, symbol, assetClass, req.StartTime, req.EndTime, string(req.TimeFrame)),
+			(*entities.Bar).GetTimestampNs, w)
+	case types.DailyBars:
+		err = exportQuery(ctx, data.GetDailyBarsQuery(source, symbol, assetClass, req.StartTime, req.EndTime, string(types.OneDay)),
+			(*entities.Bar).GetTimestampNs, w)
+	case types.Trades:
+		err = exportQuery(ctx, data.GetTradesQuery(source, symbol, assetClass, req.StartTime, req.EndTime),
+			(*entities.Trade).GetTimestampNs, w)
+	case types.Quotes:
+		err = exportQuery(ctx, data.GetQuoteQuery(source, symbol, assetClass, req.StartTime, req.EndTime),
+			(*entities.Quote).GetTimestampNs, w)
+	case types.Orderbook:
+		err = exportQuery(ctx, data.GetOrderbookQuery(source, symbol, assetClass, req.StartTime, req.EndTime),
+			(*entities.Orderbook).GetTimestampNs, w)
+	case types.RawText:
+		// News are selected and ordered by their update time
+		err = exportQuery(ctx, data.GetNewsQuery(source, symbol, req.StartTime, req.EndTime),
+			(*entities.News).GetUpdatedAtNs, w)
+	case types.Sentiment:
+		err = exportQuery(ctx, data.GetSentimentsQuery(source, symbol, req.StartTime, req.EndTime),
+			(*entities.NewsSentiment).GetTimestampNs, w)
+	default:
+		err = fmt.Errorf("invalid data type %s", dtype)
+	}
+	if err != nil {
+		w.abort()
+		return w.files, err
+	}
+	return w.files, w.closeFile()
+}
+
+// Write the rows selected by a query page by page, the query has to be ordered by the timestamp
+// the rows are partitioned with
+func exportQuery[M any, V interface {
+	entities.FingerprintablePayloader
+	proto.Message
+}](ctx context.Context, query data.DataQuery[M, V], timestamp func(V) int64, w *partitionWriter) error {
+
+	query = query.WithContext(ctx)
+	pageSize := communication.DEFAULT_QUEUE_PAGE_SIZE
+	var key *data.PageKey
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		page, next, err := query.Page(key, pageSize)
+		if err != nil {
+			return err
+		}
+		for _, entity := range page {
+			if err := w.write(timestamp(entity), entity); err != nil {
+				return err
+			}
+		}
+		if len(page) < pageSize {
+			return nil
+		}
+		key = next
+	}
+}
+
+// Writer of the rows of a data type of a symbol into a file per day (UTC). The file of a day is
+// written to a temporary file renamed once the day is complete, so that failed exports do not leave
+// truncated files behind
+type partitionWriter struct {
+	// Directory of the export
+	root string
+	// Directory of the files of the data type and symbol, relative to root
+	dir      string
+	format   export.Format
+	dataType types.DataType
+	symbol   string
+	sample   proto.Message
+
+	date   string
+	file   *os.File
+	hash   hash.Hash
+	writer export.Writer
+	rows   int
+	// Days whose file was written
+	exported map[string]bool
+	files    []types.ExportedFile
+}
+
+func (w *partitionWriter) write(timestamp int64, entity proto.Message) error {
+	date := time.Unix(0, timestamp).UTC().Format(time.DateOnly)
+	if date != w.date {
+		if err := w.closeFile(); err != nil {
+			return err
+		}
+		if w.exported[date] {
+			return fmt.Errorf("rows of %s are not contiguous", date)
+		}
+		if err := w.openFile(date); err != nil {
+			return err
+		}
+	}
+	w.rows++
+	return w.writer.Write(entity)
+}
+
+func (w *partitionWriter) path(date string) string {
+	return filepath.Join(w.dir, date+"."+string(w.format))
+}
+
+func (w *partitionWriter) openFile(date string) error {
+	dir := filepath.Join(w.root, w.dir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(dir, "."+date+".*.tmp")
+	if err != nil {
+		return err
+	}
+	w.hash = sha256.New()
+	writer, err := export.NewWriter(w.format, io.MultiWriter(file, w.hash), w.sample)
+	if err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+	w.date = date
+	w.file = file
+	w.writer = writer
+	w.rows = 0
+	return nil
+}
+
+// Close the file of the current day and record it in the manifest
+func (w *partitionWriter) closeFile() error {
+	if w.file == nil {
+		return nil
+	}
+	defer func() {
+		w.file = nil
+		w.exported[w.date] = true
+	}()
+	if err := w.writer.Close(); err != nil {
+		w.abort()
+		return err
+	}
+	info, err := w.file.Stat()
+	if err == nil {
+		err = w.file.Close()
+	}
+	if err == nil {
+		err = os.Rename(w.file.Name(), filepath.Join(w.root, w.path(w.date)))
+	}
+	if err != nil {
+		os.Remove(w.file.Name())
+		return err
+	}
+	w.files = append(w.files, types.ExportedFile{
+		Path:     filepath.ToSlash(w.path(w.date)),
+		DataType: w.dataType,
+		Symbol:   w.symbol,
+		Date:     w.date,
+		Rows:     w.rows,
+		Size:     info.Size(),
+		SHA256:   hex.EncodeToString(w.hash.Sum(nil)),
+	})
+	return nil
+}
+
+// Remove the file of the current day
+func (w *partitionWriter) abort() {
+	if w.file == nil {
+		return
+	}
+	w.file.Close()
+	os.Remove(w.file.Name())
+	w.file = nil
+}
+
+// Write the request and the files of an export in the manifest of its directory
+func writeManifest(dir string, req requests.ExportRequest, files []types.ExportedFile) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	manifest, err := json.MarshalIndent(struct {
+		Request requests.ExportRequest
+		Files   []types.ExportedFile
+	}{req, files}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, manifestFile), manifest, 0o644)
+}
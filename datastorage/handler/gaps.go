@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"tradingplatform/datastorage/data"
+
+	"tradingplatform/shared/calendar"
+	"tradingplatform/shared/entities"
+	"tradingplatform/shared/logging"
+	"tradingplatform/shared/metrics"
+	"tradingplatform/shared/requests"
+	"tradingplatform/shared/types"
+	"tradingplatform/shared/utils"
+)
+
+// HandleDataGapsRequest finds the bars missing from the datastorage in the range of a request and
+// fills them with data requested from the dataprovider if the request asks for it
+func HandleDataGapsRequest(ctx context.Context, req requests.DataGapsRequest) types.DataGapsResponse {
+	logging.Log().Debug().RawJSON("dataGapsRequest", req.JSON()).Msg("handling data gaps request")
+
+	cal, ok := calendar.GetCalendar(req.Calendar)
+	if !ok {
+		return types.NewDataGapsError(fmt.Errorf("invalid calendar %s", req.Calendar))
+	}
+	if req.DataType == types.DailyBars && req.TimeFrame != types.OneDay {
+		return types.NewDataGapsError(
+			fmt.Errorf("invalid timeframe %s for %s, only %s is supported", req.TimeFrame, req.DataType, types.OneDay),
+		)
+	}
+
+	timestamps, err := data.GetBarTimestamps(req.DataType,
+		string(req.Source),
+		req.Symbol,
+		string(req.AssetClass),
+		req.StartTime,
+		req.EndTime,
+		string(req.TimeFrame))
+	if err != nil {
+		logging.Log().Error().Err(err).RawJSON("dataGapsRequest", req.JSON()).Msg("getting bar timestamps from database")
+		return types.NewDataGapsError(err)
+	}
+	barGaps, err := data.FindBarGaps(cal, req.TimeFrame, time.Unix(0, req.StartTime), time.Unix(0, req.EndTime), timestamps)
+	if err != nil {
+		return types.NewDataGapsError(err)
+	}
+
+	gaps := make([]types.DataGap, 0, len(barGaps))
+	for _, barGap := range barGaps {
+		gap := types.DataGap{
+			Start:   barGap.Start.UnixNano(),
+			End:     barGap.End.UnixNano(),
+			Missing: len(barGap.Missing),
+		}
+		if req.Fill {
+			gap.Filled, err = fillBarGap(ctx, req, cal, barGap)
+			if err != nil {
+				gap.Err = err.Error()
+			}
+		}
+		gaps = append(gaps, gap)
+	}
+
+	response := types.NewDataGapsResponse(types.Success, "", nil, gaps)
+	response.Message = fmt.Sprintf("Found %d missing bars in %d gaps", response.Missing, len(gaps))
+	if req.Fill {
+		response.Message += fmt.Sprintf(", filled %d", response.Filled)
+	}
+	return response
+}
+
+// Request the bars of a gap from the dataprovider and store them, returns the number of missing
+// bars that were received
+func fillBarGap(ctx context.Context, req requests.DataGapsRequest, cal calendar.Calendar, gap data.BarGap) (int, error) {
+	dataRequest := req.DataRequest(gap.Start.UnixNano(), gap.End.UnixNano())
+	bars, err := requestBars(ctx, dataRequest)
+	if err != nil {
+		logging.Log().Warn().
+			Err(err).
+			RawJSON("dataRequest", dataRequest.JSON()).
+			Msg("requesting missing bars from dataprovider")
+		return 0, err
+	}
+	if len(bars) == 0 {
+		return 0, nil
+	}
+
+	start := time.Now()
+	if req.DataType == types.DailyBars {
+		err = data.InsertBatchEntity(data.DailyBarsFromEntities(bars))
+	} else {
+		err = data.InsertBatchEntity(data.BarsFromEntities(bars))
+	}
+	metrics.ObserveInsert(string(req.DataType), len(bars), start, err)
+	if err != nil {
+		return 0, err
+	}
+
+	received := make(map[int64]struct{}, len(bars))
+	for _, bar := range bars {
+		p, _ := data.GetBarPeriod(cal, req.TimeFrame, time.Unix(0, bar.TimestampNs))
+		received[p.Start.UnixNano()] = struct{}{}
+	}
+	filled := 0
+	for _, p := range gap.Missing {
+		if _, ok := received[p.Start.UnixNano()]; ok {
+			filled++
+		}
+	}
+	return filled, nil
+}
+
+// Request bars from the dataprovider
+func requestBars(ctx context.Context, dataRequest requests.DataRequest) ([]*entities.Bar, error) {
+	var bars []*entities.Bar
+	err := requests.RequestData(ctx, utils.NewCommandTopic(types.DataProvider), dataRequest, func(msg *entities.Message) {
+		utils.HandleEntity(msg, &entities.Bar{}, func(bar *entities.Bar) error {
+			bars = append(bars, bar)
+			return nil
+		})
+	})
+	return bars, err
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"strconv"
+	"tradingplatform/datastorage/subscriber"
+	shsubscriber "tradingplatform/shared/communication/subscriber"
+	"tradingplatform/shared/data"
+	"tradingplatform/shared/logging"
+	"tradingplatform/shared/requests"
+	"tradingplatform/shared/types"
+)
+
+// Attach again the topics of the local database subscribed in a previous run with their agent count.
+// The topics that cannot be subscribed are reported and removed from the local database, returns the
+// number of failed topics
+func RestoreSubscribedTopics() int {
+	restored, failed := 0, 0
+	for _, topic := range data.GetSubscribedTopics() {
+		req, err := requests.NewStreamSubscribeRequestFromRaw(
+			[]string{topic.Topic + "," + strconv.Itoa(topic.AgentsCount)},
+			types.StreamAddOp)
+		if err != nil {
+			logging.Log().Error().
+				Err(err).
+				Str("topic", topic.Topic).
+				Int("agentsCount", topic.AgentsCount).
+				Msg("restoring subscribed topic of the local database, the topic is removed")
+			data.RemoveSubscribedTopic(topic.Topic)
+			failed++
+			continue
+		}
+		for _, su := range req.StreamSubscribeWithAgents {
+			shsubscriber.AttatchFunctionalityRoundRobin(su.Topic, subscriber.HandleStoreData, su.AgentCount)
+		}
+		restored++
+	}
+	if restored+failed > 0 {
+		logging.Log().Info().
+			Int("restored", restored).
+			Int("failed", failed).
+			Msg("restored subscribed topics of the local database")
+	}
+	return failed
+}
@@ -1,93 +1,142 @@
 package subscriber
 
 import (
+	"time"
+
 	"tradingplatform/datastorage/data"
 
 	"tradingplatform/shared/communication/subscriber"
 	"tradingplatform/shared/entities"
+	"tradingplatform/shared/metrics"
 	"tradingplatform/shared/types"
 	"tradingplatform/shared/utils"
 )
 
-func HandleStoreDataFromQueue(msg *entities.Message) {
+func HandleStoreDataFromQueue(msg *entities.Message) error {
 	queue := subscriber.DrainQueue(msg.Topic)
 	if len(queue) == 0 {
-		return
+		return nil
 	}
+	start := time.Now()
+	err := storeQueue(msg, queue)
+	metrics.ObserveInsert(msg.DataType, len(queue), start, err)
+	return err
+}
+
+// Store the entities of a complete data queue in a batch
+func storeQueue(msg *entities.Message, queue []*entities.Message) error {
 	switch msg.DataType {
 	case string(types.Bar):
-		utils.HandleEntityQueueWithConversion(queue,
+		return utils.HandleEntityQueueWithConversion(queue,
 			&entities.Bar{},
 			data.InsertBatchEntity[data.Bar],
 			data.BarsFromEntities)
 	case string(types.DailyBars):
-		utils.HandleEntityQueueWithConversion(queue,
+		return utils.HandleEntityQueueWithConversion(queue,
 			&entities.Bar{},
 			data.InsertBatchEntity[data.DailyBar],
 			data.DailyBarsFromEntities)
-	case string(types.Trades):
-		utils.HandleEntityQueueWithConversion(queue,
+	case string(types.Trades), string(types.TradeUpdates):
+		return utils.HandleEntityQueueWithConversion(queue,
 			&entities.Trade{},
 			data.InsertBatchEntity[data.Trade],
 			data.TradesFromEntities)
 	case string(types.Quotes):
-		utils.HandleEntityQueueWithConversion(queue,
+		return utils.HandleEntityQueueWithConversion(queue,
 			&entities.Quote{},
 			data.InsertBatchEntity[data.Quote],
 			data.QuotesFromEntities)
 	case string(types.LULD):
-		utils.HandleEntityQueueWithConversion(queue,
+		return utils.HandleEntityQueueWithConversion(queue,
 			&entities.LULD{},
 			data.InsertBatchEntity[data.LULD],
 			data.LULDsFromEntities)
 	case string(types.Orderbook):
-		utils.HandleEntityQueueWithConversion(queue,
+		return utils.HandleEntityQueueWithConversion(queue,
 			&entities.Orderbook{},
 			data.InsertBatchEntity[data.Orderbook],
 			data.OrderbooksFromEntities)
 	case string(types.Status):
-		utils.HandleEntityQueueWithConversion(queue,
+		return utils.HandleEntityQueueWithConversion(queue,
 			&entities.TradingStatus{},
 			data.InsertBatchEntity[data.TradingStatus],
 			data.TradingStatusesFromEntities)
 	case string(types.RawText):
-		utils.HandleEntityQueueWithConversion(queue,
+		return utils.HandleEntityQueueWithConversion(queue,
 			&entities.News{},
 			data.InsertBatchNews,
 			data.NewsFromEntities)
 	case string(types.NewsWithSentiment):
-		utils.HandleEntityQueueWithConversion(queue,
+		return utils.HandleEntityQueueWithConversion(queue,
 			&entities.News{},
 			data.InsertBatchNewsWithSentiment,
 			data.NewsFromEntities)
+	case string(types.Indicator):
+		return utils.HandleEntityQueueWithConversion(queue,
+			&entities.Indicator{},
+			data.InsertBatchEntity[data.Indicator],
+			data.IndicatorsFromEntities)
+	case string(types.Orders):
+		return utils.HandleEntityQueueWithConversion(queue,
+			&entities.Order{},
+			data.InsertBatchEntity[data.Order],
+			data.OrdersFromEntities)
+	case string(types.Fills):
+		return utils.HandleEntityQueueWithConversion(queue,
+			&entities.Fill{},
+			data.InsertBatchEntity[data.Fill],
+			data.FillsFromEntities)
+	case string(types.Positions):
+		return utils.HandleEntityQueueWithConversion(queue,
+			&entities.Position{},
+			data.InsertBatchEntity[data.Position],
+			data.PositionsFromEntities)
 	}
+	return nil
 }
 
-func HandleStoreData(msg *entities.Message) {
+// Store the data of a message, the message is acknowledged only if no error is returned
+func HandleStoreData(msg *entities.Message) error {
 	if subscriber.IsQueue(msg.Topic) {
-		HandleStoreDataFromQueue(msg)
-		return
+		return HandleStoreDataFromQueue(msg)
 	}
+	start := time.Now()
+	err := storeEntity(msg)
+	metrics.ObserveInsert(msg.DataType, 1, start, err)
+	return err
+}
+
+// Store the entity of a stream message
+func storeEntity(msg *entities.Message) error {
 	switch msg.DataType {
 	case string(types.Bar):
-		utils.HandleEntity[*entities.Bar](msg, &entities.Bar{}, data.InsertBar)
+		return utils.HandleEntity[*entities.Bar](msg, &entities.Bar{}, data.InsertBar)
 	case string(types.DailyBars):
-		utils.HandleEntity[*entities.Bar](msg, &entities.Bar{}, data.InsertDailyBar)
-	case string(types.Trades):
-		utils.HandleEntity[*entities.Trade](msg, &entities.Trade{}, data.InsertTrade)
+		return utils.HandleEntity[*entities.Bar](msg, &entities.Bar{}, data.InsertDailyBar)
+	case string(types.Trades), string(types.TradeUpdates):
+		return utils.HandleEntity[*entities.Trade](msg, &entities.Trade{}, data.InsertTrade)
 	case string(types.Quotes):
-		utils.HandleEntity[*entities.Quote](msg, &entities.Quote{}, data.InsertQuote)
+		return utils.HandleEntity[*entities.Quote](msg, &entities.Quote{}, data.InsertQuote)
 	case string(types.LULD):
-		utils.HandleEntity[*entities.LULD](msg, &entities.LULD{}, data.InsertLULD)
+		return utils.HandleEntity[*entities.LULD](msg, &entities.LULD{}, data.InsertLULD)
 	case string(types.Log):
-		data.InsertLog(msg.Payload)
+		return data.InsertLog(msg.Payload)
 	case string(types.Orderbook):
-		utils.HandleEntity[*entities.Orderbook](msg, &entities.Orderbook{}, data.InsertOrderbook)
+		return utils.HandleEntity[*entities.Orderbook](msg, &entities.Orderbook{}, data.InsertOrderbook)
 	case string(types.RawText):
-		utils.HandleEntity[*entities.News](msg, &entities.News{}, data.InsertNews)
+		return utils.HandleEntity[*entities.News](msg, &entities.News{}, data.InsertNews)
 	case string(types.NewsWithSentiment):
-		utils.HandleEntity[*entities.News](msg, &entities.News{}, data.InsertNewsWithSentiment)
+		return utils.HandleEntity[*entities.News](msg, &entities.News{}, data.InsertNewsWithSentiment)
 	case string(types.Status):
-		utils.HandleEntity[*entities.TradingStatus](msg, &entities.TradingStatus{}, data.InsertTradingStatus)
+		return utils.HandleEntity[*entities.TradingStatus](msg, &entities.TradingStatus{}, data.InsertTradingStatus)
+	case string(types.Indicator):
+		return utils.HandleEntity[*entities.Indicator](msg, &entities.Indicator{}, data.InsertIndicator)
+	case string(types.Orders):
+		return utils.HandleEntity[*entities.Order](msg, &entities.Order{}, data.InsertOrder)
+	case string(types.Fills):
+		return utils.HandleEntity[*entities.Fill](msg, &entities.Fill{}, data.InsertFill)
+	case string(types.Positions):
+		return utils.HandleEntity[*entities.Position](msg, &entities.Position{}, data.InsertPosition)
 	}
+	return nil
 }
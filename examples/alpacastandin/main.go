@@ -0,0 +1,523 @@
+// Local stand-in for the Alpaca trading API built from the fixtures checked into
+// execution/alpaca/testdata. Orders are accepted for any key pair, each key has its own account,
+// and are filled at a fixed price with a partial fill first. Start it and point the execution
+// component to it with
+//
+//	ALPACA_TRADING_URL=http://localhost:8091
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var fixtures string
+var interval time.Duration
+var price float64
+
+type account struct {
+	orders map[string]map[string]any
+	// Quantity and average entry price of the positions by symbol
+	quantities map[string]float64
+	prices     map[string]float64
+	// Trade updates sent so far, replayed to the streams requesting updates since a time
+	updates     []map[string]any
+	subscribers map[chan map[string]any]struct{}
+}
+
+var accounts = map[string]*account{}
+var lock sync.Mutex
+
+// Read a fixture and decode it
+func readFixture(name string) (map[string]any, error) {
+	b, err := os.ReadFile(filepath.Join(fixtures, name))
+	if err != nil {
+		return nil, err
+	}
+	var v map[string]any
+	err = json.Unmarshal(b, &v)
+	return v, err
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// Write an error like the Alpaca API does
+func writeError(w http.ResponseWriter, status int, code int, message string) {
+	writeJSON(w, status, map[string]any{"code": code, "message": message})
+}
+
+func formatDecimal(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func parseDecimal(v any) float64 {
+	s, _ := v.(string)
+	f, _ := strconv.ParseFloat(s, 64)
+	return f
+}
+
+func copyOrder(order map[string]any) map[string]any {
+	c := make(map[string]any, len(order))
+	for k, v := range order {
+		c[k] = v
+	}
+	return c
+}
+
+// Get the account of the key of a request, requests without key are rejected
+func getAccount(w http.ResponseWriter, r *http.Request) *account {
+	key := r.Header.Get("APCA-API-KEY-ID")
+	if key == "" {
+		writeError(w, http.StatusUnauthorized, 40110000, "request is not authorized")
+		return nil
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	a, ok := accounts[key]
+	if !ok {
+		a = &account{
+			orders:      map[string]map[string]any{},
+			quantities:  map[string]float64{},
+			prices:      map[string]float64{},
+			subscribers: map[chan map[string]any]struct{}{},
+		}
+		accounts[key] = a
+	}
+	return a
+}
+
+// Send a trade update of an order to the streams of the account. Must be called with the lock held
+func (a *account) sendUpdate(event string, order map[string]any, fillQty float64) {
+	update, err := readFixture("trade_update.json")
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	update["at"] = now
+	update["event"] = event
+	update["event_id"] = uuid.New().String()
+	update["order"] = copyOrder(order)
+	if fillQty > 0 {
+		update["execution_id"] = uuid.New().String()
+		update["price"] = formatDecimal(price)
+		update["qty"] = formatDecimal(fillQty)
+		update["timestamp"] = now
+		update["position_qty"] = formatDecimal(a.quantities[order["symbol"].(string)])
+	} else {
+		delete(update, "execution_id")
+		delete(update, "price")
+		delete(update, "qty")
+		delete(update, "timestamp")
+		delete(update, "position_qty")
+	}
+	a.updates = append(a.updates, update)
+	for ch := range a.subscribers {
+		select {
+		case ch <- update:
+		default:
+			log.Println("dropping trade update of slow stream")
+		}
+	}
+}
+
+// Whether an order can be filled at the price of the stand-in
+func isMarketable(order map[string]any) bool {
+	buy := order["side"] == "buy"
+	switch order["type"] {
+	case "limit":
+		limit := parseDecimal(order["limit_price"])
+		return (buy && limit >= price) || (!buy && limit <= price)
+	case "stop":
+		stop := parseDecimal(order["stop_price"])
+		return (buy && price >= stop) || (!buy && price <= stop)
+	}
+	return true
+}
+
+// Fill a quantity of an order and update the position of its symbol. Must be called with the lock
+// held
+func (a *account) fill(order map[string]any, qty float64) {
+	filled := parseDecimal(order["filled_qty"]) + qty
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	order["filled_qty"] = formatDecimal(filled)
+	order["filled_avg_price"] = formatDecimal(price)
+	order["updated_at"] = now
+	event := "partial_fill"
+	order["status"] = "partially_filled"
+	if filled >= parseDecimal(order["qty"]) {
+		event = "fill"
+		order["status"] = "filled"
+		order["filled_at"] = now
+	}
+
+	symbol := order["symbol"].(string)
+	signed := qty
+	if order["side"] == "sell" {
+		signed = -qty
+	}
+	previous := a.quantities[symbol]
+	if previous == 0 || (previous > 0) == (signed > 0) {
+		a.prices[symbol] = (a.prices[symbol]*previous + price*signed) / (previous + signed)
+	}
+	a.quantities[symbol] = previous + signed
+	if a.quantities[symbol] == 0 {
+		delete(a.quantities, symbol)
+		delete(a.prices, symbol)
+	}
+	a.sendUpdate(event, order, qty)
+}
+
+// Accept a new order, then fill marketable orders in two steps
+func (a *account) accept(order map[string]any) {
+	time.Sleep(interval)
+	lock.Lock()
+	if order["status"] != "accepted" {
+		lock.Unlock()
+		return
+	}
+	order["status"] = "new"
+	order["updated_at"] = time.Now().UTC().Format(time.RFC3339Nano)
+	a.sendUpdate("new", order, 0)
+	lock.Unlock()
+
+	qty := parseDecimal(order["qty"])
+	for _, part := range []float64{qty / 2, qty / 2} {
+		time.Sleep(interval)
+		lock.Lock()
+		open := order["status"] == "new" || order["status"] == "partially_filled"
+		if open && isMarketable(order) {
+			a.fill(order, part)
+		}
+		lock.Unlock()
+		if !open {
+			return
+		}
+	}
+}
+
+func (a *account) newOrder(req map[string]any) (map[string]any, error) {
+	order, err := readFixture("order.json")
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	order["id"] = uuid.New().String()
+	order["client_order_id"] = uuid.New().String()
+	if id, _ := req["client_order_id"].(string); id != "" {
+		order["client_order_id"] = id
+	}
+	for _, field := range []string{"symbol", "qty", "side", "type", "time_in_force", "limit_price", "stop_price"} {
+		if v, ok := req[field]; ok {
+			order[field] = v
+		}
+	}
+	order["order_type"] = order["type"]
+	order["asset_class"] = "us_equity"
+	if strings.Contains(order["symbol"].(string), "/") {
+		order["asset_class"] = "crypto"
+	}
+	order["created_at"] = now
+	order["updated_at"] = now
+	order["submitted_at"] = now
+	order["status"] = "accepted"
+	return order, nil
+}
+
+// Place an order, orders without symbol or quantity are rejected like Alpaca does
+func (a *account) placeOrder(w http.ResponseWriter, r *http.Request) {
+	var req map[string]any
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, 40010000, "request body format is invalid")
+		return
+	}
+	if symbol, _ := req["symbol"].(string); symbol == "" {
+		writeError(w, http.StatusUnprocessableEntity, 40010001, "symbol is required")
+		return
+	}
+	if parseDecimal(req["qty"]) <= 0 {
+		writeError(w, http.StatusUnprocessableEntity, 40010001, "qty must be > 0")
+		return
+	}
+	order, err := a.newOrder(req)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, 50010000, err.Error())
+		return
+	}
+	lock.Lock()
+	a.orders[order["id"].(string)] = order
+	response := copyOrder(order)
+	lock.Unlock()
+	go a.accept(order)
+	writeJSON(w, http.StatusOK, response)
+}
+
+func isOpen(order map[string]any) bool {
+	switch order["status"] {
+	case "accepted", "new", "partially_filled":
+		return true
+	}
+	return false
+}
+
+func (a *account) serveOrders(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost {
+		a.placeOrder(w, r)
+		return
+	}
+	status := r.URL.Query().Get("status")
+	lock.Lock()
+	var orders []map[string]any
+	for _, order := range a.orders {
+		if status == "all" || (status == "closed") != isOpen(order) {
+			orders = append(orders, copyOrder(order))
+		}
+	}
+	lock.Unlock()
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i]["created_at"].(string) < orders[j]["created_at"].(string)
+	})
+	if r.URL.Query().Get("direction") != "asc" {
+		for i, j := 0, len(orders)-1; i < j; i, j = i+1, j-1 {
+			orders[i], orders[j] = orders[j], orders[i]
+		}
+	}
+	if orders == nil {
+		orders = []map[string]any{}
+	}
+	writeJSON(w, http.StatusOK, orders)
+}
+
+// Get, cancel or replace an order
+func (a *account) serveOrder(w http.ResponseWriter, r *http.Request, id string) {
+	lock.Lock()
+	defer lock.Unlock()
+	order, ok := a.orders[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, 40410000, "order not found")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, copyOrder(order))
+	case http.MethodDelete:
+		if !isOpen(order) {
+			writeError(w, http.StatusUnprocessableEntity, 42210000, "order is not cancelable")
+			return
+		}
+		now := time.Now().UTC().Format(time.RFC3339Nano)
+		order["status"] = "canceled"
+		order["canceled_at"] = now
+		order["updated_at"] = now
+		a.sendUpdate("canceled", order, 0)
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodPatch:
+		if !isOpen(order) {
+			writeError(w, http.StatusUnprocessableEntity, 42210000, "order is not replaceable")
+			return
+		}
+		var req map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, 40010000, "request body format is invalid")
+			return
+		}
+		replacement := copyOrder(order)
+		for _, field := range []string{"qty", "limit_price", "stop_price", "time_in_force"} {
+			if v, ok := req[field]; ok && v != nil {
+				replacement[field] = v
+			}
+		}
+		if _, ok := req["qty"]; !ok || req["qty"] == nil {
+			replacement["qty"] = formatDecimal(parseDecimal(order["qty"]) - parseDecimal(order["filled_qty"]))
+		}
+		delete(replacement, "client_order_id")
+		replacement, err := a.newOrder(replacement)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, 50010000, err.Error())
+			return
+		}
+		if clientID, _ := req["client_order_id"].(string); clientID != "" {
+			replacement["client_order_id"] = clientID
+		}
+		replacement["replaces"] = id
+		now := time.Now().UTC().Format(time.RFC3339Nano)
+		order["status"] = "replaced"
+		order["replaced_at"] = now
+		order["replaced_by"] = replacement["id"]
+		order["updated_at"] = now
+		a.orders[replacement["id"].(string)] = replacement
+		a.sendUpdate("replaced", order, 0)
+		go a.accept(replacement)
+		writeJSON(w, http.StatusOK, copyOrder(replacement))
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// The position of a symbol as the Alpaca API reports it
+func (a *account) position(symbol string) (map[string]any, error) {
+	position, err := readFixture("position.json")
+	if err != nil {
+		return nil, err
+	}
+	qty := a.quantities[symbol]
+	side := "long"
+	if qty < 0 {
+		side = "short"
+	}
+	position["symbol"] = symbol
+	position["side"] = side
+	position["qty"] = formatDecimal(qty)
+	position["qty_available"] = formatDecimal(qty)
+	position["avg_entry_price"] = formatDecimal(a.prices[symbol])
+	position["current_price"] = formatDecimal(price)
+	position["market_value"] = formatDecimal(qty * price)
+	position["cost_basis"] = formatDecimal(qty * a.prices[symbol])
+	return position, nil
+}
+
+func (a *account) servePositions(w http.ResponseWriter, r *http.Request) {
+	lock.Lock()
+	defer lock.Unlock()
+	positions := []map[string]any{}
+	for symbol := range a.quantities {
+		position, err := a.position(symbol)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, 50010000, err.Error())
+			return
+		}
+		positions = append(positions, position)
+	}
+	writeJSON(w, http.StatusOK, positions)
+}
+
+// Serve the open position of a symbol, given without the slash of crypto symbols
+func (a *account) servePosition(w http.ResponseWriter, r *http.Request, symbol string) {
+	lock.Lock()
+	defer lock.Unlock()
+	for held := range a.quantities {
+		if strings.ReplaceAll(held, "/", "") != symbol {
+			continue
+		}
+		position, err := a.position(held)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, 50010000, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, position)
+		return
+	}
+	writeError(w, http.StatusNotFound, 40410000, "position does not exist")
+}
+
+// Stream the trade updates of the account as server-sent events, the updates since the time of the
+// request are sent first
+func (a *account) serveTradeUpdates(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	ch := make(chan map[string]any, 100)
+	lock.Lock()
+	var missed []map[string]any
+	if since := r.URL.Query().Get("since"); since != "" {
+		t, err := time.Parse(time.RFC3339Nano, since)
+		if err != nil {
+			lock.Unlock()
+			writeError(w, http.StatusBadRequest, 40010000, "invalid since")
+			return
+		}
+		for _, update := range a.updates {
+			at, _ := time.Parse(time.RFC3339Nano, update["at"].(string))
+			if !at.Before(t) {
+				missed = append(missed, update)
+			}
+		}
+	}
+	a.subscribers[ch] = struct{}{}
+	lock.Unlock()
+	defer func() {
+		lock.Lock()
+		delete(a.subscribers, ch)
+		lock.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+	write := func(update map[string]any) {
+		b, _ := json.Marshal(update)
+		fmt.Fprintf(w, "data: %s\n\n", b)
+		flusher.Flush()
+	}
+	for _, update := range missed {
+		write(update)
+	}
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case update := <-ch:
+			write(update)
+		}
+	}
+}
+
+func serve(w http.ResponseWriter, r *http.Request) {
+	log.Println(r.Method, r.URL)
+	a := getAccount(w, r)
+	if a == nil {
+		return
+	}
+	switch path := r.URL.Path; {
+	case path == "/v2/orders":
+		a.serveOrders(w, r)
+	case strings.HasPrefix(path, "/v2/orders/"):
+		a.serveOrder(w, r, strings.TrimPrefix(path, "/v2/orders/"))
+	case path == "/v2/positions":
+		a.servePositions(w, r)
+	case strings.HasPrefix(path, "/v2/positions/"):
+		a.servePosition(w, r, strings.TrimPrefix(path, "/v2/positions/"))
+	case path == "/v2beta1/events/trades":
+		a.serveTradeUpdates(w, r)
+	default:
+		writeError(w, http.StatusNotFound, 40410000, "endpoint not found")
+	}
+}
+
+func main() {
+	addr := flag.String("addr", ":8091", "Address to listen on")
+	flag.StringVar(&fixtures, "fixtures", "execution/alpaca/testdata", "Directory containing the fixtures")
+	flag.DurationVar(&interval, "interval", time.Second, "Interval between the updates of an order")
+	flag.Float64Var(&price, "price", 0, "Price of the fills, the price of the trade update fixture by default")
+	flag.Parse()
+
+	if price == 0 {
+		update, err := readFixture("trade_update.json")
+		if err != nil {
+			log.Fatal(err)
+		}
+		price = parseDecimal(update["price"])
+	}
+
+	log.Println("serving alpaca stand-in on", *addr)
+	log.Fatal(http.ListenAndServe(*addr, http.HandlerFunc(serve)))
+}
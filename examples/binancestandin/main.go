@@ -0,0 +1,202 @@
+// Local stand-in for the Binance public market data API serving the fixtures checked into
+// dataprovider/provider/binance/testdata. Start it and point the dataprovider to it with
+//
+//	BINANCE_REST_URL=http://localhost:8090 BINANCE_WS_URL=ws://localhost:8090
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"nhooyr.io/websocket"
+)
+
+var fixtures string
+var interval time.Duration
+
+// Read a fixture and decode it
+func readFixture(name string) (any, error) {
+	b, err := os.ReadFile(filepath.Join(fixtures, name))
+	if err != nil {
+		return nil, err
+	}
+	var v any
+	err = json.Unmarshal(b, &v)
+	return v, err
+}
+
+// Serve a fixture as is
+func serveFixture(name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		v, err := readFixture(name)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(v)
+	}
+}
+
+// Serve the exchange information of the requested symbol, unknown symbols are rejected like Binance does
+func serveExchangeInfo(w http.ResponseWriter, r *http.Request) {
+	v, err := readFixture("exchangeInfo.json")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	info := v.(map[string]any)
+	symbol := r.URL.Query().Get("symbol")
+	if symbol != "" {
+		var matching []any
+		for _, s := range info["symbols"].([]any) {
+			if s.(map[string]any)["symbol"] == symbol {
+				matching = append(matching, s)
+			}
+		}
+		if len(matching) == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"code":-1121,"msg":"Invalid symbol."}`)
+			return
+		}
+		info["symbols"] = matching
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(info)
+}
+
+// Build the next event of a stream from its fixture, with the symbol and times of the subscription
+func streamEvent(stream string, now time.Time) (any, error) {
+	symbol, kind, _ := strings.Cut(stream, "@")
+	var name string
+	switch {
+	case strings.HasPrefix(kind, "kline"):
+		name = "stream_kline.json"
+	case kind == "aggTrade":
+		name = "stream_aggTrade.json"
+	case kind == "bookTicker":
+		name = "stream_bookTicker.json"
+	case strings.HasPrefix(kind, "depth"):
+		name = "stream_depth.json"
+	default:
+		return nil, fmt.Errorf("unknown stream %s", stream)
+	}
+	v, err := readFixture(name)
+	if err != nil {
+		return nil, err
+	}
+	event := v.(map[string]any)
+	ms := float64(now.UnixMilli())
+	if _, ok := event["s"]; ok {
+		event["s"] = strings.ToUpper(symbol)
+	}
+	if _, ok := event["E"]; ok {
+		event["E"] = ms
+	}
+	if _, ok := event["T"]; ok {
+		event["T"] = ms
+	}
+	if k, ok := event["k"].(map[string]any); ok {
+		start := now.Truncate(time.Minute)
+		k["s"] = strings.ToUpper(symbol)
+		k["t"] = float64(start.UnixMilli())
+		k["T"] = float64(start.Add(time.Minute).UnixMilli() - 1)
+	}
+	return event, nil
+}
+
+// Serve the combined websocket streams, each subscribed stream emits its fixture periodically
+func serveStream(w http.ResponseWriter, r *http.Request) {
+	conn, err := websocket.Accept(w, r, nil)
+	if err != nil {
+		log.Println("accepting websocket:", err)
+		return
+	}
+	defer conn.CloseNow()
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	var lock sync.Mutex
+	subscriptions := map[string]context.CancelFunc{}
+	write := func(v any) {
+		b, _ := json.Marshal(v)
+		lock.Lock()
+		defer lock.Unlock()
+		conn.Write(ctx, websocket.MessageText, b)
+	}
+
+	for {
+		_, msg, err := conn.Read(ctx)
+		if err != nil {
+			return
+		}
+		var req struct {
+			Method string   `json:"method"`
+			Params []string `json:"params"`
+			ID     int64    `json:"id"`
+		}
+		if err := json.Unmarshal(msg, &req); err != nil {
+			log.Println("unmarshalling request:", err)
+			continue
+		}
+		log.Println(req.Method, req.Params)
+		for _, stream := range req.Params {
+			lock.Lock()
+			if stop, ok := subscriptions[stream]; ok {
+				stop()
+				delete(subscriptions, stream)
+			}
+			if req.Method == "SUBSCRIBE" {
+				streamCtx, stop := context.WithCancel(ctx)
+				subscriptions[stream] = stop
+				go func(stream string) {
+					ticker := time.NewTicker(interval)
+					defer ticker.Stop()
+					for {
+						select {
+						case <-streamCtx.Done():
+							return
+						case now := <-ticker.C:
+							event, err := streamEvent(stream, now)
+							if err != nil {
+								log.Println(err)
+								return
+							}
+							write(map[string]any{"stream": stream, "data": event})
+						}
+					}
+				}(stream)
+			}
+			lock.Unlock()
+		}
+		write(map[string]any{"result": nil, "id": req.ID})
+	}
+}
+
+func main() {
+	addr := flag.String("addr", ":8090", "Address to listen on")
+	flag.StringVar(&fixtures, "fixtures", "dataprovider/provider/binance/testdata", "Directory containing the fixtures")
+	flag.DurationVar(&interval, "interval", time.Second, "Interval between two events of a stream")
+	flag.Parse()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/klines", serveFixture("klines.json"))
+	mux.HandleFunc("/api/v3/aggTrades", serveFixture("aggTrades.json"))
+	mux.HandleFunc("/api/v3/depth", serveFixture("depth.json"))
+	mux.HandleFunc("/api/v3/ticker/bookTicker", serveFixture("bookTicker.json"))
+	mux.HandleFunc("/api/v3/exchangeInfo", serveExchangeInfo)
+	mux.HandleFunc("/stream", serveStream)
+
+	log.Println("serving binance stand-in on", *addr)
+	log.Fatal(http.ListenAndServe(*addr, mux))
+}
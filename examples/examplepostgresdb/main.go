@@ -96,7 +96,7 @@ func main() {
 			Close:       newBar.Close,
 			Volume:      newBar.Volume,
 			VWAP:        newBar.VWAP,
-			Timestamp:   time.Unix(newBar.Timestamp, 0),
+			Timestamp:   time.Unix(0, newBar.TimestampNs),
 			TradeCount:  newBar.TradeCount,
 			Source:      newBar.Source,
 		}
@@ -0,0 +1,119 @@
+// Runs the JetStream backed stream topics against an embedded nats-server: trades are published
+// while no subscriber is running, then consumed by a subscriber whose first attempts fail
+// (e.g. the database is unavailable) to check that nothing is lost and failed messages are redelivered
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"tradingplatform/shared/communication"
+	"tradingplatform/shared/communication/producer"
+	"tradingplatform/shared/communication/subscriber"
+	sharedent "tradingplatform/shared/entities"
+	"tradingplatform/shared/logging"
+	"tradingplatform/shared/types"
+	"tradingplatform/shared/utils"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/rs/zerolog"
+)
+
+func main() {
+	count := flag.Int("count", 100, "Number of trades to publish")
+	failures := flag.Int("failures", 10, "Number of handling attempts that fail before the subscriber recovers")
+	timeout := flag.Duration("timeout", 30*time.Second, "Time to wait for all trades to be handled")
+	flag.Parse()
+
+	logger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stdout}).Level(zerolog.InfoLevel)
+	logging.SetLogger(&logger)
+
+	storeDir, err := os.MkdirTemp("", "jetstream")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(storeDir)
+	ns, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      -1,
+		JetStream: true,
+		StoreDir:  storeDir,
+	})
+	if err != nil {
+		panic(err)
+	}
+	go ns.Start()
+	if !ns.ReadyForConnections(5 * time.Second) {
+		panic("embedded nats-server not ready")
+	}
+	defer ns.Shutdown()
+
+	communication.SetNatsURL(ns.ClientURL())
+	cfg := communication.GetJetStreamConfig()
+	cfg.Enabled = true
+	cfg.Storage = "memory"
+	cfg.RetryDelay = 100 * time.Millisecond
+	communication.SetJetStreamConfig(cfg)
+
+	symbol := "TEST"
+	topic := utils.NewStreamTopic(types.DataProvider, types.Synthetic, types.Stock, types.Trades, symbol).Generate()
+	subscribeTopic := utils.NewStreamTopic(types.DataProvider, types.Synthetic, types.Stock, types.Trades, "*").Generate()
+
+	// Publish all trades before any subscriber exists, core NATS would drop them
+	for i := 0; i < *count; i++ {
+		trade := sharedent.Trade{
+			ID:          int64(i),
+			Symbol:      symbol,
+			Price:       100,
+			Size:        1,
+			TimestampNs: time.Now().UnixNano(),
+			Source:      string(types.Synthetic),
+			AssetClass:  string(types.Stock),
+		}
+		trade.SetFingerprint()
+		msg := sharedent.GenerateMessage(&trade, types.Trades, topic)
+		producer.GetStreamHandler(msg.Topic).Ch <- msg
+	}
+	producer.StopTopicHandler(topic[:strings.LastIndex(topic, ".")])
+	logging.Log().Info().Int("count", *count).Msg("published trades")
+
+	var attempts atomic.Int64
+	var handled sync.Map
+	var handledCount atomic.Int64
+	done := make(chan struct{})
+	subscriber.AttatchFunctionalityRoundRobin(subscribeTopic, func(msg *sharedent.Message) error {
+		if attempts.Add(1) <= int64(*failures) {
+			return fmt.Errorf("simulated storage failure")
+		}
+		var trade sharedent.Trade
+		if err := utils.HandleEntity(msg, &trade, func(*sharedent.Trade) error { return nil }); err != nil {
+			return err
+		}
+		if _, loaded := handled.LoadOrStore(trade.ID, struct{}{}); !loaded {
+			if handledCount.Add(1) == int64(*count) {
+				close(done)
+			}
+		}
+		return nil
+	}, 4)
+	defer subscriber.StopTopicHandler(subscribeTopic)
+
+	select {
+	case <-done:
+		logging.Log().Info().
+			Int64("handled", handledCount.Load()).
+			Int64("attempts", attempts.Load()).
+			Msg("all trades handled")
+	case <-time.After(*timeout):
+		logging.Log().Error().
+			Int64("handled", handledCount.Load()).
+			Int("expected", *count).
+			Msg("timed out waiting for trades")
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,234 @@
+package alpaca
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"tradingplatform/execution/publisher"
+	"tradingplatform/shared/accounts"
+	"tradingplatform/shared/entities"
+	"tradingplatform/shared/logging"
+	"tradingplatform/shared/requests"
+	"tradingplatform/shared/types"
+
+	"github.com/alpacahq/alpaca-trade-api-go/v3/alpaca"
+)
+
+// Bounds of the delay before reconnecting to the trade updates of an account
+var minReconnectDelay = time.Second
+var maxReconnectDelay = time.Minute
+
+// Trading clients by account
+var clients = make(map[requests.Account]*alpaca.Client)
+var clientsLock sync.RWMutex
+
+// Start routing the orders of the accounts of the registry to Alpaca, the trade updates of each
+// account are published as orders and fills until the context is done
+func Start(ctx context.Context) {
+	clientsLock.Lock()
+	defer clientsLock.Unlock()
+	for _, summary := range accounts.List() {
+		credentials, ok := accounts.Get(summary.Name)
+		if !ok {
+			continue
+		}
+		account := requests.Account(summary.Name)
+		client := alpaca.NewClient(alpaca.ClientOpts{
+			APIKey:    credentials.Key,
+			APISecret: credentials.Secret,
+			BaseURL:   tradingURL(credentials),
+		})
+		clients[account] = client
+		go streamTradeUpdates(ctx, account, client)
+		logging.Log().Info().
+			Str("account", string(account)).
+			Str("url", tradingURL(credentials)).
+			Msg("routing orders of account to alpaca")
+	}
+}
+
+func getClient(account requests.Account) (*alpaca.Client, error) {
+	clientsLock.RLock()
+	defer clientsLock.RUnlock()
+	client, ok := clients[account]
+	if !ok {
+		return nil, fmt.Errorf("no alpaca account %s configured", account)
+	}
+	return client, nil
+}
+
+// Submit an order, its updates are published from the trade updates of the account
+func Submit(req requests.OrderRequest) (*entities.Order, error) {
+	client, err := getClient(req.Account)
+	if err != nil {
+		return nil, err
+	}
+	order, err := client.PlaceOrder(alpaca.PlaceOrderRequest{
+		Symbol:        req.Symbol,
+		Qty:           toDecimal(req.Quantity),
+		Side:          alpaca.Side(req.Side),
+		Type:          alpaca.OrderType(req.Type),
+		TimeInForce:   toTimeInForce(req.AssetClass),
+		LimitPrice:    toDecimal(req.LimitPrice),
+		StopPrice:     toDecimal(req.StopPrice),
+		ClientOrderID: req.ClientOrderID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return OrderToEntity(req.Account, *order), nil
+}
+
+// Request the cancellation of an open order, the order is canceled once the trade update of the
+// cancellation is received
+func Cancel(account requests.Account, id string) (*entities.Order, error) {
+	client, err := getClient(account)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.CancelOrder(id); err != nil {
+		return nil, err
+	}
+	order, err := client.GetOrder(id)
+	if err != nil {
+		return nil, err
+	}
+	return OrderToEntity(account, *order), nil
+}
+
+// Replace the quantity or the prices of an open order, Alpaca replaces the order by a new order
+func Replace(req requests.OrderRequest) (*entities.Order, error) {
+	client, err := getClient(req.Account)
+	if err != nil {
+		return nil, err
+	}
+	order, err := client.ReplaceOrder(req.OrderID, alpaca.ReplaceOrderRequest{
+		Qty:           toDecimal(req.Quantity),
+		LimitPrice:    toDecimal(req.LimitPrice),
+		StopPrice:     toDecimal(req.StopPrice),
+		ClientOrderID: req.ClientOrderID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return OrderToEntity(req.Account, *order), nil
+}
+
+// GetOrders returns the open orders of an account in submission order
+func GetOrders(account requests.Account) ([]*entities.Order, error) {
+	client, err := getClient(account)
+	if err != nil {
+		return nil, err
+	}
+	orders, err := client.GetOrders(alpaca.GetOrdersRequest{
+		Status:    "open",
+		Limit:     500,
+		Direction: "asc",
+	})
+	if err != nil {
+		return nil, err
+	}
+	return OrdersToEntities(account, orders), nil
+}
+
+// GetPositions returns the open positions of an account
+func GetPositions(account requests.Account) ([]*entities.Position, error) {
+	client, err := getClient(account)
+	if err != nil {
+		return nil, err
+	}
+	positions, err := client.GetPositions()
+	if err != nil {
+		return nil, err
+	}
+	result := PositionsToEntities(account, positions)
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Symbol < result[j].Symbol
+	})
+	return result, nil
+}
+
+// Stream the trade updates of an account until the context is done, reconnecting with an
+// increasing delay after errors. Updates missed while disconnected are requested on reconnection
+func streamTradeUpdates(ctx context.Context, account requests.Account, client *alpaca.Client) {
+	var last time.Time
+	delay := minReconnectDelay
+	for {
+		req := alpaca.StreamTradeUpdatesRequest{}
+		if !last.IsZero() {
+			req.Since = last.Add(time.Nanosecond)
+		}
+		err := client.StreamTradeUpdates(ctx, func(update alpaca.TradeUpdate) {
+			last = update.At
+			delay = minReconnectDelay
+			handleTradeUpdate(account, client, update)
+		}, req)
+		if ctx.Err() != nil {
+			return
+		}
+		logging.Log().Warn().
+			Err(err).
+			Str("account", string(account)).
+			Dur("delay", delay).
+			Msg("alpaca trade updates disconnected, reconnecting")
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(2*delay, maxReconnectDelay)
+	}
+}
+
+// Publish the order of a trade update, and the fill and the position of fill and partial_fill updates
+func handleTradeUpdate(account requests.Account, client *alpaca.Client, update alpaca.TradeUpdate) {
+	logging.Log().Debug().
+		Str("account", string(account)).
+		Str("event", update.Event).
+		Str("orderId", update.Order.ID).
+		Msg("received alpaca trade update")
+	if update.Event != "fill" && update.Event != "partial_fill" {
+		publisher.Publish(OrderToEntity(account, update.Order), types.Orders)
+		return
+	}
+	fill := FillToEntity(account, update)
+	publisher.Publish(fill, types.Fills)
+	publisher.Publish(OrderToEntity(account, update.Order), types.Orders)
+	position, err := fillPosition(account, client, update)
+	if err != nil {
+		logging.Log().Error().
+			Err(err).
+			Str("account", string(account)).
+			Str("symbol", update.Order.Symbol).
+			Msg("getting alpaca position of fill")
+		return
+	}
+	position.Timestamp = fill.Timestamp
+	publisher.Publish(position, types.Positions)
+}
+
+// The position of the market of a fill after the fill, the average price of an open position is
+// requested as trade updates only carry its quantity
+func fillPosition(account requests.Account, client *alpaca.Client, update alpaca.TradeUpdate) (*entities.Position, error) {
+	order := update.Order
+	if update.PositionQty != nil && update.PositionQty.IsZero() {
+		return &entities.Position{
+			Account:    string(account),
+			Symbol:     order.Symbol,
+			Source:     string(types.Alpaca),
+			AssetClass: string(toAssetClass(order.AssetClass)),
+		}, nil
+	}
+	// Positions are identified by the symbol of their asset, e.g. BTCUSD for the orders of BTC/USD
+	position, err := client.GetPosition(strings.ReplaceAll(order.Symbol, "/", ""))
+	if err != nil {
+		return nil, err
+	}
+	entity := PositionToEntity(account, *position)
+	entity.Symbol = order.Symbol
+	return entity, nil
+}
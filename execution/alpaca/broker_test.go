@@ -0,0 +1,537 @@
+package alpaca
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"tradingplatform/shared/communication"
+	"tradingplatform/shared/communication/producer"
+	"tradingplatform/shared/entities"
+	"tradingplatform/shared/logging"
+	"tradingplatform/shared/requests"
+	"tradingplatform/shared/types"
+	"tradingplatform/shared/utils"
+
+	"github.com/alpacahq/alpaca-trade-api-go/v3/alpaca"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestMain(m *testing.M) {
+	logger := zerolog.Nop()
+	logging.SetLogger(&logger)
+	os.Exit(m.Run())
+}
+
+// A request received by the stand-in of the trading API
+type apiRequest struct {
+	Method string
+	Path   string
+	Body   map[string]any
+}
+
+// Stand-in of the trading API of an account, serving the order fixture with the fields of the
+// requests applied and recording the requests
+type standIn struct {
+	lock     sync.Mutex
+	requests []apiRequest
+	// Status of the order once it was canceled
+	canceled bool
+	// Trade updates served to the trade updates stream
+	updates []map[string]any
+}
+
+func newStandIn(t *testing.T, account requests.Account) *standIn {
+	s := &standIn{}
+	server := httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(server.Close)
+
+	clientsLock.Lock()
+	clients[account] = alpaca.NewClient(alpaca.ClientOpts{
+		APIKey:     "key",
+		APISecret:  "secret",
+		BaseURL:    server.URL,
+		RetryLimit: 1,
+		RetryDelay: time.Millisecond,
+	})
+	clientsLock.Unlock()
+	t.Cleanup(func() {
+		clientsLock.Lock()
+		delete(clients, account)
+		clientsLock.Unlock()
+	})
+	return s
+}
+
+func (s *standIn) serve(w http.ResponseWriter, r *http.Request) {
+	req := apiRequest{Method: r.Method, Path: r.URL.Path}
+	if r.Body != nil && r.ContentLength != 0 {
+		json.NewDecoder(r.Body).Decode(&req.Body)
+	}
+	s.lock.Lock()
+	s.requests = append(s.requests, req)
+	s.lock.Unlock()
+
+	if r.Header.Get("APCA-API-KEY-ID") != "key" {
+		http.Error(w, `{"code":40110000,"message":"request is not authorized"}`, http.StatusUnauthorized)
+		return
+	}
+	if r.Method == http.MethodGet && r.URL.Path == "/v2beta1/events/trades" {
+		s.streamTradeUpdates(w, r)
+		return
+	}
+	order, err := readFixture("order.json")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	id, _ := order["id"].(string)
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/v2/positions/"+order["symbol"].(string):
+		position, err := readFixture("position.json")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(position)
+		return
+	case r.Method == http.MethodPost && r.URL.Path == "/v2/orders":
+		if req.Body["symbol"] == "REJECTED" {
+			http.Error(w, `{"code":40310000,"message":"insufficient buying power"}`, http.StatusForbidden)
+			return
+		}
+		for _, field := range []string{"symbol", "qty", "side", "type", "time_in_force", "limit_price", "stop_price", "client_order_id"} {
+			if value := req.Body[field]; value != nil {
+				order[field] = value
+			}
+		}
+	case r.Method == http.MethodDelete && r.URL.Path == "/v2/orders/"+id:
+		s.lock.Lock()
+		s.canceled = true
+		s.lock.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+		return
+	case r.Method == http.MethodGet && r.URL.Path == "/v2/orders/"+id:
+		s.lock.Lock()
+		if s.canceled {
+			order["status"] = "canceled"
+		}
+		s.lock.Unlock()
+	case r.Method == http.MethodPatch && r.URL.Path == "/v2/orders/"+id:
+		// The replacing order is a new order
+		order["id"] = "b3ce1f6a-0a0c-4b6b-9d1e-5c5f0f9a1d2e"
+		order["replaces"] = id
+		for _, field := range []string{"qty", "limit_price", "stop_price", "client_order_id"} {
+			if value := req.Body[field]; value != nil {
+				order[field] = value
+			}
+		}
+	default:
+		http.Error(w, `{"code":40410000,"message":"order not found"}`, http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(order)
+}
+
+// Serve the trade updates as server-sent events, the stream stays open until the client disconnects
+func (s *standIn) streamTradeUpdates(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.WriteHeader(http.StatusOK)
+	s.lock.Lock()
+	updates := s.updates
+	s.lock.Unlock()
+	for _, update := range updates {
+		b, _ := json.Marshal(update)
+		fmt.Fprintf(w, "data: %s\n\n", b)
+	}
+	w.(http.Flusher).Flush()
+	<-r.Context().Done()
+}
+
+// Requests received so far
+func (s *standIn) received() []apiRequest {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]apiRequest(nil), s.requests...)
+}
+
+func readFixture(name string) (map[string]any, error) {
+	b, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		return nil, err
+	}
+	var fixture map[string]any
+	err = json.Unmarshal(b, &fixture)
+	return fixture, err
+}
+
+func TestSubmit(t *testing.T) {
+	api := newStandIn(t, "paper")
+
+	order, err := Submit(requests.OrderRequest{
+		Account:       "paper",
+		Symbol:        "BTC/USD",
+		AssetClass:    types.Crypto,
+		Side:          types.Buy,
+		Type:          types.LimitOrder,
+		Quantity:      0.5,
+		LimitPrice:    61250.5,
+		ClientOrderID: "my-order",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	received := api.received()
+	if len(received) != 1 || received[0].Method != http.MethodPost {
+		t.Fatalf("expected one order placed, got %+v", received)
+	}
+	expected := map[string]any{
+		"symbol":          "BTC/USD",
+		"qty":             "0.5",
+		"side":            "buy",
+		"type":            "limit",
+		"time_in_force":   "gtc",
+		"limit_price":     "61250.5",
+		"client_order_id": "my-order",
+	}
+	for field, value := range expected {
+		if received[0].Body[field] != value {
+			t.Errorf("placed order field %s is %v instead of %v", field, received[0].Body[field], value)
+		}
+	}
+	if received[0].Body["stop_price"] != nil {
+		t.Errorf("placed order has a stop price without one requested: %v", received[0].Body)
+	}
+
+	if order.Account != "paper" || order.Source != string(types.Alpaca) || order.Broker != string(types.AlpacaBroker) {
+		t.Errorf("order not mapped to the account and broker: %+v", order)
+	}
+	if order.Symbol != "BTC/USD" || order.Quantity != 0.5 || order.LimitPrice != 61250.5 || order.Type != "limit" {
+		t.Errorf("order not mapped from the placed order: %+v", order)
+	}
+	if order.Status != string(types.OrderNew) {
+		t.Errorf("accepted order has status %s instead of %s", order.Status, types.OrderNew)
+	}
+}
+
+func TestSubmitStockOrderIsValidForTheDay(t *testing.T) {
+	api := newStandIn(t, "paper")
+
+	_, err := Submit(requests.OrderRequest{
+		Account:    "paper",
+		Symbol:     "AAPL",
+		AssetClass: types.Stock,
+		Side:       types.Sell,
+		Type:       types.MarketOrder,
+		Quantity:   10,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body := api.received()[0].Body; body["time_in_force"] != "day" || body["side"] != "sell" {
+		t.Errorf("unexpected stock order %v", body)
+	}
+}
+
+func TestSubmitRejected(t *testing.T) {
+	newStandIn(t, "paper")
+
+	_, err := Submit(requests.OrderRequest{
+		Account:    "paper",
+		Symbol:     "REJECTED",
+		AssetClass: types.Stock,
+		Side:       types.Buy,
+		Type:       types.MarketOrder,
+		Quantity:   1,
+	})
+	if err == nil || !strings.Contains(err.Error(), "insufficient buying power") {
+		t.Fatalf("expected the rejection of the order, got %v", err)
+	}
+}
+
+func TestSubmitUnknownAccount(t *testing.T) {
+	newStandIn(t, "paper")
+
+	_, err := Submit(requests.OrderRequest{Account: "other", Symbol: "AAPL", Quantity: 1})
+	if err == nil || !strings.Contains(err.Error(), "no alpaca account other") {
+		t.Fatalf("expected an error for the unknown account, got %v", err)
+	}
+}
+
+func TestCancel(t *testing.T) {
+	api := newStandIn(t, "paper")
+
+	order, err := Cancel("paper", "61e69015-8549-4bfd-b9c3-01e75843f47d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	received := api.received()
+	if len(received) != 2 || received[0].Method != http.MethodDelete || received[1].Method != http.MethodGet {
+		t.Fatalf("expected the cancellation then the order requested, got %+v", received)
+	}
+	if order.Status != string(types.OrderCanceled) {
+		t.Errorf("canceled order has status %s", order.Status)
+	}
+
+	_, err = Cancel("paper", "unknown")
+	if err == nil || !strings.Contains(err.Error(), "order not found") {
+		t.Fatalf("expected an error for the unknown order, got %v", err)
+	}
+}
+
+func TestReplace(t *testing.T) {
+	api := newStandIn(t, "paper")
+
+	order, err := Replace(requests.OrderRequest{
+		Account:    "paper",
+		OrderID:    "61e69015-8549-4bfd-b9c3-01e75843f47d",
+		Quantity:   20,
+		LimitPrice: 174.5,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	received := api.received()
+	if len(received) != 1 || received[0].Method != http.MethodPatch {
+		t.Fatalf("expected one order replaced, got %+v", received)
+	}
+	if received[0].Body["qty"] != "20" || received[0].Body["limit_price"] != "174.5" {
+		t.Errorf("unexpected replacement %v", received[0].Body)
+	}
+	if order.Replaces != "61e69015-8549-4bfd-b9c3-01e75843f47d" || order.ID == order.Replaces {
+		t.Errorf("replacing order does not reference the replaced one: %+v", order)
+	}
+	if order.Quantity != 20 || order.LimitPrice != 174.5 {
+		t.Errorf("replacing order not mapped: %+v", order)
+	}
+}
+
+func readTradeUpdate(t *testing.T) alpaca.TradeUpdate {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join("testdata", "trade_update.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var update alpaca.TradeUpdate
+	if err := json.Unmarshal(b, &update); err != nil {
+		t.Fatal(err)
+	}
+	return update
+}
+
+func TestFillToEntity(t *testing.T) {
+	update := readTradeUpdate(t)
+
+	fill := FillToEntity("paper", update)
+	if fill.ID != "3b1e0f3c-7a4f-4c7e-9f0e-2d8c6a1b5e77" {
+		t.Errorf("fill has id %s instead of the execution id", fill.ID)
+	}
+	if fill.OrderID != "61e69015-8549-4bfd-b9c3-01e75843f47d" || fill.Account != "paper" {
+		t.Errorf("fill not mapped to its order and account: %+v", fill)
+	}
+	if fill.Symbol != "AAPL" || fill.AssetClass != string(types.Stock) || fill.Side != "buy" {
+		t.Errorf("fill not mapped from its order: %+v", fill)
+	}
+	if fill.Quantity != 10 || fill.Price != 175.12 {
+		t.Errorf("fill has quantity %v and price %v", fill.Quantity, fill.Price)
+	}
+	// The time of the execution is used rather than the time of the update
+	if expected := time.Date(2024, 3, 4, 14, 30, 1, 396458000, time.UTC).UnixNano(); fill.Timestamp != expected {
+		t.Errorf("fill has timestamp %d instead of %d", fill.Timestamp, expected)
+	}
+
+	// Updates without execution id and timestamp fall back to the event
+	update.ExecutionID = ""
+	update.Timestamp = nil
+	fill = FillToEntity("paper", update)
+	if fill.ID != update.EventID || fill.Timestamp != update.At.UnixNano() {
+		t.Errorf("fill without execution not mapped from the event: %+v", fill)
+	}
+}
+
+func TestOrderToEntityStatus(t *testing.T) {
+	update := readTradeUpdate(t)
+	order := OrderToEntity("paper", update.Order)
+	if order.Status != string(types.OrderFilled) || order.FilledQuantity != 10 || order.AveragePrice != 175.12 {
+		t.Errorf("filled order not mapped: %+v", order)
+	}
+	if order.CreatedAt >= order.UpdatedAt {
+		t.Errorf("order created at %d and updated at %d", order.CreatedAt, order.UpdatedAt)
+	}
+
+	tests := []struct {
+		status string
+		filled string
+		want   types.OrderStatus
+	}{
+		{status: "accepted", filled: "0", want: types.OrderNew},
+		{status: "pending_cancel", filled: "0", want: types.OrderNew},
+		{status: "partially_filled", filled: "4", want: types.OrderPartiallyFilled},
+		{status: "done_for_day", filled: "4", want: types.OrderPartiallyFilled},
+		{status: "canceled", filled: "4", want: types.OrderCanceled},
+		{status: "expired", filled: "0", want: types.OrderExpired},
+		{status: "replaced", filled: "0", want: types.OrderReplaced},
+		{status: "rejected", filled: "0", want: types.OrderRejected},
+	}
+	for _, test := range tests {
+		fixture, err := readFixture("order.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		fixture["status"] = test.status
+		fixture["filled_qty"] = test.filled
+		b, _ := json.Marshal(fixture)
+		var alpacaOrder alpaca.Order
+		if err := json.Unmarshal(b, &alpacaOrder); err != nil {
+			t.Fatal(err)
+		}
+		if got := OrderToEntity("paper", alpacaOrder).Status; got != string(test.want) {
+			t.Errorf("order with status %s and %s filled mapped to %s instead of %s", test.status, test.filled, got, test.want)
+		}
+	}
+}
+
+func startNats(t *testing.T) *nats.Conn {
+	ns, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ns.Start()
+	if !ns.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats-server not ready")
+	}
+	t.Cleanup(ns.Shutdown)
+	communication.SetNatsURL(ns.ClientURL())
+	nc, err := nats.Connect(ns.ClientURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(nc.Close)
+	return nc
+}
+
+// Subscribe to the execution stream topic of AAPL of a data type, the stream handler publishing on
+// the topic is stopped with the test as it is connected to the nats-server of the test
+func subscribeExecution(t *testing.T, nc *nats.Conn, dtype types.DataType) *nats.Subscription {
+	t.Helper()
+	topic := utils.NewStreamTopic(types.Execution, types.Alpaca, types.Stock, dtype, "AAPL").Generate()
+	sub, err := nc.SubscribeSync(topic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		producer.StopTopicHandler(topic[:strings.LastIndex(topic, ".")])
+	})
+	return sub
+}
+
+// Next entity published on a subscription
+func nextEntity(t *testing.T, sub *nats.Subscription) any {
+	t.Helper()
+	natsMsg, err := sub.NextMsg(5 * time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var msg entities.Message
+	if err := proto.Unmarshal(natsMsg.Data, &msg); err != nil {
+		t.Fatal(err)
+	}
+	entity, err := msg.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return entity
+}
+
+func TestStreamTradeUpdatesPublishesFillsAndPositions(t *testing.T) {
+	nc := startNats(t)
+	api := newStandIn(t, "paper")
+	fills := subscribeExecution(t, nc, types.Fills)
+	orders := subscribeExecution(t, nc, types.Orders)
+	positions := subscribeExecution(t, nc, types.Positions)
+
+	// The buy fill opens the position and the sell fill closes it
+	buy, err := readFixture("trade_update.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sell, err := readFixture("trade_update.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sell["execution_id"] = "9a3f5d2e-1c4b-4e8a-b6d7-0f2e4c6a8b1d"
+	sell["position_qty"] = "0"
+	sell["price"] = "176.5"
+	sell["timestamp"] = "2024-03-04T15:02:11.204518Z"
+	sell["order"].(map[string]any)["side"] = "sell"
+	api.updates = []map[string]any{buy, sell}
+
+	client, err := getClient("paper")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		streamTradeUpdates(ctx, "paper", client)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	fill, ok := nextEntity(t, fills).(*entities.Fill)
+	if !ok || fill.ID != "3b1e0f3c-7a4f-4c7e-9f0e-2d8c6a1b5e77" || fill.Account != "paper" || fill.Side != "buy" {
+		t.Fatalf("unexpected fill of the buy %+v", fill)
+	}
+	if fill.Quantity != 10 || fill.Price != 175.12 {
+		t.Errorf("buy fill has quantity %v and price %v", fill.Quantity, fill.Price)
+	}
+	order, ok := nextEntity(t, orders).(*entities.Order)
+	if !ok || order.ID != "61e69015-8549-4bfd-b9c3-01e75843f47d" || order.Status != string(types.OrderFilled) {
+		t.Fatalf("unexpected order of the buy %+v", order)
+	}
+	position, ok := nextEntity(t, positions).(*entities.Position)
+	if !ok || position.Account != "paper" || position.Symbol != "AAPL" || position.Source != string(types.Alpaca) {
+		t.Fatalf("unexpected position after the buy %+v", position)
+	}
+	if position.Quantity != 10 || position.AveragePrice != 175.12 || position.Timestamp != fill.Timestamp {
+		t.Errorf("position after the buy has quantity %v, average price %v and timestamp %d",
+			position.Quantity, position.AveragePrice, position.Timestamp)
+	}
+
+	fill, ok = nextEntity(t, fills).(*entities.Fill)
+	if !ok || fill.ID != "9a3f5d2e-1c4b-4e8a-b6d7-0f2e4c6a8b1d" || fill.Side != "sell" || fill.Price != 176.5 {
+		t.Fatalf("unexpected fill of the sell %+v", fill)
+	}
+	position, ok = nextEntity(t, positions).(*entities.Position)
+	if !ok || position.Quantity != 0 || position.Symbol != "AAPL" || position.Timestamp != fill.Timestamp {
+		t.Fatalf("position not closed by the sell %+v", position)
+	}
+
+	// The position of the buy was requested, the closed one is known from the trade update
+	var requested []string
+	for _, req := range api.received() {
+		if strings.HasPrefix(req.Path, "/v2/positions/") {
+			requested = append(requested, req.Path)
+		}
+	}
+	if len(requested) != 1 || requested[0] != "/v2/positions/AAPL" {
+		t.Errorf("unexpected position requests %v", requested)
+	}
+}
@@ -0,0 +1,22 @@
+package alpaca
+
+import (
+	"os"
+
+	"tradingplatform/shared/accounts"
+)
+
+var PAPER_URL = "https://paper-api.alpaca.markets"
+var LIVE_URL = "https://api.alpaca.markets"
+
+// Base URL of the trading API of an account, can be overridden for all accounts (e.g. to point to
+// a local stand-in) with the ALPACA_TRADING_URL environment variable
+func tradingURL(credentials accounts.Credentials) string {
+	if url := os.Getenv("ALPACA_TRADING_URL"); url != "" {
+		return url
+	}
+	if credentials.Live {
+		return LIVE_URL
+	}
+	return PAPER_URL
+}
@@ -0,0 +1,148 @@
+package alpaca
+
+import (
+	"time"
+
+	"tradingplatform/shared/entities"
+	"tradingplatform/shared/requests"
+	"tradingplatform/shared/types"
+
+	"github.com/alpacahq/alpaca-trade-api-go/v3/alpaca"
+	"github.com/shopspring/decimal"
+)
+
+func toFloat(d *decimal.Decimal) float64 {
+	if d == nil {
+		return 0
+	}
+	return d.InexactFloat64()
+}
+
+func toDecimal(f float64) *decimal.Decimal {
+	if f == 0 {
+		return nil
+	}
+	d := decimal.NewFromFloat(f)
+	return &d
+}
+
+func toAssetClass(assetClass alpaca.AssetClass) types.AssetClass {
+	if assetClass == alpaca.Crypto {
+		return types.Crypto
+	}
+	return types.Stock
+}
+
+// Orders of stocks are valid for the day and orders of crypto until they are canceled
+func toTimeInForce(assetClass types.AssetClass) alpaca.TimeInForce {
+	if assetClass == types.Crypto {
+		return alpaca.GTC
+	}
+	return alpaca.Day
+}
+
+// Map the status of an Alpaca order, the intermediate statuses of open orders (e.g. accepted,
+// pending_cancel or done_for_day) are mapped to new or partially-filled
+func toOrderStatus(order alpaca.Order) types.OrderStatus {
+	switch order.Status {
+	case "filled":
+		return types.OrderFilled
+	case "canceled":
+		return types.OrderCanceled
+	case "expired":
+		return types.OrderExpired
+	case "replaced":
+		return types.OrderReplaced
+	case "rejected":
+		return types.OrderRejected
+	}
+	if order.FilledQty.IsPositive() {
+		return types.OrderPartiallyFilled
+	}
+	return types.OrderNew
+}
+
+func OrderToEntity(account requests.Account, order alpaca.Order) *entities.Order {
+	entity := &entities.Order{
+		ID:             order.ID,
+		ClientOrderID:  order.ClientOrderID,
+		Account:        string(account),
+		Symbol:         order.Symbol,
+		Source:         string(types.Alpaca),
+		AssetClass:     string(toAssetClass(order.AssetClass)),
+		Side:           string(order.Side),
+		Type:           string(order.Type),
+		Quantity:       toFloat(order.Qty),
+		LimitPrice:     toFloat(order.LimitPrice),
+		StopPrice:      toFloat(order.StopPrice),
+		Status:         string(toOrderStatus(order)),
+		FilledQuantity: order.FilledQty.InexactFloat64(),
+		AveragePrice:   toFloat(order.FilledAvgPrice),
+		CreatedAt:      order.CreatedAt.UnixNano(),
+		UpdatedAt:      order.UpdatedAt.UnixNano(),
+		Broker:         string(types.AlpacaBroker),
+	}
+	if order.Replaces != nil {
+		entity.Replaces = *order.Replaces
+	}
+	return entity
+}
+
+func OrdersToEntities(account requests.Account, orders []alpaca.Order) []*entities.Order {
+	entities := make([]*entities.Order, len(orders))
+	for i, order := range orders {
+		entities[i] = OrderToEntity(account, order)
+	}
+	return entities
+}
+
+// Map the execution of a fill or partial_fill trade update
+func FillToEntity(account requests.Account, update alpaca.TradeUpdate) *entities.Fill {
+	order := OrderToEntity(account, update.Order)
+	id := update.ExecutionID
+	if id == "" {
+		id = update.EventID
+	}
+	timestamp := update.At
+	if update.Timestamp != nil {
+		timestamp = *update.Timestamp
+	}
+	return &entities.Fill{
+		ID:         id,
+		OrderID:    order.ID,
+		Account:    order.Account,
+		Symbol:     order.Symbol,
+		Source:     order.Source,
+		AssetClass: order.AssetClass,
+		Side:       order.Side,
+		Quantity:   toFloat(update.Qty),
+		Price:      toFloat(update.Price),
+		Timestamp:  timestamp.UnixNano(),
+		Broker:     order.Broker,
+	}
+}
+
+// Map a position of an account, short positions have a negative quantity
+func PositionToEntity(account requests.Account, position alpaca.Position) *entities.Position {
+	quantity := position.Qty.InexactFloat64()
+	if position.Side == "short" && quantity > 0 {
+		quantity = -quantity
+	}
+	return &entities.Position{
+		Account:      string(account),
+		Symbol:       position.Symbol,
+		Source:       string(types.Alpaca),
+		AssetClass:   string(toAssetClass(position.AssetClass)),
+		Quantity:     quantity,
+		AveragePrice: position.AvgEntryPrice.InexactFloat64(),
+		Timestamp:    time.Now().UnixNano(),
+	}
+}
+
+func PositionsToEntities(account requests.Account, positions []alpaca.Position) []*entities.Position {
+	entities := make([]*entities.Position, len(positions))
+	for i, position := range positions {
+		entities[i] = PositionToEntity(account, position)
+	}
+	return entities
+}
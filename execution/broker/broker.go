@@ -0,0 +1,337 @@
+package broker
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"tradingplatform/execution/publisher"
+	"tradingplatform/shared/communication/subscriber"
+	"tradingplatform/shared/entities"
+	"tradingplatform/shared/requests"
+	"tradingplatform/shared/types"
+	"tradingplatform/shared/utils"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
+)
+
+// The market of a symbol streamed by a source, orders are matched against its quotes and trades
+type market struct {
+	source     types.Source
+	assetClass types.AssetClass
+	symbol     string
+}
+
+func (m market) key() string {
+	return string(m.source) + "." + string(m.assetClass) + "." + m.symbol
+}
+
+// Topic of the market data of a type of the market
+func (m market) dataTopic(dtype types.DataType) string {
+	return utils.NewStreamTopic(types.DataProvider, m.source, m.assetClass, dtype, m.symbol).Generate()
+}
+
+type openOrder struct {
+	order *entities.Order
+	// Whether the stop price of a stop order was reached
+	triggered bool
+}
+
+// The open orders of a market in submission order with the topics of the market data they are
+// matched against
+type book struct {
+	market market
+	orders []*openOrder
+	topics []string
+}
+
+var books = make(map[string]*book)
+
+// Positions by account and market
+var positions = make(map[requests.Account]map[string]*entities.Position)
+var lock sync.Mutex
+
+// Submit a new order, the order is matched against the quotes and trades of its market streamed by
+// the dataprovider from then on
+func Submit(req requests.OrderRequest) *entities.Order {
+	now := time.Now().UnixNano()
+	order := &entities.Order{
+		ID:            uuid.New().String(),
+		ClientOrderID: req.ClientOrderID,
+		Account:       string(req.Account),
+		Symbol:        req.Symbol,
+		Source:        string(req.Source),
+		AssetClass:    string(req.AssetClass),
+		Side:          string(req.Side),
+		Type:          string(req.Type),
+		Quantity:      req.Quantity,
+		LimitPrice:    req.LimitPrice,
+		StopPrice:     req.StopPrice,
+		Status:        string(types.OrderNew),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+		Broker:        string(types.SimulatedBroker),
+	}
+	m := market{source: req.Source, assetClass: req.AssetClass, symbol: req.Symbol}
+
+	lock.Lock()
+	defer lock.Unlock()
+	b := getOrCreateBook(m)
+	b.orders = append(b.orders, &openOrder{order: order})
+	publisher.Publish(order, types.Orders)
+	return proto.Clone(order).(*entities.Order)
+}
+
+// Cancel an open order of an account
+func Cancel(account requests.Account, id string) (*entities.Order, error) {
+	lock.Lock()
+	defer lock.Unlock()
+	b, i, err := findOrder(account, id)
+	if err != nil {
+		return nil, err
+	}
+	o := b.orders[i]
+	b.orders = append(b.orders[:i], b.orders[i+1:]...)
+	o.order.Status = string(types.OrderCanceled)
+	o.order.UpdatedAt = time.Now().UnixNano()
+	publisher.Publish(o.order, types.Orders)
+	closeEmptyBook(b)
+	return proto.Clone(o.order).(*entities.Order), nil
+}
+
+// Replace an open order of an account by a new order with the quantity and the prices of a request,
+// the quantity already filled stays with the replaced order. The new order keeps the remaining
+// quantity and the prices of the replaced order that are not given
+func Replace(req requests.OrderRequest) (*entities.Order, error) {
+	lock.Lock()
+	defer lock.Unlock()
+	b, i, err := findOrder(req.Account, req.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	replaced := b.orders[i].order
+	if req.LimitPrice > 0 && types.OrderType(replaced.Type) != types.LimitOrder {
+		return nil, fmt.Errorf("cannot set the limit price of %s order %s", replaced.Type, replaced.ID)
+	}
+	if req.StopPrice > 0 && types.OrderType(replaced.Type) != types.StopOrder {
+		return nil, fmt.Errorf("cannot set the stop price of %s order %s", replaced.Type, replaced.ID)
+	}
+
+	now := time.Now().UnixNano()
+	order := proto.Clone(replaced).(*entities.Order)
+	order.ID = uuid.New().String()
+	order.ClientOrderID = req.ClientOrderID
+	order.Replaces = replaced.ID
+	order.Status = string(types.OrderNew)
+	order.Quantity = replaced.Quantity - replaced.FilledQuantity
+	order.FilledQuantity = 0
+	order.AveragePrice = 0
+	order.CreatedAt = now
+	order.UpdatedAt = now
+	if req.Quantity > 0 {
+		order.Quantity = req.Quantity
+	}
+	if req.LimitPrice > 0 {
+		order.LimitPrice = req.LimitPrice
+	}
+	if req.StopPrice > 0 {
+		order.StopPrice = req.StopPrice
+	}
+
+	replaced.Status = string(types.OrderReplaced)
+	replaced.UpdatedAt = now
+	publisher.Publish(replaced, types.Orders)
+	// The new order loses the priority of the replaced order
+	b.orders = append(append(b.orders[:i], b.orders[i+1:]...), &openOrder{order: order})
+	publisher.Publish(order, types.Orders)
+	return proto.Clone(order).(*entities.Order), nil
+}
+
+// Find the book and the index in the book of an open order of an account. Must be called with the
+// lock held
+func findOrder(account requests.Account, id string) (*book, int, error) {
+	for _, b := range books {
+		for i, o := range b.orders {
+			if o.order.ID == id && o.order.Account == string(account) {
+				return b, i, nil
+			}
+		}
+	}
+	return nil, 0, fmt.Errorf("no open order %s for account %s", id, account)
+}
+
+// GetOrders returns the open orders of an account in submission order
+func GetOrders(account requests.Account) []*entities.Order {
+	lock.Lock()
+	defer lock.Unlock()
+	var orders []*entities.Order
+	for _, b := range books {
+		for _, o := range b.orders {
+			if o.order.Account == string(account) {
+				orders = append(orders, proto.Clone(o.order).(*entities.Order))
+			}
+		}
+	}
+	sort.SliceStable(orders, func(i, j int) bool {
+		return orders[i].CreatedAt < orders[j].CreatedAt
+	})
+	return orders
+}
+
+// GetPositions returns the positions of an account, including the closed ones
+func GetPositions(account requests.Account) []*entities.Position {
+	lock.Lock()
+	defer lock.Unlock()
+	result := make([]*entities.Position, 0, len(positions[account]))
+	for _, p := range positions[account] {
+		result = append(result, proto.Clone(p).(*entities.Position))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Symbol < result[j].Symbol
+	})
+	return result
+}
+
+// Get the book of a market, subscribing to its quotes and trades when it is created. Must be called
+// with the lock held
+func getOrCreateBook(m market) *book {
+	if b, ok := books[m.key()]; ok {
+		return b
+	}
+	b := &book{market: m}
+	// The sources are known by the dataprovider, the topics of data types a source does not stream stay silent
+	for _, dtype := range []types.DataType{types.Quotes, types.Trades} {
+		b.topics = append(b.topics, m.dataTopic(dtype))
+	}
+	books[m.key()] = b
+	for _, topic := range b.topics {
+		subscriber.AttatchFunctionalityRoundRobin(topic, b.handleMessage, 1)
+	}
+	return b
+}
+
+// Stop matching the orders of a market once none are open. Must be called with the lock held
+func closeEmptyBook(b *book) {
+	if len(b.orders) > 0 {
+		return
+	}
+	for _, topic := range b.topics {
+		subscriber.StopTopicHandler(topic)
+	}
+	delete(books, b.market.key())
+}
+
+// Match the open orders of the market against the quotes or trades of a message
+func (b *book) handleMessage(msg *entities.Message) error {
+	switch types.DataType(msg.DataType) {
+	case types.Quotes:
+		return utils.HandleEntity(msg, &entities.Quote{}, func(quote *entities.Quote) error {
+			b.match(quote.TimestampNs, func(order *entities.Order) marketPrice {
+				return quotePrice(order, quote)
+			})
+			return nil
+		})
+	case types.Trades:
+		return utils.HandleEntity(msg, &entities.Trade{}, func(trade *entities.Trade) error {
+			// Corrections and cancels of trades do not fill orders
+			if trade.Update != "" {
+				return nil
+			}
+			b.match(trade.TimestampNs, func(order *entities.Order) marketPrice {
+				return tradePrice(trade)
+			})
+			return nil
+		})
+	}
+	return nil
+}
+
+// Match the open orders of the market in submission order against the prices of an event, the
+// quantity available at a price is shared by the orders of the same side
+func (b *book) match(timestamp int64, priceOf func(*entities.Order) marketPrice) {
+	lock.Lock()
+	defer lock.Unlock()
+	if books[b.market.key()] != b {
+		return
+	}
+	cfg := GetConfig()
+	available := make(map[string]*marketPrice)
+	open := b.orders[:0]
+	for _, o := range b.orders {
+		mp, ok := available[o.order.Side]
+		if !ok {
+			price := priceOf(o.order)
+			mp = &price
+			available[o.order.Side] = mp
+		}
+		if types.OrderType(o.order.Type) == types.StopOrder && !o.triggered && mp.price > 0 {
+			o.triggered = isTriggered(o.order, *mp)
+		}
+		if price, quantity, ok := match(o.order, o.triggered, *mp, cfg); ok {
+			mp.quantity -= quantity
+			b.fill(o.order, price, quantity, timestamp, cfg)
+		}
+		if types.OrderStatus(o.order.Status).IsOpen() {
+			open = append(open, o)
+		}
+	}
+	b.orders = open
+	closeEmptyBook(b)
+}
+
+// Fill a quantity of an order and update the position of its account. Must be called with the
+// lock held
+func (b *book) fill(order *entities.Order, price float64, quantity float64, timestamp int64, cfg Config) {
+	remaining := order.Quantity - order.FilledQuantity
+	order.AveragePrice = (order.AveragePrice*order.FilledQuantity + price*quantity) / (order.FilledQuantity + quantity)
+	if quantity >= remaining {
+		order.FilledQuantity = order.Quantity
+		order.Status = string(types.OrderFilled)
+	} else {
+		order.FilledQuantity += quantity
+		order.Status = string(types.OrderPartiallyFilled)
+	}
+	order.UpdatedAt = time.Now().UnixNano()
+
+	fill := &entities.Fill{
+		ID:         uuid.New().String(),
+		OrderID:    order.ID,
+		Account:    order.Account,
+		Symbol:     order.Symbol,
+		Source:     order.Source,
+		AssetClass: order.AssetClass,
+		Side:       order.Side,
+		Quantity:   quantity,
+		Price:      price,
+		Commission: commission(quantity, price, cfg),
+		Timestamp:  timestamp,
+		Broker:     order.Broker,
+	}
+
+	account := requests.Account(order.Account)
+	if positions[account] == nil {
+		positions[account] = make(map[string]*entities.Position)
+	}
+	position, ok := positions[account][b.market.key()]
+	if !ok {
+		position = &entities.Position{
+			Account:    order.Account,
+			Symbol:     order.Symbol,
+			Source:     order.Source,
+			AssetClass: order.AssetClass,
+		}
+		positions[account][b.market.key()] = position
+	}
+	signed := quantity
+	if types.OrderSide(order.Side) == types.Sell {
+		signed = -quantity
+	}
+	position.Apply(signed, price)
+	position.Timestamp = timestamp
+
+	publisher.Publish(fill, types.Fills)
+	publisher.Publish(order, types.Orders)
+	publisher.Publish(position, types.Positions)
+}
@@ -0,0 +1,29 @@
+package broker
+
+import "sync"
+
+// Config of the simulated broker
+type Config struct {
+	// Slippage applied against the fills of market and triggered stop orders, in basis points of
+	// the fill price
+	Slippage float64
+	// Commission per unit filled
+	Commission float64
+	// Commission in basis points of the notional filled
+	CommissionRate float64
+}
+
+var config Config
+var configLock sync.RWMutex
+
+func SetConfig(cfg Config) {
+	configLock.Lock()
+	defer configLock.Unlock()
+	config = cfg
+}
+
+func GetConfig() Config {
+	configLock.RLock()
+	defer configLock.RUnlock()
+	return config
+}
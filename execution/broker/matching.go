@@ -0,0 +1,79 @@
+package broker
+
+import (
+	"math"
+
+	"tradingplatform/shared/entities"
+	"tradingplatform/shared/types"
+)
+
+// A price of the market of an order with the quantity available at it
+type marketPrice struct {
+	price    float64
+	quantity float64
+}
+
+// Market price of a size, a size that is not given does not limit the quantity available
+func newMarketPrice(price float64, size float64) marketPrice {
+	if size <= 0 {
+		size = math.Inf(1)
+	}
+	return marketPrice{price: price, quantity: size}
+}
+
+// Price a quote offers to an order, the ask for buy orders and the bid for sell orders
+func quotePrice(order *entities.Order, quote *entities.Quote) marketPrice {
+	if types.OrderSide(order.Side) == types.Buy {
+		return newMarketPrice(quote.AskPrice, quote.AskSize)
+	}
+	return newMarketPrice(quote.BidPrice, quote.BidSize)
+}
+
+func tradePrice(trade *entities.Trade) marketPrice {
+	return newMarketPrice(trade.Price, trade.Size)
+}
+
+// Whether the price reaches the stop price of a stop order, stop orders are triggered by prices at or
+// above their stop price when buying and at or below when selling
+func isTriggered(order *entities.Order, mp marketPrice) bool {
+	if types.OrderSide(order.Side) == types.Buy {
+		return mp.price >= order.StopPrice
+	}
+	return mp.price <= order.StopPrice
+}
+
+// Price and quantity at which an open order is filled at a market price, ok is false if the order
+// is not filled. Stop orders are filled like market orders once triggered
+func match(order *entities.Order, triggered bool, mp marketPrice, cfg Config) (price float64, quantity float64, ok bool) {
+	if mp.price <= 0 {
+		return 0, 0, false
+	}
+	buy := types.OrderSide(order.Side) == types.Buy
+	price = mp.price
+	switch types.OrderType(order.Type) {
+	case types.LimitOrder:
+		if (buy && price > order.LimitPrice) || (!buy && price < order.LimitPrice) {
+			return 0, 0, false
+		}
+	case types.StopOrder:
+		if !triggered {
+			return 0, 0, false
+		}
+		fallthrough
+	default:
+		slippage := price * cfg.Slippage / 10000
+		if buy {
+			price += slippage
+		} else {
+			price -= slippage
+		}
+	}
+
+	quantity = math.Min(order.Quantity-order.FilledQuantity, mp.quantity)
+	return price, quantity, quantity > 0
+}
+
+// Commission of a fill
+func commission(quantity float64, price float64, cfg Config) float64 {
+	return quantity*cfg.Commission + quantity*price*cfg.CommissionRate/10000
+}
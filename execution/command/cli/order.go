@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"tradingplatform/execution/handler"
+	"tradingplatform/shared/entities"
+	"tradingplatform/shared/logging"
+	"tradingplatform/shared/requests"
+	"tradingplatform/shared/types"
+
+	"github.com/spf13/cobra"
+)
+
+// Submit, cancel, replace and get orders
+func NewOrderCmd() *cobra.Command {
+	orderCmd := cobra.Command{
+		Use:   "order",
+		Short: "Submit, cancel, replace or get the orders of an account",
+	}
+
+	orderCmd.AddCommand(NewOrderOperationCmd(types.OrderSubmitOp,
+		"Submit an order to the broker of the account"))
+	orderCmd.AddCommand(NewOrderOperationCmd(types.OrderCancelOp,
+		"Cancel an open order"))
+	orderCmd.AddCommand(NewOrderOperationCmd(types.OrderReplaceOp,
+		"Replace the quantity or the prices of an open order"))
+	orderCmd.AddCommand(NewOrderOperationCmd(types.OrderGetOp,
+		"Get the open orders and the positions of an account"))
+
+	return &orderCmd
+}
+
+func NewOrderOperationCmd(operation types.OrderRequestOp, short string) *cobra.Command {
+	operationCmd := cobra.Command{
+		Use:   string(operation),
+		Short: short,
+		Run: func(cmd *cobra.Command, args []string) {
+			broker, _ := cmd.Flags().GetString("broker")
+			account, _ := cmd.Flags().GetString("account")
+			orderID, _ := cmd.Flags().GetString("order-id")
+			clientOrderID, _ := cmd.Flags().GetString("client-order-id")
+			source, _ := cmd.Flags().GetString("source")
+			assetClass, _ := cmd.Flags().GetString("asset-class")
+			symbol, _ := cmd.Flags().GetString("symbol")
+			side, _ := cmd.Flags().GetString("side")
+			orderType, _ := cmd.Flags().GetString("type")
+			quantity, _ := cmd.Flags().GetFloat64("quantity")
+			limitPrice, _ := cmd.Flags().GetFloat64("limit-price")
+			stopPrice, _ := cmd.Flags().GetFloat64("stop-price")
+
+			req, err := requests.NewOrderRequestFromRaw(string(operation),
+				broker,
+				account,
+				orderID,
+				clientOrderID,
+				source,
+				assetClass,
+				symbol,
+				side,
+				orderType,
+				quantity,
+				limitPrice,
+				stopPrice,
+				requests.DefaultForEmptyOrderRequest)
+
+			logging.Log().Info().
+				RawJSON("orderRequest", req.JSON()).
+				Msg("receiving order request")
+
+			if err != nil {
+				cmd.Print(entities.NewOrderError(err).Respond())
+				return
+			}
+
+			cmd.Print(handler.HandleOrderRequest(req).Respond())
+		},
+	}
+
+	operationCmd.Flags().StringP("broker", "b", "",
+		"Broker of the account (simulated or alpaca), simulated by default")
+	operationCmd.Flags().StringP("account", "u", "",
+		"Account of the order, default by default")
+	switch operation {
+	case types.OrderSubmitOp:
+		operationCmd.Flags().StringP("client-order-id", "c", "",
+			"ID of the order given by the client")
+		operationCmd.Flags().StringP("source", "s", "",
+			"Source of the quotes and trades the simulated broker matches the order against, alpaca by default")
+		operationCmd.Flags().StringP("asset-class", "a", "",
+			"Asset class")
+		operationCmd.Flags().StringP("symbol", "y", "",
+			"Symbol")
+		operationCmd.Flags().StringP("side", "d", "",
+			"Side of the order (buy or sell)")
+		operationCmd.Flags().StringP("type", "t", "",
+			"Type of the order (market, limit or stop), market by default")
+		operationCmd.Flags().Float64P("quantity", "q", 0,
+			"Quantity")
+		operationCmd.Flags().Float64P("limit-price", "p", 0,
+			"Price of limit orders")
+		operationCmd.Flags().Float64P("stop-price", "o", 0,
+			"Price triggering stop orders")
+	case types.OrderCancelOp:
+		operationCmd.Flags().StringP("order-id", "i", "",
+			"ID of the order")
+		operationCmd.MarkFlagRequired("order-id")
+	case types.OrderReplaceOp:
+		operationCmd.Flags().StringP("order-id", "i", "",
+			"ID of the order")
+		operationCmd.MarkFlagRequired("order-id")
+		operationCmd.Flags().StringP("client-order-id", "c", "",
+			"ID of the new order given by the client")
+		operationCmd.Flags().Float64P("quantity", "q", 0,
+			"New quantity, the remaining quantity by default")
+		operationCmd.Flags().Float64P("limit-price", "p", 0,
+			"New price of a limit order")
+		operationCmd.Flags().Float64P("stop-price", "o", 0,
+			"New price triggering a stop order")
+	}
+
+	return &operationCmd
+}
@@ -0,0 +1,116 @@
+package local
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"tradingplatform/execution/alpaca"
+	"tradingplatform/execution/broker"
+	"tradingplatform/execution/command/cli"
+	"tradingplatform/execution/command/json"
+	"tradingplatform/shared/accounts"
+	"tradingplatform/shared/communication"
+	"tradingplatform/shared/communication/command"
+	"tradingplatform/shared/communication/subscriber"
+	"tradingplatform/shared/logging"
+	"tradingplatform/shared/metrics"
+	"tradingplatform/shared/tracing"
+	"tradingplatform/shared/types"
+	"tradingplatform/shared/utils"
+
+	"github.com/nats-io/nats.go"
+	"github.com/spf13/cobra"
+)
+
+func NewRootCmd() *cobra.Command {
+	rootCmd := cobra.Command{
+		Use:   "execution",
+		Short: "Execution startup command",
+		Run: func(cmd *cobra.Command, args []string) {
+			natsURL, _ := cmd.Flags().GetString("nats-url")
+			slippage, _ := cmd.Flags().GetFloat64("slippage")
+			commission, _ := cmd.Flags().GetFloat64("commission")
+			commissionRate, _ := cmd.Flags().GetFloat64("commission-rate")
+			accountsPath, _ := cmd.Flags().GetString("accounts")
+			communication.SetNatsURL(natsURL)
+			if err := communication.SetJetStreamConfigFromFlags(cmd.Flags()); err != nil {
+				panic(err)
+			}
+			if err := communication.SetBatchConfigFromFlags(cmd.Flags()); err != nil {
+				panic(err)
+			}
+			// The quote and trade streams are also consumed by other components, each component
+			// needs every message
+			subscriber.SetConsumerGroup(string(types.Execution))
+			broker.SetConfig(broker.Config{
+				Slippage:       slippage,
+				Commission:     commission,
+				CommissionRate: commissionRate,
+			})
+			nc, err := nats.Connect(communication.GetNatsURL())
+			if err != nil {
+				panic(err)
+			}
+			defer nc.Close()
+
+			loggingTopic := utils.NewLoggingTopic(types.Execution).Generate()
+			mlLogger := logging.NewMultiLevelLogger(types.Execution,
+				os.Stdout, logging.NewNatsWriter(nc, loggingTopic))
+			logging.SetLogger(&mlLogger)
+			shutdownTracing, err := tracing.InitFromFlags(types.Execution, cmd.Flags())
+			if err != nil {
+				panic(err)
+			}
+			defer shutdownTracing()
+
+			logging.Log().Info().
+				Str("natsUrl", communication.GetNatsURL()).
+				Bool("jetstream", communication.IsJetStreamEnabled()).
+				Interface("broker", broker.GetConfig()).
+				Msg("starting execution, remote logging enabled")
+
+			if err := accounts.Load(accountsPath); err != nil {
+				panic(err)
+			}
+			// The trade updates are streamed before orders can be submitted so that none is missed
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			alpaca.Start(ctx)
+
+			// Create a channel to receive OS signals
+			sigs := make(chan os.Signal, 1)
+
+			// Register the channel to receive SIGINT and SIGTERM signals
+			signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+
+			command.StartCommandHandler(types.Execution, cli.NewRootCmd, json.HandleJSONCommand)
+			cmdHandler := command.GetCommandHandler()
+			command.StartHealthServerFromFlags(cmd.Flags())
+			metrics.StartServerFromFlags(cmdHandler.Ctx(), cmd.Flags())
+
+			go func() {
+				<-sigs
+				cmdHandler.Cancel()
+			}()
+			<-cmdHandler.Ctx().Done()
+			cmdHandler.Wg.Wait()
+		},
+	}
+	rootCmd.Flags().StringP("nats-url", "n", communication.GetNatsURL(), "NATS server URL")
+	rootCmd.Flags().Float64P("slippage", "l", 0,
+		"Slippage applied against market and stop order fills, in basis points of the price")
+	rootCmd.Flags().Float64P("commission", "m", 0,
+		"Commission per unit filled")
+	rootCmd.Flags().Float64P("commission-rate", "k", 0,
+		"Commission in basis points of the notional filled")
+	rootCmd.Flags().String("accounts", "",
+		"Path to the JSON file of the Alpaca accounts registry of the dataprovider, read on startup. The default account is the one of ALPACA_KEY, ALPACA_SECRET and ALPACA_LIVE unless registered")
+	communication.AddJetStreamFlags(rootCmd.Flags())
+	communication.AddBatchFlags(rootCmd.Flags())
+	command.AddHealthFlags(rootCmd.Flags())
+	metrics.AddMetricsFlags(rootCmd.Flags())
+	tracing.AddTracingFlags(rootCmd.Flags())
+	return &rootCmd
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"fmt"
+
+	"tradingplatform/execution/alpaca"
+	"tradingplatform/execution/broker"
+	"tradingplatform/shared/entities"
+	"tradingplatform/shared/requests"
+	"tradingplatform/shared/types"
+)
+
+// A broker the orders of an account are routed to
+type Broker interface {
+	// Submit an order, a rejected order is returned with an error
+	Submit(req requests.OrderRequest) (*entities.Order, error)
+	Cancel(account requests.Account, id string) (*entities.Order, error)
+	Replace(req requests.OrderRequest) (*entities.Order, error)
+	GetOrders(account requests.Account) ([]*entities.Order, error)
+	GetPositions(account requests.Account) ([]*entities.Position, error)
+}
+
+func getBroker(name types.Broker) (Broker, error) {
+	switch name {
+	case types.SimulatedBroker:
+		return simulatedBroker{}, nil
+	case types.AlpacaBroker:
+		return alpacaBroker{}, nil
+	}
+	return nil, fmt.Errorf("broker %s not supported", name)
+}
+
+// Broker matching orders against the market data of the dataprovider
+type simulatedBroker struct{}
+
+func (simulatedBroker) Submit(req requests.OrderRequest) (*entities.Order, error) {
+	order := broker.Submit(req)
+	if types.OrderStatus(order.Status) == types.OrderRejected {
+		return order, fmt.Errorf("order rejected: %s", order.Reason)
+	}
+	return order, nil
+}
+
+func (simulatedBroker) Cancel(account requests.Account, id string) (*entities.Order, error) {
+	return broker.Cancel(account, id)
+}
+
+func (simulatedBroker) Replace(req requests.OrderRequest) (*entities.Order, error) {
+	return broker.Replace(req)
+}
+
+func (simulatedBroker) GetOrders(account requests.Account) ([]*entities.Order, error) {
+	return broker.GetOrders(account), nil
+}
+
+func (simulatedBroker) GetPositions(account requests.Account) ([]*entities.Position, error) {
+	return broker.GetPositions(account), nil
+}
+
+// Broker routing orders to the Alpaca trading API of the account
+type alpacaBroker struct{}
+
+func (alpacaBroker) Submit(req requests.OrderRequest) (*entities.Order, error) {
+	return alpaca.Submit(req)
+}
+
+func (alpacaBroker) Cancel(account requests.Account, id string) (*entities.Order, error) {
+	return alpaca.Cancel(account, id)
+}
+
+func (alpacaBroker) Replace(req requests.OrderRequest) (*entities.Order, error) {
+	return alpaca.Replace(req)
+}
+
+func (alpacaBroker) GetOrders(account requests.Account) ([]*entities.Order, error) {
+	return alpaca.GetOrders(account)
+}
+
+func (alpacaBroker) GetPositions(account requests.Account) ([]*entities.Position, error) {
+	return alpaca.GetPositions(account)
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"fmt"
+
+	"tradingplatform/shared/entities"
+	"tradingplatform/shared/logging"
+	"tradingplatform/shared/requests"
+	"tradingplatform/shared/types"
+)
+
+// HandleOrderRequest submits, cancels or replaces an order of the broker of the request, or returns
+// the open orders and positions of an account
+func HandleOrderRequest(req requests.OrderRequest) entities.OrderResponse {
+	logging.Log().Debug().RawJSON("request", req.JSON()).Msg("handling order request")
+
+	b, err := getBroker(req.Broker)
+	if err != nil {
+		return entities.NewOrderError(err)
+	}
+
+	switch req.Operation {
+	case types.OrderSubmitOp:
+		order, err := b.Submit(req)
+		if err != nil {
+			logging.Log().Warn().
+				Err(err).
+				RawJSON("request", req.JSON()).
+				Msg("submitting order")
+			var orders []*entities.Order
+			if order != nil {
+				orders = append(orders, order)
+			}
+			return entities.NewOrderResponse(types.Failure, "", err, orders, nil)
+		}
+		logging.Log().Info().
+			Str("orderId", order.ID).
+			Str("broker", order.Broker).
+			Str("account", order.Account).
+			Str("symbol", order.Symbol).
+			Str("side", order.Side).
+			Str("type", order.Type).
+			Float64("quantity", order.Quantity).
+			Msg("submitted order")
+		return entities.NewOrderResponse(types.Success,
+			"Submitted order",
+			nil,
+			[]*entities.Order{order},
+			nil)
+	case types.OrderCancelOp:
+		order, err := b.Cancel(req.Account, req.OrderID)
+		if err != nil {
+			return entities.NewOrderError(err)
+		}
+		logging.Log().Info().Str("orderId", order.ID).Msg("canceled order")
+		return entities.NewOrderResponse(types.Success,
+			"Canceled order",
+			nil,
+			[]*entities.Order{order},
+			nil)
+	case types.OrderReplaceOp:
+		order, err := b.Replace(req)
+		if err != nil {
+			return entities.NewOrderError(err)
+		}
+		logging.Log().Info().
+			Str("orderId", order.ID).
+			Str("replaces", req.OrderID).
+			Msg("replaced order")
+		return entities.NewOrderResponse(types.Success,
+			"Replaced order",
+			nil,
+			[]*entities.Order{order},
+			nil)
+	case types.OrderGetOp:
+		orders, err := b.GetOrders(req.Account)
+		if err != nil {
+			return entities.NewOrderError(err)
+		}
+		positions, err := b.GetPositions(req.Account)
+		if err != nil {
+			return entities.NewOrderError(err)
+		}
+		return entities.NewOrderResponse(types.Success, "", nil, orders, positions)
+	}
+	return entities.NewOrderError(fmt.Errorf("operation %s not supported", req.Operation))
+}
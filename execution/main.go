@@ -0,0 +1,7 @@
+package main
+
+import "tradingplatform/execution/command/local"
+
+func main() {
+	local.NewRootCmd().Execute()
+}
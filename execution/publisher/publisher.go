@@ -0,0 +1,28 @@
+package publisher
+
+import (
+	"tradingplatform/shared/communication/producer"
+	"tradingplatform/shared/entities"
+	"tradingplatform/shared/types"
+	"tradingplatform/shared/utils"
+)
+
+// An order, fill or position of the execution component
+type Publishable interface {
+	entities.Payloader
+	SetFingerprint()
+	GetSource() string
+	GetAssetClass() string
+	GetSymbol() string
+}
+
+// Publish an entity on the execution stream topic of its data type, source, asset class and symbol
+func Publish(entity Publishable, dtype types.DataType) {
+	entity.SetFingerprint()
+	topic := utils.NewStreamTopic(types.Execution,
+		types.Source(entity.GetSource()),
+		types.AssetClass(entity.GetAssetClass()),
+		dtype,
+		entity.GetSymbol()).Generate()
+	producer.GetStreamHandler(topic).Ch <- entities.GenerateMessage(entity, dtype, topic)
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"tradingplatform/indicators/handler"
+	"tradingplatform/shared/logging"
+	"tradingplatform/shared/requests"
+	"tradingplatform/shared/types"
+
+	"github.com/spf13/cobra"
+)
+
+// Indicator stream commands (does nothing by itself)
+func NewStreamCmd() *cobra.Command {
+	streamCmd := cobra.Command{
+		Use:   "stream",
+		Short: "Command to handle indicator streams",
+	}
+
+	streamCmd.AddCommand(newStreamOperationCmd("add", types.StreamAddOp,
+		"Compute indicators on the bar streams of symbols",
+		`Subscribe to the bar streams of symbols and publish the indicators computed on them
+		on indicators.stream.<source>.<assetClass>.<indicator>.<symbol>.`))
+	streamCmd.AddCommand(newStreamOperationCmd("delete", types.StreamRemoveOp,
+		"Stop computing indicators on the bar streams of symbols", ""))
+	streamCmd.AddCommand(newStreamOperationCmd("get", types.StreamGetOp,
+		"Get the active indicator streams", ""))
+
+	return &streamCmd
+}
+
+func newStreamOperationCmd(use string, operation types.StreamRequestOp, short string, long string) *cobra.Command {
+	streamOperationCmd := cobra.Command{
+		Use:   use,
+		Short: short,
+		Long:  long,
+		Run: func(cmd *cobra.Command, args []string) {
+			source, _ := cmd.Flags().GetString("source")
+			assetClass, _ := cmd.Flags().GetString("asset-class")
+			symbols, _ := cmd.Flags().GetStringArray("symbols")
+			barType, _ := cmd.Flags().GetString("bar-type")
+			indicators, _ := cmd.Flags().GetStringArray("indicators")
+			warmup, _ := cmd.Flags().GetDuration("warmup")
+
+			req, err := requests.NewIndicatorStreamRequestFromRaw(source,
+				assetClass,
+				symbols,
+				string(operation),
+				barType,
+				indicators,
+				warmup,
+				requests.DefaultForEmptyIndicatorStreamRequest)
+
+			logging.Log().Info().
+				RawJSON("indicatorStreamRequest", req.JSON()).
+				Msg("receiving indicator stream request")
+
+			if err != nil {
+				cmd.Print(handler.NewStreamError(err).Respond())
+				return
+			}
+
+			cmd.Print(handler.HandleIndicatorStreamRequest(cmd.Context(), req).Respond())
+		},
+	}
+
+	streamOperationCmd.Flags().StringP("source", "s", "",
+		"Source of the bar stream")
+	streamOperationCmd.Flags().StringArrayP("symbols", "y", []string{},
+		"Symbols")
+	streamOperationCmd.Flags().StringP("asset-class", "a", "",
+		"Asset class")
+	streamOperationCmd.Flags().StringP("bar-type", "t", "",
+		"Data type of the bar stream (e.g. bar, or bar-5sec for the aggregator source)")
+	streamOperationCmd.Flags().StringArrayP("indicators", "i", []string{},
+		"Indicators with their parameters (e.g. sma-20, ema-12, rsi-14, macd-12-26-9, bbands-20-2, atr-14)")
+	streamOperationCmd.Flags().DurationP("warmup", "w", 0,
+		"Length of the history of bars of the datastorage the new indicators are warmed up with (e.g. 24h)")
+
+	return &streamOperationCmd
+}
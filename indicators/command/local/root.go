@@ -0,0 +1,128 @@
+package local
+
+import (
+	"context"
+	JSON "encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"tradingplatform/indicators/command/cli"
+	"tradingplatform/indicators/command/json"
+	"tradingplatform/indicators/handler"
+	"tradingplatform/shared/communication"
+	"tradingplatform/shared/communication/command"
+	"tradingplatform/shared/communication/subscriber"
+	"tradingplatform/shared/logging"
+	"tradingplatform/shared/metrics"
+	"tradingplatform/shared/requests"
+	"tradingplatform/shared/tracing"
+	"tradingplatform/shared/types"
+	"tradingplatform/shared/utils"
+
+	"github.com/nats-io/nats.go"
+	"github.com/spf13/cobra"
+)
+
+func NewRootCmd() *cobra.Command {
+	rootCmd := cobra.Command{
+		Use:   "indicators",
+		Short: "Indicators startup command",
+		Run: func(cmd *cobra.Command, args []string) {
+			startupConfig, _ := cmd.Flags().GetString("startup-commands")
+			natsURL, _ := cmd.Flags().GetString("nats-url")
+			communication.SetNatsURL(natsURL)
+			if err := communication.SetJetStreamConfigFromFlags(cmd.Flags()); err != nil {
+				panic(err)
+			}
+			if err := communication.SetBatchConfigFromFlags(cmd.Flags()); err != nil {
+				panic(err)
+			}
+			// The bar streams are also consumed by the datastorage, each component needs every bar
+			subscriber.SetConsumerGroup(string(types.Indicators))
+			nc, err := nats.Connect(communication.GetNatsURL())
+			if err != nil {
+				panic(err)
+			}
+			defer nc.Close()
+
+			loggingTopic := utils.NewLoggingTopic(types.Indicators).Generate()
+			mlLogger := logging.NewMultiLevelLogger(types.Indicators,
+				os.Stdout, logging.NewNatsWriter(nc, loggingTopic))
+			logging.SetLogger(&mlLogger)
+			shutdownTracing, err := tracing.InitFromFlags(types.Indicators, cmd.Flags())
+			if err != nil {
+				panic(err)
+			}
+			defer shutdownTracing()
+
+			logging.Log().Info().
+				Str("natsUrl", communication.GetNatsURL()).
+				Bool("jetstream", communication.IsJetStreamEnabled()).
+				Msg("starting indicators, remote logging enabled")
+
+			// Create a channel to receive OS signals
+			sigs := make(chan os.Signal, 1)
+
+			// Register the channel to receive SIGINT and SIGTERM signals
+			signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+
+			command.StartCommandHandler(types.Indicators, cli.NewRootCmd, json.HandleJSONCommand)
+			cmdHandler := command.GetCommandHandler()
+			command.StartHealthServerFromFlags(cmd.Flags())
+			metrics.StartServerFromFlags(cmdHandler.Ctx(), cmd.Flags())
+
+			if startupConfig != "" {
+				var commands []command.JSONCommand
+				// Open config file
+				file, err := os.Open(startupConfig)
+				if err != nil {
+					panic(err)
+				}
+				defer file.Close()
+				fileContent, err := io.ReadAll(file)
+				if err != nil {
+					panic(err)
+				}
+
+				err = JSON.Unmarshal(fileContent, &commands)
+				if err != nil {
+					panic(err)
+				}
+				for _, cmd := range commands {
+					if cmd.RootOperation != command.JSONOperationStream {
+						panic(fmt.Errorf("startup config can only contain stream requests for now"))
+					}
+					var indicatorRequest requests.IndicatorStreamRequest
+					err := JSON.Unmarshal(cmd.Request, &indicatorRequest)
+					if err != nil {
+						panic(err)
+					}
+					indicatorRequest, err = requests.NewIndicatorStreamRequestFromExisting(&indicatorRequest,
+						requests.DefaultForEmptyIndicatorStreamRequest)
+					if err != nil {
+						panic(err)
+					}
+					handler.HandleIndicatorStreamRequest(context.Background(), indicatorRequest)
+				}
+			}
+
+			go func() {
+				<-sigs
+				cmdHandler.Cancel()
+			}()
+			<-cmdHandler.Ctx().Done()
+			cmdHandler.Wg.Wait()
+		},
+	}
+	rootCmd.Flags().StringP("startup-commands", "c", "", "Path to the config startup commands")
+	rootCmd.Flags().StringP("nats-url", "n", communication.GetNatsURL(), "NATS server URL")
+	communication.AddJetStreamFlags(rootCmd.Flags())
+	communication.AddBatchFlags(rootCmd.Flags())
+	command.AddHealthFlags(rootCmd.Flags())
+	metrics.AddMetricsFlags(rootCmd.Flags())
+	tracing.AddTracingFlags(rootCmd.Flags())
+	return &rootCmd
+}
@@ -0,0 +1,360 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"tradingplatform/shared/communication/producer"
+	"tradingplatform/shared/communication/subscriber"
+	"tradingplatform/shared/entities"
+	"tradingplatform/shared/indicators"
+	"tradingplatform/shared/logging"
+	"tradingplatform/shared/requests"
+	"tradingplatform/shared/types"
+	"tradingplatform/shared/utils"
+)
+
+// An active indicator stream, the indicators computed on the bar stream of a symbol
+type IndicatorStream struct {
+	Source     types.Source
+	AssetClass types.AssetClass
+	BarType    types.DataType
+	Symbol     string
+	Indicators []string
+}
+
+// The indicators computed on the bar stream of a symbol
+type subscription struct {
+	stream IndicatorStream
+	// Topic of the bar stream
+	topic string
+	specs map[string]indicators.Spec
+	// State of the indicators for each source of the bars, the bars built by the aggregator source
+	// come from several sources
+	sets map[string]*indicators.Set
+	// Indicators being warmed up, added to specs once warmed up
+	warming map[string]bool
+	// Bars received during the warm ups in progress
+	buffers map[*warmupBuffer]struct{}
+	lock    sync.Mutex
+}
+
+// Bars received while indicators are warmed up, applied to them once the bars of the datastorage are
+type warmupBuffer struct {
+	bars []*entities.Bar
+}
+
+var subscriptions = make(map[string]*subscription)
+var subscriptionsLock sync.Mutex
+
+func NewStreamTopic(source types.Source, assetClass types.AssetClass, indicator string, symbol string) utils.Topic {
+	return utils.NewStreamTopic(types.Indicators, source, assetClass, types.DataType(indicator), symbol)
+}
+
+// HandleIndicatorStreamRequest adds, removes or gets indicator streams
+func HandleIndicatorStreamRequest(ctx context.Context, req requests.IndicatorStreamRequest) types.StreamResponse {
+	logging.Log().Debug().RawJSON("request", req.JSON()).Msg("handling indicator stream request")
+
+	switch req.Operation {
+	case types.StreamAddOp:
+		return handleIndicatorStreamAddRequest(ctx, req)
+	case types.StreamRemoveOp:
+		return handleIndicatorStreamRemoveRequest(req)
+	case types.StreamGetOp:
+		return NewStreamResponse(types.Success, "Successfully retrieved streams", nil, getTopics(GetIndicatorStreams()))
+	default:
+		return NewStreamError(fmt.Errorf("operation %s not supported", req.Operation))
+	}
+}
+
+func handleIndicatorStreamAddRequest(ctx context.Context, req requests.IndicatorStreamRequest) types.StreamResponse {
+	specs := make([]indicators.Spec, 0, len(req.Indicators))
+	for _, name := range req.Indicators {
+		spec, err := indicators.ParseSpec(name)
+		if err != nil {
+			return NewStreamError(err)
+		}
+		specs = append(specs, spec)
+	}
+	if req.Warmup > 0 && req.BarType != types.Bar {
+		return NewStreamError(fmt.Errorf("warm up is only supported for %s streams, the datastorage does not hold %s", types.Bar, req.BarType))
+	}
+
+	var added []IndicatorStream
+	for _, symbol := range req.Symbols {
+		sub := getOrCreateSubscription(req, symbol)
+		sub.lock.Lock()
+		var newSpecs []indicators.Spec
+		for _, spec := range specs {
+			if _, ok := sub.specs[spec.Name()]; !ok && !sub.warming[spec.Name()] {
+				newSpecs = append(newSpecs, spec)
+			}
+		}
+		if req.Warmup == 0 || len(newSpecs) == 0 {
+			sub.addSpecs(newSpecs, nil)
+			added = append(added, sub.indicatorStream())
+			sub.lock.Unlock()
+			subscriber.AttatchOrderedFunctionality(sub.topic, sub.handleBar)
+			continue
+		}
+
+		// The bar stream is subscribed before the bars of the datastorage are requested so that no bar
+		// is missed between them, the bars received meanwhile are buffered
+		buffer := &warmupBuffer{}
+		sub.buffers[buffer] = struct{}{}
+		for _, spec := range newSpecs {
+			sub.warming[spec.Name()] = true
+		}
+		sub.lock.Unlock()
+		subscriber.AttatchOrderedFunctionality(sub.topic, sub.handleBar)
+
+		set := sub.warmup(ctx, req, symbol, newSpecs)
+
+		sub.lock.Lock()
+		delete(sub.buffers, buffer)
+		for _, spec := range newSpecs {
+			delete(sub.warming, spec.Name())
+		}
+		// Bars both stored and buffered are applied once, the set ignores bars that are not later
+		var values []*entities.Indicator
+		for _, bar := range buffer.bars {
+			if bar.Source != string(req.Source) {
+				continue
+			}
+			barValues, _ := set.Update(bar)
+			values = append(values, barValues...)
+		}
+		sub.addSpecs(newSpecs, set)
+		added = append(added, sub.indicatorStream())
+		sub.lock.Unlock()
+		sub.publish(values)
+	}
+
+	return NewStreamResponse(types.Success, "Successfully added indicator stream", nil, getTopics(added))
+}
+
+func handleIndicatorStreamRemoveRequest(req requests.IndicatorStreamRequest) types.StreamResponse {
+	var removed []IndicatorStream
+	for _, symbol := range req.Symbols {
+		topic := getBarTopic(req.Source, req.AssetClass, req.BarType, symbol)
+		subscriptionsLock.Lock()
+		sub, ok := subscriptions[topic]
+		if !ok {
+			subscriptionsLock.Unlock()
+			continue
+		}
+		sub.lock.Lock()
+		stream := sub.stream
+		for _, name := range req.Indicators {
+			spec, err := indicators.ParseSpec(name)
+			if err != nil {
+				continue
+			}
+			if _, ok := sub.specs[spec.Name()]; !ok {
+				continue
+			}
+			delete(sub.specs, spec.Name())
+			for _, set := range sub.sets {
+				set.Remove(spec.Name())
+			}
+			stream.Indicators = append(stream.Indicators, spec.Name())
+		}
+		removed = append(removed, stream)
+		empty := len(sub.specs) == 0 && len(sub.warming) == 0
+		sub.lock.Unlock()
+		if empty {
+			subscriber.StopTopicHandler(topic)
+			delete(subscriptions, topic)
+		}
+		subscriptionsLock.Unlock()
+	}
+
+	return NewStreamResponse(types.Success, "Successfully removed indicator stream", nil, getTopics(removed))
+}
+
+// Topic of the bar stream the indicators of a symbol are computed on
+func getBarTopic(source types.Source, assetClass types.AssetClass, barType types.DataType, symbol string) string {
+	return utils.NewStreamTopic(types.DataProvider, source, assetClass, barType, symbol).Generate()
+}
+
+func getOrCreateSubscription(req requests.IndicatorStreamRequest, symbol string) *subscription {
+	topic := getBarTopic(req.Source, req.AssetClass, req.BarType, symbol)
+	subscriptionsLock.Lock()
+	defer subscriptionsLock.Unlock()
+	sub, ok := subscriptions[topic]
+	if !ok {
+		sub = &subscription{
+			stream: IndicatorStream{
+				Source:     req.Source,
+				AssetClass: req.AssetClass,
+				BarType:    req.BarType,
+				Symbol:     symbol,
+			},
+			topic:   topic,
+			specs:   make(map[string]indicators.Spec),
+			sets:    make(map[string]*indicators.Set),
+			warming: make(map[string]bool),
+			buffers: make(map[*warmupBuffer]struct{}),
+		}
+		subscriptions[topic] = sub
+	}
+	return sub
+}
+
+// Warm up new indicators with the bars of the datastorage and return them, must be called without the
+// lock held. A failed warm up is logged and the indicators start with empty state
+func (s *subscription) warmup(ctx context.Context, req requests.IndicatorStreamRequest, symbol string, specs []indicators.Spec) *indicators.Set {
+	set := indicators.NewSet()
+	for _, spec := range specs {
+		set.Add(spec)
+	}
+	dataRequest := req.WarmupRequest(symbol, time.Now())
+	count := 0
+	err := requests.RequestData(ctx, utils.NewCommandTopic(types.DataStorage), dataRequest, func(msg *entities.Message) {
+		utils.HandleEntity(msg, &entities.Bar{}, func(bar *entities.Bar) error {
+			if _, ok := set.Update(bar); ok {
+				count++
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		logging.Log().Warn().
+			Err(err).
+			RawJSON("request", dataRequest.JSON()).
+			Msg("warming up indicators, starting with empty state")
+		set = indicators.NewSet()
+		for _, spec := range specs {
+			set.Add(spec)
+		}
+		return set
+	}
+	logging.Log().Debug().
+		Str("topic", s.topic).
+		Int("bars", count).
+		Msg("warmed up indicators")
+	return set
+}
+
+// Add indicators to the subscription, the indicators of the source of the request are taken from a
+// warmed up set if any. Must be called with the lock held
+func (s *subscription) addSpecs(specs []indicators.Spec, warmed *indicators.Set) {
+	for _, spec := range specs {
+		s.specs[spec.Name()] = spec
+	}
+	if warmed != nil {
+		existing, ok := s.sets[string(s.stream.Source)]
+		if !ok {
+			s.sets[string(s.stream.Source)] = warmed
+		} else {
+			existing.Merge(warmed)
+		}
+	}
+	for _, set := range s.sets {
+		for _, spec := range specs {
+			set.Add(spec)
+		}
+	}
+}
+
+// Compute the indicators on a bar and publish their values
+func (s *subscription) handleBar(msg *entities.Message) error {
+	return utils.HandleEntity(msg, &entities.Bar{}, func(bar *entities.Bar) error {
+		// Minute bars streamed by the dataprovider have no timeframe
+		if bar.Timeframe == "" && s.stream.BarType == types.Bar {
+			bar.Timeframe = string(types.OneMin)
+		}
+		s.lock.Lock()
+		for buffer := range s.buffers {
+			buffer.bars = append(buffer.bars, bar)
+		}
+		set, ok := s.sets[bar.Source]
+		if !ok {
+			set = indicators.NewSet()
+			for _, spec := range s.specs {
+				set.Add(spec)
+			}
+			s.sets[bar.Source] = set
+		}
+		values, ok := set.Update(bar)
+		s.lock.Unlock()
+		if !ok {
+			logging.Log().Debug().
+				Str("topic", s.topic).
+				Int64("timestamp", bar.TimestampNs).
+				Msg("ignoring bar that is not later than the last one")
+			return nil
+		}
+
+		s.publish(values)
+		return nil
+	})
+}
+
+// Publish the values of indicators on their topics
+func (s *subscription) publish(values []*entities.Indicator) {
+	for _, value := range values {
+		topic := NewStreamTopic(s.stream.Source, s.stream.AssetClass, value.Name, s.stream.Symbol).Generate()
+		producer.GetStreamHandler(topic).Ch <- entities.GenerateMessage(value, types.Indicator, topic)
+	}
+}
+
+// Must be called with the lock held
+func (s *subscription) indicatorStream() IndicatorStream {
+	stream := s.stream
+	stream.Indicators = make([]string, 0, len(s.specs))
+	for name := range s.specs {
+		stream.Indicators = append(stream.Indicators, name)
+	}
+	sort.Strings(stream.Indicators)
+	return stream
+}
+
+// GetIndicatorStreams returns the active indicator streams
+func GetIndicatorStreams() []IndicatorStream {
+	subscriptionsLock.Lock()
+	defer subscriptionsLock.Unlock()
+	streams := make([]IndicatorStream, 0, len(subscriptions))
+	for _, sub := range subscriptions {
+		sub.lock.Lock()
+		streams = append(streams, sub.indicatorStream())
+		sub.lock.Unlock()
+	}
+	sort.Slice(streams, func(i, j int) bool {
+		return getBarTopic(streams[i].Source, streams[i].AssetClass, streams[i].BarType, streams[i].Symbol) <
+			getBarTopic(streams[j].Source, streams[j].AssetClass, streams[j].BarType, streams[j].Symbol)
+	})
+	return streams
+}
+
+// Generate the topics of indicator streams by indicator
+func getTopics(streams []IndicatorStream) string {
+	tmap := map[string][]string{}
+	for _, stream := range streams {
+		for _, indicator := range stream.Indicators {
+			tmap[indicator] = append(tmap[indicator],
+				NewStreamTopic(stream.Source, stream.AssetClass, indicator, stream.Symbol).Generate())
+		}
+	}
+	js, err := json.Marshal(tmap)
+	if err != nil {
+		logging.Log().Error().Err(err).Msg("generating topics")
+		return ""
+	}
+	return string(js)
+}
+
+// New indicators-specific stream error
+func NewStreamError(err error) types.StreamResponse {
+	return NewStreamResponse(types.Failure, "", err, "")
+}
+
+// New indicators-specific stream response, listing all active indicator streams
+func NewStreamResponse(status types.OpStatus, message string, err error, topics string) types.StreamResponse {
+	streamsJSON, _ := json.Marshal(GetIndicatorStreams())
+	return types.NewStreamResponse(status, message, err, string(streamsJSON), topics)
+}
@@ -0,0 +1,7 @@
+package main
+
+import "tradingplatform/indicators/command/local"
+
+func main() {
+	local.NewRootCmd().Execute()
+}
@@ -0,0 +1,38 @@
+package command
+
+import (
+	"fmt"
+
+	"tradingplatform/shared/types"
+
+	"github.com/spf13/cobra"
+)
+
+// Cancel a command started with a cancel key
+func NewCancelCmd() *cobra.Command {
+	cancelCmd := cobra.Command{
+		Use:   "cancel <component> <cancel-key>",
+		Short: "Cancel a command started with a cancel key on a component",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			component, ok := types.GetComponentMap()[args[0]]
+			if !ok {
+				return fmt.Errorf("invalid component %s", args[0])
+			}
+
+			s, err := connect(cmd)
+			if err != nil {
+				return err
+			}
+			defer s.Close()
+
+			if err := s.client.Cancel(s.ctx, component, args[1]); err != nil {
+				return err
+			}
+			cmd.Printf("cancelled %s on %s\n", args[1], component)
+			return nil
+		},
+	}
+
+	return &cancelCmd
+}
@@ -0,0 +1,251 @@
+package command
+
+import (
+	"fmt"
+	"io"
+
+	"tradingplatform/client"
+	"tradingplatform/shared/export"
+	"tradingplatform/shared/requests"
+	"tradingplatform/shared/types"
+
+	"github.com/spf13/cobra"
+)
+
+// Data-related commands (does nothing by itself)
+func NewDataCmd() *cobra.Command {
+	dataCmd := cobra.Command{
+		Use:   "data",
+		Short: "Command to request historical data",
+	}
+
+	dataCmd.AddCommand(NewDataGetCmd())
+	dataCmd.AddCommand(NewDataExportCmd())
+
+	return &dataCmd
+}
+
+// Get the historical data of symbols
+func NewDataGetCmd() *cobra.Command {
+	dataGetCmd := cobra.Command{
+		Use:   "get",
+		Short: "Get the historical data of symbols",
+		Long: `Request the data of symbols from the datastorage, or from the dataprovider
+fetching it from the source, and write the entities as JSON lines, CSV or Parquet.
+Times are RFC3339, dates (2006-01-02) or Unix timestamps (seconds or nanoseconds).`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			component, _ := cmd.Flags().GetString("component")
+			source, _ := cmd.Flags().GetString("source")
+			assetClass, _ := cmd.Flags().GetString("asset-class")
+			symbols, _ := cmd.Flags().GetStringSlice("symbols")
+			dataType, _ := cmd.Flags().GetString("data-type")
+			account, _ := cmd.Flags().GetString("account")
+			startTime, _ := cmd.Flags().GetString("start-time")
+			endTime, _ := cmd.Flags().GetString("end-time")
+			timeFrame, _ := cmd.Flags().GetString("time-frame")
+
+			if len(symbols) == 0 {
+				return fmt.Errorf("no symbol")
+			}
+			fromComponent, ok := types.GetComponentMap()[component]
+			if !ok {
+				return fmt.Errorf("invalid component %s", component)
+			}
+			start, err := parseTime(startTime)
+			if err != nil {
+				return err
+			}
+			end, err := parseTime(endTime)
+			if err != nil {
+				return err
+			}
+			w, err := newEntityWriter(cmd, types.DataType(dataType))
+			if err != nil {
+				return err
+			}
+
+			s, err := connect(cmd)
+			if err != nil {
+				w.Close()
+				return err
+			}
+			defer s.Close()
+
+			count := 0
+			for _, symbol := range symbols {
+				req := requests.NewDataRequest(types.Source(source),
+					types.AssetClass(assetClass),
+					symbol,
+					types.DataGetOp,
+					types.DataType(dataType),
+					requests.Account(account),
+					start,
+					end,
+					types.TimeFrame(timeFrame),
+					false)
+				requests.DefaultForEmptyDataRequest(&req)
+
+				n, err := writeHistory(s, fromComponent, req, w)
+				count += n
+				if err != nil {
+					w.Close()
+					return fmt.Errorf("%s: %w", symbol, err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				return err
+			}
+			cmd.PrintErrf("%d entities\n", count)
+			return nil
+		},
+	}
+	dataGetCmd.Flags().String("component", string(types.DataStorage),
+		"Component the data is requested from (datastorage or dataprovider)")
+	dataGetCmd.Flags().StringP("source", "s", "",
+		"Source of the data")
+	dataGetCmd.Flags().StringSliceP("symbols", "y", []string{},
+		"Symbols, comma separated or repeated")
+	dataGetCmd.Flags().StringP("asset-class", "a", "",
+		"Asset class")
+	dataGetCmd.Flags().StringP("data-type", "t", "",
+		"Type of data (e.g. bar, trades...)")
+	dataGetCmd.Flags().StringP("account", "c", "",
+		"Account to use for the request")
+	dataGetCmd.Flags().StringP("start-time", "b", "",
+		"Start time")
+	dataGetCmd.Flags().StringP("end-time", "e", "",
+		"End time")
+	dataGetCmd.Flags().StringP("time-frame", "m", "",
+		"Time frame of the bars (e.g. 1min, 1day)")
+	addEntityWriterFlags(&dataGetCmd)
+
+	return &dataGetCmd
+}
+
+// Write the entities answering a data request, returns the number of entities written
+func writeHistory(s *session, component types.Component, req requests.DataRequest, w *entityWriter) (int, error) {
+	it, err := s.client.HistoryFrom(s.ctx, component, req)
+	if err != nil {
+		return 0, err
+	}
+	return writeEntities(it, w)
+}
+
+func writeEntities(it *client.Iterator, w *entityWriter) (int, error) {
+	defer it.Close()
+	count := 0
+	for it.Next() {
+		event := it.Event()
+		if event.Err != nil {
+			return count, event.Err
+		}
+		if event.Entity == nil {
+			continue
+		}
+		if err := w.Write(event.Entity); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, it.Err()
+}
+
+// Export the stored data of symbols to files
+func NewDataExportCmd() *cobra.Command {
+	dataExportCmd := cobra.Command{
+		Use:   "export",
+		Short: "Make the datastorage export the stored data of symbols to files",
+		Long: `Make the datastorage write the stored data of symbols into one Parquet or CSV file per
+data type, symbol and day (<dataType>/<symbol>/<date>.parquet) under a directory of its
+export directory, and print the manifest of the files written. The export is cancelled
+when the command is interrupted. Times are RFC3339, dates (2006-01-02) or Unix timestamps (seconds or nanoseconds).`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			source, _ := cmd.Flags().GetString("source")
+			assetClass, _ := cmd.Flags().GetString("asset-class")
+			symbols, _ := cmd.Flags().GetStringSlice("symbols")
+			dataTypes, _ := cmd.Flags().GetStringSlice("data-types")
+			startTime, _ := cmd.Flags().GetString("start-time")
+			endTime, _ := cmd.Flags().GetString("end-time")
+			timeFrame, _ := cmd.Flags().GetString("time-frame")
+			format, _ := cmd.Flags().GetString("format")
+			directory, _ := cmd.Flags().GetString("directory")
+			cancelKey, _ := cmd.Flags().GetString("with-cancel-key")
+
+			start, err := parseTime(startTime)
+			if err != nil {
+				return err
+			}
+			end, err := parseTime(endTime)
+			if err != nil {
+				return err
+			}
+			req, err := requests.NewExportRequestFromRaw(source,
+				assetClass,
+				symbols,
+				dataTypes,
+				start,
+				end,
+				timeFrame,
+				format,
+				directory,
+				requests.DefaultForEmptyExportRequest)
+			if err != nil {
+				return err
+			}
+
+			s, err := connect(cmd)
+			if err != nil {
+				return err
+			}
+			defer s.Close()
+
+			ctx := s.ctx
+			if cancelKey != "" {
+				ctx = client.WithCancelKey(ctx, cancelKey)
+			}
+			response, err := s.client.Export(ctx, req)
+			if len(response.Files) > 0 || err == nil {
+				if printErr := printExportResponse(cmd, response); err == nil {
+					err = printErr
+				}
+			}
+			return err
+		},
+	}
+	dataExportCmd.Flags().StringP("source", "s", "",
+		"Source of the data")
+	dataExportCmd.Flags().StringSliceP("symbols", "y", []string{},
+		"Symbols, comma separated or repeated")
+	dataExportCmd.Flags().StringP("asset-class", "a", "",
+		"Asset class")
+	dataExportCmd.Flags().StringSliceP("data-types", "t", []string{},
+		"Types of data (bar, daily-bars, trades, quotes, orderbook, raw-text or sentiment), comma separated or repeated")
+	dataExportCmd.Flags().StringP("start-time", "b", "",
+		"Start time")
+	dataExportCmd.Flags().StringP("end-time", "e", "",
+		"End time")
+	dataExportCmd.Flags().StringP("time-frame", "m", "",
+		"Time frame of the bars (e.g. 1min, 1day)")
+	dataExportCmd.Flags().String("format", string(export.Parquet),
+		"Format of the files (parquet, csv or jsonl)")
+	dataExportCmd.Flags().StringP("directory", "d", "",
+		"Directory the files are written to, relative to the export directory of the datastorage")
+	dataExportCmd.Flags().String("with-cancel-key", "",
+		"Set the cancellation key, to cancel the export with otp cancel")
+
+	return &dataExportCmd
+}
+
+func printExportResponse(cmd *cobra.Command, response types.ExportResponse) error {
+	return printResponse(cmd, response, func(w io.Writer) error {
+		if response.Message != "" {
+			printMessage(w, response.Response)
+		}
+		fmt.Fprintf(w, "%d rows in %d files under %s\n\n", response.Rows, len(response.Files), response.Directory)
+		t := newTable(w, "PATH", "ROWS", "SIZE", "SHA256")
+		for _, file := range response.Files {
+			t.row(file.Path, file.Rows, file.Size, file.SHA256)
+		}
+		return t.flush()
+	})
+}
@@ -0,0 +1,167 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"tradingplatform/shared/entities"
+	"tradingplatform/shared/export"
+	"tradingplatform/shared/types"
+	"tradingplatform/shared/utils"
+
+	"github.com/spf13/cobra"
+)
+
+type output string
+
+const (
+	tableOutput output = "table"
+	jsonOutput  output = "json"
+)
+
+func getOutput(cmd *cobra.Command) (output, error) {
+	value, _ := cmd.Flags().GetString("output")
+	switch output(value) {
+	case tableOutput, jsonOutput:
+		return output(value), nil
+	}
+	return "", fmt.Errorf("invalid output %s, expecting table or json", value)
+}
+
+// Print the answer of a component as indented JSON, or as the tables written by printTables
+func printResponse(cmd *cobra.Command, response any, printTables func(io.Writer) error) error {
+	out, err := getOutput(cmd)
+	if err != nil {
+		return err
+	}
+	if out == jsonOutput {
+		encoder := json.NewEncoder(cmd.OutOrStdout())
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(response)
+	}
+	return printTables(cmd.OutOrStdout())
+}
+
+// Table with aligned columns
+type table struct {
+	writer *tabwriter.Writer
+}
+
+func newTable(w io.Writer, headers ...string) *table {
+	t := &table{writer: tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)}
+	t.row(toAny(headers)...)
+	return t
+}
+
+func (t *table) row(values ...any) {
+	cells := make([]string, len(values))
+	for i, value := range values {
+		cells[i] = fmt.Sprint(value)
+	}
+	fmt.Fprintln(t.writer, strings.Join(cells, "\t"))
+}
+
+func (t *table) flush() error {
+	return t.writer.Flush()
+}
+
+func toAny(values []string) []any {
+	result := make([]any, len(values))
+	for i, value := range values {
+		result[i] = value
+	}
+	return result
+}
+
+// Print the message of a response, as a table line
+func printMessage(w io.Writer, response types.Response) {
+	fmt.Fprintf(w, "%s: %s\n", response.Status, response.Message)
+}
+
+// Format a timestamp in Unix nanoseconds, empty for 0
+func formatTimestamp(ts int64) string {
+	if ts == 0 {
+		return ""
+	}
+	return time.Unix(0, ts).UTC().Format(time.RFC3339Nano)
+}
+
+// Parse a time argument given as RFC3339, as a date (2006-01-02, midnight UTC) or as a Unix timestamp,
+// returns Unix nanoseconds. Timestamps too small to be nanoseconds are taken as seconds
+func parseTime(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t.UnixNano(), nil
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t.UnixNano(), nil
+	}
+	ts, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %s, expecting RFC3339, a date or a Unix timestamp", value)
+	}
+	return utils.NormalizeTimestamp(ts), nil
+}
+
+// Writer of the entities of a data type to a file, or to the output of a command without file
+type entityWriter struct {
+	export.Writer
+	file *os.File
+}
+
+func newEntityWriter(cmd *cobra.Command, dataType types.DataType) (*entityWriter, error) {
+	format, _ := cmd.Flags().GetString("format")
+	path, _ := cmd.Flags().GetString("file")
+	exportFormat, ok := export.GetFormatMap()[format]
+	if !ok {
+		return nil, fmt.Errorf("invalid format %s, expecting jsonl, csv or parquet", format)
+	}
+	sample, err := entities.NewEntity(dataType)
+	if err != nil {
+		return nil, err
+	}
+	w := &entityWriter{}
+	var out io.Writer = cmd.OutOrStdout()
+	if path != "" {
+		w.file, err = os.Create(path)
+		if err != nil {
+			return nil, err
+		}
+		out = w.file
+	}
+	w.Writer, err = export.NewWriter(exportFormat, out, sample)
+	if err != nil {
+		w.closeFile()
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *entityWriter) Close() error {
+	err := w.Writer.Close()
+	if closeErr := w.closeFile(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func (w *entityWriter) closeFile() error {
+	if w.file == nil {
+		return nil
+	}
+	return w.file.Close()
+}
+
+// Add the flags of the commands writing entities
+func addEntityWriterFlags(cmd *cobra.Command) {
+	cmd.Flags().String("format", string(export.JSONLines), "Format of the entities (jsonl, csv or parquet)")
+	cmd.Flags().String("file", "", "File the entities are written to, standard output without file")
+}
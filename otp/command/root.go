@@ -0,0 +1,71 @@
+package command
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"tradingplatform/client"
+	"tradingplatform/shared/communication"
+
+	"github.com/spf13/cobra"
+)
+
+func NewRootCmd() *cobra.Command {
+	rootCmd := cobra.Command{
+		Use:   "otp",
+		Short: "Command-line client of the OpenTradingPlatform",
+		Long: `Sends commands to the components of the platform over NATS and prints their
+answers, entities are decoded from their payloads.`,
+		SilenceUsage: true,
+	}
+	rootCmd.PersistentFlags().StringP("nats-url", "n", communication.GetNatsURL(), "NATS server URL")
+	rootCmd.PersistentFlags().Duration("timeout", 0, "Time after which the command is abandoned and cancelled on the component, no timeout with 0")
+	rootCmd.PersistentFlags().StringP("output", "o", string(tableOutput), "Output of the answers of the components (table or json)")
+
+	rootCmd.AddCommand(NewStreamCmd())
+	rootCmd.AddCommand(NewDataCmd())
+	rootCmd.AddCommand(NewStorageCmd())
+	rootCmd.AddCommand(NewSentimentCmd())
+	rootCmd.AddCommand(NewCancelCmd())
+	rootCmd.AddCommand(NewTailCmd())
+	rootCmd.AddCommand(NewStatusCmd())
+
+	return &rootCmd
+}
+
+// Connection of a command to the platform, its context is done on SIGINT and SIGTERM or after the
+// timeout of the command
+type session struct {
+	client *client.Client
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// Connect to NATS with the flags of the root command
+func connect(cmd *cobra.Command) (*session, error) {
+	natsURL, _ := cmd.Flags().GetString("nats-url")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+
+	c, err := client.Connect(natsURL)
+	if err != nil {
+		return nil, err
+	}
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	cancel := stop
+	if timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, timeout)
+		cancel = func() {
+			cancelTimeout()
+			stop()
+		}
+	}
+	return &session{client: c, ctx: ctx, cancel: cancel}, nil
+}
+
+func (s *session) Close() {
+	s.cancel()
+	s.client.Close()
+}
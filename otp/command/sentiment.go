@@ -0,0 +1,126 @@
+package command
+
+import (
+	"tradingplatform/client"
+	"tradingplatform/shared/requests"
+	"tradingplatform/shared/types"
+
+	"github.com/spf13/cobra"
+)
+
+// Sentiment analysis commands (does nothing by itself)
+func NewSentimentCmd() *cobra.Command {
+	sentimentCmd := cobra.Command{
+		Use:   "sentiment",
+		Short: "Command to analyze the sentiment of news",
+	}
+
+	sentimentCmd.AddCommand(NewSentimentAnalyzeCmd())
+
+	return &sentimentCmd
+}
+
+// Analyze the sentiment of the stored news of a symbol
+func NewSentimentAnalyzeCmd() *cobra.Command {
+	sentimentAnalyzeCmd := cobra.Command{
+		Use:   "analyze",
+		Short: "Analyze the sentiment of the stored news of a symbol",
+		Long: `Make the sentiment analyzer analyze the news of a symbol stored by the datastorage
+and write the news with their sentiments as JSON lines, CSV or Parquet. The analysis
+is cancelled when the command is interrupted. Times are RFC3339, dates (2006-01-02)
+or Unix timestamps (seconds or nanoseconds).`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			symbol, _ := cmd.Flags().GetString("symbol")
+			source, _ := cmd.Flags().GetString("source")
+			systemPrompt, _ := cmd.Flags().GetString("system-prompt")
+			cancelKey, _ := cmd.Flags().GetString("with-cancel-key")
+			retryFailed, _ := cmd.Flags().GetBool("retry-failed")
+			startTime, _ := cmd.Flags().GetString("start-time")
+			endTime, _ := cmd.Flags().GetString("end-time")
+			failFastOnBadSentiment, _ := cmd.Flags().GetBool("fail-fast-bad-sentiment")
+			model, _ := cmd.Flags().GetString("model")
+			sentimentAnalysisProcess, _ := cmd.Flags().GetString("process")
+
+			start, err := parseTime(startTime)
+			if err != nil {
+				return err
+			}
+			end, err := parseTime(endTime)
+			if err != nil {
+				return err
+			}
+			dataRequest := requests.NewDataRequest(types.Source(source),
+				types.News,
+				symbol,
+				types.DataGetOp,
+				types.RawText,
+				"",
+				start,
+				end,
+				types.NoTimeFrame,
+				false)
+			req, err := requests.NewSentimentAnalysisRequestFromRaw(dataRequest,
+				sentimentAnalysisProcess,
+				model,
+				systemPrompt,
+				failFastOnBadSentiment,
+				retryFailed,
+				requests.DefaultForEmptySentimentAnalysisRequest)
+			if err != nil {
+				return err
+			}
+			w, err := newEntityWriter(cmd, types.NewsWithSentiment)
+			if err != nil {
+				return err
+			}
+
+			s, err := connect(cmd)
+			if err != nil {
+				w.Close()
+				return err
+			}
+			defer s.Close()
+
+			ctx := s.ctx
+			if cancelKey != "" {
+				ctx = client.WithCancelKey(ctx, cancelKey)
+			}
+			it, err := s.client.AnalyzeSentiment(ctx, req)
+			if err != nil {
+				w.Close()
+				return err
+			}
+			count, err := writeEntities(it, w)
+			if closeErr := w.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				return err
+			}
+			cmd.PrintErrf("%d news analyzed\n", count)
+			return nil
+		},
+	}
+	sentimentAnalyzeCmd.Flags().StringP("source", "s", "",
+		"Source of the news data")
+	sentimentAnalyzeCmd.Flags().StringP("symbol", "y", "",
+		"Symbol")
+	sentimentAnalyzeCmd.Flags().StringP("system-prompt", "t", "",
+		"System prompt for sentiment analysis")
+	sentimentAnalyzeCmd.Flags().StringP("model", "m", "", `LLM to use for sentiment analysis. Format:
+	{provider}/{model} (e.g. ollama/llama2)`)
+	sentimentAnalyzeCmd.Flags().StringP("start-time", "b", "",
+		"Start time of the news")
+	sentimentAnalyzeCmd.Flags().StringP("end-time", "e", "",
+		"End time of the news")
+	sentimentAnalyzeCmd.Flags().StringP("process", "p", "", "Sentiment analysis process")
+	sentimentAnalyzeCmd.Flags().BoolP("fail-fast-bad-sentiment", "f", false,
+		"Whether to fail fast when invalid sentiment is detected")
+	sentimentAnalyzeCmd.Flags().BoolP("retry-failed", "r", false,
+		"Whether to retry sentiment analysis for news that failed previously")
+	sentimentAnalyzeCmd.Flags().StringP("with-cancel-key", "c", "",
+		"Set the cancellation key, to cancel the analysis with otp cancel")
+	addEntityWriterFlags(&sentimentAnalyzeCmd)
+
+	return &sentimentAnalyzeCmd
+}
@@ -0,0 +1,83 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"tradingplatform/shared/types"
+
+	"github.com/spf13/cobra"
+)
+
+// Time a component has to answer the status command
+const statusTimeout = 5 * time.Second
+
+// Get the status of components
+func NewStatusCmd() *cobra.Command {
+	statusCmd := cobra.Command{
+		Use:   "status [component]...",
+		Short: "Get the status of components, of all the components without component",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			components := make([]types.Component, 0, len(args))
+			for _, arg := range args {
+				component, ok := types.GetComponentMap()[arg]
+				if !ok {
+					return fmt.Errorf("invalid component %s", arg)
+				}
+				components = append(components, component)
+			}
+			if len(components) == 0 {
+				for _, component := range types.GetComponentMap() {
+					components = append(components, component)
+				}
+				sort.Slice(components, func(i, j int) bool { return components[i] < components[j] })
+			}
+
+			s, err := connect(cmd)
+			if err != nil {
+				return err
+			}
+			defer s.Close()
+
+			statuses := make([]types.StatusResponse, len(components))
+			for i, component := range components {
+				ctx, cancel := context.WithTimeout(s.ctx, statusTimeout)
+				status, err := s.client.Status(ctx, component)
+				cancel()
+				if err != nil {
+					// Components that do not answer are listed with the error
+					status = types.StatusResponse{Response: types.Response{Err: err.Error()}}
+				}
+				status.Component = component
+				statuses[i] = status
+			}
+			return printResponse(cmd, statuses, func(w io.Writer) error {
+				t := newTable(w, "COMPONENT", "READY", "NATS", "UPTIME", "IN-FLIGHT", "STREAMS", "QUEUES", "DATABASES", "ERROR")
+				for _, status := range statuses {
+					if status.Err != "" {
+						t.row(status.Component, false, "unreachable", "", "", "", "", "", status.Err)
+						continue
+					}
+					databases := make([]string, len(status.Databases))
+					for i, database := range status.Databases {
+						state := "connected"
+						if !database.Connected {
+							state = "disconnected"
+						}
+						databases[i] = database.Name + " " + state
+					}
+					uptime := time.Duration(status.Uptime * float64(time.Second)).Truncate(time.Second)
+					t.row(status.Component, status.Ready, status.Nats, uptime, status.InFlightCommands,
+						len(status.Streams), len(status.Queues), strings.Join(databases, ", "), "")
+				}
+				return t.flush()
+			})
+		},
+	}
+
+	return &statusCmd
+}
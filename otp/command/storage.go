@@ -0,0 +1,111 @@
+package command
+
+import (
+	"io"
+
+	"tradingplatform/shared/types"
+
+	"github.com/spf13/cobra"
+)
+
+// Commands handling the topics stored by the datastorage (does nothing by itself)
+func NewStorageCmd() *cobra.Command {
+	storageCmd := cobra.Command{
+		Use:   "storage",
+		Short: "Command to handle the topics stored by the datastorage",
+	}
+
+	storageCmd.AddCommand(NewStorageSubscribeCmd())
+	storageCmd.AddCommand(NewStorageUnsubscribeCmd())
+	storageCmd.AddCommand(NewStorageListCmd())
+
+	return &storageCmd
+}
+
+// Store topics
+func NewStorageSubscribeCmd() *cobra.Command {
+	storageSubscribeCmd := cobra.Command{
+		Use:   "subscribe <topic>...",
+		Short: "Make the datastorage store the entities of topics or topic patterns",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			agents, _ := cmd.Flags().GetInt("agents")
+
+			s, err := connect(cmd)
+			if err != nil {
+				return err
+			}
+			defer s.Close()
+
+			response, err := s.client.StorageSubscribe(s.ctx, args, agents)
+			if err != nil {
+				return err
+			}
+			return printStorageResponse(cmd, response)
+		},
+	}
+	storageSubscribeCmd.Flags().Int("agents", 1,
+		"Number of agents storing the entities of each topic")
+
+	return &storageSubscribeCmd
+}
+
+// Stop storing topics
+func NewStorageUnsubscribeCmd() *cobra.Command {
+	storageUnsubscribeCmd := cobra.Command{
+		Use:   "unsubscribe <topic>...",
+		Short: "Stop the datastorage from storing the entities of topics",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			s, err := connect(cmd)
+			if err != nil {
+				return err
+			}
+			defer s.Close()
+
+			response, err := s.client.StorageUnsubscribe(s.ctx, args)
+			if err != nil {
+				return err
+			}
+			return printStorageResponse(cmd, response)
+		},
+	}
+
+	return &storageUnsubscribeCmd
+}
+
+// List the stored topics
+func NewStorageListCmd() *cobra.Command {
+	storageListCmd := cobra.Command{
+		Use:   "list",
+		Short: "List the topics stored by the datastorage",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			s, err := connect(cmd)
+			if err != nil {
+				return err
+			}
+			defer s.Close()
+
+			topics, err := s.client.StorageTopics(s.ctx)
+			if err != nil {
+				return err
+			}
+			return printResponse(cmd, topics, func(w io.Writer) error {
+				t := newTable(w, "TOPIC")
+				for _, topic := range topics {
+					t.row(topic)
+				}
+				return t.flush()
+			})
+		},
+	}
+
+	return &storageListCmd
+}
+
+func printStorageResponse(cmd *cobra.Command, response types.StreamResponse) error {
+	return printResponse(cmd, response.Response, func(w io.Writer) error {
+		printMessage(w, response.Response)
+		return nil
+	})
+}
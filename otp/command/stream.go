@@ -0,0 +1,224 @@
+package command
+
+import (
+	"io"
+
+	"tradingplatform/client"
+	"tradingplatform/shared/entities"
+	"tradingplatform/shared/requests"
+	"tradingplatform/shared/types"
+
+	"github.com/spf13/cobra"
+)
+
+// Stream-related commands of the dataprovider (does nothing by itself)
+func NewStreamCmd() *cobra.Command {
+	streamCmd := cobra.Command{
+		Use:   "stream",
+		Short: "Command to handle the data streams of the dataprovider",
+	}
+
+	streamCmd.AddCommand(NewStreamAddCmd())
+	streamCmd.AddCommand(NewStreamRemoveCmd())
+	streamCmd.AddCommand(NewStreamListCmd())
+
+	return &streamCmd
+}
+
+// Start data streams
+func NewStreamAddCmd() *cobra.Command {
+	streamAddCmd := cobra.Command{
+		Use:   "add",
+		Short: "Add data streams",
+		Long: `Make the dataprovider subscribe to the streams of the source for symbols and
+data types, all the data types of the source are streamed without data type.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runStreamRequest(cmd, types.StreamAddOp)
+		},
+	}
+	addStreamFlags(&streamAddCmd)
+	streamAddCmd.Flags().String("replay-start", "",
+		"Start time of the session replayed by the replay source (RFC3339, date or unix time)")
+	streamAddCmd.Flags().String("replay-end", "",
+		"End time (exclusive) of the session replayed by the replay source (RFC3339, date or unix time)")
+	streamAddCmd.Flags().Float64("replay-speed", 1,
+		"Replay speed multiplier (e.g. 1, 10), 0 replays as fast as possible")
+	streamAddCmd.Flags().String("replay-source", "",
+		"Source of the stored data to replay (default alpaca)")
+
+	return &streamAddCmd
+}
+
+// Stop data streams
+func NewStreamRemoveCmd() *cobra.Command {
+	streamRemoveCmd := cobra.Command{
+		Use:   "remove",
+		Short: "Remove data streams",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runStreamRequest(cmd, types.StreamRemoveOp)
+		},
+	}
+	addStreamFlags(&streamRemoveCmd)
+
+	return &streamRemoveCmd
+}
+
+// List the active data streams
+func NewStreamListCmd() *cobra.Command {
+	streamListCmd := cobra.Command{
+		Use:   "list",
+		Short: "List the active data streams and the state of their connections",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			source, _ := cmd.Flags().GetString("source")
+			assetClass, _ := cmd.Flags().GetString("asset-class")
+			account, _ := cmd.Flags().GetString("account")
+
+			s, err := connect(cmd)
+			if err != nil {
+				return err
+			}
+			defer s.Close()
+
+			req := requests.NewStreamRequest(types.Source(source),
+				types.AssetClass(assetClass),
+				[]string{},
+				types.StreamGetOp,
+				nil,
+				requests.Account(account))
+			requests.DefaultForEmptyStreamRequest(&req)
+			response, err := s.client.StreamList(s.ctx, req.Source, req.AssetClass, req.Account)
+			if err != nil {
+				return err
+			}
+			return printStreamResponse(cmd, response)
+		},
+	}
+	streamListCmd.Flags().StringP("source", "s", "",
+		"Source of the data streams")
+	streamListCmd.Flags().StringP("asset-class", "a", "",
+		"Asset class")
+	streamListCmd.Flags().StringP("account", "c", "",
+		"Account of the streams")
+
+	return &streamListCmd
+}
+
+func addStreamFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("source", "s", "",
+		"Source of the data streams")
+	cmd.Flags().StringSliceP("symbols", "y", []string{},
+		"Symbols, comma separated or repeated")
+	cmd.Flags().StringP("asset-class", "a", "",
+		"Asset class")
+	cmd.Flags().StringSliceP("data-types", "t", []string{},
+		"Types of data (e.g. bar, trades...), comma separated or repeated")
+	cmd.Flags().StringP("account", "c", "",
+		"Account to use for the streams")
+}
+
+func runStreamRequest(cmd *cobra.Command, operation types.StreamRequestOp) error {
+	source, _ := cmd.Flags().GetString("source")
+	assetClass, _ := cmd.Flags().GetString("asset-class")
+	symbols, _ := cmd.Flags().GetStringSlice("symbols")
+	dataTypes, _ := cmd.Flags().GetStringSlice("data-types")
+	account, _ := cmd.Flags().GetString("account")
+
+	convertedDataTypes := make([]types.DataType, len(dataTypes))
+	for i, dataType := range dataTypes {
+		convertedDataTypes[i] = types.DataType(dataType)
+	}
+	req := requests.NewStreamRequest(types.Source(source),
+		types.AssetClass(assetClass),
+		symbols,
+		operation,
+		convertedDataTypes,
+		requests.Account(account))
+	if operation == types.StreamAddOp && req.Source == types.Replay {
+		replay, err := replayOptions(cmd)
+		if err != nil {
+			return err
+		}
+		req.Replay = replay
+	}
+	requests.DefaultForEmptyStreamAddDeleteRequest(&req)
+
+	s, err := connect(cmd)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	var response types.StreamResponse
+	if operation == types.StreamAddOp {
+		response, err = s.client.StreamAdd(s.ctx, req)
+	} else {
+		response, err = s.client.StreamRemove(s.ctx, req)
+	}
+	if err != nil {
+		return err
+	}
+	return printStreamResponse(cmd, response)
+}
+
+// The session replayed by a replay stream add request
+func replayOptions(cmd *cobra.Command) (*requests.ReplayOptions, error) {
+	startTime, _ := cmd.Flags().GetString("replay-start")
+	endTime, _ := cmd.Flags().GetString("replay-end")
+	speed, _ := cmd.Flags().GetFloat64("replay-speed")
+	source, _ := cmd.Flags().GetString("replay-source")
+	start, err := parseTime(startTime)
+	if err != nil {
+		return nil, err
+	}
+	end, err := parseTime(endTime)
+	if err != nil {
+		return nil, err
+	}
+	return &requests.ReplayOptions{
+		StoredSource: types.Source(source),
+		StartTime:    start,
+		EndTime:      end,
+		Speed:        speed,
+	}, nil
+}
+
+func printStreamResponse(cmd *cobra.Command, response types.StreamResponse) error {
+	streams, err := client.ParseStreams(response)
+	if err != nil {
+		return err
+	}
+	connections, err := client.ParseConnections(response)
+	if err != nil {
+		return err
+	}
+	return printResponse(cmd, struct {
+		types.Response
+		Topics      string
+		Streams     []client.Stream
+		Connections []*entities.ConnectionStatus
+	}{response.Response, response.Topics, streams, connections}, func(w io.Writer) error {
+		printMessage(w, response.Response)
+		if len(streams) > 0 {
+			io.WriteString(w, "\n")
+			t := newTable(w, "SOURCE", "ACCOUNT", "ASSET CLASS", "DATA TYPE", "SYMBOL")
+			for _, stream := range streams {
+				t.row(stream.DataSource, stream.Account, stream.AssetClass, stream.DataType, stream.Symbol)
+			}
+			if err := t.flush(); err != nil {
+				return err
+			}
+		}
+		if len(connections) > 0 {
+			io.WriteString(w, "\n")
+			t := newTable(w, "ACCOUNT", "ASSET CLASS", "STATE", "SINCE", "ERROR")
+			for _, connection := range connections {
+				t.row(connection.Account, connection.AssetClass, connection.State,
+					formatTimestamp(connection.Timestamp), connection.Error)
+			}
+			if err := t.flush(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,99 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"tradingplatform/client"
+
+	"github.com/spf13/cobra"
+)
+
+// Print the entities published on topics
+func NewTailCmd() *cobra.Command {
+	tailCmd := cobra.Command{
+		Use:   "tail <topic pattern>...",
+		Short: "Print the entities published on topics or topic patterns",
+		Long: `Subscribe to topics or topic patterns (e.g. dataprovider.stream.alpaca.stock.>) and
+print their entities, decoded according to their data type, until interrupted. Each
+entity is a line of the table output or a JSON object with the json output.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out, err := getOutput(cmd)
+			if err != nil {
+				return err
+			}
+
+			s, err := connect(cmd)
+			if err != nil {
+				return err
+			}
+			defer s.Close()
+
+			// The handler is called concurrently for the topics of different subscriptions
+			var mu sync.Mutex
+			w := cmd.OutOrStdout()
+			handler := func(event client.Event) {
+				mu.Lock()
+				defer mu.Unlock()
+				if out == jsonOutput {
+					printEventJSON(w, event)
+				} else {
+					printEventLine(w, event)
+				}
+			}
+			sub, err := s.client.SubscribeTopics(s.ctx, args, handler)
+			if err != nil {
+				return err
+			}
+			<-sub.Done()
+			return nil
+		},
+	}
+
+	return &tailCmd
+}
+
+// Payload of an event as JSON, the entity when it was decoded
+func eventPayload(event client.Event) json.RawMessage {
+	if event.Entity != nil {
+		payload, err := json.Marshal(event.Entity)
+		if err == nil {
+			return payload
+		}
+	}
+	if json.Valid(event.Payload) {
+		return event.Payload
+	}
+	payload, _ := json.Marshal(string(event.Payload))
+	return payload
+}
+
+func printEventJSON(w io.Writer, event client.Event) {
+	line := struct {
+		Time     string
+		Topic    string
+		DataType string
+		Entity   json.RawMessage `json:",omitempty"`
+		Err      string          `json:",omitempty"`
+	}{Time: time.Now().UTC().Format(time.RFC3339Nano), Topic: event.Topic, DataType: string(event.DataType)}
+	if event.Err != nil {
+		line.Err = event.Err.Error()
+	} else {
+		line.Entity = eventPayload(event)
+	}
+	encoded, _ := json.Marshal(line)
+	fmt.Fprintf(w, "%s\n", encoded)
+}
+
+func printEventLine(w io.Writer, event client.Event) {
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	if event.Err != nil {
+		fmt.Fprintf(w, "%s  %s  %s  error: %s\n", now, event.Topic, event.DataType, event.Err)
+		return
+	}
+	fmt.Fprintf(w, "%s  %s  %s  %s\n", now, event.Topic, event.DataType, eventPayload(event))
+}
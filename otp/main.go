@@ -0,0 +1,13 @@
+package main
+
+import (
+	"os"
+
+	"tradingplatform/otp/command"
+)
+
+func main() {
+	if err := command.NewRootCmd().Execute(); err != nil {
+		os.Exit(1)
+	}
+}
@@ -107,9 +107,9 @@ func NewAnalyzeFromDBCmd() *cobra.Command {
 	analyzeFromDBCmd.Flags().StringP("model", "m", "", `LLM to use for sentiment analysis. Format: 
 	{provider}/{model} (e.g. ollama/llama2)`)
 	analyzeFromDBCmd.Flags().Int64P("start-time", "b", 0,
-		"Start time for the data")
+		"Start time for the data (unix nanoseconds, seconds are also accepted)")
 	analyzeFromDBCmd.Flags().Int64P("end-time", "e", 0,
-		"End time for the data")
+		"End time for the data (unix nanoseconds, seconds are also accepted)")
 	analyzeFromDBCmd.Flags().StringP("process", "p", "", "Sentiment analysis process")
 	analyzeFromDBCmd.Flags().BoolP("no-confirm", "o", false,
 		"Setting this flag will make so that data is streamed as soon as ready")
@@ -12,6 +12,8 @@ import (
 	"tradingplatform/shared/communication"
 	"tradingplatform/shared/communication/command"
 	"tradingplatform/shared/logging"
+	"tradingplatform/shared/metrics"
+	"tradingplatform/shared/tracing"
 	"tradingplatform/shared/types"
 	"tradingplatform/shared/utils"
 
@@ -27,6 +29,9 @@ func NewRootCmd() *cobra.Command {
 			startupConfig, _ := cmd.Flags().GetString("startup-commands")
 			natsURL, _ := cmd.Flags().GetString("nats-url")
 			communication.SetNatsURL(natsURL)
+			if err := communication.SetBatchConfigFromFlags(cmd.Flags()); err != nil {
+				panic(err)
+			}
 			nc, err := nats.Connect(communication.GetNatsURL())
 			if err != nil {
 				panic(err)
@@ -37,6 +42,11 @@ func NewRootCmd() *cobra.Command {
 			mlLogger := logging.NewMultiLevelLogger(types.SentimentAnalyzer,
 				os.Stdout, logging.NewNatsWriter(nc, loggingTopic))
 			logging.SetLogger(&mlLogger)
+			shutdownTracing, err := tracing.InitFromFlags(types.SentimentAnalyzer, cmd.Flags())
+			if err != nil {
+				panic(err)
+			}
+			defer shutdownTracing()
 
 			logging.Log().Info().
 				Str("natsUrl", communication.GetNatsURL()).
@@ -50,6 +60,8 @@ func NewRootCmd() *cobra.Command {
 
 			command.StartCommandHandler(types.SentimentAnalyzer, cli.NewRootCmd, json.HandleJSONCommand)
 			cmdHandler := command.GetCommandHandler()
+			command.StartHealthServerFromFlags(cmd.Flags())
+			metrics.StartServerFromFlags(cmdHandler.Ctx(), cmd.Flags())
 
 			if startupConfig != "" {
 				var commands []command.JSONCommand
@@ -84,5 +96,9 @@ func NewRootCmd() *cobra.Command {
 	}
 	rootCmd.Flags().StringP("startup-commands", "c", "", "Path to the config startup commands")
 	rootCmd.Flags().StringP("nats-url", "n", communication.GetNatsURL(), "NATS server URL")
+	communication.AddBatchFlags(rootCmd.Flags())
+	command.AddHealthFlags(rootCmd.Flags())
+	metrics.AddMetricsFlags(rootCmd.Flags())
+	tracing.AddTracingFlags(rootCmd.Flags())
 	return &rootCmd
 }
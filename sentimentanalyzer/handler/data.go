@@ -15,10 +15,13 @@ import (
 	"tradingplatform/shared/communication/producer"
 	"tradingplatform/shared/entities"
 	"tradingplatform/shared/logging"
+	"tradingplatform/shared/metrics"
 	"tradingplatform/shared/requests"
+	"tradingplatform/shared/tracing"
 	"tradingplatform/shared/types"
 	"tradingplatform/shared/utils"
 
+	"go.opentelemetry.io/otel/attribute"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -26,9 +29,9 @@ import (
 func HandleAnalysisRequest(ctx context.Context, req *requests.SentimentAnalysisRequest, och chan<- types.DataResponse) {
 	switch req.ModelProvider {
 	case types.Ollama:
-		och <- HandleAnalysisNewsFromDB(ctx, req, ollama.HandleAnalysis)
+		och <- HandleAnalysisNewsFromDB(ctx, req, observeLLM(types.Ollama, ollama.HandleAnalysis))
 	case types.GPT4All:
-		och <- HandleAnalysisNewsFromDB(ctx, req, gpt4all.HandleAnalysis)
+		och <- HandleAnalysisNewsFromDB(ctx, req, observeLLM(types.GPT4All, gpt4all.HandleAnalysis))
 	default:
 		err := fmt.Errorf("provided model provider \"%s\" is not currently supported", req.ModelProvider)
 		logging.Log().Debug().Err(err).RawJSON("request", req.JSON()).Msg("while handling sentiment analysis request")
@@ -36,6 +39,18 @@ func HandleAnalysisRequest(ctx context.Context, req *requests.SentimentAnalysisR
 	}
 }
 
+// Record the latency, the failures and the span of the prompts of an analysis function of an LLM provider
+func observeLLM(provider types.LLMProvider, analysisF func(context.Context, string, string, string) (string, error)) func(context.Context, string, string, string) (string, error) {
+	return func(ctx context.Context, systemPrompt string, news string, model string) (string, error) {
+		start := time.Now()
+		ctx, span := tracing.Start(ctx, "llm "+string(provider), attribute.String("model", model))
+		answer, err := analysisF(ctx, systemPrompt, news, model)
+		tracing.End(span, err)
+		metrics.ObserveLLMRequest(string(provider), model, start, err)
+		return answer, err
+	}
+}
+
 func HandleAnalysisNews(ctx context.Context, news *entities.News, req *requests.SentimentAnalysisRequest, analysisF func(context.Context, string, string, string) (string, error)) (string, error) {
 	var systemPrompt string
 	var err error
@@ -163,7 +178,7 @@ func handleJSONResponse(analyzedSentiment string, n *entities.News, req *request
 			vString = ""
 		}
 		sentiment := entities.NewsSentiment{
-			Timestamp:                time.Now().Unix(),
+			TimestampNs:              time.Now().UnixNano(),
 			Sentiment:                vString,
 			SentimentAnalysisProcess: string(req.SentimentAnalysisProcess),
 			News:                     n,
@@ -216,7 +231,7 @@ func handlePlainResponse(analyzedSentiment string, n *entities.News, req *reques
 	}
 
 	sentiment := entities.NewsSentiment{
-		Timestamp:                time.Now().Unix(),
+		TimestampNs:              time.Now().UnixNano(),
 		Sentiment:                extractedSentiment,
 		SentimentAnalysisProcess: string(req.SentimentAnalysisProcess),
 		News:                     n,
@@ -251,6 +266,7 @@ func HandleAnalysisNewsFromDB(ctx context.Context, req *requests.SentimentAnalys
 			logging.Log().Debug().Err(err).Msg("while unmarshalling news from database")
 			return
 		}
+		entity.SyncTimestamps()
 		news = append(news, &entity)
 	})
 
@@ -297,7 +313,7 @@ func HandleAnalysisNewsFromDB(ctx context.Context, req *requests.SentimentAnalys
 		}
 
 		sort.Slice(news, func(i, j int) bool {
-			return news[i].UpdatedAt < news[j].UpdatedAt
+			return news[i].UpdatedAtNs < news[j].UpdatedAtNs
 		})
 
 		responseTopic := utils.NewDataTopic(types.SentimentAnalyzer, types.Internal, types.News, types.NewsWithSentiment, req.GetSymbol(), producer.GenerateQueueID(), len(news)).Generate()
@@ -311,7 +327,7 @@ func HandleAnalysisNewsFromDB(ctx context.Context, req *requests.SentimentAnalys
 			messages = append(messages, message)
 		}
 
-		handler.Ch <- &messages
+		handler.Ch <- producer.NewQueue(responseTopic, messages)
 		return types.NewDataResponse(
 			types.Success,
 			fmt.Sprintf("successfully processed %d news", len(news)),
@@ -0,0 +1,158 @@
+package accounts
+
+import (
+	JSON "encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"tradingplatform/shared/types"
+)
+
+// Name of the account used when a request does not name one
+const Default = "default"
+
+// Credentials of an Alpaca account and the feed of its stock data subscription. The orders of live
+// accounts are routed to the live trading API, the ones of the others to the paper trading API
+type Credentials struct {
+	Key    string     `json:"key"`
+	Secret string     `json:"secret"`
+	Feed   types.Feed `json:"feed,omitempty"`
+	Live   bool       `json:"live,omitempty"`
+}
+
+var registry = make(map[string]Credentials)
+
+// File the registry is saved to, the registry is only kept in memory without file
+var path string
+var lock sync.RWMutex
+
+// Load the accounts of a JSON file mapping account names to their credentials, the file is created
+// by the first account added when it does not exist
+func Load(p string) error {
+	accounts := make(map[string]Credentials)
+	if p != "" {
+		content, err := os.ReadFile(p)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		if len(content) > 0 {
+			if err := JSON.Unmarshal(content, &accounts); err != nil {
+				return fmt.Errorf("reading accounts of %s: %w", p, err)
+			}
+		}
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	registry = accounts
+	path = p
+	return nil
+}
+
+// Default account of the ALPACA_KEY, ALPACA_SECRET, ALPACA_FEED and ALPACA_LIVE environment
+// variables, used when the registry does not define it
+func defaultFromEnv() Credentials {
+	return Credentials{
+		Key:    os.Getenv("ALPACA_KEY"),
+		Secret: os.Getenv("ALPACA_SECRET"),
+		Feed:   types.Feed(strings.ToLower(os.Getenv("ALPACA_FEED"))),
+		Live:   strings.EqualFold(os.Getenv("ALPACA_LIVE"), "true"),
+	}
+}
+
+// Get the credentials of an account, the default account always exists
+func Get(name string) (Credentials, bool) {
+	lock.RLock()
+	defer lock.RUnlock()
+	credentials, ok := registry[name]
+	if !ok && name == Default {
+		return defaultFromEnv(), true
+	}
+	return credentials, ok
+}
+
+// Whether an account is registered, the default account always is
+func Exists(name string) bool {
+	_, ok := Get(name)
+	return ok
+}
+
+// Add an account to the registry and save the registry
+func Add(name string, credentials Credentials) error {
+	lock.Lock()
+	defer lock.Unlock()
+	if _, ok := registry[name]; ok {
+		return fmt.Errorf("account %s already exists", name)
+	}
+	registry[name] = credentials
+	if err := save(); err != nil {
+		delete(registry, name)
+		return err
+	}
+	return nil
+}
+
+// Remove an account from the registry and save the registry
+func Remove(name string) error {
+	lock.Lock()
+	defer lock.Unlock()
+	credentials, ok := registry[name]
+	if !ok {
+		return fmt.Errorf("account %s does not exist", name)
+	}
+	delete(registry, name)
+	if err := save(); err != nil {
+		registry[name] = credentials
+		return err
+	}
+	return nil
+}
+
+// List the accounts of the registry by name without their secret, including the default account of
+// the environment when it has a key
+func List() []types.AccountSummary {
+	lock.RLock()
+	defer lock.RUnlock()
+	accounts := make(map[string]Credentials, len(registry)+1)
+	for name, credentials := range registry {
+		accounts[name] = credentials
+	}
+	if _, ok := accounts[Default]; !ok && defaultFromEnv().Key != "" {
+		accounts[Default] = defaultFromEnv()
+	}
+	summaries := make([]types.AccountSummary, 0, len(accounts))
+	for name, credentials := range accounts {
+		summaries = append(summaries, types.AccountSummary{
+			Name: name,
+			Key:  maskKey(credentials.Key),
+			Feed: credentials.Feed,
+			Live: credentials.Live,
+		})
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Name < summaries[j].Name
+	})
+	return summaries
+}
+
+func maskKey(key string) string {
+	if len(key) <= 4 {
+		return strings.Repeat("*", len(key))
+	}
+	return key[:4] + strings.Repeat("*", len(key)-4)
+}
+
+// Write the registry to its file, readable by the owner only. Must be called with the lock held
+func save() error {
+	if path == "" {
+		return nil
+	}
+	content, err := JSON.MarshalIndent(registry, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0600)
+}
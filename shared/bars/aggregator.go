@@ -0,0 +1,127 @@
+package bars
+
+import (
+	"math"
+	"time"
+
+	"tradingplatform/shared/calendar"
+	"tradingplatform/shared/entities"
+	"tradingplatform/shared/types"
+)
+
+// Aggregator builds the bars of a timeframe from finer bars or from trades, given in time order.
+// The bars are aligned to the trading sessions of a calendar (see calendar.SessionBarPeriod)
+type Aggregator struct {
+	cal       calendar.Calendar
+	timeFrame types.TimeFrame
+
+	current   accumulator
+	period    calendar.Period
+	completed []*entities.Bar
+}
+
+// Accumulates the data of a bar
+type accumulator struct {
+	bar *entities.Bar
+	// Sum of price times volume, data without VWAP is weighted at its close
+	notional float64
+}
+
+// Start a bar with the first data added to it
+func (acc *accumulator) start(bar *entities.Bar, open float64, high float64, low float64) {
+	bar.Open = open
+	bar.High = high
+	bar.Low = low
+	acc.bar = bar
+	acc.notional = 0
+}
+
+func (acc *accumulator) add(high float64, low float64, close float64,
+	volume float64, vwap float64, tradeCount uint64) {
+
+	bar := acc.bar
+	bar.High = math.Max(bar.High, high)
+	bar.Low = math.Min(bar.Low, low)
+	bar.Close = close
+	bar.Volume += volume
+	bar.TradeCount += tradeCount
+	acc.notional += vwap * volume
+}
+
+// Complete the bar, setting its VWAP and fingerprint
+func (acc *accumulator) complete() *entities.Bar {
+	bar := acc.bar
+	if bar.Volume > 0 {
+		bar.VWAP = acc.notional / bar.Volume
+	} else {
+		bar.VWAP = bar.Close
+	}
+	bar.SetFingerprint()
+	acc.bar = nil
+	acc.notional = 0
+	return bar
+}
+
+// NewAggregator creates an aggregator building bars of a timeframe
+func NewAggregator(cal calendar.Calendar, timeFrame types.TimeFrame) (*Aggregator, error) {
+	if _, err := calendar.SessionBarPeriod(cal, timeFrame, time.Unix(0, 0)); err != nil {
+		return nil, err
+	}
+	return &Aggregator{cal: cal, timeFrame: timeFrame}, nil
+}
+
+// AddBar adds a finer bar, it is added to the bar containing its start
+func (a *Aggregator) AddBar(bar *entities.Bar) {
+	vwap := bar.VWAP
+	if vwap == 0 {
+		vwap = bar.Close
+	}
+	a.add(bar.TimestampNs, bar.Symbol, bar.Exchange, bar.Source, bar.AssetClass,
+		bar.Open, bar.High, bar.Low, bar.Close, bar.Volume, vwap, bar.TradeCount)
+}
+
+// AddTrade adds a trade
+func (a *Aggregator) AddTrade(trade *entities.Trade) {
+	a.add(trade.TimestampNs, trade.Symbol, trade.Exchange, trade.Source, trade.AssetClass,
+		trade.Price, trade.Price, trade.Price, trade.Price, trade.Size, trade.Price, 1)
+}
+
+func (a *Aggregator) add(timestamp int64,
+	symbol string, exchange string, source string, assetClass string,
+	open float64, high float64, low float64, close float64,
+	volume float64, vwap float64, tradeCount uint64) {
+
+	t := time.Unix(0, timestamp)
+	// Late data still belongs to the current bar
+	if a.current.bar != nil && !t.Before(a.period.End) {
+		a.completed = append(a.completed, a.current.complete())
+	}
+	if a.current.bar == nil {
+		a.period, _ = calendar.SessionBarPeriod(a.cal, a.timeFrame, t)
+		a.current.start(&entities.Bar{
+			Symbol:      symbol,
+			Exchange:    exchange,
+			Source:      source,
+			AssetClass:  assetClass,
+			Timeframe:   string(a.timeFrame),
+			TimestampNs: a.period.Start.UnixNano(),
+		}, open, high, low)
+	}
+	a.current.add(high, low, close, volume, vwap, tradeCount)
+}
+
+// Completed returns the bars completed since the last call, a bar is completed once data
+// of a later bar is added
+func (a *Aggregator) Completed() []*entities.Bar {
+	completed := a.completed
+	a.completed = nil
+	return completed
+}
+
+// Flush completes the current bar and returns all bars completed since the last call
+func (a *Aggregator) Flush() []*entities.Bar {
+	if a.current.bar != nil {
+		a.completed = append(a.completed, a.current.complete())
+	}
+	return a.Completed()
+}
@@ -0,0 +1,94 @@
+package bars
+
+import (
+	"time"
+
+	"tradingplatform/shared/entities"
+)
+
+// Builder builds the bars of a spec from trades given in time order. Time bars start at the
+// beginning of their period, other bars at their first trade
+type Builder struct {
+	spec    Spec
+	current accumulator
+	// End of the current time bar
+	end       int64
+	completed []*entities.Bar
+}
+
+// NewBuilder creates a builder of the bars of a spec
+func NewBuilder(spec Spec) *Builder {
+	return &Builder{spec: spec}
+}
+
+// Spec returns the spec of the bars built
+func (b *Builder) Spec() Spec {
+	return b.spec
+}
+
+// AddTrade adds a trade, it completes the current bar if the trade belongs to a later time bar or
+// if the bar reaches its threshold
+func (b *Builder) AddTrade(trade *entities.Trade) {
+	if b.spec.Kind == TimeBars {
+		b.Advance(time.Unix(0, trade.TimestampNs))
+	}
+	if b.current.bar == nil {
+		timestamp := trade.TimestampNs
+		if b.spec.Kind == TimeBars {
+			duration := int64(b.spec.Duration)
+			timestamp -= mod(timestamp, duration)
+			b.end = timestamp + duration
+		}
+		b.current.start(&entities.Bar{
+			Symbol:      trade.Symbol,
+			Exchange:    trade.Exchange,
+			Source:      trade.Source,
+			AssetClass:  trade.AssetClass,
+			Timeframe:   b.spec.Name,
+			TimestampNs: timestamp,
+		}, trade.Price, trade.Price, trade.Price)
+	}
+	b.current.add(trade.Price, trade.Price, trade.Price, trade.Size, trade.Price, 1)
+
+	bar := b.current.bar
+	switch b.spec.Kind {
+	case TickBars:
+		if float64(bar.TradeCount) >= b.spec.Threshold {
+			b.completed = append(b.completed, b.current.complete())
+		}
+	case VolumeBars:
+		if bar.Volume >= b.spec.Threshold {
+			b.completed = append(b.completed, b.current.complete())
+		}
+	case DollarBars:
+		if b.current.notional >= b.spec.Threshold {
+			b.completed = append(b.completed, b.current.complete())
+		}
+	}
+}
+
+// Advance completes the current time bar if it ends at or before t, no later trade can belong to it
+func (b *Builder) Advance(t time.Time) {
+	if b.spec.Kind == TimeBars && b.current.bar != nil && t.UnixNano() >= b.end {
+		b.completed = append(b.completed, b.current.complete())
+	}
+}
+
+// Completed returns the bars completed since the last call
+func (b *Builder) Completed() []*entities.Bar {
+	completed := b.completed
+	b.completed = nil
+	return completed
+}
+
+// Flush completes the current bar and returns all bars completed since the last call
+func (b *Builder) Flush() []*entities.Bar {
+	if b.current.bar != nil {
+		b.completed = append(b.completed, b.current.complete())
+	}
+	return b.Completed()
+}
+
+func mod(a int64, b int64) int64 {
+	return ((a % b) + b) % b
+}
@@ -0,0 +1,132 @@
+package bars
+
+import (
+	"sort"
+	"time"
+
+	"tradingplatform/shared/entities"
+	"tradingplatform/shared/types"
+)
+
+// LiveBuilder builds the bars of several specs from a live stream of trades of a symbol. Trades are
+// held back for a grace window before they are passed to the builders: trades arriving late within
+// the window are put in order, and trades corrected or cancelled within the window (see the Update
+// field of a trade) are replaced or removed. Trades and updates arriving after the window are dropped.
+//
+// The time of the stream is the timestamp of the latest trade, advanced by the wall clock time elapsed
+// since it was received, so that bars are also completed while no trades arrive and replayed streams
+// are built in their own time. A LiveBuilder is not safe for concurrent use
+type LiveBuilder struct {
+	grace    time.Duration
+	builders map[string]*Builder
+	// Trades within the grace window, ordered by timestamp
+	pending []*entities.Trade
+	// Trades before the watermark were passed to the builders
+	watermark int64
+	// Timestamp of the latest trade and wall clock time at which it was received
+	latest     int64
+	receivedAt time.Time
+	// Number of trades and updates dropped because they arrived after the grace window
+	dropped int
+}
+
+// NewLiveBuilder creates a live builder holding trades back for a grace window
+func NewLiveBuilder(grace time.Duration) *LiveBuilder {
+	return &LiveBuilder{
+		grace:    grace,
+		builders: make(map[string]*Builder),
+	}
+}
+
+// AddSpec starts building the bars of a spec, the first bar contains the trades after the watermark
+func (l *LiveBuilder) AddSpec(spec Spec) {
+	if _, ok := l.builders[spec.Name]; !ok {
+		l.builders[spec.Name] = NewBuilder(spec)
+	}
+}
+
+// RemoveSpec stops building the bars of a spec, returns whether specs are left
+func (l *LiveBuilder) RemoveSpec(name string) bool {
+	delete(l.builders, name)
+	return len(l.builders) > 0
+}
+
+// Dropped returns the number of trades and updates dropped since the last call because they
+// arrived after the grace window
+func (l *LiveBuilder) Dropped() int {
+	dropped := l.dropped
+	l.dropped = 0
+	return dropped
+}
+
+// AddTrade adds a trade received at the wall clock time now
+func (l *LiveBuilder) AddTrade(trade *entities.Trade, now time.Time) {
+	if trade.TimestampNs > l.latest {
+		l.latest = trade.TimestampNs
+		l.receivedAt = now
+	}
+
+	switch trade.Update {
+	case types.TradeCanceled, types.TradeIncorrect:
+		if !l.removePending(trade.ID) {
+			l.dropped++
+		}
+		return
+	case types.TradeCorrected:
+		l.removePending(trade.ID)
+	}
+	if trade.TimestampNs < l.watermark {
+		l.dropped++
+		return
+	}
+	i := sort.Search(len(l.pending), func(i int) bool {
+		return l.pending[i].TimestampNs > trade.TimestampNs
+	})
+	l.pending = append(l.pending, nil)
+	copy(l.pending[i+1:], l.pending[i:])
+	l.pending[i] = trade
+}
+
+// Remove the pending trade with an ID, returns whether it was found
+func (l *LiveBuilder) removePending(id int64) bool {
+	for i, pending := range l.pending {
+		if pending.ID == id {
+			l.pending = append(l.pending[:i], l.pending[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Advance passes the trades older than the grace window to the builders at the wall clock time now
+// and returns the bars completed
+func (l *LiveBuilder) Advance(now time.Time) []*entities.Bar {
+	if l.receivedAt.IsZero() {
+		return nil
+	}
+	watermark := l.latest + int64(now.Sub(l.receivedAt)) - int64(l.grace)
+	if watermark > l.watermark {
+		l.watermark = watermark
+	}
+
+	released := 0
+	for released < len(l.pending) && l.pending[released].TimestampNs < l.watermark {
+		released++
+	}
+	for _, trade := range l.pending[:released] {
+		for _, builder := range l.builders {
+			builder.AddTrade(trade)
+		}
+	}
+	l.pending = append(l.pending[:0], l.pending[released:]...)
+
+	var completed []*entities.Bar
+	for _, builder := range l.builders {
+		builder.Advance(time.Unix(0, l.watermark))
+		completed = append(completed, builder.Completed()...)
+	}
+	sort.SliceStable(completed, func(i, j int) bool {
+		return completed[i].TimestampNs < completed[j].TimestampNs
+	})
+	return completed
+}
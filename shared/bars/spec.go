@@ -0,0 +1,115 @@
+package bars
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"tradingplatform/shared/types"
+)
+
+// Kind of bars built from trades
+type Kind string
+
+const (
+	// Bars closing after a fixed time
+	TimeBars Kind = "time"
+	// Bars closing after a number of trades
+	TickBars Kind = "tick"
+	// Bars closing once the traded volume reaches a threshold
+	VolumeBars Kind = "volume"
+	// Bars closing once the traded notional (price times size) reaches a threshold
+	DollarBars Kind = "dollar"
+)
+
+// Prefix of the data types of bars built from trades, e.g. bar-5sec
+const specDataTypePrefix = "bar-"
+
+var specPattern = regexp.MustCompile(`^([0-9]+(?:\.[0-9]+)?)(ms|sec|min|hour|tick|volume|dollar)$`)
+
+var timeUnits = map[string]time.Duration{
+	"ms":   time.Millisecond,
+	"sec":  time.Second,
+	"min":  time.Minute,
+	"hour": time.Hour,
+}
+
+// Spec describes the bars built from trades, its name is a size followed by a unit, e.g. 5sec,
+// 500ms, 100tick, 1000volume or 1000000dollar
+type Spec struct {
+	Name string
+	Kind Kind
+	// Length of time bars, they are aligned to multiples of it since the unix epoch
+	Duration time.Duration
+	// Number of trades, volume or notional closing tick, volume and dollar bars
+	Threshold float64
+}
+
+// ParseSpec parses the name of a bar spec
+func ParseSpec(name string) (Spec, error) {
+	match := specPattern.FindStringSubmatch(name)
+	if match == nil {
+		return Spec{}, fmt.Errorf("invalid bar spec %s, expected a size followed by ms, sec, min, hour, tick, volume or dollar", name)
+	}
+	size, err := strconv.ParseFloat(match[1], 64)
+	if err != nil || size <= 0 {
+		return Spec{}, fmt.Errorf("invalid size of bar spec %s", name)
+	}
+	spec := Spec{Name: name}
+	unit := match[2]
+	switch unit {
+	case "tick":
+		spec.Kind = TickBars
+	case "volume":
+		spec.Kind = VolumeBars
+	case "dollar":
+		spec.Kind = DollarBars
+	default:
+		spec.Kind = TimeBars
+	}
+	if spec.Kind == TimeBars || spec.Kind == TickBars {
+		if size != float64(int64(size)) {
+			return Spec{}, fmt.Errorf("size of bar spec %s must be a whole number", name)
+		}
+	}
+	if spec.Kind == TimeBars {
+		spec.Duration = time.Duration(size) * timeUnits[unit]
+	} else {
+		spec.Threshold = size
+	}
+	return spec, nil
+}
+
+// ParseSpecs parses the names of several bar specs, duplicates are removed
+func ParseSpecs(names []string) ([]Spec, error) {
+	specs := make([]Spec, 0, len(names))
+	seen := map[string]struct{}{}
+	for _, name := range names {
+		spec, err := ParseSpec(name)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := seen[spec.Name]; ok {
+			continue
+		}
+		seen[spec.Name] = struct{}{}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+// DataType returns the data type of the stream of bars of the spec, e.g. bar-5sec
+func (s Spec) DataType() types.DataType {
+	return types.DataType(specDataTypePrefix + s.Name)
+}
+
+// ParseSpecDataType parses the spec of a stream data type of bars built from trades
+func ParseSpecDataType(dtype types.DataType) (Spec, error) {
+	name, ok := strings.CutPrefix(string(dtype), specDataTypePrefix)
+	if !ok {
+		return Spec{}, fmt.Errorf("data type %s is not a bar spec", dtype)
+	}
+	return ParseSpec(name)
+}
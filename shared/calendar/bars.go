@@ -0,0 +1,162 @@
+package calendar
+
+import (
+	"fmt"
+	"time"
+
+	"tradingplatform/shared/types"
+)
+
+// Period is the time covered by a bar, between Start (inclusive) and End (exclusive)
+type Period struct {
+	Start time.Time
+	End   time.Time
+}
+
+// BarPeriod returns the period of the bar of a timeframe containing t. Minute and hour bars are
+// aligned to the clock, day, week (starting on Monday) and month bars to the trading days of
+// the calendar location
+func BarPeriod(cal Calendar, timeFrame types.TimeFrame, t time.Time) (Period, error) {
+	t = t.In(cal.Location())
+	day := startOfDay(t, cal.Location())
+	var start time.Time
+	var end time.Time
+	switch timeFrame {
+	case types.OneMin:
+		start = t.Truncate(time.Minute)
+		end = start.Add(time.Minute)
+	case types.OneHour:
+		start = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, cal.Location())
+		end = start.Add(time.Hour)
+	case types.OneDay:
+		start = day
+		end = start.AddDate(0, 0, 1)
+	case types.OneWeek:
+		start = day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+		end = start.AddDate(0, 0, 7)
+	case types.OneMonth:
+		start = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, cal.Location())
+		end = start.AddDate(0, 1, 0)
+	default:
+		return Period{}, fmt.Errorf("invalid timeframe %s", timeFrame)
+	}
+	return Period{Start: start, End: end}, nil
+}
+
+// ExpectedBars returns the periods of the bars of a timeframe expected in the trading sessions
+// of a calendar, for the bars starting between start (inclusive) and end (exclusive)
+func ExpectedBars(cal Calendar, timeFrame types.TimeFrame, start time.Time, end time.Time) ([]Period, error) {
+	if _, err := BarPeriod(cal, timeFrame, start); err != nil {
+		return nil, err
+	}
+	return expectedBars(cal, timeFrame, start, end, BarPeriod), nil
+}
+
+// ExpectedSessionBars is ExpectedBars for a timeframe made of any number of units, with the bars
+// aligned as by SessionBarPeriod
+func ExpectedSessionBars(cal Calendar, timeFrame types.TimeFrame, start time.Time, end time.Time) ([]Period, error) {
+	if _, err := SessionBarPeriod(cal, timeFrame, start); err != nil {
+		return nil, err
+	}
+	return expectedBars(cal, timeFrame, start, end, SessionBarPeriod), nil
+}
+
+func expectedBars(cal Calendar,
+	timeFrame types.TimeFrame,
+	start time.Time,
+	end time.Time,
+	barPeriod func(Calendar, types.TimeFrame, time.Time) (Period, error)) []Period {
+
+	_, unit, _ := types.ParseTimeFrame(timeFrame)
+	var periods []Period
+	add := func(p Period) {
+		if p.Start.Before(start) || !p.Start.Before(end) {
+			return
+		}
+		if len(periods) > 0 && periods[len(periods)-1].Start.Equal(p.Start) {
+			return
+		}
+		periods = append(periods, p)
+	}
+	// Days, weeks and months are expected if they contain a trading day
+	for day := startOfDay(start, cal.Location()); day.Before(end); day = day.AddDate(0, 0, 1) {
+		session, ok := cal.Session(day)
+		if !ok {
+			continue
+		}
+		switch unit {
+		case types.OneMin, types.OneHour:
+			p, _ := barPeriod(cal, timeFrame, session.Open)
+			for ; p.Start.Before(session.Close); p, _ = barPeriod(cal, timeFrame, p.End) {
+				add(p)
+			}
+		default:
+			p, _ := barPeriod(cal, timeFrame, day)
+			add(p)
+		}
+	}
+	return periods
+}
+
+// Monday the weeks of multi-week bars are counted from
+var weekEpoch = time.Date(1970, 1, 5, 0, 0, 0, 0, time.UTC)
+
+// SessionBarPeriod returns the period of the bar containing t of a timeframe made of any number of
+// units (e.g. 5min, 4hour, 2week). Intraday bars are aligned to the open of the trading session and
+// cut at its close so that no bar spans the open or the close, the hours before the open, after the
+// close and of days without a session are split from their start in the same way. Bars of several
+// days, weeks or months are counted from the Unix epoch in the calendar location
+func SessionBarPeriod(cal Calendar, timeFrame types.TimeFrame, t time.Time) (Period, error) {
+	count, unit, ok := types.ParseTimeFrame(timeFrame)
+	if !ok {
+		return Period{}, fmt.Errorf("invalid timeframe %s", timeFrame)
+	}
+	loc := cal.Location()
+	t = t.In(loc)
+	day := startOfDay(t, loc)
+	date := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch unit {
+	case types.OneMin, types.OneHour:
+		length := time.Duration(count) * time.Minute
+		if unit == types.OneHour {
+			length = time.Duration(count) * time.Hour
+		}
+		// The day is split in segments at the open and close of its session
+		segment := Period{Start: day, End: day.AddDate(0, 0, 1)}
+		if session, ok := cal.Session(t); ok {
+			switch {
+			case t.Before(session.Open):
+				segment.End = session.Open
+			case t.Before(session.Close):
+				segment = Period{Start: session.Open, End: session.Close}
+			default:
+				segment.Start = session.Close
+			}
+		}
+		start := segment.Start.Add(t.Sub(segment.Start) / length * length)
+		end := start.Add(length)
+		if end.After(segment.End) {
+			end = segment.End
+		}
+		return Period{Start: start, End: end}, nil
+	case types.OneDay:
+		days := int(date.Unix() / (24 * 60 * 60))
+		start := day.AddDate(0, 0, -mod(days, count))
+		return Period{Start: start, End: start.AddDate(0, 0, count)}, nil
+	case types.OneWeek:
+		days := int(date.Sub(weekEpoch).Hours() / 24)
+		start := day.AddDate(0, 0, -mod(days, 7*count))
+		return Period{Start: start, End: start.AddDate(0, 0, 7*count)}, nil
+	default:
+		months := t.Year()*12 + int(t.Month()) - 1
+		months -= mod(months, count)
+		start := time.Date(months/12, time.Month(months%12+1), 1, 0, 0, 0, 0, loc)
+		return Period{Start: start, End: start.AddDate(0, count, 0)}, nil
+	}
+}
+
+// Modulo that is not negative for negative a
+func mod(a int, b int) int {
+	return (a%b + b) % b
+}
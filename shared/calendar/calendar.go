@@ -0,0 +1,81 @@
+package calendar
+
+import (
+	"time"
+	// Calendars need the time zone database even where the system one is missing
+	_ "time/tzdata"
+
+	"tradingplatform/shared/types"
+)
+
+type Name string
+
+const (
+	// Regular trading sessions of the New York Stock Exchange
+	NYSE Name = "nyse"
+	// Markets trading around the clock, e.g. crypto
+	AlwaysOpen Name = "always"
+)
+
+func GetCalendarNameMap() map[string]Name {
+	return map[string]Name{
+		"nyse":   NYSE,
+		"always": AlwaysOpen,
+	}
+}
+
+// Session is the trading session of a day, between Open (inclusive) and Close (exclusive)
+type Session struct {
+	Open  time.Time
+	Close time.Time
+}
+
+// Calendar gives the trading sessions of a market
+type Calendar interface {
+	Name() Name
+	// Location the trading days of the calendar are defined in
+	Location() *time.Location
+	// Session returns the trading session of the day containing t, ok is false if the market is
+	// closed on that day
+	Session(t time.Time) (session Session, ok bool)
+}
+
+// GetCalendar returns the calendar with the given name
+func GetCalendar(name Name) (Calendar, bool) {
+	switch name {
+	case NYSE:
+		return nyseCalendar, true
+	case AlwaysOpen:
+		return alwaysOpenCalendar{}, true
+	}
+	return nil, false
+}
+
+// ForAssetClass returns the calendar of the market an asset class is traded on
+func ForAssetClass(assetClass types.AssetClass) Calendar {
+	if assetClass == types.Stock {
+		return nyseCalendar
+	}
+	return alwaysOpenCalendar{}
+}
+
+// Start of the day containing t in a location
+func startOfDay(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+type alwaysOpenCalendar struct{}
+
+func (alwaysOpenCalendar) Name() Name {
+	return AlwaysOpen
+}
+
+func (alwaysOpenCalendar) Location() *time.Location {
+	return time.UTC
+}
+
+func (alwaysOpenCalendar) Session(t time.Time) (Session, bool) {
+	open := startOfDay(t, time.UTC)
+	return Session{Open: open, Close: open.AddDate(0, 0, 1)}, true
+}
@@ -0,0 +1,132 @@
+package calendar
+
+import (
+	"time"
+)
+
+var nyseCalendar = newNYSECalendar()
+
+// Regular sessions of the NYSE: 9:30 to 16:00 New York time on weekdays, closing at 13:00 on the
+// usual early close days. Unscheduled closures (e.g. national days of mourning) are not known
+type nyse struct {
+	loc *time.Location
+}
+
+func newNYSECalendar() *nyse {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		panic(err)
+	}
+	return &nyse{loc: loc}
+}
+
+func (c *nyse) Name() Name {
+	return NYSE
+}
+
+func (c *nyse) Location() *time.Location {
+	return c.loc
+}
+
+func (c *nyse) Session(t time.Time) (Session, bool) {
+	day := startOfDay(t, c.loc)
+	if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday || isNYSEHoliday(day) {
+		return Session{}, false
+	}
+	closeHour := 16
+	if isNYSEEarlyClose(day) {
+		closeHour = 13
+	}
+	return Session{
+		Open:  time.Date(day.Year(), day.Month(), day.Day(), 9, 30, 0, 0, c.loc),
+		Close: time.Date(day.Year(), day.Month(), day.Day(), closeHour, 0, 0, 0, c.loc),
+	}, true
+}
+
+func sameDay(a time.Time, year int, month time.Month, day int) bool {
+	return a.Year() == year && a.Month() == month && a.Day() == day
+}
+
+// Day of the nth weekday of a month, n < 0 counts from the end of the month
+func nthWeekday(year int, month time.Month, weekday time.Weekday, n int) int {
+	if n > 0 {
+		first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+		return 1 + (int(weekday)-int(first.Weekday())+7)%7 + (n-1)*7
+	}
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC)
+	return last.Day() - (int(last.Weekday())-int(weekday)+7)%7 + (n+1)*7
+}
+
+// Easter Sunday of a year (anonymous Gregorian algorithm)
+func easter(year int) (time.Month, int) {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Month(month), day
+}
+
+// Whether a fixed date holiday falling on a weekend is observed on day, on the Friday before
+// when it falls on a Saturday and on the Monday after when it falls on a Sunday
+func isObserved(day time.Time, month time.Month, dayOfMonth int) bool {
+	holiday := time.Date(day.Year(), month, dayOfMonth, 0, 0, 0, 0, day.Location())
+	switch holiday.Weekday() {
+	case time.Saturday:
+		holiday = holiday.AddDate(0, 0, -1)
+	case time.Sunday:
+		holiday = holiday.AddDate(0, 0, 1)
+	}
+	return sameDay(day, holiday.Year(), holiday.Month(), holiday.Day())
+}
+
+func isNYSEHoliday(day time.Time) bool {
+	year := day.Year()
+	// New Year's Day is not observed on the Friday before when it falls on a Saturday
+	if sameDay(day, year, time.January, 1) ||
+		day.Weekday() == time.Monday && sameDay(day, year, time.January, 2) {
+		return true
+	}
+	// Martin Luther King Jr. Day, Washington's Birthday, Memorial Day, Labor Day and Thanksgiving
+	if sameDay(day, year, time.January, nthWeekday(year, time.January, time.Monday, 3)) ||
+		sameDay(day, year, time.February, nthWeekday(year, time.February, time.Monday, 3)) ||
+		sameDay(day, year, time.May, nthWeekday(year, time.May, time.Monday, -1)) ||
+		sameDay(day, year, time.September, nthWeekday(year, time.September, time.Monday, 1)) ||
+		sameDay(day, year, time.November, nthWeekday(year, time.November, time.Thursday, 4)) {
+		return true
+	}
+	easterMonth, easterDay := easter(year)
+	goodFriday := time.Date(year, easterMonth, easterDay-2, 0, 0, 0, 0, time.UTC)
+	if sameDay(day, goodFriday.Year(), goodFriday.Month(), goodFriday.Day()) {
+		return true
+	}
+	if year >= 2022 && isObserved(day, time.June, 19) {
+		return true
+	}
+	return isObserved(day, time.July, 4) || isObserved(day, time.December, 25)
+}
+
+func isNYSEEarlyClose(day time.Time) bool {
+	year := day.Year()
+	switch {
+	// Day before Independence Day, unless it is the observed holiday itself
+	case sameDay(day, year, time.July, 3):
+		return day.Weekday() >= time.Monday && day.Weekday() <= time.Thursday
+	// Day after Thanksgiving
+	case sameDay(day, year, time.November, nthWeekday(year, time.November, time.Thursday, 4)+1):
+		return true
+	// Christmas Eve
+	case sameDay(day, year, time.December, 24):
+		return day.Weekday() >= time.Monday && day.Weekday() <= time.Thursday
+	}
+	return false
+}
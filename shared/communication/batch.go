@@ -0,0 +1,86 @@
+package communication
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// Configuration of the batching of entities into a single NATS message. Batching is disabled by
+// default so that consumers that do not unpack batches keep working, once enabled data queues are
+// always sent in batches and stream topics only if Streams is set
+type BatchConfig struct {
+	// Maximum number of entities in a batch, 1 or less disables batching
+	MaxCount int
+	// Maximum size in bytes of the payloads of a batch, a single entity larger than this
+	// is still sent on its own
+	MaxBytes int
+	// Whether messages published on stream topics are batched as well
+	Streams bool
+	// Maximum time a message published on a stream topic waits for its batch to fill
+	Linger time.Duration
+}
+
+var batchConfig = BatchConfig{
+	MaxCount: 1,
+	MaxBytes: 512 * 1024,
+	Streams:  false,
+	Linger:   100 * time.Millisecond,
+}
+var batchConfigLock sync.RWMutex
+
+func SetBatchConfig(cfg BatchConfig) {
+	batchConfigLock.Lock()
+	batchConfig = cfg
+	batchConfigLock.Unlock()
+}
+
+func GetBatchConfig() BatchConfig {
+	batchConfigLock.RLock()
+	defer batchConfigLock.RUnlock()
+	return batchConfig
+}
+
+// IsEnabled returns true if entities are packed in batches
+func (cfg BatchConfig) IsEnabled() bool {
+	return cfg.MaxCount > 1
+}
+
+// IsStreamEnabled returns true if messages published on stream topics are packed in batches
+func (cfg BatchConfig) IsStreamEnabled() bool {
+	return cfg.IsEnabled() && cfg.Streams && cfg.Linger > 0
+}
+
+// Fits returns true if a payload of the given size can be added to a batch of count payloads
+// totalling size bytes
+func (cfg BatchConfig) Fits(count int, size int, payload int) bool {
+	if count == 0 {
+		return true
+	}
+	return count < cfg.MaxCount && size+payload <= cfg.MaxBytes
+}
+
+// Add the flags configuring batching to a command
+func AddBatchFlags(flags *pflag.FlagSet) {
+	cfg := GetBatchConfig()
+	flags.Int("batch-max-count", cfg.MaxCount, "Maximum number of entities packed in a single message, more than 1 enables batching (e.g. 500)")
+	flags.Int("batch-max-bytes", cfg.MaxBytes, "Maximum size in bytes of the payloads packed in a single message")
+	flags.Bool("batch-streams", cfg.Streams, "Also pack the messages published on stream topics in batches")
+	flags.Duration("batch-linger", cfg.Linger, "Maximum time a stream message waits for its batch to fill")
+}
+
+// Set the batching configuration from the flags added with AddBatchFlags
+func SetBatchConfigFromFlags(flags *pflag.FlagSet) error {
+	var cfg BatchConfig
+	cfg.MaxCount, _ = flags.GetInt("batch-max-count")
+	cfg.MaxBytes, _ = flags.GetInt("batch-max-bytes")
+	cfg.Streams, _ = flags.GetBool("batch-streams")
+	cfg.Linger, _ = flags.GetDuration("batch-linger")
+	if cfg.IsEnabled() && cfg.MaxBytes <= 0 {
+		return fmt.Errorf("batch max bytes must be positive, got %d", cfg.MaxBytes)
+	}
+	SetBatchConfig(cfg)
+	return nil
+}
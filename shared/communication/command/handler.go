@@ -3,16 +3,20 @@ package command
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"sync"
 	"tradingplatform/shared/communication"
 	"tradingplatform/shared/logging"
+	"tradingplatform/shared/tracing"
 	"tradingplatform/shared/types"
 	"tradingplatform/shared/utils"
 
 	"github.com/nats-io/nats.go"
 	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 var commandHandler *utils.Handler[string]
@@ -47,9 +51,10 @@ func RemoveCancelFunc(key string) {
 }
 
 // Initialize command handling
-func StartCommandHandler(component types.Component, cliHandler func() *cobra.Command, jsonHandler func(context.Context, string) string) {
+func StartCommandHandler(c types.Component, cliHandler func() *cobra.Command, jsonHandler func(context.Context, string) string) {
 	commandHandler = utils.NewHandler[string]()
-	go handleCommand(commandHandler, component, cliHandler, jsonHandler)
+	component = c
+	go handleCommand(commandHandler, c, cliHandler, jsonHandler)
 }
 
 // GetCommandHandler returns the command handler
@@ -68,18 +73,37 @@ func handleCommandContent(m *nats.Msg, handler *utils.Handler[string], cliHandle
 }
 
 func handleJSONCommand(m *nats.Msg, handler *utils.Handler[string], jsonHandler func(context.Context, string) string) {
+	var jsonCommand JSONCommand
+	if err := json.Unmarshal(m.Data[4:], &jsonCommand); err == nil && jsonCommand.RootOperation == JSONOperationStatus {
+		m.Respond([]byte(GetStatus().Respond()))
+		return
+	}
+	inFlightCommands.Add(1)
+	defer inFlightCommands.Add(-1)
 	childContext, cancel := context.WithCancel(handler.Ctx())
 	defer cancel()
-	response := jsonHandler(context.WithValue(childContext, CancelKey{}, cancel), string(m.Data[4:]))
+	ctx, span := tracing.Start(tracing.Extract(childContext, m.Header), "command "+string(jsonCommand.RootOperation),
+		attribute.String("component", string(component)))
+	response := jsonHandler(context.WithValue(ctx, CancelKey{}, cancel), string(m.Data[4:]))
 	if len(response) == 0 {
-		m.Respond([]byte(types.NewError(
-			fmt.Errorf("no response provided, either component quit or command was cancelled"),
-		).Respond()))
+		err := fmt.Errorf("no response provided, either component quit or command was cancelled")
+		tracing.End(span, err)
+		m.Respond([]byte(types.NewError(err).Respond()))
 		return
 	}
+	tracing.End(span, responseError(response))
 	m.Respond([]byte(response))
 }
 
+// Error of the response of a command, nil if the command succeeded
+func responseError(response string) error {
+	var r types.Response
+	if err := json.Unmarshal([]byte(response), &r); err != nil || r.Err == "" {
+		return nil
+	}
+	return errors.New(r.Err)
+}
+
 func splitArgs(input string) []string {
 	// Split the string by spaces
 	words := strings.Fields(input)
@@ -115,10 +139,15 @@ func splitArgs(input string) []string {
 type CancelKey struct{}
 
 func handleCLICommand(m *nats.Msg, handler *utils.Handler[string], cliHandler func() *cobra.Command) {
-	iString := string(m.Data)
+	args := splitArgs(string(m.Data))
+	if len(args) == 0 || args[0] != "status" {
+		inFlightCommands.Add(1)
+		defer inFlightCommands.Add(-1)
+	}
 
 	rootCmd := cliHandler()
-	rootCmd.SetArgs(splitArgs(iString))
+	rootCmd.AddCommand(newStatusCommand())
+	rootCmd.SetArgs(args)
 
 	buf := new(bytes.Buffer)
 	errBuf := new(bytes.Buffer)
@@ -126,23 +155,30 @@ func handleCLICommand(m *nats.Msg, handler *utils.Handler[string], cliHandler fu
 	rootCmd.SetErr(errBuf)
 	childContext, cancel := context.WithCancel(handler.Ctx())
 	defer cancel()
-	err := rootCmd.ExecuteContext(context.WithValue(childContext, CancelKey{}, cancel))
+	name := "command"
+	if len(args) > 0 {
+		name += " " + args[0]
+	}
+	ctx, span := tracing.Start(tracing.Extract(childContext, m.Header), name,
+		attribute.String("component", string(component)))
+	err := rootCmd.ExecuteContext(context.WithValue(ctx, CancelKey{}, cancel))
 	// If cobra produces an error (e.g. unknown command)
 	// we want to send it back to the caller
 	if errBuf.Len() > 0 {
 		logging.Log().Debug().Err(err)
-		m.Respond([]byte(types.NewError(
-			fmt.Errorf("%s\n%s", errBuf.String(), buf.String()),
-		).Respond()))
+		err = fmt.Errorf("%s\n%s", errBuf.String(), buf.String())
+		tracing.End(span, err)
+		m.Respond([]byte(types.NewError(err).Respond()))
 		return
 	}
 	// If command produced no response, the command is considered to have failed
 	if buf.Len() == 0 {
-		m.Respond([]byte(types.NewError(
-			fmt.Errorf("no response provided, either component quit or command was cancelled"),
-		).Respond()))
+		err = fmt.Errorf("no response provided, either component quit or command was cancelled")
+		tracing.End(span, err)
+		m.Respond([]byte(types.NewError(err).Respond()))
 		return
 	}
+	tracing.End(span, responseError(buf.String()))
 	m.Respond(buf.Bytes())
 }
 
@@ -162,6 +198,7 @@ func handleCommand(handler *utils.Handler[string], component types.Component, cl
 			handler.Wg.Done()
 		}()
 	})
+	setCommandConn(nc)
 	<-ctx.Done()
 	handler.Wg.Wait()
 	logging.Log().Debug().Str("topic", commandTopic).Msg("unsubscribing from topic")
@@ -12,8 +12,17 @@ const (
 	JSONOperationStream          JSONOperation = "stream"
 	JSONOperationStreamSubscribe JSONOperation = "stream-subscribe"
 	JSONOperationCancel          JSONOperation = "cancel"
+	JSONOperationStatus          JSONOperation = "status"
 
-	JSONOperationData JSONOperation = "data"
+	JSONOperationData     JSONOperation = "data"
+	JSONOperationDataGaps JSONOperation = "data-gaps"
+	JSONOperationExport   JSONOperation = "export"
+
+	JSONOperationBacktest JSONOperation = "backtest"
+
+	JSONOperationOrder JSONOperation = "order"
+
+	JSONOperationAccount JSONOperation = "account"
 )
 
 type JSONCommand struct {
@@ -0,0 +1,197 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"tradingplatform/shared/communication/producer"
+	"tradingplatform/shared/logging"
+	"tradingplatform/shared/types"
+
+	"github.com/nats-io/nats.go"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Time after which a database that does not answer a ping is considered disconnected
+const databasePingTimeout = 2 * time.Second
+
+var component types.Component
+var startedAt = time.Now()
+
+// Number of commands being handled, without the status command
+var inFlightCommands atomic.Int64
+
+// Connection the commands are received on, nil until the command topic is subscribed
+var commandConn *nats.Conn
+
+var databases = make(map[string]func(context.Context) error)
+var streamsFunc func() []string
+var failedStreamsFunc func() []string
+var statusLock sync.RWMutex
+
+// RegisterDatabase adds a database to the status of the component, the component is not ready while
+// the ping of one of its databases fails
+func RegisterDatabase(name string, ping func(context.Context) error) {
+	statusLock.Lock()
+	defer statusLock.Unlock()
+	databases[name] = ping
+}
+
+// SetStreamsFunc sets the function listing the active streams or subscribed topics of the component
+// in its status
+func SetStreamsFunc(f func() []string) {
+	statusLock.Lock()
+	defer statusLock.Unlock()
+	streamsFunc = f
+}
+
+// SetFailedStreamsFunc sets the function listing the streams or topics the component failed to restore
+// on startup in its status
+func SetFailedStreamsFunc(f func() []string) {
+	statusLock.Lock()
+	defer statusLock.Unlock()
+	failedStreamsFunc = f
+}
+
+func setCommandConn(nc *nats.Conn) {
+	statusLock.Lock()
+	defer statusLock.Unlock()
+	commandConn = nc
+}
+
+// GetStatus returns the status of the component, pinging its databases
+func GetStatus() types.StatusResponse {
+	return getStatus(true)
+}
+
+// Status of the component, without the databases when they are not pinged. The component is then
+// ready whatever the state of its databases
+func getStatus(pingDatabases bool) types.StatusResponse {
+	statusLock.RLock()
+	nc := commandConn
+	getStreams := streamsFunc
+	getFailedStreams := failedStreamsFunc
+	pings := make(map[string]func(context.Context) error, len(databases))
+	if pingDatabases {
+		for name, ping := range databases {
+			pings[name] = ping
+		}
+	}
+	statusLock.RUnlock()
+
+	status := types.StatusResponse{
+		Response:         types.NewResponse(types.Success, "", nil),
+		Component:        component,
+		StartedAt:        startedAt.UnixNano(),
+		Uptime:           time.Since(startedAt).Seconds(),
+		Nats:             nats.DISCONNECTED.String(),
+		InFlightCommands: int(inFlightCommands.Load()),
+		Queues:           producer.GetQueueTopics(),
+		Streams:          []string{},
+		FailedStreams:    []string{},
+		Databases:        make([]types.DatabaseStatus, 0, len(pings)),
+	}
+	if nc != nil {
+		status.Nats = nc.Status().String()
+	}
+	if getStreams != nil {
+		status.Streams = getStreams()
+	}
+	if getFailedStreams != nil {
+		status.FailedStreams = getFailedStreams()
+	}
+	status.Ready = nc != nil && nc.IsConnected() && commandHandler.Ctx().Err() == nil
+
+	for name, ping := range pings {
+		ctx, cancel := context.WithTimeout(context.Background(), databasePingTimeout)
+		err := ping(ctx)
+		cancel()
+		database := types.DatabaseStatus{Name: name, Connected: err == nil}
+		if err != nil {
+			database.Err = err.Error()
+			status.Ready = false
+		}
+		status.Databases = append(status.Databases, database)
+	}
+	sort.Slice(status.Databases, func(i, j int) bool {
+		return status.Databases[i].Name < status.Databases[j].Name
+	})
+	if !status.Ready {
+		status.Message = "not ready"
+	}
+	return status
+}
+
+// Command getting the status of the component, added to the CLI of every component
+func newStatusCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Get the uptime, the connections, the streams and the commands being handled by the component",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Print(GetStatus().Respond())
+		},
+	}
+}
+
+// Add the flags configuring the health endpoints to a command
+func AddHealthFlags(flags *pflag.FlagSet) {
+	flags.String("health-addr", "", "Address of the HTTP listener serving the /healthz and /readyz endpoints (e.g. :8081), disabled without address")
+}
+
+// Start the health endpoints on the address of the flag added with AddHealthFlags, must be called after
+// StartCommandHandler. The listener stops with the command handler
+func StartHealthServerFromFlags(flags *pflag.FlagSet) {
+	addr, _ := flags.GetString("health-addr")
+	if addr == "" {
+		return
+	}
+	StartHealthServer(addr)
+}
+
+// Start an HTTP listener answering /healthz while the command handler runs and /readyz while the
+// component is ready, both with the status of the component. The liveness check stays in the process,
+// the databases are only pinged by the readiness check
+func StartHealthServer(addr string) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		status := getStatus(false)
+		code := http.StatusOK
+		if commandHandler.Ctx().Err() != nil {
+			code = http.StatusServiceUnavailable
+		}
+		writeStatus(w, code, status)
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		status := GetStatus()
+		code := http.StatusOK
+		if !status.Ready {
+			code = http.StatusServiceUnavailable
+		}
+		writeStatus(w, code, status)
+	})
+	server := &http.Server{Addr: addr, Handler: mux}
+	go func() {
+		logging.Log().Info().Str("addr", addr).Msg("serving health endpoints")
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logging.Log().Error().Err(err).Str("addr", addr).Msg("serving health endpoints")
+		}
+	}()
+	go func() {
+		<-commandHandler.Ctx().Done()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(ctx)
+	}()
+}
+
+func writeStatus(w http.ResponseWriter, code int, status types.StatusResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write([]byte(status.Respond()))
+}
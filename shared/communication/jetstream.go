@@ -0,0 +1,209 @@
+package communication
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"tradingplatform/shared/types"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/spf13/pflag"
+)
+
+// Configuration of the JetStream streams backing the dataprovider stream topics
+type JetStreamConfig struct {
+	// Whether stream topics are published and consumed through JetStream instead of core NATS
+	Enabled bool
+	// Retention policy of the streams: limits, interest or workqueue
+	Retention string
+	// Storage of the streams: file or memory
+	Storage string
+	// Maximum age of the messages kept in a stream, 0 for unlimited
+	MaxAge time.Duration
+	// Maximum size in bytes of a stream, -1 for unlimited
+	MaxBytes int64
+	// Maximum number of messages in a stream, -1 for unlimited
+	MaxMsgs int64
+	// Number of replicas of each stream
+	Replicas int
+	// Time a consumer has to acknowledge a message before it is redelivered
+	AckWait time.Duration
+	// Delay before redelivering a message that could not be handled
+	RetryDelay time.Duration
+}
+
+var jetStreamConfig = JetStreamConfig{
+	Retention:  "limits",
+	Storage:    "file",
+	MaxAge:     24 * time.Hour,
+	MaxBytes:   -1,
+	MaxMsgs:    -1,
+	Replicas:   1,
+	AckWait:    30 * time.Second,
+	RetryDelay: 5 * time.Second,
+}
+var jetStreamConfigLock sync.RWMutex
+
+func SetJetStreamConfig(cfg JetStreamConfig) {
+	jetStreamConfigLock.Lock()
+	jetStreamConfig = cfg
+	jetStreamConfigLock.Unlock()
+	// The streams are ensured again with the new configuration
+	ensuredStreamsLock.Lock()
+	ensuredStreams = make(map[string]bool)
+	ensuredStreamsLock.Unlock()
+}
+
+func GetJetStreamConfig() JetStreamConfig {
+	jetStreamConfigLock.RLock()
+	defer jetStreamConfigLock.RUnlock()
+	return jetStreamConfig
+}
+
+func IsJetStreamEnabled() bool {
+	return GetJetStreamConfig().Enabled
+}
+
+// Convert the configuration to the configuration of the stream with the given name and subjects
+func (cfg JetStreamConfig) StreamConfig(name string, subjects ...string) (jetstream.StreamConfig, error) {
+	streamCfg := jetstream.StreamConfig{
+		Name:     name,
+		Subjects: subjects,
+		MaxAge:   cfg.MaxAge,
+		MaxBytes: cfg.MaxBytes,
+		MaxMsgs:  cfg.MaxMsgs,
+		Replicas: cfg.Replicas,
+	}
+	switch cfg.Retention {
+	case "limits", "":
+		streamCfg.Retention = jetstream.LimitsPolicy
+	case "interest":
+		streamCfg.Retention = jetstream.InterestPolicy
+	case "workqueue":
+		streamCfg.Retention = jetstream.WorkQueuePolicy
+	default:
+		return streamCfg, fmt.Errorf("unknown jetstream retention policy %s", cfg.Retention)
+	}
+	switch cfg.Storage {
+	case "file", "":
+		streamCfg.Storage = jetstream.FileStorage
+	case "memory":
+		streamCfg.Storage = jetstream.MemoryStorage
+	default:
+		return streamCfg, fmt.Errorf("unknown jetstream storage %s", cfg.Storage)
+	}
+	return streamCfg, nil
+}
+
+// Get the name and subject of the JetStream stream holding a stream topic. There is a stream
+// for each dataprovider.stream.<source>.<assetClass> subject hierarchy, ok is false if the topic
+// is not a stream topic or does not identify a single source and asset class
+func GetJetStreamStream(topic string) (name string, subject string, ok bool) {
+	parts := strings.Split(topic, ".")
+	if len(parts) < 4 ||
+		parts[0] != string(types.DataProvider) ||
+		parts[1] != string(types.Stream) {
+		return "", "", false
+	}
+	for _, part := range parts[2:4] {
+		if part == "" || part == "*" || part == ">" {
+			return "", "", false
+		}
+	}
+	name = strings.ToUpper(strings.Join(parts[:4], "_"))
+	name = strings.NewReplacer("-", "_", "/", "_").Replace(name)
+	subject = strings.Join(parts[:4], ".") + ".>"
+	return name, subject, true
+}
+
+// Streams known to exist, by name, and whether their configuration was updated to the current one
+var ensuredStreams = make(map[string]bool)
+var ensuredStreamsLock sync.Mutex
+
+// Ensure the JetStream stream holding a stream topic exists and return its name. If update is
+// true the stream configuration is updated to the current configuration, otherwise an
+// existing stream is used as is. Streams are only ensured again after ForgetJetStream
+func EnsureJetStream(ctx context.Context, js jetstream.JetStream, topic string, update bool) (string, error) {
+	name, subject, ok := GetJetStreamStream(topic)
+	if !ok {
+		return "", fmt.Errorf("topic %s does not belong to a jetstream stream", topic)
+	}
+
+	ensuredStreamsLock.Lock()
+	defer ensuredStreamsLock.Unlock()
+	if updated, ok := ensuredStreams[name]; ok && (updated || !update) {
+		return name, nil
+	}
+
+	cfg, err := GetJetStreamConfig().StreamConfig(name, subject)
+	if err != nil {
+		return "", err
+	}
+	if update {
+		_, err = js.CreateOrUpdateStream(ctx, cfg)
+	} else {
+		_, err = js.CreateStream(ctx, cfg)
+		if errors.Is(err, jetstream.ErrStreamNameAlreadyInUse) {
+			err = nil
+		}
+	}
+	if err != nil {
+		return "", fmt.Errorf("creating jetstream stream %s: %w", name, err)
+	}
+	ensuredStreams[name] = update
+	return name, nil
+}
+
+// ForgetJetStream forgets that the JetStream stream holding a stream topic exists, after it was
+// deleted or could not be reached, so that the next EnsureJetStream creates it again
+func ForgetJetStream(topic string) {
+	name, _, ok := GetJetStreamStream(topic)
+	if !ok {
+		return
+	}
+	ensuredStreamsLock.Lock()
+	delete(ensuredStreams, name)
+	ensuredStreamsLock.Unlock()
+}
+
+// IsJetStreamMissing returns true if an error tells that the stream of a subject does not exist
+func IsJetStreamMissing(err error) bool {
+	return errors.Is(err, jetstream.ErrNoStreamResponse) || errors.Is(err, jetstream.ErrStreamNotFound)
+}
+
+// Add the flags configuring JetStream to a command
+func AddJetStreamFlags(flags *pflag.FlagSet) {
+	cfg := GetJetStreamConfig()
+	flags.Bool("jetstream", cfg.Enabled, "Use durable NATS JetStream streams for the dataprovider stream topics")
+	flags.String("jetstream-retention", cfg.Retention, "Retention policy of the JetStream streams (limits, interest, workqueue)")
+	flags.String("jetstream-storage", cfg.Storage, "Storage of the JetStream streams (file, memory)")
+	flags.Duration("jetstream-max-age", cfg.MaxAge, "Maximum age of the messages kept in a JetStream stream, 0 for unlimited")
+	flags.Int64("jetstream-max-bytes", cfg.MaxBytes, "Maximum size in bytes of a JetStream stream, -1 for unlimited")
+	flags.Int64("jetstream-max-msgs", cfg.MaxMsgs, "Maximum number of messages in a JetStream stream, -1 for unlimited")
+	flags.Int("jetstream-replicas", cfg.Replicas, "Number of replicas of each JetStream stream")
+	flags.Duration("jetstream-ack-wait", cfg.AckWait, "Time to acknowledge a JetStream message before it is redelivered")
+	flags.Duration("jetstream-retry-delay", cfg.RetryDelay, "Delay before redelivering a JetStream message that could not be handled")
+}
+
+// Set the JetStream configuration from the flags added with AddJetStreamFlags
+func SetJetStreamConfigFromFlags(flags *pflag.FlagSet) error {
+	var cfg JetStreamConfig
+	cfg.Enabled, _ = flags.GetBool("jetstream")
+	cfg.Retention, _ = flags.GetString("jetstream-retention")
+	cfg.Storage, _ = flags.GetString("jetstream-storage")
+	cfg.MaxAge, _ = flags.GetDuration("jetstream-max-age")
+	cfg.MaxBytes, _ = flags.GetInt64("jetstream-max-bytes")
+	cfg.MaxMsgs, _ = flags.GetInt64("jetstream-max-msgs")
+	cfg.Replicas, _ = flags.GetInt("jetstream-replicas")
+	cfg.AckWait, _ = flags.GetDuration("jetstream-ack-wait")
+	cfg.RetryDelay, _ = flags.GetDuration("jetstream-retry-delay")
+	if _, err := cfg.StreamConfig(""); err != nil {
+		return err
+	}
+	SetJetStreamConfig(cfg)
+	return nil
+}
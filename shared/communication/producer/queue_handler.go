@@ -2,37 +2,72 @@ package producer
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/google/uuid"
+	"sort"
 	"sync"
 	"time"
 	"tradingplatform/shared/communication"
 	"tradingplatform/shared/logging"
+	"tradingplatform/shared/metrics"
+	"tradingplatform/shared/tracing"
 	"tradingplatform/shared/types"
 	"tradingplatform/shared/utils"
 
 	sharedent "tradingplatform/shared/entities"
 
 	"github.com/nats-io/nats.go"
+	"go.opentelemetry.io/otel/attribute"
 	"google.golang.org/protobuf/proto"
 )
 
-var queues = make(map[string]*utils.Handler[[]*sharedent.Message])
+// Queue is a data queue to send on a response topic. Its messages are read page by page while
+// the queue is sent so that large responses are never held in memory entirely
+type Queue struct {
+	Topic string
+	// Number of messages in the queue when it is known up front, 0 otherwise. The end of the queue
+	// is marked by an end message in any case
+	Count int
+	// Get the next page of at most limit messages, an empty page ends the queue
+	NextPage func(limit int) ([]*sharedent.Message, error)
+	// Context of the request answered by the queue, its trace is propagated in the headers of the
+	// messages of the queue
+	Ctx context.Context
+}
+
+// NewQueue creates a queue from messages that are already in memory
+func NewQueue(topic string, messages []*sharedent.Message) *Queue {
+	return &Queue{
+		Topic: topic,
+		Count: len(messages),
+		NextPage: func(limit int) ([]*sharedent.Message, error) {
+			if limit > len(messages) {
+				limit = len(messages)
+			}
+			page := messages[:limit]
+			messages = messages[limit:]
+			return page, nil
+		},
+	}
+}
+
+var queues = make(map[string]*utils.Handler[Queue])
 var queuesMutex sync.RWMutex
 
 // GetQueueHandler returns a queue handler for a topic
-func GetQueueHandler(topic string, noConfirm bool) (*utils.Handler[[]*sharedent.Message], types.DataResponse) {
+func GetQueueHandler(topic string, noConfirm bool) (*utils.Handler[Queue], types.DataResponse) {
 
 	queuesMutex.RLock()
 	_, ok := queues[topic]
 	queuesMutex.RUnlock()
 
 	if !ok {
-		newQueueHandler := utils.NewHandler[[]*sharedent.Message]()
+		newQueueHandler := utils.NewHandler[Queue]()
 		queuesMutex.Lock()
 		queues[topic] = newQueueHandler
 		queuesMutex.Unlock()
-		StartQueueHandler(newQueueHandler, noConfirm)
+		StartQueueHandler(newQueueHandler, topic, noConfirm)
 		handler := newQueueHandler
 		return handler, types.NewDataResponse(
 			types.Success,
@@ -47,46 +82,51 @@ func GetQueueHandler(topic string, noConfirm bool) (*utils.Handler[[]*sharedent.
 	)
 }
 
-// StartQueueHandler starts a queue handler
-func StartQueueHandler(handler *utils.Handler[[]*sharedent.Message], noConfirm bool) {
-	ich := make(chan *[]*sharedent.Message)
+// StartQueueHandler starts a queue handler, the control topic of the queue is subscribed before
+// returning so that the consumer can start the transfer as soon as it gets the response topic
+func StartQueueHandler(handler *utils.Handler[Queue], topic string, noConfirm bool) {
+	ich := make(chan *Queue)
 	handler.SetChannel(ich)
-	go handleQueue(handler, noConfirm)
-}
 
-func handleQueue(handler *utils.Handler[[]*sharedent.Message], noConfirm bool) {
 	nc, err := nats.Connect(communication.GetNatsURL())
-	var topic string
 	if err != nil {
 		logging.Log().Fatal().Err(err).Msg("connecting to NATS")
 		return
 	}
-	defer nc.Close()
-	msgs := <-handler.Ch
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*120)
-
-	first := (*msgs)[0]
-	topic = first.Topic
-	logging.Log().Debug().Str("topic", topic).Msg("starting queue handler")
+	sender := newQueueSender(nc, topic)
 	if !noConfirm {
-		sub, _ := nc.Subscribe(first.Topic, func(msg *nats.Msg) {
-			cancel()
-		})
-		<-ctx.Done()
-		sub.Unsubscribe()
-	}
-	for _, msg := range *msgs {
-		messagePayload, _ := proto.Marshal(msg)
-		err := nc.Publish(msg.Topic, messagePayload)
+		sub, err := nc.Subscribe(communication.GetQueueControlTopic(topic), sender.handleControl)
 		if err != nil {
-			logging.Log().Error().
-				Err(err).
-				Msg("publishing message to data queue")
+			logging.Log().Error().Err(err).Str("topic", topic).Msg("subscribing to queue control topic")
 		}
+		sender.sub = sub
+	}
+	go handleQueue(handler, sender, noConfirm)
+}
 
+func handleQueue(handler *utils.Handler[Queue], sender *queueSender, noConfirm bool) {
+	defer sender.nc.Close()
+	topic := sender.topic
+	logging.Log().Debug().Str("topic", topic).Msg("starting queue handler")
+
+	queue := <-handler.Ch
+	ctx, span := tracing.Start(queue.Ctx, "send queue",
+		attribute.String("topic", topic),
+		attribute.Int("count", queue.Count))
+	sender.lock.Lock()
+	sender.ctx = ctx
+	sender.lock.Unlock()
+	if noConfirm {
+		sender.sendAll(queue)
+	} else {
+		sender.send(queue)
 	}
+	sender.lock.Lock()
+	err := sender.err
+	sender.lock.Unlock()
+	tracing.End(span, err)
+
 	handler.Cancel()
-	cancel()
 	<-handler.Ctx().Done()
 	queuesMutex.Lock()
 	delete(queues, topic)
@@ -94,7 +134,268 @@ func handleQueue(handler *utils.Handler[[]*sharedent.Message], noConfirm bool) {
 	logging.Log().Debug().Str("topic", topic).Msg("queue handler stopped")
 }
 
+// Sends a queue following the control messages of its consumer
+type queueSender struct {
+	// Context of the span of the sending of the queue
+	ctx   context.Context
+	nc    *nats.Conn
+	sub   *nats.Subscription
+	topic string
+	batch communication.BatchConfig
+
+	// The queue and the messages read from it are only used by the goroutine sending the queue, its
+	// pages are read without holding the lock
+	queue *Queue
+	// Messages read from the queue but not sent yet
+	page []*sharedent.Message
+	// Whether the queue has no more messages to read
+	exhausted bool
+	// Data type of the messages of the queue, carried by its end message
+	dataType string
+
+	lock sync.Mutex
+	// Messages sent but not acknowledged yet, by sequence number of their first entity
+	unacked map[uint64]*sharedent.Message
+	// Next sequence number to send
+	next uint64
+	// Sequence number of the end message, 0 until it was sent
+	end uint64
+	// Sequence number up to which all messages were acknowledged
+	acked uint64
+	// Sequence number up to which messages may be sent
+	limit    uint64
+	err      error
+	canceled bool
+	// Signals the sending loop that a control message was received
+	wake chan struct{}
+}
+
+func newQueueSender(nc *nats.Conn, topic string) *queueSender {
+	return &queueSender{
+		ctx:     context.Background(),
+		nc:      nc,
+		topic:   topic,
+		unacked: make(map[uint64]*sharedent.Message),
+		next:    1,
+		batch:   communication.GetBatchConfig(),
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+func (s *queueSender) publish(msg *sharedent.Message) {
+	messagePayload, _ := proto.Marshal(msg)
+	m := nats.NewMsg(s.topic)
+	m.Data = messagePayload
+	tracing.Inject(s.ctx, m)
+	err := s.nc.PublishMsg(m)
+	metrics.ObservePublish(s.topic, msg.EntityCount(), err)
+	if err != nil {
+		logging.Log().Error().
+			Err(err).
+			Str("topic", s.topic).
+			Uint64("sequence", msg.Sequence).
+			Int("entities", msg.EntityCount()).
+			Msg("publishing message to data queue")
+	}
+}
+
+// Publish the messages allowed by the window, and the end message once the queue is exhausted. The
+// lock is only held while publishing so that control messages are answered while pages are read
+func (s *queueSender) fillWindow() {
+	for {
+		s.lock.Lock()
+		open := s.err == nil && !s.canceled && s.end == 0 && s.next <= s.limit
+		s.lock.Unlock()
+		if !open {
+			return
+		}
+		if len(s.page) == 0 && !s.exhausted {
+			page, err := s.queue.NextPage(communication.DEFAULT_QUEUE_PAGE_SIZE)
+			if err != nil {
+				logging.Log().Error().Err(err).Str("topic", s.topic).Msg("reading data queue")
+				s.lock.Lock()
+				s.err = err
+				s.lock.Unlock()
+				return
+			}
+			s.page = page
+			s.exhausted = len(page) == 0
+		}
+		s.lock.Lock()
+		s.publishNext()
+		s.lock.Unlock()
+	}
+}
+
+// Publish the next messages of the page that fit in a batch and are allowed by the window, or the end
+// message once the queue is exhausted. Must be called with the lock held
+func (s *queueSender) publishNext() {
+	if s.exhausted {
+		msg := sharedent.GenerateEndMessage(s.topic, s.dataType, s.next)
+		s.end = s.next
+		s.unacked[s.next] = msg
+		s.publish(msg)
+		s.next++
+		return
+	}
+	var messages []*sharedent.Message
+	size := 0
+	for len(s.page) > 0 && s.next+uint64(len(messages)) <= s.limit {
+		msg := s.page[0]
+		if !s.batch.Fits(len(messages), size, len(msg.Payload)) {
+			break
+		}
+		s.page = s.page[1:]
+		messages = append(messages, msg)
+		size += len(msg.Payload)
+	}
+	msg := messages[0]
+	if len(messages) > 1 {
+		msg = sharedent.GenerateBatchMessage(s.topic, messages)
+	}
+	s.dataType = msg.DataType
+	msg.Topic = s.topic
+	msg.Sequence = s.next
+	s.unacked[s.next] = msg
+	s.publish(msg)
+	s.next += uint64(len(messages))
+}
+
+// Send the queue to a consumer that controls the transfer
+func (s *queueSender) send(queue *Queue) {
+	s.queue = queue
+	defer func() {
+		if s.sub != nil {
+			s.sub.Unsubscribe()
+		}
+	}()
+
+	idle := time.NewTimer(communication.QUEUE_IDLE_TIMEOUT)
+	defer idle.Stop()
+	for {
+		s.fillWindow()
+		s.lock.Lock()
+		finished := s.canceled || (s.end != 0 && s.acked >= s.end)
+		s.lock.Unlock()
+		if finished {
+			return
+		}
+
+		select {
+		case <-s.wake:
+			if !idle.Stop() {
+				<-idle.C
+			}
+			idle.Reset(communication.QUEUE_IDLE_TIMEOUT)
+		case <-idle.C:
+			logging.Log().Error().
+				Str("topic", s.topic).
+				Msg("timed out waiting for the consumer of the data queue")
+			return
+		}
+	}
+}
+
+// Send the whole queue without waiting for a consumer
+func (s *queueSender) sendAll(queue *Queue) {
+	s.queue = queue
+	window := uint64(1)
+	if s.batch.IsEnabled() {
+		window = uint64(s.batch.MaxCount)
+	}
+	for {
+		s.lock.Lock()
+		if s.err != nil || s.end != 0 {
+			s.lock.Unlock()
+			return
+		}
+		s.limit = s.next + window - 1
+		s.lock.Unlock()
+		s.fillWindow()
+		// Nothing is sent again without a consumer controlling the transfer
+		s.lock.Lock()
+		clear(s.unacked)
+		s.lock.Unlock()
+	}
+}
+
+// Handle a control message of the consumer of the queue
+func (s *queueSender) handleControl(m *nats.Msg) {
+	var control communication.QueueControl
+	var reply communication.QueueControlReply
+	if err := json.Unmarshal(m.Data, &control); err != nil {
+		reply.Err = fmt.Sprintf("invalid control message: %v", err)
+		s.respond(m, reply)
+		return
+	}
+
+	s.lock.Lock()
+	switch control.Operation {
+	case communication.QueueAck:
+		if control.Ack > s.acked {
+			for seq, msg := range s.unacked {
+				if seq+uint64(msg.EntityCount())-1 <= control.Ack {
+					delete(s.unacked, seq)
+				}
+			}
+			s.acked = control.Ack
+		}
+		window := control.Window
+		if window <= 0 {
+			window = communication.DEFAULT_QUEUE_WINDOW
+		}
+		s.limit = s.acked + uint64(window)
+	case communication.QueueResend:
+		// A batch is sent again if any of its entities is missing
+		missing := make(map[uint64]struct{}, len(control.Sequences))
+		for _, seq := range control.Sequences {
+			missing[seq] = struct{}{}
+		}
+		for seq, msg := range s.unacked {
+			for i := 0; i < msg.EntityCount(); i++ {
+				if _, ok := missing[seq+uint64(i)]; ok {
+					s.publish(msg)
+					break
+				}
+			}
+		}
+	case communication.QueueCancel:
+		s.canceled = true
+	default:
+		reply.Err = fmt.Sprintf("unknown queue operation %s", control.Operation)
+	}
+	if s.err != nil {
+		reply.Err = s.err.Error()
+	}
+	s.lock.Unlock()
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	s.respond(m, reply)
+}
+
+func (s *queueSender) respond(m *nats.Msg, reply communication.QueueControlReply) {
+	data, _ := json.Marshal(reply)
+	if err := m.Respond(data); err != nil {
+		logging.Log().Error().Err(err).Str("topic", s.topic).Msg("replying to queue control message")
+	}
+}
+
 func GenerateQueueID() string {
 	return uuid.New().String()
 
 }
+
+// GetQueueTopics returns the response topics of the queue handlers that are open
+func GetQueueTopics() []string {
+	queuesMutex.RLock()
+	topics := make([]string, 0, len(queues))
+	for topic := range queues {
+		topics = append(topics, topic)
+	}
+	queuesMutex.RUnlock()
+	sort.Strings(topics)
+	return topics
+}
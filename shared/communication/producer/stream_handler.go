@@ -1,14 +1,21 @@
 package producer
 
 import (
+	"context"
 	"strings"
 	"sync"
+	"time"
 	"tradingplatform/shared/communication"
 	sharedent "tradingplatform/shared/entities"
 	"tradingplatform/shared/logging"
+	"tradingplatform/shared/metrics"
+	"tradingplatform/shared/tracing"
 	"tradingplatform/shared/utils"
 
 	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -61,17 +68,203 @@ func StopTopicHandler(topic string) {
 
 func handleTopic(handler *utils.Handler[sharedent.Message]) {
 	nc, _ := nats.Connect(communication.GetNatsURL())
-
 	defer nc.Close()
-	for msg := range handler.Ch {
-		messagePayload, _ := proto.Marshal(msg)
-		err := nc.Publish(msg.Topic, messagePayload)
+
+	// Publish a message, observe is called with the outcome once it is known
+	publish := func(m *nats.Msg, observe func(error)) {
+		observe(nc.PublishMsg(m))
+	}
+	if communication.IsJetStreamEnabled() {
+		js, err := jetstream.New(nc)
 		if err != nil {
-			logging.Log().Error().
-				Err(err).
-				RawJSON("message", messagePayload).
-				Msg("publishing message")
+			logging.Log().Error().Err(err).Msg("creating jetstream context")
+		} else {
+			ensure := func(subject string) error {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				_, err := communication.EnsureJetStream(ctx, js, subject, true)
+				return err
+			}
+			// The acknowledgements of the messages published asynchronously are awaited in order, a message
+			// that was not stored is published again
+			acks := make(chan *pendingPublish, pendingPublishes)
+			confirmed := make(chan struct{})
+			go func() {
+				defer close(confirmed)
+				for pending := range acks {
+					pending.observe(confirmPublish(js, pending.future, ensure))
+				}
+			}()
+			publish = func(m *nats.Msg, observe func(error)) {
+				if _, _, ok := communication.GetJetStreamStream(m.Subject); !ok {
+					observe(nc.PublishMsg(m))
+					return
+				}
+				if err := ensure(m.Subject); err != nil {
+					observe(err)
+					return
+				}
+				future, err := js.PublishMsgAsync(m)
+				if err != nil {
+					observe(err)
+					return
+				}
+				acks <- &pendingPublish{future: future, observe: observe}
+			}
+			defer func() {
+				close(acks)
+				select {
+				case <-confirmed:
+				case <-time.After(publishAckTimeout):
+					logging.Log().Warn().
+						Int("pending", js.PublishAsyncPending()).
+						Msg("timed out waiting for jetstream publish acknowledgements")
+				}
+			}()
+		}
+	}
+
+	send := func(msg *sharedent.Message) {
+		messagePayload, _ := proto.Marshal(msg)
+		m := nats.NewMsg(msg.Topic)
+		m.Data = messagePayload
+		// Each published message starts a trace followed by the components handling it
+		var span trace.Span
+		if tracing.IsEnabled() {
+			var ctx context.Context
+			ctx, span = tracing.Start(context.Background(), "publish "+metrics.TopicRoot(msg.Topic),
+				attribute.String("topic", msg.Topic),
+				attribute.Int("entities", msg.EntityCount()))
+			tracing.Inject(ctx, m)
+		}
+		topic, entities := msg.Topic, msg.EntityCount()
+		publish(m, func(err error) {
+			if span != nil {
+				tracing.End(span, err)
+			}
+			metrics.ObservePublish(topic, entities, err)
+			if err != nil {
+				logging.Log().Error().
+					Err(err).
+					Str("topic", topic).
+					Int("entities", entities).
+					Msg("publishing message")
+			}
+		})
+	}
+
+	batchCfg := communication.GetBatchConfig()
+	if !batchCfg.IsStreamEnabled() {
+		for msg := range handler.Ch {
+			send(msg)
+		}
+		<-handler.Ctx().Done()
+		return
+	}
+
+	// The handler publishes the topics of all symbols of a stream, messages are batched by topic
+	batches := make(map[string]*streamBatch)
+	flush := func(topic string) {
+		batch, ok := batches[topic]
+		if !ok {
+			return
+		}
+		delete(batches, topic)
+		if len(batch.messages) == 1 {
+			send(batch.messages[0])
+			return
 		}
+		send(sharedent.GenerateBatchMessage(topic, batch.messages))
 	}
-	<-handler.Ctx().Done()
+	ticker := time.NewTicker(batchCfg.Linger)
+	defer ticker.Stop()
+	for {
+		select {
+		case msg, ok := <-handler.Ch:
+			if !ok {
+				for topic := range batches {
+					flush(topic)
+				}
+				<-handler.Ctx().Done()
+				return
+			}
+			batch := batches[msg.Topic]
+			if batch != nil && (batch.messages[0].DataType != msg.DataType ||
+				!batchCfg.Fits(len(batch.messages), batch.size, len(msg.Payload))) {
+				flush(msg.Topic)
+				batch = nil
+			}
+			if batch == nil {
+				batch = &streamBatch{}
+				batches[msg.Topic] = batch
+			}
+			batch.messages = append(batch.messages, msg)
+			batch.size += len(msg.Payload)
+		case <-ticker.C:
+			for topic := range batches {
+				flush(topic)
+			}
+		}
+	}
+}
+
+// Number of messages published to JetStream that can wait for their acknowledgement
+const pendingPublishes = 4096
+
+// Time to wait for an acknowledgement of JetStream and for the pending acknowledgements when a
+// handler stops
+const publishAckTimeout = 5 * time.Second
+
+// Number of times a message JetStream did not store is published again
+const publishRetries = 3
+
+// A message published to JetStream waiting for its acknowledgement
+type pendingPublish struct {
+	future  jetstream.PubAckFuture
+	observe func(error)
+}
+
+// Wait for the acknowledgement of a message published to JetStream and publish it again, synchronously,
+// if it was not stored. A stream that could not be reached is ensured again before retrying, in case it
+// was deleted. Returns the error of the last attempt
+func confirmPublish(js jetstream.JetStream, future jetstream.PubAckFuture, ensure func(subject string) error) error {
+	var err error
+	select {
+	case <-future.Ok():
+		return nil
+	case err = <-future.Err():
+	case <-time.After(publishAckTimeout):
+		err = nats.ErrTimeout
+	}
+	m := future.Msg()
+	for attempt := 1; attempt <= publishRetries; attempt++ {
+		logging.Log().Warn().
+			Err(err).
+			Str("topic", m.Subject).
+			Int("attempt", attempt).
+			Msg("jetstream did not store message, publishing again")
+		if communication.IsJetStreamMissing(err) {
+			communication.ForgetJetStream(m.Subject)
+		}
+		time.Sleep(time.Duration(attempt) * 100 * time.Millisecond)
+		if err = ensure(m.Subject); err != nil {
+			continue
+		}
+		retry := nats.NewMsg(m.Subject)
+		retry.Header = m.Header
+		retry.Data = m.Data
+		ctx, cancel := context.WithTimeout(context.Background(), publishAckTimeout)
+		_, err = js.PublishMsg(ctx, retry)
+		cancel()
+		if err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// Messages of a stream topic waiting to be published in a batch
+type streamBatch struct {
+	messages []*sharedent.Message
+	size     int
 }
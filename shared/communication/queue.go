@@ -0,0 +1,65 @@
+package communication
+
+import (
+	"strings"
+	"time"
+
+	"tradingplatform/shared/types"
+	"tradingplatform/shared/utils"
+)
+
+// Data queues are sent with a sequenced, flow-controlled protocol: every message of a queue carries its
+// sequence number (starting at 1), the consumer acknowledges the messages it received contiguously and
+// grants the producer a window of further messages, and asks for missing sequence numbers again.
+// The last message of a queue is an end message carrying no entity, the number of messages in the
+// topic of a queue is only known up front for queues held in memory and 0 otherwise.
+// Control messages are JSON requests on the queue control topic of the queue, answered by the producer.
+
+type QueueOperation string
+
+const (
+	// Acknowledge all messages up to Ack and allow sending Window more messages
+	QueueAck QueueOperation = "ack"
+	// Send the messages with the given sequence numbers again
+	QueueResend QueueOperation = "resend"
+	// Stop sending the queue
+	QueueCancel QueueOperation = "cancel"
+)
+
+// Number of messages the producer may send ahead of the last acknowledgement
+var DEFAULT_QUEUE_WINDOW = 1000
+
+// Number of messages read at once from the source of a queue
+var DEFAULT_QUEUE_PAGE_SIZE = 1000
+
+// Time the producer waits for a control message before giving up on the queue
+var QUEUE_IDLE_TIMEOUT = 120 * time.Second
+
+// Time the consumer waits for a missing message before asking for it again
+var QUEUE_RESEND_TIMEOUT = time.Second
+
+// Control message sent by the consumer of a data queue to its producer
+type QueueControl struct {
+	Operation QueueOperation `json:"operation"`
+	// Sequence number up to which all messages were received
+	Ack uint64 `json:"ack"`
+	// Number of messages after Ack the producer may send
+	Window int `json:"window,omitempty"`
+	// Sequence numbers to send again
+	Sequences []uint64 `json:"sequences,omitempty"`
+}
+
+// Reply of the producer of a data queue to a control message
+type QueueControlReply struct {
+	Err string `json:"err,omitempty"`
+}
+
+// GetQueueControlTopic returns the control topic of the data queue sent on a response topic
+func GetQueueControlTopic(responseTopic string) string {
+	parts := strings.Split(responseTopic, ".")
+	queueID := ""
+	if len(parts) >= 2 {
+		queueID = parts[len(parts)-2]
+	}
+	return utils.NewQueueControlTopic(types.Component(parts[0]), queueID).Generate()
+}
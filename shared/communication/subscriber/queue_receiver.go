@@ -0,0 +1,251 @@
+package subscriber
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"tradingplatform/shared/communication"
+	"tradingplatform/shared/entities"
+	"tradingplatform/shared/logging"
+	"tradingplatform/shared/tracing"
+
+	"github.com/nats-io/nats.go"
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/protobuf/proto"
+)
+
+// Number of consecutive failed control requests after which the producer of a queue is considered gone
+var maxControlFailures = 10
+
+// Receives a data queue, acknowledging windows of messages and asking for missing ones again
+type queueReceiver struct {
+	nc           *nats.Conn
+	topic        string
+	controlTopic string
+	window       int
+	// Sequence number of the end message of the queue, 0 until it was received
+	end uint64
+	// Sequence number up to which all messages were delivered
+	delivered uint64
+	// Sequence number acknowledged last
+	acked uint64
+	// Messages received out of order
+	buffer          map[uint64]*entities.Message
+	controlFailures int
+}
+
+// ReceiveQueue receives the data queue sent on a response topic and calls onData for each of its
+// messages in order, batch messages are unpacked first. It returns once the end message of the queue
+// was received, or with an error if the producer of the queue stops answering or the context is done
+func ReceiveQueue(ctx context.Context, nc *nats.Conn, topic string, onData func(*entities.Message)) (err error) {
+	_, count := GetQueueComponents(topic)
+	ctx, span := tracing.Start(ctx, "receive queue",
+		attribute.String("topic", topic),
+		attribute.Int("count", count))
+	defer func() {
+		tracing.End(span, err)
+	}()
+	r := &queueReceiver{
+		nc:           nc,
+		topic:        topic,
+		controlTopic: communication.GetQueueControlTopic(topic),
+		window:       communication.DEFAULT_QUEUE_WINDOW,
+		buffer:       make(map[uint64]*entities.Message),
+	}
+
+	ch := make(chan *nats.Msg, 2*r.window)
+	sub, err := nc.ChanSubscribe(topic, ch)
+	if err != nil {
+		return fmt.Errorf("error while subscribing to data response stream %v (topic: %s)", err, topic)
+	}
+	defer sub.Unsubscribe()
+	sub.SetPendingLimits(-1, -1)
+
+	// Ask the producer to start sending
+	if err := r.ack(ctx); err != nil {
+		return err
+	}
+	keepAliveCtx, stopKeepAlive := context.WithCancel(ctx)
+	defer stopKeepAlive()
+	go r.keepAlive(keepAliveCtx)
+
+	ticker := time.NewTicker(communication.QUEUE_RESEND_TIMEOUT / 2)
+	defer ticker.Stop()
+	lastProgress := time.Now()
+	for !r.done() {
+		select {
+		case m := <-ch:
+			var msg entities.Message
+			if err := proto.Unmarshal(m.Data, &msg); err != nil {
+				logging.Log().Debug().Err(err).Str("topic", topic).Msg("unmarshalling data queue message")
+				continue
+			}
+			for _, part := range msg.Unpack() {
+				if r.receive(part, onData) {
+					lastProgress = time.Now()
+				}
+			}
+			if !r.done() && r.delivered-r.acked >= uint64(r.window/2) {
+				if err := r.ack(ctx); err != nil {
+					return err
+				}
+			}
+		case <-ticker.C:
+			if time.Since(lastProgress) < communication.QUEUE_RESEND_TIMEOUT {
+				continue
+			}
+			if err := r.resend(ctx); err != nil {
+				return err
+			}
+			lastProgress = time.Now()
+		case <-ctx.Done():
+			r.control(context.Background(), communication.QueueControl{Operation: communication.QueueCancel})
+			return ctx.Err()
+		}
+	}
+
+	// Let the producer know that everything was received, it stops waiting for acknowledgements
+	r.control(ctx, communication.QueueControl{Operation: communication.QueueAck, Ack: r.delivered})
+	return nil
+}
+
+// Whether all messages of the queue were delivered
+func (r *queueReceiver) done() bool {
+	return r.end != 0 && r.delivered >= r.end
+}
+
+// Buffer a received message and deliver the messages that are now in order, returns whether
+// the message was new. The end message is not delivered
+func (r *queueReceiver) receive(msg *entities.Message, onData func(*entities.Message)) bool {
+	seq := msg.Sequence
+	if seq <= r.delivered || (r.end != 0 && seq > r.end) {
+		return false
+	}
+	if _, ok := r.buffer[seq]; ok {
+		return false
+	}
+	if msg.GetEnd() {
+		r.end = seq
+	}
+	r.buffer[seq] = msg
+	for {
+		next, ok := r.buffer[r.delivered+1]
+		if !ok {
+			break
+		}
+		delete(r.buffer, r.delivered+1)
+		r.delivered++
+		if !next.GetEnd() {
+			onData(next)
+		}
+	}
+	return true
+}
+
+// Acknowledge the messages delivered so far and open the next window
+func (r *queueReceiver) ack(ctx context.Context) error {
+	err := r.controlWithRetry(ctx, communication.QueueControl{
+		Operation: communication.QueueAck,
+		Ack:       r.delivered,
+		Window:    r.window,
+	})
+	if err == nil {
+		r.acked = r.delivered
+	}
+	return err
+}
+
+// Keep the producer waiting while onData is slower than the idle timeout of the producer, e.g. while
+// the consumer reads other queues first. The acknowledgement of nothing does not move the window
+func (r *queueReceiver) keepAlive(ctx context.Context) {
+	ticker := time.NewTicker(communication.QUEUE_IDLE_TIMEOUT / 4)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			r.control(ctx, communication.QueueControl{Operation: communication.QueueAck, Window: r.window})
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Ask again for the missing messages of the current window
+func (r *queueReceiver) resend(ctx context.Context) error {
+	end := r.delivered + uint64(r.window)
+	if r.end != 0 && end > r.end {
+		end = r.end
+	}
+	var missing []uint64
+	for seq := r.delivered + 1; seq <= end; seq++ {
+		if _, ok := r.buffer[seq]; !ok {
+			missing = append(missing, seq)
+		}
+	}
+	logging.Log().Debug().
+		Str("topic", r.topic).
+		Uint64("delivered", r.delivered).
+		Int("missing", len(missing)).
+		Msg("requesting missing data queue messages")
+	if err := r.controlWithRetry(ctx, communication.QueueControl{
+		Operation: communication.QueueResend,
+		Sequences: missing,
+	}); err != nil {
+		return err
+	}
+	// Also acknowledge in case a previous acknowledgement was lost
+	return r.ack(ctx)
+}
+
+// Send a control message, retrying while the producer does not answer
+func (r *queueReceiver) controlWithRetry(ctx context.Context, control communication.QueueControl) error {
+	for {
+		err := r.control(ctx, control)
+		if err == nil {
+			r.controlFailures = 0
+			return nil
+		}
+		var queueErr queueError
+		if errors.As(err, &queueErr) || ctx.Err() != nil {
+			return err
+		}
+		r.controlFailures++
+		if r.controlFailures >= maxControlFailures {
+			return fmt.Errorf("producer of data queue %s is not answering: %w", r.topic, err)
+		}
+		select {
+		case <-time.After(time.Duration(r.controlFailures) * 100 * time.Millisecond):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// An error reported by the producer of a queue
+type queueError string
+
+func (e queueError) Error() string {
+	return string(e)
+}
+
+// Send a control message to the producer of the queue and apply its reply
+func (r *queueReceiver) control(ctx context.Context, control communication.QueueControl) error {
+	data, _ := json.Marshal(control)
+	requestCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	m, err := r.nc.RequestWithContext(requestCtx, r.controlTopic, data)
+	if err != nil {
+		return err
+	}
+	var reply communication.QueueControlReply
+	if err := json.Unmarshal(m.Data, &reply); err != nil {
+		return err
+	}
+	if reply.Err != "" {
+		return queueError(fmt.Sprintf("data queue %s: %s", r.topic, reply.Err))
+	}
+	return nil
+}
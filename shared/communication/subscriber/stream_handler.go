@@ -1,33 +1,72 @@
 package subscriber
 
 import (
+	"context"
+	"errors"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"tradingplatform/shared/communication"
 	"tradingplatform/shared/entities"
 	"tradingplatform/shared/logging"
+	"tradingplatform/shared/metrics"
+	"tradingplatform/shared/tracing"
 	"tradingplatform/shared/utils"
 
 	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/protobuf/proto"
 )
 
-var streams = make(map[string]*utils.Handler[entities.Message])
+var streams = make(map[string]*utils.Handler[ReceivedMessage])
 var streamsMutex sync.RWMutex
-var dataQueues = make(map[string][]*entities.Message)
+var dataQueues = make(map[string]map[uint64]*entities.Message)
 var dataQueuesMutex sync.RWMutex
 
+// ReceivedMessage is a message passed to the agents of a topic with the trace context of the component
+// that published it
+type ReceivedMessage struct {
+	Msg *entities.Message
+	// Trace context of the headers of the message, the background context when the spans are not exported
+	Ctx context.Context
+}
+
+// A message consumed from JetStream waiting for the messages unpacked from it to be handled
+type pendingAck struct {
+	msg jetstream.Msg
+	// Number of unpacked messages not handled yet
+	remaining int
+	// First error returned while handling the unpacked messages
+	err error
+}
+
+// Messages unpacked from JetStream messages waiting to be handled by the attached functionality
+var pendingAcks = make(map[*entities.Message]*pendingAck)
+var pendingAcksMutex sync.Mutex
+
+// Name of the group sharing the consumption of a topic between agents of different instances
+var consumerGroup = "storage"
+
+// SetConsumerGroup sets the name of the group consuming the topics subscribed by a component, components
+// consuming the same topics for different purposes must use different groups so that each of them
+// receives every message
+func SetConsumerGroup(group string) {
+	consumerGroup = group
+}
+
 // GetStreamHandler returns the stream producer handler for a topic
-func GetStreamHandler(topic string) *utils.Handler[entities.Message] {
+func GetStreamHandler(topic string) *utils.Handler[ReceivedMessage] {
 	streamsMutex.RLock()
 	handler, ok := streams[topic]
 	streamsMutex.RUnlock()
 
 	if !ok {
 		streamsMutex.Lock()
-		streams[topic] = utils.NewHandler[entities.Message]()
+		streams[topic] = utils.NewHandler[ReceivedMessage]()
 		handler = streams[topic]
 		streamsMutex.Unlock()
 		StartTopicHandler(streams[topic], topic)
@@ -37,30 +76,39 @@ func GetStreamHandler(topic string) *utils.Handler[entities.Message] {
 }
 
 // GetStreamHandlerRoundRobin returns the stream producer handler for a topic with round robin functionality
-func GetStreamHandlerRoundRobin(topic string, maxAgents int) *utils.Handler[entities.Message] {
+func GetStreamHandlerRoundRobin(topic string, maxAgents int) *utils.Handler[ReceivedMessage] {
+	return getStreamHandlerRoundRobin(topic, maxAgents, false)
+}
+
+func getStreamHandlerRoundRobin(topic string, maxAgents int, ordered bool) *utils.Handler[ReceivedMessage] {
 	streamsMutex.RLock()
 	handler, ok := streams[topic]
 	streamsMutex.RUnlock()
 
 	if !ok {
 		streamsMutex.Lock()
-		streams[topic] = utils.NewRoundRobinHandler[entities.Message](maxAgents)
+		streams[topic] = utils.NewRoundRobinHandler[ReceivedMessage](maxAgents)
 		handler = streams[topic]
 		streamsMutex.Unlock()
-		StartTopicHandler(streams[topic], topic)
+		startTopicHandler(streams[topic], topic, ordered)
 	}
 
 	return handler
 }
 
 // StartTopicHandler starts a new stream producer handler for a topic
-func StartTopicHandler(handler *utils.Handler[entities.Message], topic string) {
-	ich := make(chan *entities.Message)
+func StartTopicHandler(handler *utils.Handler[ReceivedMessage], topic string) {
+	startTopicHandler(handler, topic, false)
+}
+
+func startTopicHandler(handler *utils.Handler[ReceivedMessage], topic string, ordered bool) {
+	ich := make(chan *ReceivedMessage)
 	handler.SetChannel(ich)
-	go handleTopic(handler, topic)
+	go handleTopic(handler, topic, ordered)
 }
 
-// StopTopicHandler stops a stream producer handler for a topic
+// StopTopicHandler stops a stream producer handler for a topic, the durable consumer of a JetStream
+// topic is deleted for the whole consumer group
 func StopTopicHandler(topic string) {
 	streamsMutex.Lock()
 	defer streamsMutex.Unlock()
@@ -74,26 +122,50 @@ func StopTopicHandler(topic string) {
 	delete(streams, topic)
 }
 
-func handleTopic(handler *utils.Handler[entities.Message], topic string) {
+// Consume a topic, the messages received from core NATS are passed to the agents concurrently unless
+// the handler is ordered
+func handleTopic(handler *utils.Handler[ReceivedMessage], topic string, ordered bool) {
+	if _, _, ok := communication.GetJetStreamStream(topic); ok && communication.IsJetStreamEnabled() {
+		handleJetStreamTopic(handler, topic)
+		return
+	}
 	nc, _ := nats.Connect(communication.GetNatsURL())
 	defer nc.Close()
-	sub, _ := nc.QueueSubscribe(topic, "storage", func(m *nats.Msg) {
-		go func() {
-			var msg entities.Message
-			err := proto.Unmarshal(m.Data, &msg)
-			if err != nil {
-				logging.Log().Error().Err(err).Msg("unmarshalling message")
-			}
-			if IsQueue(m.Subject) {
-				accumulateData(m.Subject, &msg)
-			}
+	deliver := func(m *nats.Msg) {
+		var msg entities.Message
+		err := proto.Unmarshal(m.Data, &msg)
+		if err != nil {
+			logging.Log().Error().Err(err).Msg("unmarshalling message")
+		}
+		parts := msg.Unpack()
+		if IsQueue(m.Subject) {
+			accumulateData(m.Subject, parts)
+		}
+		metrics.ObserveReceive(topic, len(parts))
+		ctx := traceContext(m.Header)
+		for i, part := range parts {
 			ch, err := handler.GetNextAgent()
 			if err != nil {
 				logging.Log().Error().Err(err).Msg("getting next agent")
+				metrics.ObserveDropped(topic, len(parts)-i)
+				return
+			}
+			select {
+			case ch <- &ReceivedMessage{Msg: part, Ctx: ctx}:
+			case <-handler.Ctx().Done():
+				metrics.ObserveDropped(topic, len(parts)-i)
 				return
 			}
-			ch <- &msg
-		}()
+		}
+	}
+	sub, _ := nc.QueueSubscribe(topic, consumerGroup, func(m *nats.Msg) {
+		// An ordered handler has a single agent handling the messages in the order they are received,
+		// the messages of the others are delivered without waiting for the agents
+		if ordered {
+			deliver(m)
+			return
+		}
+		go deliver(m)
 	})
 	sub.SetPendingLimits(-1, -1)
 
@@ -102,9 +174,168 @@ func handleTopic(handler *utils.Handler[entities.Message], topic string) {
 	nc.Close()
 }
 
-// AttatchFunctionality attatches functionality to a stream handler
-func AttatchFunctionalityRoundRobin(topic string, f func(*entities.Message), numAgents int) {
-	handler := GetStreamHandlerRoundRobin(topic, numAgents)
+// Consume a topic through a durable JetStream pull consumer, messages are acknowledged once the
+// attached functionality handled them successfully so that they survive restarts of the subscriber
+func handleJetStreamTopic(handler *utils.Handler[ReceivedMessage], topic string) {
+	log := logging.Log().With().Str("topic", topic).Logger()
+	nc, err := nats.Connect(communication.GetNatsURL())
+	if err != nil {
+		log.Error().Err(err).Msg("connecting to nats")
+		return
+	}
+	defer nc.Close()
+	js, err := jetstream.New(nc)
+	if err != nil {
+		log.Error().Err(err).Msg("creating jetstream context")
+		return
+	}
+
+	cfg := communication.GetJetStreamConfig()
+	ctx, cancel := context.WithTimeout(handler.Ctx(), 10*time.Second)
+	defer cancel()
+	var stream string
+	var consumer jetstream.Consumer
+	// A stream deleted since it was ensured is created again
+	for attempt := 0; attempt < 2; attempt++ {
+		stream, err = communication.EnsureJetStream(ctx, js, topic, false)
+		if err != nil {
+			log.Error().Err(err).Msg("creating jetstream stream")
+			return
+		}
+		consumer, err = js.CreateOrUpdateConsumer(ctx, stream, jetstream.ConsumerConfig{
+			Durable:       GetDurableName(topic),
+			FilterSubject: topic,
+			AckPolicy:     jetstream.AckExplicitPolicy,
+			AckWait:       cfg.AckWait,
+		})
+		if !communication.IsJetStreamMissing(err) {
+			break
+		}
+		communication.ForgetJetStream(topic)
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("creating jetstream consumer")
+		return
+	}
+
+	consumeCtx, err := consumer.Consume(func(m jetstream.Msg) {
+		var msg entities.Message
+		if err := proto.Unmarshal(m.Data(), &msg); err != nil {
+			log.Error().Err(err).Msg("unmarshalling message")
+			m.Term()
+			return
+		}
+		// A batch is acknowledged once all of its entities were handled
+		parts := msg.Unpack()
+		if len(parts) == 0 {
+			m.Ack()
+			return
+		}
+		metrics.ObserveReceive(topic, len(parts))
+		ctx := traceContext(m.Headers())
+		pending := &pendingAck{msg: m, remaining: len(parts)}
+		pendingAcksMutex.Lock()
+		for _, part := range parts {
+			pendingAcks[part] = pending
+		}
+		pendingAcksMutex.Unlock()
+		for i, part := range parts {
+			ch, err := handler.GetNextAgent()
+			if err != nil {
+				log.Error().Err(err).Msg("getting next agent")
+				metrics.ObserveDropped(topic, len(parts)-i)
+				for _, undelivered := range parts[i:] {
+					acknowledge(undelivered, err)
+				}
+				return
+			}
+			select {
+			case ch <- &ReceivedMessage{Msg: part, Ctx: ctx}:
+			case <-handler.Ctx().Done():
+				metrics.ObserveDropped(topic, len(parts)-i)
+				for _, undelivered := range parts[i:] {
+					acknowledge(undelivered, handler.Ctx().Err())
+				}
+				return
+			}
+		}
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("consuming jetstream messages")
+		return
+	}
+	log.Debug().Str("stream", stream).Msg("consuming topic from jetstream")
+
+	<-handler.Ctx().Done()
+	consumeCtx.Stop()
+
+	// The handler stops when the topic is unsubscribed, the durable consumer is not needed anymore. It
+	// is kept when the subscriber exits so that it continues where it stopped after a restart
+	deleteCtx, cancelDelete := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelDelete()
+	err = js.DeleteConsumer(deleteCtx, stream, GetDurableName(topic))
+	if err != nil && !errors.Is(err, jetstream.ErrConsumerNotFound) {
+		log.Error().Err(err).Msg("deleting jetstream consumer")
+		return
+	}
+	log.Debug().Str("stream", stream).Msg("deleted jetstream consumer of unsubscribed topic")
+}
+
+// GetDurableName returns the name of the durable JetStream consumer of a topic
+func GetDurableName(topic string) string {
+	return consumerGroup + "_" + strings.NewReplacer(".", "_", "*", "ANY", ">", "ALL").Replace(topic)
+}
+
+// Acknowledge a message consumed from JetStream once it has been handled, messages that could not be
+// handled are redelivered after the retry delay. A batch is acknowledged once all of its entities were
+// handled and redelivered as a whole if any of them failed. Messages received from core NATS are ignored
+func acknowledge(msg *entities.Message, handleErr error) {
+	pendingAcksMutex.Lock()
+	pending, ok := pendingAcks[msg]
+	delete(pendingAcks, msg)
+	if !ok {
+		pendingAcksMutex.Unlock()
+		return
+	}
+	pending.remaining--
+	if handleErr != nil && pending.err == nil {
+		pending.err = handleErr
+	}
+	done := pending.remaining == 0
+	pendingAcksMutex.Unlock()
+	if !done {
+		return
+	}
+
+	var err error
+	if pending.err != nil {
+		logging.Log().Warn().
+			Err(pending.err).
+			Str("topic", msg.Topic).
+			Msg("handling message failed, requesting redelivery")
+		err = pending.msg.NakWithDelay(communication.GetJetStreamConfig().RetryDelay)
+	} else {
+		err = pending.msg.Ack()
+	}
+	if err != nil {
+		logging.Log().Error().Err(err).Str("topic", msg.Topic).Msg("acknowledging message")
+	}
+}
+
+// AttatchFunctionality attatches functionality to a stream handler, the error returned by the
+// functionality decides whether a message consumed from JetStream is acknowledged
+func AttatchFunctionalityRoundRobin(topic string, f func(*entities.Message) error, numAgents int) {
+	attatchFunctionality(GetStreamHandlerRoundRobin(topic, numAgents), topic, f, numAgents)
+}
+
+// AttatchOrderedFunctionality attatches functionality handling the messages of a topic one at a time in
+// the order they are received, for functionality depending on the order of the messages (e.g. bars).
+// The topic must not be handled yet
+func AttatchOrderedFunctionality(topic string, f func(*entities.Message) error) {
+	attatchFunctionality(getStreamHandlerRoundRobin(topic, 1, true), topic, f, 1)
+}
+
+func attatchFunctionality(handler *utils.Handler[ReceivedMessage], topic string, f func(*entities.Message) error, numAgents int) {
 	handler.Lock.RLock()
 	if handler.FunctionalityAttatched {
 		logging.Log().Debug().Str("topic", topic).Int("numAgents", numAgents).Msg("functionality already attatched to all agents")
@@ -114,14 +345,22 @@ func AttatchFunctionalityRoundRobin(topic string, f func(*entities.Message), num
 	handler.Lock.RUnlock()
 	handler.Lock.Lock()
 	for i := 0; i < len(handler.RoundRobinChannels); i++ {
-		go func(h *utils.Handler[entities.Message], chId int) {
+		go func(h *utils.Handler[ReceivedMessage], chId int) {
 			h.Lock.RLock()
 			ch := h.RoundRobinChannels[chId]
 			h.Lock.RUnlock()
 			for {
 				select {
-				case msg := <-ch:
-					f(msg)
+				case received := <-ch:
+					msg := received.Msg
+					start := time.Now()
+					span := startHandleSpan(received.Ctx, topic, msg)
+					err := f(msg)
+					if span != nil {
+						tracing.End(span, err)
+					}
+					metrics.ObserveHandle(topic, start, err)
+					acknowledge(msg, err)
 				case <-h.Ctx().Done():
 					logging.Log().Debug().Str("topic", topic).Int("agent", chId).Msg("functionality detatched from agent")
 					return
@@ -134,27 +373,69 @@ func AttatchFunctionalityRoundRobin(topic string, f func(*entities.Message), num
 	handler.Lock.Unlock()
 }
 
-func accumulateData(topic string, msg *entities.Message) {
+// Trace context of the headers of a received message, only extracted when the spans are exported
+func traceContext(header nats.Header) context.Context {
+	if !tracing.IsEnabled() {
+		return context.Background()
+	}
+	return tracing.Extract(context.Background(), header)
+}
+
+// Start the span of the handling of a message by an agent, as child of the span of the component that
+// published the message. Returns nil when the spans are not exported
+func startHandleSpan(ctx context.Context, topic string, msg *entities.Message) trace.Span {
+	if !tracing.IsEnabled() {
+		return nil
+	}
+	_, span := tracing.Start(ctx, "handle "+metrics.TopicRoot(topic),
+		attribute.String("topic", msg.Topic),
+		attribute.String("dataType", msg.DataType))
+	return span
+}
+
+// Accumulate the messages received on a data queue topic, batch messages must be unpacked
+func accumulateData(topic string, messages []*entities.Message) {
 	dataQueuesMutex.Lock()
-	if len(dataQueues[topic]) == 0 {
-		dataQueues[topic] = make([]*entities.Message, 0)
+	if dataQueues[topic] == nil {
+		dataQueues[topic] = make(map[uint64]*entities.Message)
+	}
+	for _, msg := range messages {
+		// Messages sent again by the producer have the same sequence number and are kept once
+		seq := msg.Sequence
+		if seq == 0 {
+			seq = uint64(len(dataQueues[topic])) + 1
+		}
+		dataQueues[topic][seq] = msg
 	}
-	dataQueues[topic] = append(dataQueues[topic], msg)
 	dataQueuesMutex.Unlock()
 }
 
-// DrainQueue drains a queue and returns all messages
+// DrainQueue drains a queue once its end message and all messages before it were received and returns
+// its messages in order, the entities of batch messages are returned as separate messages
 func DrainQueue(topic string) []*entities.Message {
-	_, queueCount := GetQueueComponents(topic)
 	dataQueuesMutex.Lock()
-	if len(dataQueues[topic]) < queueCount {
-		dataQueuesMutex.Unlock()
+	defer dataQueuesMutex.Unlock()
+	received := dataQueues[topic]
+	var end uint64
+	for seq, msg := range received {
+		if msg.GetEnd() {
+			end = seq
+			break
+		}
+	}
+	if end == 0 || uint64(len(received)) < end {
 		return nil
 	}
+	queue := make([]*entities.Message, 0, end-1)
+	for seq := uint64(1); seq < end; seq++ {
+		msg, ok := received[seq]
+		if !ok {
+			return nil
+		}
+		queue = append(queue, msg)
+	}
 	logging.Log().Debug().Str("topic", topic).Msg("received all messages for queue")
-	defer dataQueuesMutex.Unlock()
-	queue := dataQueues[topic]
-	dataQueues[topic] = []*entities.Message{}
+	delete(dataQueues, topic)
 	return queue
 }
 
@@ -0,0 +1,214 @@
+package subscriber_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"tradingplatform/shared/communication"
+	"tradingplatform/shared/communication/producer"
+	"tradingplatform/shared/communication/subscriber"
+	"tradingplatform/shared/entities"
+	"tradingplatform/shared/logging"
+	"tradingplatform/shared/types"
+	"tradingplatform/shared/utils"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/rs/zerolog"
+)
+
+func TestMain(m *testing.M) {
+	logger := zerolog.Nop()
+	logging.SetLogger(&logger)
+	os.Exit(m.Run())
+}
+
+// Start an embedded nats-server with JetStream enabled for the stream topics and return a JetStream
+// context to inspect it
+func startJetStream(t *testing.T) jetstream.JetStream {
+	t.Helper()
+	ns, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      -1,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go ns.Start()
+	if !ns.ReadyForConnections(5 * time.Second) {
+		t.Fatal("embedded nats-server not ready")
+	}
+	t.Cleanup(ns.Shutdown)
+
+	previousURL := communication.GetNatsURL()
+	previousCfg := communication.GetJetStreamConfig()
+	communication.SetNatsURL(ns.ClientURL())
+	cfg := previousCfg
+	cfg.Enabled = true
+	cfg.Storage = "memory"
+	cfg.RetryDelay = 50 * time.Millisecond
+	communication.SetJetStreamConfig(cfg)
+	t.Cleanup(func() {
+		communication.SetNatsURL(previousURL)
+		communication.SetJetStreamConfig(previousCfg)
+	})
+
+	nc, err := nats.Connect(ns.ClientURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(nc.Close)
+	js, err := jetstream.New(nc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return js
+}
+
+// Publish trades with the given ids on the stream topic of a symbol
+func publishTrades(topic string, symbol string, ids ...int64) {
+	for _, id := range ids {
+		trade := entities.Trade{
+			ID:          id,
+			Symbol:      symbol,
+			Price:       100,
+			Size:        1,
+			TimestampNs: time.Now().UnixNano(),
+			Source:      string(types.Synthetic),
+			AssetClass:  string(types.Stock),
+		}
+		trade.SetFingerprint()
+		msg := entities.GenerateMessage(&trade, types.Trades, topic)
+		producer.GetStreamHandler(msg.Topic).Ch <- msg
+	}
+}
+
+// Wait until a stream holds a number of messages
+func waitForMessages(t *testing.T, js jetstream.JetStream, stream string, count uint64) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		s, err := js.Stream(ctx, stream)
+		var msgs uint64
+		if err == nil {
+			var info *jetstream.StreamInfo
+			info, err = s.Info(ctx)
+			if err == nil {
+				msgs = info.State.Msgs
+			}
+		}
+		cancel()
+		if err == nil && msgs == count {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("stream %s holds %d messages instead of %d (%v)", stream, msgs, count, err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func streamName(t *testing.T, topic string) string {
+	t.Helper()
+	name, _, ok := communication.GetJetStreamStream(topic)
+	if !ok {
+		t.Fatalf("%s is not a jetstream topic", topic)
+	}
+	return name
+}
+
+func TestJetStreamRedeliversUntilHandledAndDeletesConsumerOnUnsubscribe(t *testing.T) {
+	js := startJetStream(t)
+	symbol := "REDELIVER"
+	topic := utils.NewStreamTopic(types.DataProvider, types.Synthetic, types.Stock, types.Trades, symbol).Generate()
+	subscribeTopic := utils.NewStreamTopic(types.DataProvider, types.Synthetic, types.Stock, types.Trades, "*").Generate()
+	stream := streamName(t, topic)
+
+	// The trades are published while no subscriber is running
+	const count = 20
+	ids := make([]int64, count)
+	for i := range ids {
+		ids[i] = int64(i)
+	}
+	publishTrades(topic, symbol, ids...)
+	producer.StopTopicHandler(topic[:strings.LastIndex(topic, ".")])
+	waitForMessages(t, js, stream, count)
+
+	const failures = 5
+	var attempts atomic.Int64
+	var handled sync.Map
+	var handledCount atomic.Int64
+	done := make(chan struct{})
+	subscriber.AttatchFunctionalityRoundRobin(subscribeTopic, func(msg *entities.Message) error {
+		if attempts.Add(1) <= failures {
+			return fmt.Errorf("simulated storage failure")
+		}
+		var trade entities.Trade
+		if err := utils.HandleEntity(msg, &trade, func(*entities.Trade) error { return nil }); err != nil {
+			return err
+		}
+		if _, loaded := handled.LoadOrStore(trade.ID, struct{}{}); !loaded && handledCount.Add(1) == count {
+			close(done)
+		}
+		return nil
+	}, 2)
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatalf("handled %d of %d trades", handledCount.Load(), count)
+	}
+	if attempts.Load() < count+failures {
+		t.Fatalf("expected the %d failed attempts to be redelivered, got %d attempts", failures, attempts.Load())
+	}
+
+	durable := subscriber.GetDurableName(subscribeTopic)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := js.Consumer(ctx, stream, durable); err != nil {
+		t.Fatalf("durable consumer missing while subscribed: %v", err)
+	}
+	subscriber.StopTopicHandler(subscribeTopic)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		_, err := js.Consumer(ctx, stream, durable)
+		if errors.Is(err, jetstream.ErrConsumerNotFound) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("durable consumer not deleted after unsubscribing: %v", err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestJetStreamPublishRecreatesDeletedStream(t *testing.T) {
+	js := startJetStream(t)
+	symbol := "RECREATE"
+	topic := utils.NewStreamTopic(types.DataProvider, types.Synthetic, types.Crypto, types.Trades, symbol).Generate()
+	stream := streamName(t, topic)
+	defer producer.StopTopicHandler(topic[:strings.LastIndex(topic, ".")])
+
+	publishTrades(topic, symbol, 1)
+	waitForMessages(t, js, stream, 1)
+
+	// The publisher still knows the stream as ensured, the failed publish must create it again
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := js.DeleteStream(ctx, stream); err != nil {
+		t.Fatal(err)
+	}
+	publishTrades(topic, symbol, 2)
+	waitForMessages(t, js, stream, 1)
+}
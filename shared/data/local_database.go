@@ -1,6 +1,8 @@
 package data
 
 import (
+	"context"
+	"fmt"
 	"sync"
 	"tradingplatform/shared/logging"
 
@@ -11,8 +13,14 @@ import (
 var LocalDB *gorm.DB
 var LocalDBLock sync.RWMutex
 
-func InitializeLocalDatabase(dst ...interface{}) (*gorm.DB, func()) {
-	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+// Initialize the local database of a component in a SQLite file so that its state survives restarts,
+// the database is kept in memory without path
+func InitializeLocalDatabase(path string, dst ...interface{}) (*gorm.DB, func()) {
+	dsn := path
+	if dsn == "" {
+		dsn = ":memory:"
+	}
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
 
 	if err != nil {
 		logging.Log().Error().Err(err).Msg("failed to connect to local database")
@@ -38,3 +46,15 @@ func InitializeLocalDatabase(dst ...interface{}) (*gorm.DB, func()) {
 
 	return db, cleanup
 }
+
+// Ping the local database, fails when the database is not initialized or does not answer
+func PingLocalDatabase(ctx context.Context) error {
+	if LocalDB == nil {
+		return fmt.Errorf("local database not initialized")
+	}
+	sqlDB, err := LocalDB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
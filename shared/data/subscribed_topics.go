@@ -1,6 +1,8 @@
 package data
 
 import (
+	"sort"
+
 	"tradingplatform/shared/logging"
 
 	"gorm.io/gorm/clause"
@@ -60,6 +62,16 @@ func GetSubscribedTopics() []SubscribedTopic {
 	return subscribedTopics
 }
 
+// Get the names of the subscribed topics
+func GetSubscribedTopicNames() []string {
+	topics := []string{}
+	for _, subscribedTopic := range GetSubscribedTopics() {
+		topics = append(topics, subscribedTopic.Topic)
+	}
+	sort.Strings(topics)
+	return topics
+}
+
 func GetSubscribedTopic(topic string) SubscribedTopic {
 	var subscribedTopic SubscribedTopic
 
@@ -29,11 +29,12 @@ type LULD struct {
 	LimitUpPrice   float64 `protobuf:"fixed64,2,opt,name=LimitUpPrice,proto3" json:"LimitUpPrice,omitempty"`
 	LimitDownPrice float64 `protobuf:"fixed64,3,opt,name=LimitDownPrice,proto3" json:"LimitDownPrice,omitempty"`
 	Indicator      string  `protobuf:"bytes,4,opt,name=Indicator,proto3" json:"Indicator,omitempty"`
-	Timestamp      int64   `protobuf:"varint,5,opt,name=Timestamp,proto3" json:"Timestamp,omitempty"`
+	Timestamp      int64   `protobuf:"varint,5,opt,name=Timestamp,proto3" json:"Timestamp,omitempty"` // Unix seconds, see TimestampNs
 	Tape           string  `protobuf:"bytes,6,opt,name=Tape,proto3" json:"Tape,omitempty"`
 	Fingerprint    string  `protobuf:"bytes,7,opt,name=Fingerprint,proto3" json:"Fingerprint,omitempty"`
 	Source         string  `protobuf:"bytes,8,opt,name=Source,proto3" json:"Source,omitempty"`
 	AssetClass     string  `protobuf:"bytes,9,opt,name=AssetClass,proto3" json:"AssetClass,omitempty"`
+	TimestampNs    int64   `protobuf:"varint,10,opt,name=TimestampNs,proto3" json:"TimestampNs,omitempty"` // Unix nanoseconds
 }
 
 func (x *LULD) Reset() {
@@ -131,11 +132,18 @@ func (x *LULD) GetAssetClass() string {
 	return ""
 }
 
+func (x *LULD) GetTimestampNs() int64 {
+	if x != nil {
+		return x.TimestampNs
+	}
+	return 0
+}
+
 var File_proto_LULD_proto protoreflect.FileDescriptor
 
 var file_proto_LULD_proto_rawDesc = []byte{
 	0x0a, 0x10, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x4c, 0x55, 0x4c, 0x44, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x12, 0x08, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x22, 0x94, 0x02, 0x0a,
+	0x74, 0x6f, 0x12, 0x08, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x22, 0xb6, 0x02, 0x0a,
 	0x04, 0x4c, 0x55, 0x4c, 0x44, 0x12, 0x16, 0x0a, 0x06, 0x53, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x18,
 	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x53, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x12, 0x22, 0x0a,
 	0x0c, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x55, 0x70, 0x50, 0x72, 0x69, 0x63, 0x65, 0x18, 0x02, 0x20,
@@ -153,8 +161,10 @@ var file_proto_LULD_proto_rawDesc = []byte{
 	0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x53, 0x6f, 0x75,
 	0x72, 0x63, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x41, 0x73, 0x73, 0x65, 0x74, 0x43, 0x6c, 0x61, 0x73,
 	0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x41, 0x73, 0x73, 0x65, 0x74, 0x43, 0x6c,
-	0x61, 0x73, 0x73, 0x42, 0x0b, 0x5a, 0x09, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x2f,
-	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x61, 0x73, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x4e, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x4e, 0x73, 0x42, 0x0b, 0x5a, 0x09, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65,
+	0x73, 0x2f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
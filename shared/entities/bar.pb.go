@@ -33,12 +33,13 @@ type Bar struct {
 	Close       float64 `protobuf:"fixed64,6,opt,name=Close,proto3" json:"Close,omitempty"`
 	Volume      float64 `protobuf:"fixed64,7,opt,name=Volume,proto3" json:"Volume,omitempty"`
 	VWAP        float64 `protobuf:"fixed64,8,opt,name=VWAP,proto3" json:"VWAP,omitempty"`
-	Timestamp   int64   `protobuf:"varint,9,opt,name=Timestamp,proto3" json:"Timestamp,omitempty"`
+	Timestamp   int64   `protobuf:"varint,9,opt,name=Timestamp,proto3" json:"Timestamp,omitempty"` // Unix seconds, see TimestampNs
 	TradeCount  uint64  `protobuf:"varint,10,opt,name=TradeCount,proto3" json:"TradeCount,omitempty"`
 	Fingerprint string  `protobuf:"bytes,11,opt,name=Fingerprint,proto3" json:"Fingerprint,omitempty"`
 	Source      string  `protobuf:"bytes,12,opt,name=Source,proto3" json:"Source,omitempty"`
 	AssetClass  string  `protobuf:"bytes,13,opt,name=AssetClass,proto3" json:"AssetClass,omitempty"`
 	Timeframe   string  `protobuf:"bytes,14,opt,name=Timeframe,proto3" json:"Timeframe,omitempty"`
+	TimestampNs int64   `protobuf:"varint,15,opt,name=TimestampNs,proto3" json:"TimestampNs,omitempty"` // Unix nanoseconds
 }
 
 func (x *Bar) Reset() {
@@ -171,11 +172,18 @@ func (x *Bar) GetTimeframe() string {
 	return ""
 }
 
+func (x *Bar) GetTimestampNs() int64 {
+	if x != nil {
+		return x.TimestampNs
+	}
+	return 0
+}
+
 var File_proto_bar_proto protoreflect.FileDescriptor
 
 var file_proto_bar_proto_rawDesc = []byte{
 	0x0a, 0x0f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x62, 0x61, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x12, 0x08, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x22, 0xeb, 0x02, 0x0a, 0x03,
+	0x6f, 0x12, 0x08, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x22, 0x8d, 0x03, 0x0a, 0x03,
 	0x42, 0x61, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x53, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x18, 0x01, 0x20,
 	0x01, 0x28, 0x09, 0x52, 0x06, 0x53, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x12, 0x1a, 0x0a, 0x08, 0x45,
 	0x78, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x45,
@@ -198,8 +206,10 @@ var file_proto_bar_proto_rawDesc = []byte{
 	0x73, 0x73, 0x65, 0x74, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52,
 	0x0a, 0x41, 0x73, 0x73, 0x65, 0x74, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x54,
 	0x69, 0x6d, 0x65, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
-	0x54, 0x69, 0x6d, 0x65, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x42, 0x0b, 0x5a, 0x09, 0x65, 0x6e, 0x74,
-	0x69, 0x74, 0x69, 0x65, 0x73, 0x2f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x54, 0x69, 0x6d, 0x65, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x4e, 0x73, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x4e, 0x73, 0x42, 0x0b, 0x5a, 0x09, 0x65,
+	0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x2f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -0,0 +1,59 @@
+package entities
+
+import (
+	"fmt"
+
+	"tradingplatform/shared/types"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// NewEntity returns an empty entity of the type carried by the messages of a data type
+func NewEntity(dataType types.DataType) (proto.Message, error) {
+	switch dataType {
+	case types.Bar, types.DailyBars, types.UpdatedBars:
+		return &Bar{}, nil
+	case types.Trades, types.TradeUpdates:
+		return &Trade{}, nil
+	case types.Quotes:
+		return &Quote{}, nil
+	case types.Orderbook:
+		return &Orderbook{}, nil
+	case types.LULD:
+		return &LULD{}, nil
+	case types.Status:
+		return &TradingStatus{}, nil
+	case types.RawText, types.NewsWithSentiment:
+		return &News{}, nil
+	case types.Sentiment:
+		return &NewsSentiment{}, nil
+	case types.Indicator:
+		return &Indicator{}, nil
+	case types.Orders:
+		return &Order{}, nil
+	case types.Fills:
+		return &Fill{}, nil
+	case types.Positions:
+		return &Position{}, nil
+	case types.Connection:
+		return &ConnectionStatus{}, nil
+	}
+	return nil, fmt.Errorf("no entity for data type %s", dataType)
+}
+
+// Decode unmarshals the entity of a message according to its data type, batch messages have to be
+// unpacked first
+func (m *Message) Decode() (proto.Message, error) {
+	if m.IsBatch() {
+		return nil, fmt.Errorf("batch message of %d entities on %s has to be unpacked", m.EntityCount(), m.Topic)
+	}
+	entity, err := NewEntity(types.DataType(m.DataType))
+	if err != nil {
+		return nil, err
+	}
+	if err := proto.Unmarshal(m.Payload, entity); err != nil {
+		return nil, err
+	}
+	SyncTimestamps(entity)
+	return entity, nil
+}
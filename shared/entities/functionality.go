@@ -4,6 +4,8 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"math"
+	"time"
 	"tradingplatform/shared/types"
 
 	"github.com/rs/zerolog/log"
@@ -31,40 +33,104 @@ type Payloader interface {
 }
 
 func (b *Bar) SetFingerprint() {
+	b.SyncTimestamps()
 	b.Fingerprint, _ = HashStruct(b)
 }
 
 func (s *NewsSentiment) SetFingerprint() {
-	oldTimestamp := s.Timestamp
-	s.Timestamp = 0
+	oldTimestamp, oldTimestampNs := s.Timestamp, s.TimestampNs
+	s.Timestamp, s.TimestampNs = 0, 0
 	s.Fingerprint, _ = HashStruct(s)
-	s.Timestamp = oldTimestamp
+	s.Timestamp, s.TimestampNs = oldTimestamp, oldTimestampNs
 }
 
 func (o *Orderbook) SetFingerprint() {
+	o.SyncTimestamps()
 	o.Fingerprint, _ = HashStruct(o)
 }
 
 func (q *Quote) SetFingerprint() {
+	q.SyncTimestamps()
 	q.Fingerprint, _ = HashStruct(q)
 }
 
 func (t *Trade) SetFingerprint() {
+	t.SyncTimestamps()
 	t.Fingerprint, _ = HashStruct(t)
 }
 
 func (l *LULD) SetFingerprint() {
+	l.SyncTimestamps()
 	l.Fingerprint, _ = HashStruct(l)
 }
 
 func (s *TradingStatus) SetFingerprint() {
+	s.SyncTimestamps()
 	s.Fingerprint, _ = HashStruct(s)
 }
 
 func (n *News) SetFingerprint() {
+	n.SyncTimestamps()
 	n.Fingerprint, _ = HashStruct(n)
 }
 
+// The fingerprint of an indicator identifies the bar it was computed at, not its value, so that a
+// value computed again replaces the stored one
+func (i *Indicator) SetFingerprint() {
+	value, components := i.Value, i.Components
+	i.Value, i.Components = 0, nil
+	i.Fingerprint, _ = HashStruct(i)
+	i.Value, i.Components = value, components
+}
+
+// Orders and positions are published again on every change, each state is a distinct entity
+func (o *Order) SetFingerprint() {
+	o.Fingerprint = ""
+	o.Fingerprint, _ = HashStruct(o)
+}
+
+func (f *Fill) SetFingerprint() {
+	f.Fingerprint = ""
+	f.Fingerprint, _ = HashStruct(f)
+}
+
+func (p *Position) SetFingerprint() {
+	p.Fingerprint = ""
+	p.Fingerprint, _ = HashStruct(p)
+}
+
+func (c *ConnectionStatus) SetFingerprint() {
+	c.Fingerprint = ""
+	c.Fingerprint, _ = HashStruct(c)
+}
+
+// Apply a fill of a signed quantity (negative when selling) to the position, returns the profit and
+// loss realized by the part of the fill closing the position
+func (p *Position) Apply(quantity float64, price float64) float64 {
+	if p.Quantity == 0 || (p.Quantity > 0) == (quantity > 0) {
+		total := math.Abs(p.Quantity) + math.Abs(quantity)
+		p.AveragePrice = (p.AveragePrice*math.Abs(p.Quantity) + price*math.Abs(quantity)) / total
+		p.Quantity += quantity
+		return 0
+	}
+	closed := math.Min(math.Abs(quantity), math.Abs(p.Quantity))
+	realized := closed * (price - p.AveragePrice)
+	if p.Quantity < 0 {
+		realized = -realized
+	}
+	remaining := p.Quantity + quantity
+	switch {
+	case remaining == 0:
+		p.AveragePrice = 0
+	case (remaining > 0) != (p.Quantity > 0):
+		// The position was reversed, the rest of the fill opens the new one
+		p.AveragePrice = price
+	}
+	p.Quantity = remaining
+	p.RealizedPnL += realized
+	return realized
+}
+
 func (b *Bar) SetSource(source string) {
 	b.Source = source
 }
@@ -105,6 +171,26 @@ func (n *News) SetSource(source string) {
 	n.Source = source
 }
 
+func (i *Indicator) SetSource(source string) {
+	i.Source = source
+}
+
+func (o *Order) SetSource(source string) {
+	o.Source = source
+}
+
+func (f *Fill) SetSource(source string) {
+	f.Source = source
+}
+
+func (p *Position) SetSource(source string) {
+	p.Source = source
+}
+
+func (c *ConnectionStatus) SetSource(source string) {
+	c.Source = source
+}
+
 func (b *Bar) SetExchange(exchange string) {
 	b.Exchange = exchange
 }
@@ -121,6 +207,65 @@ func (t *Trade) SetExchange(exchange string) {
 	t.Exchange = exchange
 }
 
+// The market data entities carry their timestamps in Unix nanoseconds in the *Ns fields, the
+// fields of the previous schema keep the Unix seconds for the consumers that still read them
+type TimestampSyncable interface {
+	SyncTimestamps()
+}
+
+// Fill in the seconds from the nanoseconds, or the nanoseconds from the seconds for the entities
+// of producers of the previous schema that only carry the seconds
+func syncTimestamp(seconds *int64, nanoseconds *int64) {
+	if *nanoseconds == 0 && *seconds != 0 {
+		*nanoseconds = *seconds * int64(time.Second)
+		return
+	}
+	*seconds = time.Unix(0, *nanoseconds).Unix()
+}
+
+// Sync the timestamps of an entity after it is unmarshalled, other entities are left as is
+func SyncTimestamps(entity any) {
+	if syncable, ok := entity.(TimestampSyncable); ok {
+		syncable.SyncTimestamps()
+	}
+}
+
+func (b *Bar) SyncTimestamps() {
+	syncTimestamp(&b.Timestamp, &b.TimestampNs)
+}
+
+func (o *Orderbook) SyncTimestamps() {
+	syncTimestamp(&o.Timestamp, &o.TimestampNs)
+}
+
+func (q *Quote) SyncTimestamps() {
+	syncTimestamp(&q.Timestamp, &q.TimestampNs)
+}
+
+func (t *Trade) SyncTimestamps() {
+	syncTimestamp(&t.Timestamp, &t.TimestampNs)
+}
+
+func (l *LULD) SyncTimestamps() {
+	syncTimestamp(&l.Timestamp, &l.TimestampNs)
+}
+
+func (s *TradingStatus) SyncTimestamps() {
+	syncTimestamp(&s.Timestamp, &s.TimestampNs)
+}
+
+func (n *News) SyncTimestamps() {
+	syncTimestamp(&n.CreatedAt, &n.CreatedAtNs)
+	syncTimestamp(&n.UpdatedAt, &n.UpdatedAtNs)
+	for _, sentiment := range n.Sentiments {
+		sentiment.SyncTimestamps()
+	}
+}
+
+func (s *NewsSentiment) SyncTimestamps() {
+	syncTimestamp(&s.Timestamp, &s.TimestampNs)
+}
+
 type TimeframeSettable interface {
 	SetTimeframe(string)
 }
@@ -158,6 +303,7 @@ func GenerateJson(p ProtoReflectable) []byte {
 }
 
 func (b *Bar) ToPayload() []byte {
+	b.SyncTimestamps()
 	return GeneratePayload(b)
 }
 
@@ -166,33 +312,60 @@ func (b *Bar) SetTimeframe(timeFrame string) {
 }
 
 func (o *Orderbook) ToPayload() []byte {
+	o.SyncTimestamps()
 	return GeneratePayload(o)
 }
 
 func (o *Quote) ToPayload() []byte {
+	o.SyncTimestamps()
 	return GeneratePayload(o)
 }
 
 func (o *Trade) ToPayload() []byte {
+	o.SyncTimestamps()
 	return GeneratePayload(o)
 }
 
 func (l *LULD) ToPayload() []byte {
+	l.SyncTimestamps()
 	return GeneratePayload(l)
 }
 
 func (s *TradingStatus) ToPayload() []byte {
+	s.SyncTimestamps()
 	return GeneratePayload(s)
 }
 
 func (n *News) ToPayload() []byte {
+	n.SyncTimestamps()
 	return GeneratePayload(n)
 }
 
 func (n *NewsSentiment) ToPayload() []byte {
+	n.SyncTimestamps()
 	return GeneratePayload(n)
 }
 
+func (i *Indicator) ToPayload() []byte {
+	return GeneratePayload(i)
+}
+
+func (o *Order) ToPayload() []byte {
+	return GeneratePayload(o)
+}
+
+func (f *Fill) ToPayload() []byte {
+	return GeneratePayload(f)
+}
+
+func (p *Position) ToPayload() []byte {
+	return GeneratePayload(p)
+}
+
+func (c *ConnectionStatus) ToPayload() []byte {
+	return GeneratePayload(c)
+}
+
 func GenerateMessage(p Payloader, entityType types.DataType, topic string) *Message {
 	payload := p.ToPayload()
 	msg := Message{
@@ -202,3 +375,87 @@ func GenerateMessage(p Payloader, entityType types.DataType, topic string) *Mess
 	}
 	return &msg
 }
+
+// GenerateBatchMessage packs the payloads of messages of the same data type into a single message.
+// The batch takes the sequence number of its first message, the following messages are numbered
+// consecutively when it is unpacked
+func GenerateBatchMessage(topic string, messages []*Message) *Message {
+	batch := PayloadBatch{
+		Count:    uint32(len(messages)),
+		Payloads: make([][]byte, 0, len(messages)),
+	}
+	var sequence uint64
+	if len(messages) > 0 {
+		batch.DataType = messages[0].DataType
+		sequence = messages[0].Sequence
+	}
+	for _, m := range messages {
+		batch.Payloads = append(batch.Payloads, m.Payload)
+	}
+	return &Message{
+		Topic:    topic,
+		DataType: batch.DataType,
+		Sequence: sequence,
+		Batch:    &batch,
+	}
+}
+
+// GenerateEndMessage generates the message marking the end of a data queue, it takes the sequence
+// number following the last message of the queue
+func GenerateEndMessage(topic string, dataType string, sequence uint64) *Message {
+	return &Message{
+		Topic:    topic,
+		DataType: dataType,
+		Sequence: sequence,
+		End:      true,
+	}
+}
+
+// IsBatch returns true if the message packs several entities
+func (m *Message) IsBatch() bool {
+	return m.GetBatch() != nil
+}
+
+// EntityCount returns the number of entities carried by the message
+func (m *Message) EntityCount() int {
+	if !m.IsBatch() {
+		return 1
+	}
+	return len(m.Batch.Payloads)
+}
+
+// Unpack returns the messages packed in a batch message, a message that is not a batch is
+// returned as is
+func (m *Message) Unpack() []*Message {
+	if !m.IsBatch() {
+		return []*Message{m}
+	}
+	if int(m.Batch.Count) != len(m.Batch.Payloads) {
+		log.Warn().
+			Str("topic", m.Topic).
+			Uint32("count", m.Batch.Count).
+			Int("payloads", len(m.Batch.Payloads)).
+			Msg("batch count does not match its payloads")
+	}
+	messages := make([]*Message, len(m.Batch.Payloads))
+	for i, payload := range m.Batch.Payloads {
+		messages[i] = &Message{
+			Topic:    m.Topic,
+			Payload:  payload,
+			DataType: m.Batch.DataType,
+		}
+		if m.Sequence != 0 {
+			messages[i].Sequence = m.Sequence + uint64(i)
+		}
+	}
+	return messages
+}
+
+// UnpackAll unpacks the batch messages among messages, keeping their order
+func UnpackAll(messages []*Message) []*Message {
+	unpacked := make([]*Message, 0, len(messages))
+	for _, m := range messages {
+		unpacked = append(unpacked, m.Unpack()...)
+	}
+	return unpacked
+}
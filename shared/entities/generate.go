@@ -0,0 +1,6 @@
+package entities
+
+// The entities are generated from the .proto files in shared/proto, the schema is checked for
+// breaking changes against shared/proto/schema.lock.json before generating.
+// Run with -update-lock after an intentional schema change to record it.
+//go:generate go run -C ../../tools/protogen . -root ../../shared
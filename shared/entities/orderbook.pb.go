@@ -90,13 +90,14 @@ type Orderbook struct {
 
 	Symbol      string            `protobuf:"bytes,1,opt,name=Symbol,proto3" json:"Symbol,omitempty"`
 	Exchange    string            `protobuf:"bytes,2,opt,name=Exchange,proto3" json:"Exchange,omitempty"`
-	Timestamp   int64             `protobuf:"varint,3,opt,name=Timestamp,proto3" json:"Timestamp,omitempty"`
+	Timestamp   int64             `protobuf:"varint,3,opt,name=Timestamp,proto3" json:"Timestamp,omitempty"` // Unix seconds, see TimestampNs
 	Asks        []*OrderbookEntry `protobuf:"bytes,4,rep,name=Asks,proto3" json:"Asks,omitempty"`
 	Bids        []*OrderbookEntry `protobuf:"bytes,5,rep,name=Bids,proto3" json:"Bids,omitempty"`
 	Reset_      bool              `protobuf:"varint,6,opt,name=Reset,proto3" json:"Reset,omitempty"`
 	Fingerprint string            `protobuf:"bytes,7,opt,name=Fingerprint,proto3" json:"Fingerprint,omitempty"`
 	Source      string            `protobuf:"bytes,8,opt,name=Source,proto3" json:"Source,omitempty"`
 	AssetClass  string            `protobuf:"bytes,9,opt,name=AssetClass,proto3" json:"AssetClass,omitempty"`
+	TimestampNs int64             `protobuf:"varint,10,opt,name=TimestampNs,proto3" json:"TimestampNs,omitempty"` // Unix nanoseconds
 }
 
 func (x *Orderbook) Reset() {
@@ -194,6 +195,13 @@ func (x *Orderbook) GetAssetClass() string {
 	return ""
 }
 
+func (x *Orderbook) GetTimestampNs() int64 {
+	if x != nil {
+		return x.TimestampNs
+	}
+	return 0
+}
+
 var File_proto_orderbook_proto protoreflect.FileDescriptor
 
 var file_proto_orderbook_proto_rawDesc = []byte{
@@ -204,7 +212,7 @@ var file_proto_orderbook_proto_rawDesc = []byte{
 	0x28, 0x01, 0x52, 0x05, 0x50, 0x72, 0x69, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x53, 0x69, 0x7a,
 	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x16, 0x0a,
 	0x06, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x53,
-	0x6f, 0x75, 0x72, 0x63, 0x65, 0x22, 0xa9, 0x02, 0x0a, 0x09, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x62,
+	0x6f, 0x75, 0x72, 0x63, 0x65, 0x22, 0xcb, 0x02, 0x0a, 0x09, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x62,
 	0x6f, 0x6f, 0x6b, 0x12, 0x16, 0x0a, 0x06, 0x53, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x18, 0x01, 0x20,
 	0x01, 0x28, 0x09, 0x52, 0x06, 0x53, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x12, 0x1a, 0x0a, 0x08, 0x45,
 	0x78, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x45,
@@ -223,8 +231,10 @@ var file_proto_orderbook_proto_rawDesc = []byte{
 	0x72, 0x63, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x53, 0x6f, 0x75, 0x72, 0x63,
 	0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x41, 0x73, 0x73, 0x65, 0x74, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x18,
 	0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x41, 0x73, 0x73, 0x65, 0x74, 0x43, 0x6c, 0x61, 0x73,
-	0x73, 0x42, 0x0b, 0x5a, 0x09, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x2f, 0x62, 0x06,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x73, 0x12, 0x20, 0x0a, 0x0b, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x4e, 0x73,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x4e, 0x73, 0x42, 0x0b, 0x5a, 0x09, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x2f,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -0,0 +1,43 @@
+package entities
+
+import (
+	"encoding/json"
+
+	"tradingplatform/shared/types"
+)
+
+// Response of the execution component with the orders and positions of an account
+type OrderResponse struct {
+	types.Response
+	Orders    []*Order
+	Positions []*Position
+}
+
+func NewOrderError(err error) OrderResponse {
+	return NewOrderResponse(types.Failure, "", err, nil, nil)
+}
+
+func NewOrderResponse(status types.OpStatus,
+	message string,
+	err error,
+	orders []*Order,
+	positions []*Position) OrderResponse {
+	return OrderResponse{
+		Response:  types.NewResponse(status, message, err),
+		Orders:    orders,
+		Positions: positions,
+	}
+}
+
+func (r OrderResponse) Respond() string {
+	response, err := json.Marshal(r)
+	if err != nil {
+		marshalError := &types.Response{
+			Err:     "Error marshalling response",
+			Message: "",
+			Status:  types.Failure,
+		}
+		response, _ = json.Marshal(marshalError)
+	}
+	return string(response)
+}
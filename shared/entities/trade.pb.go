@@ -30,7 +30,7 @@ type Trade struct {
 	Exchange    string   `protobuf:"bytes,3,opt,name=Exchange,proto3" json:"Exchange,omitempty"`
 	Price       float64  `protobuf:"fixed64,4,opt,name=Price,proto3" json:"Price,omitempty"`
 	Size        float64  `protobuf:"fixed64,5,opt,name=Size,proto3" json:"Size,omitempty"`
-	Timestamp   int64    `protobuf:"varint,6,opt,name=Timestamp,proto3" json:"Timestamp,omitempty"`
+	Timestamp   int64    `protobuf:"varint,6,opt,name=Timestamp,proto3" json:"Timestamp,omitempty"` // Unix seconds, see TimestampNs
 	TakerSide   string   `protobuf:"bytes,7,opt,name=TakerSide,proto3" json:"TakerSide,omitempty"`
 	Conditions  []string `protobuf:"bytes,8,rep,name=Conditions,proto3" json:"Conditions,omitempty"`
 	Tape        string   `protobuf:"bytes,9,opt,name=Tape,proto3" json:"Tape,omitempty"`
@@ -38,6 +38,7 @@ type Trade struct {
 	Update      string   `protobuf:"bytes,11,opt,name=Update,proto3" json:"Update,omitempty"`
 	Source      string   `protobuf:"bytes,13,opt,name=Source,proto3" json:"Source,omitempty"`
 	AssetClass  string   `protobuf:"bytes,14,opt,name=AssetClass,proto3" json:"AssetClass,omitempty"`
+	TimestampNs int64    `protobuf:"varint,15,opt,name=TimestampNs,proto3" json:"TimestampNs,omitempty"` // Unix nanoseconds
 }
 
 func (x *Trade) Reset() {
@@ -163,11 +164,18 @@ func (x *Trade) GetAssetClass() string {
 	return ""
 }
 
+func (x *Trade) GetTimestampNs() int64 {
+	if x != nil {
+		return x.TimestampNs
+	}
+	return 0
+}
+
 var File_proto_trade_proto protoreflect.FileDescriptor
 
 var file_proto_trade_proto_rawDesc = []byte{
 	0x0a, 0x11, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x74, 0x72, 0x61, 0x64, 0x65, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x12, 0x08, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x22, 0xd7, 0x02,
+	0x6f, 0x74, 0x6f, 0x12, 0x08, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x22, 0xf9, 0x02,
 	0x0a, 0x05, 0x54, 0x72, 0x61, 0x64, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x44, 0x18, 0x01, 0x20,
 	0x01, 0x28, 0x03, 0x52, 0x02, 0x49, 0x44, 0x12, 0x16, 0x0a, 0x06, 0x53, 0x79, 0x6d, 0x62, 0x6f,
 	0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x53, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x12,
@@ -189,8 +197,10 @@ var file_proto_trade_proto_rawDesc = []byte{
 	0x16, 0x0a, 0x06, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52,
 	0x06, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x41, 0x73, 0x73, 0x65, 0x74,
 	0x43, 0x6c, 0x61, 0x73, 0x73, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x41, 0x73, 0x73,
-	0x65, 0x74, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x42, 0x0b, 0x5a, 0x09, 0x65, 0x6e, 0x74, 0x69, 0x74,
-	0x69, 0x65, 0x73, 0x2f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x65, 0x74, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x4e, 0x73, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x4e, 0x73, 0x42, 0x0b, 0x5a, 0x09, 0x65, 0x6e, 0x74,
+	0x69, 0x74, 0x69, 0x65, 0x73, 0x2f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -30,11 +30,12 @@ type TradingStatus struct {
 	StatusMsg   string `protobuf:"bytes,3,opt,name=StatusMsg,proto3" json:"StatusMsg,omitempty"`
 	ReasonCode  string `protobuf:"bytes,4,opt,name=ReasonCode,proto3" json:"ReasonCode,omitempty"`
 	ReasonMsg   string `protobuf:"bytes,5,opt,name=ReasonMsg,proto3" json:"ReasonMsg,omitempty"`
-	Timestamp   int64  `protobuf:"varint,6,opt,name=Timestamp,proto3" json:"Timestamp,omitempty"`
+	Timestamp   int64  `protobuf:"varint,6,opt,name=Timestamp,proto3" json:"Timestamp,omitempty"` // Unix seconds, see TimestampNs
 	Tape        string `protobuf:"bytes,7,opt,name=Tape,proto3" json:"Tape,omitempty"`
 	Fingerprint string `protobuf:"bytes,8,opt,name=Fingerprint,proto3" json:"Fingerprint,omitempty"`
 	Source      string `protobuf:"bytes,9,opt,name=Source,proto3" json:"Source,omitempty"`
 	AssetClass  string `protobuf:"bytes,10,opt,name=AssetClass,proto3" json:"AssetClass,omitempty"`
+	TimestampNs int64  `protobuf:"varint,11,opt,name=TimestampNs,proto3" json:"TimestampNs,omitempty"` // Unix nanoseconds
 }
 
 func (x *TradingStatus) Reset() {
@@ -139,12 +140,19 @@ func (x *TradingStatus) GetAssetClass() string {
 	return ""
 }
 
+func (x *TradingStatus) GetTimestampNs() int64 {
+	if x != nil {
+		return x.TimestampNs
+	}
+	return 0
+}
+
 var File_proto_tradingstatus_proto protoreflect.FileDescriptor
 
 var file_proto_tradingstatus_proto_rawDesc = []byte{
 	0x0a, 0x19, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x74, 0x72, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x73,
 	0x74, 0x61, 0x74, 0x75, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x08, 0x65, 0x6e, 0x74,
-	0x69, 0x74, 0x69, 0x65, 0x73, 0x22, 0xaf, 0x02, 0x0a, 0x0d, 0x54, 0x72, 0x61, 0x64, 0x69, 0x6e,
+	0x69, 0x74, 0x69, 0x65, 0x73, 0x22, 0xd1, 0x02, 0x0a, 0x0d, 0x54, 0x72, 0x61, 0x64, 0x69, 0x6e,
 	0x67, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x53, 0x79, 0x6d, 0x62, 0x6f,
 	0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x53, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x12,
 	0x1e, 0x0a, 0x0a, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20,
@@ -163,8 +171,10 @@ var file_proto_tradingstatus_proto_rawDesc = []byte{
 	0x16, 0x0a, 0x06, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52,
 	0x06, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x41, 0x73, 0x73, 0x65, 0x74,
 	0x43, 0x6c, 0x61, 0x73, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x41, 0x73, 0x73,
-	0x65, 0x74, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x42, 0x0b, 0x5a, 0x09, 0x65, 0x6e, 0x74, 0x69, 0x74,
-	0x69, 0x65, 0x73, 0x2f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x65, 0x74, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x4e, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x4e, 0x73, 0x42, 0x0b, 0x5a, 0x09, 0x65, 0x6e, 0x74,
+	0x69, 0x74, 0x69, 0x65, 0x73, 0x2f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -25,9 +25,12 @@ type Message struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Topic    string `protobuf:"bytes,1,opt,name=Topic,proto3" json:"Topic,omitempty"`
-	Payload  []byte `protobuf:"bytes,2,opt,name=Payload,proto3" json:"Payload,omitempty"`
-	DataType string `protobuf:"bytes,3,opt,name=DataType,proto3" json:"DataType,omitempty"`
+	Topic    string        `protobuf:"bytes,1,opt,name=Topic,proto3" json:"Topic,omitempty"`
+	Payload  []byte        `protobuf:"bytes,2,opt,name=Payload,proto3" json:"Payload,omitempty"`
+	DataType string        `protobuf:"bytes,3,opt,name=DataType,proto3" json:"DataType,omitempty"`
+	Sequence uint64        `protobuf:"varint,4,opt,name=Sequence,proto3" json:"Sequence,omitempty"` // Position of the message in its data queue, starting at 1
+	Batch    *PayloadBatch `protobuf:"bytes,5,opt,name=Batch,proto3" json:"Batch,omitempty"`        // Set instead of Payload when the message packs several entities
+	End      bool          `protobuf:"varint,6,opt,name=End,proto3" json:"End,omitempty"`           // Marks the end of its data queue, the message carries no entity
 }
 
 func (x *Message) Reset() {
@@ -83,19 +86,115 @@ func (x *Message) GetDataType() string {
 	return ""
 }
 
+func (x *Message) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *Message) GetBatch() *PayloadBatch {
+	if x != nil {
+		return x.Batch
+	}
+	return nil
+}
+
+func (x *Message) GetEnd() bool {
+	if x != nil {
+		return x.End
+	}
+	return false
+}
+
+type PayloadBatch struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DataType string   `protobuf:"bytes,1,opt,name=DataType,proto3" json:"DataType,omitempty"`
+	Count    uint32   `protobuf:"varint,2,opt,name=Count,proto3" json:"Count,omitempty"`
+	Payloads [][]byte `protobuf:"bytes,3,rep,name=Payloads,proto3" json:"Payloads,omitempty"`
+}
+
+func (x *PayloadBatch) Reset() {
+	*x = PayloadBatch{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_transmission_message_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PayloadBatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayloadBatch) ProtoMessage() {}
+
+func (x *PayloadBatch) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_transmission_message_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayloadBatch.ProtoReflect.Descriptor instead.
+func (*PayloadBatch) Descriptor() ([]byte, []int) {
+	return file_proto_transmission_message_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PayloadBatch) GetDataType() string {
+	if x != nil {
+		return x.DataType
+	}
+	return ""
+}
+
+func (x *PayloadBatch) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *PayloadBatch) GetPayloads() [][]byte {
+	if x != nil {
+		return x.Payloads
+	}
+	return nil
+}
+
 var File_proto_transmission_message_proto protoreflect.FileDescriptor
 
 var file_proto_transmission_message_proto_rawDesc = []byte{
 	0x0a, 0x20, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x6d, 0x69, 0x73,
 	0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x12, 0x08, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x22, 0x55, 0x0a, 0x07,
-	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x54, 0x6f, 0x70, 0x69, 0x63,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x54, 0x6f, 0x70, 0x69, 0x63, 0x12, 0x18, 0x0a,
-	0x07, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07,
-	0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x44, 0x61, 0x74, 0x61, 0x54,
-	0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x44, 0x61, 0x74, 0x61, 0x54,
-	0x79, 0x70, 0x65, 0x42, 0x0b, 0x5a, 0x09, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x2f,
-	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x74, 0x6f, 0x12, 0x08, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x22, 0xb1, 0x01, 0x0a,
+	0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x54, 0x6f, 0x70, 0x69,
+	0x63, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x54, 0x6f, 0x70, 0x69, 0x63, 0x12, 0x18,
+	0x0a, 0x07, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x07, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x44, 0x61, 0x74, 0x61,
+	0x54, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x44, 0x61, 0x74, 0x61,
+	0x54, 0x79, 0x70, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x53, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x53, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65,
+	0x12, 0x2c, 0x0a, 0x05, 0x42, 0x61, 0x74, 0x63, 0x68, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x16, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x2e, 0x50, 0x61, 0x79, 0x6c, 0x6f,
+	0x61, 0x64, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x05, 0x42, 0x61, 0x74, 0x63, 0x68, 0x12, 0x10,
+	0x0a, 0x03, 0x45, 0x6e, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x03, 0x45, 0x6e, 0x64,
+	0x22, 0x5c, 0x0a, 0x0c, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x74, 0x63, 0x68,
+	0x12, 0x1a, 0x0a, 0x08, 0x44, 0x61, 0x74, 0x61, 0x54, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x44, 0x61, 0x74, 0x61, 0x54, 0x79, 0x70, 0x65, 0x12, 0x14, 0x0a, 0x05,
+	0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x43, 0x6f, 0x75,
+	0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x73, 0x18, 0x03,
+	0x20, 0x03, 0x28, 0x0c, 0x52, 0x08, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x73, 0x42, 0x0b,
+	0x5a, 0x09, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x2f, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
 }
 
 var (
@@ -110,16 +209,18 @@ func file_proto_transmission_message_proto_rawDescGZIP() []byte {
 	return file_proto_transmission_message_proto_rawDescData
 }
 
-var file_proto_transmission_message_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_proto_transmission_message_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
 var file_proto_transmission_message_proto_goTypes = []interface{}{
-	(*Message)(nil), // 0: entities.Message
+	(*Message)(nil),      // 0: entities.Message
+	(*PayloadBatch)(nil), // 1: entities.PayloadBatch
 }
 var file_proto_transmission_message_proto_depIdxs = []int32{
-	0, // [0:0] is the sub-list for method output_type
-	0, // [0:0] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	1, // 0: entities.Message.Batch:type_name -> entities.PayloadBatch
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_proto_transmission_message_proto_init() }
@@ -140,6 +241,18 @@ func file_proto_transmission_message_proto_init() {
 				return nil
 			}
 		}
+		file_proto_transmission_message_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PayloadBatch); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -147,7 +260,7 @@ func file_proto_transmission_message_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_proto_transmission_message_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   1,
+			NumMessages:   2,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
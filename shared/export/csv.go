@@ -0,0 +1,73 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+type csvWriter struct {
+	writer        *csv.Writer
+	columns       []column
+	record        []string
+	headerWritten bool
+}
+
+func newCSVWriter(w io.Writer, desc protoreflect.MessageDescriptor) *csvWriter {
+	columns := columnsOf(desc)
+	return &csvWriter{
+		writer:  csv.NewWriter(w),
+		columns: columns,
+		record:  make([]string, len(columns)),
+	}
+}
+
+func (w *csvWriter) writeHeader() error {
+	for i, c := range w.columns {
+		w.record[i] = c.name()
+	}
+	w.headerWritten = true
+	return w.writer.Write(w.record)
+}
+
+func (w *csvWriter) Write(entity proto.Message) error {
+	if !w.headerWritten {
+		if err := w.writeHeader(); err != nil {
+			return err
+		}
+	}
+	m := entity.ProtoReflect()
+	for i, c := range w.columns {
+		w.record[i] = formatValue(c.value(m))
+	}
+	return w.writer.Write(w.record)
+}
+
+// The header is written even without rows
+func (w *csvWriter) Close() error {
+	if !w.headerWritten {
+		if err := w.writeHeader(); err != nil {
+			return err
+		}
+	}
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+func formatValue(v any) string {
+	switch value := v.(type) {
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(value), 'f', -1, 32)
+	case []byte:
+		return string(value)
+	}
+	return fmt.Sprint(v)
+}
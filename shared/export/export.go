@@ -0,0 +1,141 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+type Format string
+
+const (
+	// One JSON object per line
+	JSONLines Format = "jsonl"
+	// Comma separated values with a header row
+	CSV Format = "csv"
+	// Apache Parquet, snappy compressed
+	Parquet Format = "parquet"
+)
+
+func GetFormatMap() map[string]Format {
+	return map[string]Format{
+		"jsonl":   JSONLines,
+		"json":    JSONLines,
+		"csv":     CSV,
+		"parquet": Parquet,
+	}
+}
+
+// Writer writes entities of a single type as the rows of a file
+type Writer interface {
+	Write(entity proto.Message) error
+	// Close flushes the buffered rows, the underlying writer is left open
+	Close() error
+}
+
+// NewWriter returns a writer of entities of the type of sample in a format
+func NewWriter(format Format, w io.Writer, sample proto.Message) (Writer, error) {
+	switch format {
+	case JSONLines:
+		return newJSONLinesWriter(w), nil
+	case CSV:
+		return newCSVWriter(w, sample.ProtoReflect().Descriptor()), nil
+	case Parquet:
+		return newParquetWriter(w, sample.ProtoReflect().Descriptor()), nil
+	}
+	return nil, fmt.Errorf("invalid export format %s", format)
+}
+
+// A column of the rows of an entity, the scalar fields of the entity are columns of their type and
+// the other fields are JSON encoded
+type column struct {
+	field protoreflect.FieldDescriptor
+	// Name of the column, the nanosecond fields of the timestamps kept in seconds for the previous
+	// schema (e.g. TimestampNs) take the name of the seconds field
+	columnName string
+	// Whether the column is a timestamp in Unix nanoseconds
+	timestamp bool
+}
+
+func (c column) name() string {
+	return c.columnName
+}
+
+func (c column) isJSON() bool {
+	return c.field.IsList() || c.field.IsMap() || c.field.Kind() == protoreflect.MessageKind
+}
+
+func columnsOf(desc protoreflect.MessageDescriptor) []column {
+	fields := desc.Fields()
+	columns := make([]column, 0, fields.Len())
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		name := string(field.Name())
+		if fields.ByName(field.Name()+"Ns") != nil {
+			// Only the nanoseconds are exported
+			continue
+		}
+		if base := strings.TrimSuffix(name, "Ns"); base != name && fields.ByName(protoreflect.Name(base)) != nil {
+			name = base
+		}
+		columns = append(columns, column{
+			field:      field,
+			columnName: name,
+			timestamp:  field.Kind() == protoreflect.Int64Kind && !field.IsList() && (name == "Timestamp" || strings.HasSuffix(name, "At")),
+		})
+	}
+	return columns
+}
+
+// Value of the column of an entity, a bool, an integer, a float, a string or bytes
+func (c column) value(m protoreflect.Message) any {
+	v := m.Get(c.field)
+	if c.isJSON() {
+		return jsonValue(c.field, v)
+	}
+	if c.field.Kind() == protoreflect.EnumKind {
+		if value := c.field.Enum().Values().ByNumber(v.Enum()); value != nil {
+			return string(value.Name())
+		}
+		return fmt.Sprint(v.Enum())
+	}
+	return v.Interface()
+}
+
+func jsonValue(field protoreflect.FieldDescriptor, v protoreflect.Value) string {
+	var value any
+	switch {
+	case field.IsList():
+		list := v.List()
+		values := make([]any, list.Len())
+		for i := range values {
+			values[i] = elementValue(field, list.Get(i))
+		}
+		value = values
+	case field.IsMap():
+		values := make(map[string]any)
+		v.Map().Range(func(key protoreflect.MapKey, v protoreflect.Value) bool {
+			values[key.String()] = elementValue(field.MapValue(), v)
+			return true
+		})
+		value = values
+	default:
+		value = elementValue(field, v)
+	}
+	encoded, _ := json.Marshal(value)
+	return string(encoded)
+}
+
+func elementValue(field protoreflect.FieldDescriptor, v protoreflect.Value) any {
+	if field.Kind() == protoreflect.MessageKind {
+		if !v.Message().IsValid() {
+			return nil
+		}
+		return v.Message().Interface()
+	}
+	return v.Interface()
+}
@@ -0,0 +1,30 @@
+package export
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+
+	"google.golang.org/protobuf/proto"
+)
+
+type jsonLinesWriter struct {
+	buffer  *bufio.Writer
+	encoder *json.Encoder
+}
+
+func newJSONLinesWriter(w io.Writer) *jsonLinesWriter {
+	buffer := bufio.NewWriter(w)
+	return &jsonLinesWriter{
+		buffer:  buffer,
+		encoder: json.NewEncoder(buffer),
+	}
+}
+
+func (w *jsonLinesWriter) Write(entity proto.Message) error {
+	return w.encoder.Encode(entity)
+}
+
+func (w *jsonLinesWriter) Close() error {
+	return w.buffer.Flush()
+}
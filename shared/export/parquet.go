@@ -0,0 +1,78 @@
+package export
+
+import (
+	"io"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/compress/snappy"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+type parquetWriter struct {
+	writer *parquet.Writer
+	// Columns in the order of the leaves of the schema, sorted by name
+	columns []column
+	row     parquet.Row
+}
+
+func newParquetWriter(w io.Writer, desc protoreflect.MessageDescriptor) *parquetWriter {
+	columns := columnsOf(desc)
+	group := make(parquet.Group, len(columns))
+	byName := make(map[string]column, len(columns))
+	for _, c := range columns {
+		group[c.name()] = parquetNode(c)
+		byName[c.name()] = c
+	}
+	schema := parquet.NewSchema(string(desc.Name()), group)
+	ordered := make([]column, 0, len(columns))
+	for _, field := range schema.Fields() {
+		ordered = append(ordered, byName[field.Name()])
+	}
+	return &parquetWriter{
+		writer:  parquet.NewWriter(w, schema, parquet.Compression(&snappy.Codec{})),
+		columns: ordered,
+		row:     make(parquet.Row, len(ordered)),
+	}
+}
+
+func parquetNode(c column) parquet.Node {
+	if c.isJSON() {
+		return parquet.JSON()
+	}
+	if c.timestamp {
+		return parquet.Timestamp(parquet.Nanosecond)
+	}
+	switch c.field.Kind() {
+	case protoreflect.BoolKind:
+		return parquet.Leaf(parquet.BooleanType)
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return parquet.Int(32)
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return parquet.Int(64)
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return parquet.Uint(32)
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return parquet.Uint(64)
+	case protoreflect.FloatKind:
+		return parquet.Leaf(parquet.FloatType)
+	case protoreflect.DoubleKind:
+		return parquet.Leaf(parquet.DoubleType)
+	case protoreflect.BytesKind:
+		return parquet.Leaf(parquet.ByteArrayType)
+	}
+	return parquet.String()
+}
+
+func (w *parquetWriter) Write(entity proto.Message) error {
+	m := entity.ProtoReflect()
+	for i, c := range w.columns {
+		w.row[i] = parquet.ValueOf(c.value(m)).Level(0, 0, i)
+	}
+	_, err := w.writer.WriteRows([]parquet.Row{w.row})
+	return err
+}
+
+func (w *parquetWriter) Close() error {
+	return w.writer.Close()
+}
@@ -0,0 +1,141 @@
+package indicators
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"tradingplatform/shared/entities"
+)
+
+// Indicator keeps the rolling state of a technical indicator computed on bars given in time order
+type Indicator interface {
+	// Name of the indicator with its parameters, e.g. sma-20
+	Name() string
+	// Update the indicator with the next bar, ok is false while the indicator is warming up
+	Update(bar *entities.Bar) (value float64, components map[string]float64, ok bool)
+}
+
+// Kind of indicator
+type Kind string
+
+const (
+	SMA       Kind = "sma"
+	EMA       Kind = "ema"
+	RSI       Kind = "rsi"
+	MACD      Kind = "macd"
+	Bollinger Kind = "bbands"
+	ATR       Kind = "atr"
+)
+
+// Parameters used when an indicator is given without them, nil if they are required
+var defaultParameters = map[Kind][]int{
+	SMA:       nil,
+	EMA:       nil,
+	RSI:       {14},
+	MACD:      {12, 26, 9},
+	Bollinger: {20, 2},
+	ATR:       {14},
+}
+
+// Number of parameters of each indicator
+var parameterCount = map[Kind]int{
+	SMA:       1,
+	EMA:       1,
+	RSI:       1,
+	MACD:      3,
+	Bollinger: 2,
+	ATR:       1,
+}
+
+func GetKindMap() map[string]Kind {
+	kinds := make(map[string]Kind, len(parameterCount))
+	for kind := range parameterCount {
+		kinds[string(kind)] = kind
+	}
+	return kinds
+}
+
+// Spec is an indicator with its parameters, named <kind>-<parameter>-... (e.g. sma-20, rsi-14,
+// macd-12-26-9, bbands-20-2). Parameters are positive whole numbers, the defaults of rsi (14),
+// macd (12-26-9), bbands (20-2) and atr (14) are used if they are omitted
+type Spec struct {
+	Kind       Kind
+	Parameters []int
+}
+
+// ParseSpec parses the name of an indicator
+func ParseSpec(name string) (Spec, error) {
+	parts := strings.Split(strings.ToLower(name), "-")
+	kind, ok := GetKindMap()[parts[0]]
+	if !ok {
+		return Spec{}, fmt.Errorf("unknown indicator %s", parts[0])
+	}
+	spec := Spec{Kind: kind}
+	if len(parts) == 1 {
+		if defaultParameters[kind] == nil {
+			return Spec{}, fmt.Errorf("indicator %s requires its parameters, e.g. %s-20", kind, kind)
+		}
+		spec.Parameters = append([]int{}, defaultParameters[kind]...)
+		return spec, nil
+	}
+	if len(parts)-1 != parameterCount[kind] {
+		return Spec{}, fmt.Errorf("indicator %s takes %d parameters, got %d", kind, parameterCount[kind], len(parts)-1)
+	}
+	for _, part := range parts[1:] {
+		parameter, err := strconv.Atoi(part)
+		if err != nil || parameter <= 0 {
+			return Spec{}, fmt.Errorf("invalid parameter %s of indicator %s", part, name)
+		}
+		spec.Parameters = append(spec.Parameters, parameter)
+	}
+	if kind == MACD && spec.Parameters[0] >= spec.Parameters[1] {
+		return Spec{}, fmt.Errorf("fast period of indicator %s must be shorter than its slow period", name)
+	}
+	return spec, nil
+}
+
+// Name returns the name of the indicator with all of its parameters, e.g. rsi-14
+func (s Spec) Name() string {
+	name := string(s.Kind)
+	for _, parameter := range s.Parameters {
+		name += "-" + strconv.Itoa(parameter)
+	}
+	return name
+}
+
+// New creates an indicator with empty state
+func (s Spec) New() Indicator {
+	p := s.Parameters
+	switch s.Kind {
+	case SMA:
+		return NewSMA(p[0])
+	case EMA:
+		return NewEMA(p[0])
+	case RSI:
+		return NewRSI(p[0])
+	case MACD:
+		return NewMACD(p[0], p[1], p[2])
+	case Bollinger:
+		return NewBollinger(p[0], float64(p[1]))
+	case ATR:
+		return NewATR(p[0])
+	}
+	return nil
+}
+
+// NewIndicator creates the entity of a value of an indicator computed at a bar
+func NewIndicator(ind Indicator, bar *entities.Bar, value float64, components map[string]float64) *entities.Indicator {
+	indicator := &entities.Indicator{
+		Symbol:     bar.Symbol,
+		Source:     bar.Source,
+		AssetClass: bar.AssetClass,
+		Timeframe:  bar.Timeframe,
+		Name:       ind.Name(),
+		Timestamp:  bar.TimestampNs,
+		Value:      value,
+		Components: components,
+	}
+	indicator.SetFingerprint()
+	return indicator
+}
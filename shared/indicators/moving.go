@@ -0,0 +1,89 @@
+package indicators
+
+import (
+	"strconv"
+
+	"tradingplatform/shared/entities"
+)
+
+// A window of the last values added
+type window struct {
+	values []float64
+	next   int
+	full   bool
+	sum    float64
+}
+
+func newWindow(size int) *window {
+	return &window{values: make([]float64, size)}
+}
+
+// Add a value, dropping the oldest one once the window is full
+func (w *window) add(value float64) {
+	w.sum += value - w.values[w.next]
+	w.values[w.next] = value
+	w.next++
+	if w.next == len(w.values) {
+		w.next = 0
+		w.full = true
+	}
+}
+
+func (w *window) mean() float64 {
+	return w.sum / float64(len(w.values))
+}
+
+// Simple moving average of the close
+type sma struct {
+	period int
+	window *window
+}
+
+func NewSMA(period int) Indicator {
+	return &sma{period: period, window: newWindow(period)}
+}
+
+func (s *sma) Name() string {
+	return string(SMA) + "-" + strconv.Itoa(s.period)
+}
+
+func (s *sma) Update(bar *entities.Bar) (float64, map[string]float64, bool) {
+	s.window.add(bar.Close)
+	return s.window.mean(), nil, s.window.full
+}
+
+// Exponential moving average, seeded with the simple moving average of its first period
+type ema struct {
+	period int
+	alpha  float64
+	count  int
+	value  float64
+}
+
+func newEMA(period int) *ema {
+	return &ema{period: period, alpha: 2 / float64(period+1)}
+}
+
+func NewEMA(period int) Indicator {
+	return newEMA(period)
+}
+
+func (e *ema) Name() string {
+	return string(EMA) + "-" + strconv.Itoa(e.period)
+}
+
+// Add a value, returns whether the average is seeded
+func (e *ema) add(value float64) bool {
+	e.count++
+	if e.count <= e.period {
+		e.value += (value - e.value) / float64(e.count)
+		return e.count == e.period
+	}
+	e.value += e.alpha * (value - e.value)
+	return true
+}
+
+func (e *ema) Update(bar *entities.Bar) (float64, map[string]float64, bool) {
+	ok := e.add(bar.Close)
+	return e.value, nil, ok
+}
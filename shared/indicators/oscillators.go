@@ -0,0 +1,94 @@
+package indicators
+
+import (
+	"fmt"
+	"strconv"
+
+	"tradingplatform/shared/entities"
+)
+
+// Relative strength index with Wilder's smoothing of the gains and losses
+type rsi struct {
+	period    int
+	count     int
+	prevClose float64
+	avgGain   float64
+	avgLoss   float64
+}
+
+func NewRSI(period int) Indicator {
+	return &rsi{period: period}
+}
+
+func (r *rsi) Name() string {
+	return string(RSI) + "-" + strconv.Itoa(r.period)
+}
+
+func (r *rsi) Update(bar *entities.Bar) (float64, map[string]float64, bool) {
+	r.count++
+	if r.count == 1 {
+		r.prevClose = bar.Close
+		return 0, nil, false
+	}
+	change := bar.Close - r.prevClose
+	r.prevClose = bar.Close
+	gain, loss := 0.0, 0.0
+	if change > 0 {
+		gain = change
+	} else {
+		loss = -change
+	}
+
+	changes := r.count - 1
+	if changes <= r.period {
+		// The first averages are plain means of the changes
+		r.avgGain += (gain - r.avgGain) / float64(changes)
+		r.avgLoss += (loss - r.avgLoss) / float64(changes)
+		if changes < r.period {
+			return 0, nil, false
+		}
+	} else {
+		n := float64(r.period)
+		r.avgGain = (r.avgGain*(n-1) + gain) / n
+		r.avgLoss = (r.avgLoss*(n-1) + loss) / n
+	}
+
+	if r.avgLoss == 0 {
+		if r.avgGain == 0 {
+			return 50, nil, true
+		}
+		return 100, nil, true
+	}
+	return 100 - 100/(1+r.avgGain/r.avgLoss), nil, true
+}
+
+// Moving average convergence divergence, the value is the MACD line and the signal line and
+// histogram are components
+type macd struct {
+	fast   *ema
+	slow   *ema
+	signal *ema
+}
+
+func NewMACD(fast int, slow int, signal int) Indicator {
+	return &macd{fast: newEMA(fast), slow: newEMA(slow), signal: newEMA(signal)}
+}
+
+func (m *macd) Name() string {
+	return fmt.Sprintf("%s-%d-%d-%d", MACD, m.fast.period, m.slow.period, m.signal.period)
+}
+
+func (m *macd) Update(bar *entities.Bar) (float64, map[string]float64, bool) {
+	m.fast.add(bar.Close)
+	if !m.slow.add(bar.Close) {
+		return 0, nil, false
+	}
+	line := m.fast.value - m.slow.value
+	if !m.signal.add(line) {
+		return line, nil, false
+	}
+	return line, map[string]float64{
+		"signal":    m.signal.value,
+		"histogram": line - m.signal.value,
+	}, true
+}
@@ -0,0 +1,58 @@
+package indicators
+
+import (
+	"tradingplatform/shared/entities"
+)
+
+// Set computes several indicators on the same series of bars
+type Set struct {
+	indicators map[string]Indicator
+	// Timestamp of the last bar, bars that are not later are ignored
+	last int64
+}
+
+func NewSet() *Set {
+	return &Set{indicators: make(map[string]Indicator)}
+}
+
+// Add an indicator with empty state, does nothing if the indicator is already computed
+func (s *Set) Add(spec Spec) {
+	if _, ok := s.indicators[spec.Name()]; !ok {
+		s.indicators[spec.Name()] = spec.New()
+	}
+}
+
+// Merge moves the indicators of another set into the set, indicators already computed by the set are kept
+func (s *Set) Merge(other *Set) {
+	for name, indicator := range other.indicators {
+		if _, ok := s.indicators[name]; !ok {
+			s.indicators[name] = indicator
+		}
+	}
+	if other.last > s.last {
+		s.last = other.last
+	}
+	other.indicators = make(map[string]Indicator)
+}
+
+// Remove an indicator, returns whether indicators are left
+func (s *Set) Remove(name string) bool {
+	delete(s.indicators, name)
+	return len(s.indicators) > 0
+}
+
+// Update the indicators with the next bar and return the values of the indicators that are warmed up,
+// ok is false if the bar was ignored because it is not later than the last one
+func (s *Set) Update(bar *entities.Bar) (values []*entities.Indicator, ok bool) {
+	if bar.TimestampNs <= s.last {
+		return nil, false
+	}
+	s.last = bar.TimestampNs
+	for _, indicator := range s.indicators {
+		value, components, ready := indicator.Update(bar)
+		if ready {
+			values = append(values, NewIndicator(indicator, bar, value, components))
+		}
+	}
+	return values, true
+}
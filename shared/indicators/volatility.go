@@ -0,0 +1,76 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"tradingplatform/shared/entities"
+)
+
+// Bollinger bands, the value is the simple moving average of the close and the bands at k
+// population standard deviations are components
+type bollinger struct {
+	period int
+	k      float64
+	window *window
+}
+
+func NewBollinger(period int, k float64) Indicator {
+	return &bollinger{period: period, k: k, window: newWindow(period)}
+}
+
+func (b *bollinger) Name() string {
+	return fmt.Sprintf("%s-%d-%s", Bollinger, b.period, strconv.FormatFloat(b.k, 'f', -1, 64))
+}
+
+func (b *bollinger) Update(bar *entities.Bar) (float64, map[string]float64, bool) {
+	b.window.add(bar.Close)
+	if !b.window.full {
+		return 0, nil, false
+	}
+	mean := b.window.mean()
+	variance := 0.0
+	for _, value := range b.window.values {
+		variance += (value - mean) * (value - mean)
+	}
+	deviation := math.Sqrt(variance / float64(b.period))
+	return mean, map[string]float64{
+		"upper": mean + b.k*deviation,
+		"lower": mean - b.k*deviation,
+	}, true
+}
+
+// Average true range with Wilder's smoothing
+type atr struct {
+	period    int
+	count     int
+	prevClose float64
+	value     float64
+}
+
+func NewATR(period int) Indicator {
+	return &atr{period: period}
+}
+
+func (a *atr) Name() string {
+	return string(ATR) + "-" + strconv.Itoa(a.period)
+}
+
+func (a *atr) Update(bar *entities.Bar) (float64, map[string]float64, bool) {
+	trueRange := bar.High - bar.Low
+	if a.count > 0 {
+		trueRange = math.Max(trueRange, math.Max(
+			math.Abs(bar.High-a.prevClose),
+			math.Abs(bar.Low-a.prevClose)))
+	}
+	a.prevClose = bar.Close
+	a.count++
+	if a.count <= a.period {
+		a.value += (trueRange - a.value) / float64(a.count)
+		return a.value, nil, a.count == a.period
+	}
+	n := float64(a.period)
+	a.value = (a.value*(n-1) + trueRange) / n
+	return a.value, nil, true
+}
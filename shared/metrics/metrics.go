@@ -0,0 +1,171 @@
+package metrics
+
+import (
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "otp"
+
+// Number of dot separated parts of a topic kept in its root, the component, functionality, source,
+// asset class and data type of stream and data topics
+const topicRootParts = 5
+
+var (
+	messagesPublished = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "messages_published_total",
+		Help:      "Messages published on stream topics and data queues, by topic root",
+	}, []string{"topic_root"})
+	publishErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "publish_errors_total",
+		Help:      "Messages that could not be published, by topic root",
+	}, []string{"topic_root"})
+	messageEntities = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "message_entities",
+		Help:      "Entities per published message, greater than one for batches, by topic root",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 12),
+	}, []string{"topic_root"})
+	messagesReceived = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "messages_received_total",
+		Help:      "Entities received on subscribed topics, batches counting their entities, by topic root",
+	}, []string{"topic_root"})
+	agentQueueDepth = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "agent_queue_depth",
+		Help:      "Entities of subscribed topics waiting for or being handled by the agents of the topic, by topic root",
+	}, []string{"topic_root"})
+	handleDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "message_handle_duration_seconds",
+		Help:      "Time the agents of subscribed topics take to handle an entity, by topic root",
+		Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 14),
+	}, []string{"topic_root"})
+	handleErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "message_handle_errors_total",
+		Help:      "Entities of subscribed topics the agents failed to handle, by topic root",
+	}, []string{"topic_root"})
+
+	insertDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "storage_insert_duration_seconds",
+		Help:      "Time taken to insert entities in the database, by data type and mode (entity or batch)",
+		Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 16),
+	}, []string{"data_type", "mode"})
+	insertErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "storage_insert_errors_total",
+		Help:      "Failed inserts in the database, by data type and mode (entity or batch)",
+	}, []string{"data_type", "mode"})
+	insertBatchEntities = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "storage_insert_batch_entities",
+		Help:      "Entities per batch inserted in the database, by data type",
+		Buckets:   prometheus.ExponentialBuckets(1, 4, 10),
+	}, []string{"data_type"})
+
+	alpacaEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "alpaca_events_total",
+		Help:      "Events received from the Alpaca streams, by asset class and data type",
+	}, []string{"asset_class", "data_type"})
+	alpacaEventErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "alpaca_event_errors_total",
+		Help:      "Events of the Alpaca streams that could not be mapped to entities, by asset class and data type",
+	}, []string{"asset_class", "data_type"})
+
+	llmDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "llm_request_duration_seconds",
+		Help:      "Time taken by the LLM to answer a sentiment analysis prompt, by provider and model",
+		Buckets:   prometheus.ExponentialBuckets(0.05, 2, 12),
+	}, []string{"provider", "model"})
+	llmRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "llm_requests_total",
+		Help:      "Sentiment analysis prompts sent to the LLM, by provider, model and status (success or failure)",
+	}, []string{"provider", "model", "status"})
+)
+
+// TopicRoot returns the component, functionality, source, asset class and data type of a topic, the
+// symbol and queue parts are left out to bound the number of series
+func TopicRoot(topic string) string {
+	parts := strings.SplitN(topic, ".", topicRootParts+1)
+	if len(parts) > topicRootParts {
+		parts = parts[:topicRootParts]
+	}
+	return strings.Join(parts, ".")
+}
+
+// ObservePublish records a message of a number of entities published on a topic
+func ObservePublish(topic string, entities int, err error) {
+	root := TopicRoot(topic)
+	if err != nil {
+		publishErrors.WithLabelValues(root).Inc()
+		return
+	}
+	messagesPublished.WithLabelValues(root).Inc()
+	messageEntities.WithLabelValues(root).Observe(float64(entities))
+}
+
+// ObserveReceive records entities received on a subscribed topic and waiting for its agents
+func ObserveReceive(topic string, entities int) {
+	root := TopicRoot(topic)
+	messagesReceived.WithLabelValues(root).Add(float64(entities))
+	agentQueueDepth.WithLabelValues(root).Add(float64(entities))
+}
+
+// ObserveDropped records entities of a subscribed topic that were not handed to its agents
+func ObserveDropped(topic string, entities int) {
+	agentQueueDepth.WithLabelValues(TopicRoot(topic)).Sub(float64(entities))
+}
+
+// ObserveHandle records an entity of a subscribed topic handled by an agent since start
+func ObserveHandle(topic string, start time.Time, err error) {
+	root := TopicRoot(topic)
+	agentQueueDepth.WithLabelValues(root).Dec()
+	handleDuration.WithLabelValues(root).Observe(time.Since(start).Seconds())
+	if err != nil {
+		handleErrors.WithLabelValues(root).Inc()
+	}
+}
+
+// ObserveInsert records the insert of entities of a data type started at start, inserts of more
+// than one entity are recorded as batches
+func ObserveInsert(dataType string, entities int, start time.Time, err error) {
+	mode := "entity"
+	if entities > 1 {
+		mode = "batch"
+		insertBatchEntities.WithLabelValues(dataType).Observe(float64(entities))
+	}
+	insertDuration.WithLabelValues(dataType, mode).Observe(time.Since(start).Seconds())
+	if err != nil {
+		insertErrors.WithLabelValues(dataType, mode).Inc()
+	}
+}
+
+// ObserveAlpacaEvent records an event of an Alpaca stream and whether it could be mapped
+func ObserveAlpacaEvent(assetClass string, dataType string, err error) {
+	alpacaEvents.WithLabelValues(assetClass, dataType).Inc()
+	if err != nil {
+		alpacaEventErrors.WithLabelValues(assetClass, dataType).Inc()
+	}
+}
+
+// ObserveLLMRequest records a prompt sent to an LLM at start and whether it was answered
+func ObserveLLMRequest(provider string, model string, start time.Time, err error) {
+	llmDuration.WithLabelValues(provider, model).Observe(time.Since(start).Seconds())
+	status := "success"
+	if err != nil {
+		status = "failure"
+	}
+	llmRequests.WithLabelValues(provider, model, status).Inc()
+}
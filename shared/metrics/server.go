@@ -0,0 +1,47 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"tradingplatform/shared/logging"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/spf13/pflag"
+)
+
+// Add the flags configuring the metrics endpoint to a command
+func AddMetricsFlags(flags *pflag.FlagSet) {
+	flags.String("metrics-addr", "", "Address of the HTTP listener serving the Prometheus metrics on /metrics (e.g. :9091), disabled without address")
+}
+
+// Start the metrics endpoint on the address of the flag added with AddMetricsFlags, the listener
+// stops when the context is done
+func StartServerFromFlags(ctx context.Context, flags *pflag.FlagSet) {
+	addr, _ := flags.GetString("metrics-addr")
+	if addr == "" {
+		return
+	}
+	StartServer(ctx, addr)
+}
+
+// Start an HTTP listener serving the metrics of the component on /metrics until the context is done
+func StartServer(ctx context.Context, addr string) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	server := &http.Server{Addr: addr, Handler: mux}
+	go func() {
+		logging.Log().Info().Str("addr", addr).Msg("serving metrics")
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logging.Log().Error().Err(err).Str("addr", addr).Msg("serving metrics")
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+}
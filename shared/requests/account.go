@@ -0,0 +1,111 @@
+package requests
+
+import (
+	"encoding/json"
+
+	"tradingplatform/shared/logging"
+	"tradingplatform/shared/types"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Request to add an account to the registry of the dataprovider, to remove it or to list the accounts
+type AccountRequest struct {
+	Operation types.AccountRequestOp `json:"operation" validate:"required,isValidOperation"`
+	Account   Account                `json:"account" validate:"isValidAccountName"`
+	Key       string                 `json:"key" validate:"isRequiredForAdd"`
+	Secret    string                 `json:"secret" validate:"isRequiredForAdd"`
+	// Feed of the stock data subscription of the account
+	Feed types.Feed `json:"feed" validate:"isValidAccountFeed"`
+	// Whether the orders of the account are routed to the live trading API instead of the paper one
+	Live bool `json:"live"`
+}
+
+func (ar *AccountRequest) Validate() error {
+	v := validator.New()
+	v.RegisterValidation("isValidOperation", IsValidAccountOperation)
+	v.RegisterValidation("isValidAccountName", IsValidAccountName)
+	v.RegisterValidation("isRequiredForAdd", IsRequiredForAdd)
+	v.RegisterValidation("isValidAccountFeed", IsValidAccountFeed)
+
+	err := v.Struct(ar)
+	return SummarizeError(err)
+}
+
+// The secret of the account is left out of the JSON used for logging
+func (ar *AccountRequest) JSON() []byte {
+	redacted := *ar
+	if redacted.Secret != "" {
+		redacted.Secret = "***"
+	}
+	js, err := json.Marshal(redacted)
+	if err != nil {
+		logging.Log().Error().
+			Err(err).
+			Msg("marshalling account request to json")
+		return []byte{}
+	}
+	return js
+}
+
+func NewAccountRequest(operation types.AccountRequestOp,
+	account Account,
+	key string,
+	secret string,
+	feed types.Feed,
+	live bool) AccountRequest {
+
+	return AccountRequest{
+		Operation: operation,
+		Account:   account,
+		Key:       key,
+		Secret:    secret,
+		Feed:      feed,
+		Live:      live,
+	}
+}
+
+func NewAccountRequestFromRaw(operation string,
+	account string,
+	key string,
+	secret string,
+	feed string,
+	live bool, defaultingFunc func(*AccountRequest)) (AccountRequest, error) {
+
+	accountRequest := NewAccountRequest(types.AccountRequestOp(operation),
+		Account(account),
+		key,
+		secret,
+		types.Feed(feed),
+		live,
+	)
+
+	defaultingFunc(&accountRequest)
+	err := accountRequest.Validate()
+	return accountRequest, err
+}
+
+func NewAccountRequestFromExisting(accountRequest *AccountRequest, defaultingFunc func(*AccountRequest)) (AccountRequest, error) {
+	return NewAccountRequestFromRaw(string(accountRequest.Operation),
+		string(accountRequest.Account),
+		accountRequest.Key,
+		accountRequest.Secret,
+		string(accountRequest.Feed),
+		accountRequest.Live, defaultingFunc)
+}
+
+func (ar *AccountRequest) GetOperation() types.AccountRequestOp {
+	return ar.Operation
+}
+
+func (ar *AccountRequest) GetAccount() Account {
+	return ar.Account
+}
+
+// The any account stands for the default account when a single account is needed
+func ResolveAccount(account Account) Account {
+	if account == AnyAccount || account == "" {
+		return DefaultAccount
+	}
+	return account
+}
@@ -0,0 +1,170 @@
+package requests
+
+import (
+	"encoding/json"
+
+	"tradingplatform/shared/logging"
+	"tradingplatform/shared/types"
+	"tradingplatform/shared/utils"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Initial cash of the simulated account of a backtest when none is given
+const DefaultInitialCash = 100000.0
+
+// Request to run a strategy against the data of the datastorage in a time range
+type BacktestRequest struct {
+	// Name of the strategy registered in the backtest component
+	Strategy string `json:"strategy" validate:"required,min=1"`
+	// Parameters of the strategy, their meaning is up to the strategy
+	Parameters map[string]string `json:"parameters"`
+	Source     types.Source      `json:"source" validate:"required,min=3,isValidDataSource"`
+	AssetClass types.AssetClass  `json:"assetClass" validate:"required,min=3,isValidAssetClass"`
+	Symbols    []string          `json:"symbols" validate:"required,min=1,dive,min=1"`
+	// Data types the strategy receives: bar, trades, quotes and raw-text (the news of the symbols
+	// with their sentiments)
+	DataTypes []types.DataType `json:"dataTypes" validate:"required,isValidBacktestDataTypes"`
+	StartTime int64            `json:"startTime" validate:"required,min=0"`
+	EndTime   int64            `json:"endTime" validate:"required,min=0,isValidEndTime"`
+	// Timeframe of the bars
+	TimeFrame   types.TimeFrame `json:"timeFrame" validate:"required,min=3,isValidDataFrame"`
+	InitialCash float64         `json:"initialCash" validate:"gt=0"`
+	// Slippage applied against every fill, in basis points of the fill price
+	Slippage float64 `json:"slippage" validate:"min=0"`
+	// Commission per unit filled
+	Commission float64 `json:"commission" validate:"min=0"`
+	// Commission in basis points of the notional filled
+	CommissionRate float64 `json:"commissionRate" validate:"min=0"`
+}
+
+func (br *BacktestRequest) Validate() error {
+	v := validator.New()
+	v.RegisterValidation("isValidDataSource", IsValidDataSource)
+	v.RegisterValidation("isValidAssetClass", IsValidAssetClass)
+	v.RegisterValidation("isValidBacktestDataTypes", IsValidBacktestDataTypes)
+	v.RegisterValidation("isValidEndTime", IsValidEndTime)
+	v.RegisterValidation("isValidDataFrame", IsValidDataFrame)
+
+	err := v.Struct(br)
+	return SummarizeError(err)
+}
+
+func (br *BacktestRequest) JSON() []byte {
+	js, err := json.Marshal(br)
+	if err != nil {
+		logging.Log().Error().
+			Err(err).
+			Msg("marshalling backtest request to json")
+		return []byte{}
+	}
+	return js
+}
+
+// DataRequest returns the request of the data of a type of a symbol to the datastorage, news are
+// requested for the news asset class
+func (br *BacktestRequest) DataRequest(symbol string, dataType types.DataType) DataRequest {
+	assetClass := br.AssetClass
+	if dataType == types.RawText {
+		assetClass = types.News
+	}
+	return NewDataRequest(br.Source,
+		assetClass,
+		symbol,
+		types.DataGetOp,
+		dataType,
+		DefaultAccount,
+		br.StartTime,
+		br.EndTime,
+		br.TimeFrame,
+		false)
+}
+
+func NewBacktestRequest(strategy string,
+	parameters map[string]string,
+	source types.Source,
+	assetClass types.AssetClass,
+	symbols []string,
+	dataTypes []types.DataType,
+	startTime int64,
+	endTime int64,
+	timeFrame types.TimeFrame,
+	initialCash float64,
+	slippage float64,
+	commission float64,
+	commissionRate float64) BacktestRequest {
+
+	return BacktestRequest{
+		Strategy:       strategy,
+		Parameters:     parameters,
+		Source:         source,
+		AssetClass:     assetClass,
+		Symbols:        symbols,
+		DataTypes:      dataTypes,
+		StartTime:      utils.NormalizeTimestamp(startTime),
+		EndTime:        utils.NormalizeTimestamp(endTime),
+		TimeFrame:      timeFrame,
+		InitialCash:    initialCash,
+		Slippage:       slippage,
+		Commission:     commission,
+		CommissionRate: commissionRate,
+	}
+}
+
+func NewBacktestRequestFromRaw(strategy string,
+	parameters map[string]string,
+	source string,
+	assetClass string,
+	symbols []string,
+	dataTypes []string,
+	startTime int64,
+	endTime int64,
+	timeFrame string,
+	initialCash float64,
+	slippage float64,
+	commission float64,
+	commissionRate float64, defaultingFunc func(*BacktestRequest)) (BacktestRequest, error) {
+
+	var dtypes []types.DataType
+	for _, dtype := range dataTypes {
+		dtypes = append(dtypes, types.DataType(dtype))
+	}
+	backtestRequest := NewBacktestRequest(strategy,
+		parameters,
+		types.Source(source),
+		types.AssetClass(assetClass),
+		symbols,
+		dtypes,
+		startTime,
+		endTime,
+		types.TimeFrame(timeFrame),
+		initialCash,
+		slippage,
+		commission,
+		commissionRate,
+	)
+
+	defaultingFunc(&backtestRequest)
+	err := backtestRequest.Validate()
+	return backtestRequest, err
+}
+
+func NewBacktestRequestFromExisting(backtestRequest *BacktestRequest, defaultingFunc func(*BacktestRequest)) (BacktestRequest, error) {
+	dataTypes := make([]string, len(backtestRequest.DataTypes))
+	for i, dtype := range backtestRequest.DataTypes {
+		dataTypes[i] = string(dtype)
+	}
+	return NewBacktestRequestFromRaw(backtestRequest.Strategy,
+		backtestRequest.Parameters,
+		string(backtestRequest.Source),
+		string(backtestRequest.AssetClass),
+		backtestRequest.Symbols,
+		dataTypes,
+		backtestRequest.StartTime,
+		backtestRequest.EndTime,
+		string(backtestRequest.TimeFrame),
+		backtestRequest.InitialCash,
+		backtestRequest.Slippage,
+		backtestRequest.Commission,
+		backtestRequest.CommissionRate, defaultingFunc)
+}
@@ -3,20 +3,21 @@ package requests
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"sync"
 	"tradingplatform/shared/communication"
 	"tradingplatform/shared/communication/command"
 	"tradingplatform/shared/communication/subscriber"
 	"tradingplatform/shared/entities"
 	"tradingplatform/shared/logging"
+	"tradingplatform/shared/tracing"
 	"tradingplatform/shared/utils"
 
 	"tradingplatform/shared/types"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/nats-io/nats.go"
-	"google.golang.org/protobuf/proto"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type DataRequest struct {
@@ -120,8 +121,8 @@ func NewDataRequest(source types.Source,
 		Operation:  operation,
 		DataType:   dataType,
 		Account:    account,
-		StartTime:  startTime,
-		EndTime:    endTime,
+		StartTime:  utils.NormalizeTimestamp(startTime),
+		EndTime:    utils.NormalizeTimestamp(endTime),
 		TimeFrame:  timeFrame,
 		NoConfirm:  noConfirm,
 	}
@@ -168,9 +169,16 @@ func NewDataRequestFromExisting(dataRequest *DataRequest, defaultingFunc func(*D
 		dataRequest.NoConfirm, defaultingFunc)
 }
 
-func RequestData(ctx context.Context, topic utils.Topic, dataRequest DataRequest, onData func(*entities.Message)) error {
+func RequestData(ctx context.Context, topic utils.Topic, dataRequest DataRequest, onData func(*entities.Message)) (err error) {
 	nc, _ := nats.Connect(communication.GetNatsURL())
 	defer nc.Close()
+	ctx, span := tracing.Start(ctx, "request data",
+		attribute.String("topic", topic.Generate()),
+		attribute.String("symbol", dataRequest.GetSymbol()),
+		attribute.String("dataType", string(dataRequest.GetDataType())))
+	defer func() {
+		tracing.End(span, err)
+	}()
 
 	// Ensure that confirmation is required
 	dataRequest.NoConfirm = false
@@ -183,7 +191,10 @@ func RequestData(ctx context.Context, topic utils.Topic, dataRequest DataRequest
 	dataRequestJSON := rawReq.JSONWithHeader()
 
 	// Request data
-	msg, err := nc.RequestWithContext(ctx, topic.Generate(), []byte(dataRequestJSON))
+	reqMsg := nats.NewMsg(topic.Generate())
+	reqMsg.Data = []byte(dataRequestJSON)
+	tracing.Inject(ctx, reqMsg)
+	msg, err := nc.RequestMsgWithContext(ctx, reqMsg)
 	if err != nil {
 		return fmt.Errorf("error while requesting data %v (topic: %s)", err, topic.Generate())
 	}
@@ -192,51 +203,16 @@ func RequestData(ctx context.Context, topic utils.Topic, dataRequest DataRequest
 	json.Unmarshal(msg.Data, &res)
 
 	if res.Err != "" {
-		return fmt.Errorf(res.Err)
-	}
-
-	// Get elements count
-	_, count := subscriber.GetQueueComponents(res.ResponseTopic)
-
-	// Prepare counter
-	var wg sync.WaitGroup
-	wg.Add(count)
-
-	// Subscribe to response topic
-	ch := make(chan *nats.Msg, count)
-	sub, err := nc.ChanSubscribe(res.ResponseTopic, ch)
-	defer sub.Unsubscribe()
-	if err != nil {
-		return fmt.Errorf("error while subscribing to data response stream %v (topic: %s)", err, res.ResponseTopic)
+		return types.ResponseError(res.Err)
 	}
 
-	// Create subcontext
-	subCtx, cancel := context.WithCancel(ctx)
-	defer cancel()
-
-	go func() {
-		for {
-			select {
-			case m := <-ch:
-				var msg entities.Message
-				// Empty message is just the receiver telling us that it's ready to receive data
-				// ignore it
-				if string(m.Data) == "" {
-					continue
-				}
-				proto.Unmarshal(m.Data, &msg)
-				onData(&msg)
-				wg.Done()
-			case <-subCtx.Done():
-				return
-			}
-		}
-	}()
+	// Receive the data queue
+	return subscriber.ReceiveQueue(ctx, nc, res.ResponseTopic, onData)
+}
 
-	// Publish empty message to start receiving data
-	nc.Publish(res.ResponseTopic, []byte(""))
-	wg.Wait()
-	return nil
+// IsNoDataError returns whether a data request failed because no data matches it
+func IsNoDataError(err error) bool {
+	return errors.Is(err, types.ErrNoData)
 }
 
 func DrainChannel[T any](ch chan *T) {
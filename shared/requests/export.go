@@ -0,0 +1,123 @@
+package requests
+
+import (
+	"encoding/json"
+
+	"tradingplatform/shared/export"
+	"tradingplatform/shared/logging"
+	"tradingplatform/shared/types"
+	"tradingplatform/shared/utils"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Request to write the stored data of symbols in a time range into files, one file per data type,
+// symbol and day
+type ExportRequest struct {
+	Source     types.Source     `json:"source" validate:"required,min=3,isValidDataSource"`
+	AssetClass types.AssetClass `json:"assetClass" validate:"required,min=3,isValidAssetClass"`
+	Symbols    []string         `json:"symbols" validate:"required,min=1,dive,min=1"`
+	// Data types exported: bar, daily-bars, trades, quotes, orderbook, raw-text (news) and sentiment
+	DataTypes []types.DataType `json:"dataTypes" validate:"required,min=1,dive,isValidExportDataType"`
+	StartTime int64            `json:"startTime" validate:"required,min=0"`
+	EndTime   int64            `json:"endTime" validate:"required,min=0,isValidEndTime"`
+	// Timeframe of the bars
+	TimeFrame types.TimeFrame `json:"timeFrame" validate:"required,min=3,isValidDataFrame"`
+	Format    export.Format   `json:"format" validate:"required,isValidExportFormat"`
+	// Directory the files are written to, relative to the export directory of the datastorage
+	Directory string `json:"directory" validate:"isValidExportDirectory"`
+}
+
+func (er *ExportRequest) Validate() error {
+	v := validator.New()
+	v.RegisterValidation("isValidDataSource", IsValidDataSource)
+	v.RegisterValidation("isValidAssetClass", IsValidAssetClass)
+	v.RegisterValidation("isValidExportDataType", IsValidExportDataType)
+	v.RegisterValidation("isValidEndTime", IsValidEndTime)
+	v.RegisterValidation("isValidDataFrame", IsValidDataFrame)
+	v.RegisterValidation("isValidExportFormat", IsValidExportFormat)
+	v.RegisterValidation("isValidExportDirectory", IsValidExportDirectory)
+
+	err := v.Struct(er)
+	return SummarizeError(err)
+}
+
+func (er *ExportRequest) JSON() []byte {
+	js, err := json.Marshal(er)
+	if err != nil {
+		logging.Log().Error().
+			Err(err).
+			Msg("marshalling export request to json")
+		return []byte{}
+	}
+	return js
+}
+
+func NewExportRequest(source types.Source,
+	assetClass types.AssetClass,
+	symbols []string,
+	dataTypes []types.DataType,
+	startTime int64,
+	endTime int64,
+	timeFrame types.TimeFrame,
+	format export.Format,
+	directory string) ExportRequest {
+
+	return ExportRequest{
+		Source:     source,
+		AssetClass: assetClass,
+		Symbols:    symbols,
+		DataTypes:  dataTypes,
+		StartTime:  utils.NormalizeTimestamp(startTime),
+		EndTime:    utils.NormalizeTimestamp(endTime),
+		TimeFrame:  timeFrame,
+		Format:     format,
+		Directory:  directory,
+	}
+}
+
+func NewExportRequestFromRaw(source string,
+	assetClass string,
+	symbols []string,
+	dataTypes []string,
+	startTime int64,
+	endTime int64,
+	timeFrame string,
+	format string,
+	directory string, defaultingFunc func(*ExportRequest)) (ExportRequest, error) {
+
+	var dtypes []types.DataType
+	for _, dtype := range dataTypes {
+		dtypes = append(dtypes, types.DataType(dtype))
+	}
+	exportRequest := NewExportRequest(types.Source(source),
+		types.AssetClass(assetClass),
+		symbols,
+		dtypes,
+		startTime,
+		endTime,
+		types.TimeFrame(timeFrame),
+		export.Format(format),
+		directory,
+	)
+
+	defaultingFunc(&exportRequest)
+	err := exportRequest.Validate()
+	return exportRequest, err
+}
+
+func NewExportRequestFromExisting(exportRequest *ExportRequest, defaultingFunc func(*ExportRequest)) (ExportRequest, error) {
+	dataTypes := make([]string, len(exportRequest.DataTypes))
+	for i, dtype := range exportRequest.DataTypes {
+		dataTypes[i] = string(dtype)
+	}
+	return NewExportRequestFromRaw(string(exportRequest.Source),
+		string(exportRequest.AssetClass),
+		exportRequest.Symbols,
+		dataTypes,
+		exportRequest.StartTime,
+		exportRequest.EndTime,
+		string(exportRequest.TimeFrame),
+		string(exportRequest.Format),
+		exportRequest.Directory, defaultingFunc)
+}
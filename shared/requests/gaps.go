@@ -0,0 +1,127 @@
+package requests
+
+import (
+	"encoding/json"
+
+	"tradingplatform/shared/calendar"
+	"tradingplatform/shared/logging"
+	"tradingplatform/shared/types"
+	"tradingplatform/shared/utils"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Request to find the bars missing from the datastorage in a time range, and optionally to fill
+// them with data requested from the dataprovider
+type DataGapsRequest struct {
+	Source     types.Source     `json:"source" validate:"required,min=3,isValidDataSource"`
+	AssetClass types.AssetClass `json:"assetClass" validate:"required,min=3,isValidAssetClass"`
+	Symbol     string           `json:"symbol" validate:"required,min=1"`
+	DataType   types.DataType   `json:"dataType" validate:"required,isValidGapsDataType"`
+	StartTime  int64            `json:"startTime" validate:"required,min=0"`
+	EndTime    int64            `json:"endTime" validate:"required,min=0,isValidEndTime"`
+	TimeFrame  types.TimeFrame  `json:"timeFrame" validate:"required,min=3,isValidDataFrame"`
+	// Trading calendar giving the expected bars, defaults to the calendar of the asset class
+	Calendar calendar.Name `json:"calendar" validate:"required,isValidCalendar"`
+	// Whether the gaps found are filled with data requested from the dataprovider
+	Fill bool `json:"fill"`
+}
+
+func (d *DataGapsRequest) Validate() error {
+	v := validator.New()
+	v.RegisterValidation("isValidDataSource", IsValidDataSource)
+	v.RegisterValidation("isValidAssetClass", IsValidAssetClass)
+	v.RegisterValidation("isValidGapsDataType", IsValidGapsDataType)
+	v.RegisterValidation("isValidEndTime", IsValidEndTime)
+	v.RegisterValidation("isValidDataFrame", IsValidDataFrame)
+	v.RegisterValidation("isValidCalendar", IsValidCalendar)
+
+	err := v.Struct(d)
+	return SummarizeError(err)
+}
+
+func (d *DataGapsRequest) JSON() []byte {
+	js, err := json.Marshal(d)
+	if err != nil {
+		logging.Log().Error().
+			Err(err).
+			Msg("marshalling data gaps request to json")
+		return []byte{}
+	}
+	return js
+}
+
+// DataRequest returns the request of the data of a time range of the gaps request to the dataprovider
+func (d *DataGapsRequest) DataRequest(startTime int64, endTime int64) DataRequest {
+	return NewDataRequest(d.Source,
+		d.AssetClass,
+		d.Symbol,
+		types.DataGetOp,
+		types.Bar,
+		DefaultAccount,
+		startTime,
+		endTime,
+		d.TimeFrame,
+		false)
+}
+
+func NewDataGapsRequest(source types.Source,
+	assetClass types.AssetClass,
+	symbol string,
+	dataType types.DataType,
+	startTime int64,
+	endTime int64,
+	timeFrame types.TimeFrame,
+	cal calendar.Name,
+	fill bool) DataGapsRequest {
+
+	return DataGapsRequest{
+		Source:     source,
+		AssetClass: assetClass,
+		Symbol:     symbol,
+		DataType:   dataType,
+		StartTime:  utils.NormalizeTimestamp(startTime),
+		EndTime:    utils.NormalizeTimestamp(endTime),
+		TimeFrame:  timeFrame,
+		Calendar:   cal,
+		Fill:       fill,
+	}
+}
+
+func NewDataGapsRequestFromRaw(source string,
+	assetClass string,
+	symbol string,
+	dataType string,
+	startTime int64,
+	endTime int64,
+	timeFrame string,
+	cal string,
+	fill bool, defaultingFunc func(*DataGapsRequest)) (DataGapsRequest, error) {
+
+	gapsRequest := NewDataGapsRequest(types.Source(source),
+		types.AssetClass(assetClass),
+		symbol,
+		types.DataType(dataType),
+		startTime,
+		endTime,
+		types.TimeFrame(timeFrame),
+		calendar.Name(cal),
+		fill,
+	)
+
+	defaultingFunc(&gapsRequest)
+	err := gapsRequest.Validate()
+	return gapsRequest, err
+}
+
+func NewDataGapsRequestFromExisting(gapsRequest *DataGapsRequest, defaultingFunc func(*DataGapsRequest)) (DataGapsRequest, error) {
+	return NewDataGapsRequestFromRaw(string(gapsRequest.Source),
+		string(gapsRequest.AssetClass),
+		gapsRequest.Symbol,
+		string(gapsRequest.DataType),
+		gapsRequest.StartTime,
+		gapsRequest.EndTime,
+		string(gapsRequest.TimeFrame),
+		string(gapsRequest.Calendar),
+		gapsRequest.Fill, defaultingFunc)
+}
@@ -0,0 +1,116 @@
+package requests
+
+import (
+	"encoding/json"
+	"time"
+
+	"tradingplatform/shared/logging"
+	"tradingplatform/shared/types"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Request to compute indicators on the bar streams of symbols, or to stop computing them
+type IndicatorStreamRequest struct {
+	Source     types.Source          `json:"source" validate:"required,min=3,isValidDataSource"`
+	AssetClass types.AssetClass      `json:"assetClass" validate:"required,min=3,isValidAssetClass"`
+	Symbols    []string              `json:"symbols" validate:"required,isValidSymbols"`
+	Operation  types.StreamRequestOp `json:"operation" validate:"required,min=3,isValidOperation"`
+	// Data type of the bar stream, bar or the bars built by the aggregator source (e.g. bar-5sec)
+	BarType types.DataType `json:"barType" validate:"required,isValidIndicatorBarType"`
+	// Indicators with their parameters (e.g. sma-20, rsi-14, macd-12-26-9)
+	Indicators []string `json:"indicators" validate:"isValidIndicators"`
+	// Length of the history of bars requested from the datastorage to warm up the indicators when
+	// they are added, no warm up if 0
+	Warmup time.Duration `json:"warmup" validate:"min=0"`
+}
+
+func (ir *IndicatorStreamRequest) Validate() error {
+	v := validator.New()
+	v.RegisterValidation("isValidDataSource", IsValidDataSource)
+	v.RegisterValidation("isValidAssetClass", IsValidAssetClass)
+	v.RegisterValidation("isValidOperation", IsValidOperationStream)
+	v.RegisterValidation("isValidSymbols", IsValidMultiSymbolStream)
+	v.RegisterValidation("isValidIndicatorBarType", IsValidIndicatorBarType)
+	v.RegisterValidation("isValidIndicators", IsValidIndicators)
+
+	err := v.Struct(ir)
+	return SummarizeError(err)
+}
+
+func (ir *IndicatorStreamRequest) JSON() []byte {
+	js, err := json.Marshal(ir)
+	if err != nil {
+		logging.Log().Error().
+			Err(err).
+			Msg("marshalling indicator stream request to json")
+		return []byte{}
+	}
+	return js
+}
+
+// WarmupRequest returns the request of the bars of a symbol the indicators are warmed up with, the
+// bars of the bar stream are minute bars
+func (ir *IndicatorStreamRequest) WarmupRequest(symbol string, now time.Time) DataRequest {
+	return NewDataRequest(ir.Source,
+		ir.AssetClass,
+		symbol,
+		types.DataGetOp,
+		types.Bar,
+		DefaultAccount,
+		now.Add(-ir.Warmup).UnixNano(),
+		now.UnixNano(),
+		types.OneMin,
+		false)
+}
+
+func NewIndicatorStreamRequest(source types.Source,
+	assetClass types.AssetClass,
+	symbols []string,
+	operation types.StreamRequestOp,
+	barType types.DataType,
+	indicators []string,
+	warmup time.Duration) IndicatorStreamRequest {
+
+	return IndicatorStreamRequest{
+		Source:     source,
+		AssetClass: assetClass,
+		Symbols:    symbols,
+		Operation:  operation,
+		BarType:    barType,
+		Indicators: indicators,
+		Warmup:     warmup,
+	}
+}
+
+func NewIndicatorStreamRequestFromRaw(source string,
+	assetClass string,
+	symbols []string,
+	operation string,
+	barType string,
+	indicators []string,
+	warmup time.Duration, defaultingFunc func(*IndicatorStreamRequest)) (IndicatorStreamRequest, error) {
+
+	indicatorRequest := NewIndicatorStreamRequest(types.Source(source),
+		types.AssetClass(assetClass),
+		symbols,
+		types.StreamRequestOp(operation),
+		types.DataType(barType),
+		indicators,
+		warmup,
+	)
+
+	defaultingFunc(&indicatorRequest)
+	err := indicatorRequest.Validate()
+	return indicatorRequest, err
+}
+
+func NewIndicatorStreamRequestFromExisting(indicatorRequest *IndicatorStreamRequest, defaultingFunc func(*IndicatorStreamRequest)) (IndicatorStreamRequest, error) {
+	return NewIndicatorStreamRequestFromRaw(string(indicatorRequest.Source),
+		string(indicatorRequest.AssetClass),
+		indicatorRequest.Symbols,
+		string(indicatorRequest.Operation),
+		string(indicatorRequest.BarType),
+		indicatorRequest.Indicators,
+		indicatorRequest.Warmup, defaultingFunc)
+}
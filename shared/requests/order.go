@@ -0,0 +1,145 @@
+package requests
+
+import (
+	"encoding/json"
+
+	"tradingplatform/shared/logging"
+	"tradingplatform/shared/types"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Request to submit an order to the execution component, to cancel or replace an order, or to get the
+// open orders and the positions of an account
+type OrderRequest struct {
+	Operation types.OrderRequestOp `json:"operation" validate:"required,isValidOperation"`
+	// Broker the orders of the account are routed to
+	Broker  types.Broker `json:"broker" validate:"required,isValidOrderBroker"`
+	Account Account      `json:"account" validate:"required,isValidOrderAccount"`
+	// ID of the order to cancel or replace
+	OrderID string `json:"orderId" validate:"isValidOrderID"`
+	// Optional ID of the order given by the client
+	ClientOrderID string `json:"clientOrderId"`
+	// Source of the quotes and trades the order is matched against
+	Source     types.Source     `json:"source" validate:"isValidOrderSource"`
+	AssetClass types.AssetClass `json:"assetClass" validate:"isValidOrderAssetClass"`
+	Symbol     string           `json:"symbol" validate:"isRequiredForSubmit"`
+	Side       types.OrderSide  `json:"side" validate:"isValidOrderSide"`
+	Type       types.OrderType  `json:"type" validate:"isValidOrderType"`
+	// Quantity of the order, the new quantity of a replaced order
+	Quantity float64 `json:"quantity" validate:"min=0,isRequiredForSubmit,isValidOrderReplacement"`
+	// Price of limit orders
+	LimitPrice float64 `json:"limitPrice" validate:"min=0,isValidOrderPrice=limit"`
+	// Price triggering stop orders
+	StopPrice float64 `json:"stopPrice" validate:"min=0,isValidOrderPrice=stop"`
+}
+
+func (or *OrderRequest) Validate() error {
+	v := validator.New()
+	v.RegisterValidation("isValidOperation", IsValidOrderOperation)
+	v.RegisterValidation("isValidOrderBroker", IsValidOrderBroker)
+	v.RegisterValidation("isValidOrderAccount", IsValidOrderAccount)
+	v.RegisterValidation("isValidOrderID", IsValidOrderID)
+	v.RegisterValidation("isValidOrderSource", IsValidOrderSource)
+	v.RegisterValidation("isValidOrderAssetClass", IsValidOrderAssetClass)
+	v.RegisterValidation("isRequiredForSubmit", IsRequiredForSubmit)
+	v.RegisterValidation("isValidOrderSide", IsValidOrderSide)
+	v.RegisterValidation("isValidOrderType", IsValidOrderType)
+	v.RegisterValidation("isValidOrderPrice", IsValidOrderPrice)
+	v.RegisterValidation("isValidOrderReplacement", IsValidOrderReplacement)
+
+	err := v.Struct(or)
+	return SummarizeError(err)
+}
+
+func (or *OrderRequest) JSON() []byte {
+	js, err := json.Marshal(or)
+	if err != nil {
+		logging.Log().Error().
+			Err(err).
+			Msg("marshalling order request to json")
+		return []byte{}
+	}
+	return js
+}
+
+func NewOrderRequest(operation types.OrderRequestOp,
+	broker types.Broker,
+	account Account,
+	orderID string,
+	clientOrderID string,
+	source types.Source,
+	assetClass types.AssetClass,
+	symbol string,
+	side types.OrderSide,
+	orderType types.OrderType,
+	quantity float64,
+	limitPrice float64,
+	stopPrice float64) OrderRequest {
+
+	return OrderRequest{
+		Operation:     operation,
+		Broker:        broker,
+		Account:       account,
+		OrderID:       orderID,
+		ClientOrderID: clientOrderID,
+		Source:        source,
+		AssetClass:    assetClass,
+		Symbol:        symbol,
+		Side:          side,
+		Type:          orderType,
+		Quantity:      quantity,
+		LimitPrice:    limitPrice,
+		StopPrice:     stopPrice,
+	}
+}
+
+func NewOrderRequestFromRaw(operation string,
+	broker string,
+	account string,
+	orderID string,
+	clientOrderID string,
+	source string,
+	assetClass string,
+	symbol string,
+	side string,
+	orderType string,
+	quantity float64,
+	limitPrice float64,
+	stopPrice float64, defaultingFunc func(*OrderRequest)) (OrderRequest, error) {
+
+	orderRequest := NewOrderRequest(types.OrderRequestOp(operation),
+		types.Broker(broker),
+		Account(account),
+		orderID,
+		clientOrderID,
+		types.Source(source),
+		types.AssetClass(assetClass),
+		symbol,
+		types.OrderSide(side),
+		types.OrderType(orderType),
+		quantity,
+		limitPrice,
+		stopPrice,
+	)
+
+	defaultingFunc(&orderRequest)
+	err := orderRequest.Validate()
+	return orderRequest, err
+}
+
+func NewOrderRequestFromExisting(orderRequest *OrderRequest, defaultingFunc func(*OrderRequest)) (OrderRequest, error) {
+	return NewOrderRequestFromRaw(string(orderRequest.Operation),
+		string(orderRequest.Broker),
+		string(orderRequest.Account),
+		orderRequest.OrderID,
+		orderRequest.ClientOrderID,
+		string(orderRequest.Source),
+		string(orderRequest.AssetClass),
+		orderRequest.Symbol,
+		string(orderRequest.Side),
+		string(orderRequest.Type),
+		orderRequest.Quantity,
+		orderRequest.LimitPrice,
+		orderRequest.StopPrice, defaultingFunc)
+}
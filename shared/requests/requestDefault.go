@@ -1,6 +1,11 @@
 package requests
 
-import "tradingplatform/shared/types"
+import (
+	"tradingplatform/shared/calendar"
+	"tradingplatform/shared/export"
+	"tradingplatform/shared/types"
+	"tradingplatform/shared/utils"
+)
 
 func DefaultForEmptyDataRequest(dr *DataRequest) {
 	if dr.Source == "" {
@@ -49,9 +54,93 @@ func DefaultForEmptyStreamAddDeleteRequest(sr *StreamRequest) {
 	}
 
 	if len(sr.DataTypes) == 0 {
-		providerDatatypes := GetDataTypeMap()[types.Source(sr.Source)]
-		for _, dtype := range providerDatatypes(types.AssetClass(sr.AssetClass)) {
+		for _, dtype := range GetSourceDataTypes(sr.Source, sr.AssetClass) {
 			sr.DataTypes = append(sr.DataTypes, types.DataType(dtype))
 		}
 	}
+	if sr.Replay != nil {
+		if sr.Replay.StoredSource == "" {
+			sr.Replay.StoredSource = types.Alpaca
+		}
+		sr.Replay.StartTime = utils.NormalizeTimestamp(sr.Replay.StartTime)
+		sr.Replay.EndTime = utils.NormalizeTimestamp(sr.Replay.EndTime)
+	}
+}
+
+func DefaultForEmptyDataGapsRequest(dr *DataGapsRequest) {
+	if dr.Source == "" {
+		dr.Source = types.Alpaca
+	}
+	if dr.DataType == "" {
+		dr.DataType = types.Bar
+	}
+	if dr.TimeFrame == "" {
+		dr.TimeFrame = types.OneMin
+		if dr.DataType == types.DailyBars {
+			dr.TimeFrame = types.OneDay
+		}
+	}
+	if dr.Calendar == "" {
+		dr.Calendar = calendar.ForAssetClass(dr.AssetClass).Name()
+	}
+}
+
+func DefaultForEmptyIndicatorStreamRequest(ir *IndicatorStreamRequest) {
+	if ir.Source == "" {
+		ir.Source = types.Alpaca
+	}
+	if ir.BarType == "" {
+		ir.BarType = types.Bar
+	}
+}
+
+func DefaultForEmptyBacktestRequest(br *BacktestRequest) {
+	if br.Source == "" {
+		br.Source = types.Alpaca
+	}
+	if len(br.DataTypes) == 0 {
+		br.DataTypes = []types.DataType{types.Bar}
+	}
+	if br.TimeFrame == "" {
+		br.TimeFrame = types.OneMin
+	}
+	if br.InitialCash == 0 {
+		br.InitialCash = DefaultInitialCash
+	}
+}
+
+func DefaultForEmptyOrderRequest(or *OrderRequest) {
+	if or.Broker == "" {
+		or.Broker = types.SimulatedBroker
+	}
+	if or.Account == "" {
+		or.Account = DefaultAccount
+	}
+	if or.Operation != types.OrderSubmitOp {
+		return
+	}
+	if or.Source == "" {
+		or.Source = types.Alpaca
+	}
+	if or.Type == "" {
+		or.Type = types.MarketOrder
+	}
+}
+
+func DefaultForEmptyAccountRequest(ar *AccountRequest) {
+	if ar.Operation == types.AccountAddOp && ar.Feed == "" {
+		ar.Feed = types.IEXFeed
+	}
+}
+
+func DefaultForEmptyExportRequest(er *ExportRequest) {
+	if er.Source == "" {
+		er.Source = types.Alpaca
+	}
+	if er.TimeFrame == "" {
+		er.TimeFrame = types.OneMin
+	}
+	if er.Format == "" {
+		er.Format = export.Parquet
+	}
 }
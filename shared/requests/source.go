@@ -0,0 +1,77 @@
+package requests
+
+import (
+	"sync"
+	"tradingplatform/shared/types"
+)
+
+// Describes a data source and the data types it supports for each asset class
+type SourceDescriptor struct {
+	Source    types.Source
+	DataTypes func(types.AssetClass) map[types.DataType]types.DataType
+}
+
+// Registry of the data sources, filled by the providers registered in the process. Only the dataprovider
+// runs providers: the other components forward the requests referring to a source to the dataprovider,
+// which validates the source and its data types
+var sourceRegistry = map[types.Source]SourceDescriptor{}
+var sourceRegistryLock sync.RWMutex
+
+// Register a data source, replacing any existing descriptor for the same source
+func RegisterSource(descriptor SourceDescriptor) {
+	if descriptor.DataTypes == nil {
+		descriptor.DataTypes = getNoDataTypes
+	}
+	sourceRegistryLock.Lock()
+	sourceRegistry[descriptor.Source] = descriptor
+	sourceRegistryLock.Unlock()
+}
+
+// Get the descriptor of a registered data source
+func GetSourceDescriptor(source types.Source) (SourceDescriptor, bool) {
+	sourceRegistryLock.RLock()
+	defer sourceRegistryLock.RUnlock()
+	descriptor, ok := sourceRegistry[source]
+	return descriptor, ok
+}
+
+// Whether sources are registered in the process, the requests referring to a source are only checked
+// against the registry where the providers run
+func HasRegisteredSources() bool {
+	sourceRegistryLock.RLock()
+	defer sourceRegistryLock.RUnlock()
+	return len(sourceRegistry) > 0
+}
+
+func GetDataTypeMap() map[types.Source]func(types.AssetClass) map[types.DataType]types.DataType {
+	sourceRegistryLock.RLock()
+	defer sourceRegistryLock.RUnlock()
+	dataTypeMap := make(map[types.Source]func(types.AssetClass) map[types.DataType]types.DataType, len(sourceRegistry))
+	for source, descriptor := range sourceRegistry {
+		dataTypeMap[source] = descriptor.DataTypes
+	}
+	return dataTypeMap
+}
+
+func GetDataSourceMap() map[string]types.Source {
+	sourceRegistryLock.RLock()
+	defer sourceRegistryLock.RUnlock()
+	sourceMap := make(map[string]types.Source, len(sourceRegistry))
+	for source := range sourceRegistry {
+		sourceMap[string(source)] = source
+	}
+	return sourceMap
+}
+
+// Get the data types supported by a source for a given asset class, nil if the source is unknown
+func GetSourceDataTypes(source types.Source, assetClass types.AssetClass) map[types.DataType]types.DataType {
+	descriptor, ok := GetSourceDescriptor(source)
+	if !ok {
+		return nil
+	}
+	return descriptor.DataTypes(assetClass)
+}
+
+func getNoDataTypes(assetClass types.AssetClass) map[types.DataType]types.DataType {
+	return nil
+}
@@ -24,7 +24,20 @@ type StreamRequest struct {
 	Symbols    []string              `json:"symbols" validate:"required,isValidSymbols"`
 	Operation  types.StreamRequestOp `json:"operation" validate:"required,min=3,isValidOperation"`
 	DataTypes  []types.DataType      `json:"dataTypes" validate:"required,isValidMultiDataType"`
-	Account    Account               `json:"account"`
+	Account    Account               `json:"account" validate:"required,isValidAccount"`
+	// Session replayed by the streams of the replay source, required to add them
+	Replay *ReplayOptions `json:"replay,omitempty" validate:"required_if=Source replay Operation add"`
+}
+
+// Session of stored data replayed by a replay stream request
+type ReplayOptions struct {
+	// Source of the stored data that is replayed (e.g. alpaca)
+	StoredSource types.Source `json:"storedSource" validate:"required,min=3"`
+	// Start and end (unix nanoseconds, end exclusive) of the replayed session
+	StartTime int64 `json:"startTime" validate:"required,min=1"`
+	EndTime   int64 `json:"endTime" validate:"required,gtfield=StartTime"`
+	// Speed multiplier applied to the original inter-event delays, 0 replays as fast as possible
+	Speed float64 `json:"speed" validate:"min=0"`
 }
 
 func (sr *StreamRequest) Validate() error {
@@ -180,7 +193,13 @@ func NewStreamRequestFromExisting(req *StreamRequest, defaultingFunc func(*Strea
 		string(req.Operation),
 		req.GetStrDataTypes(),
 		string(req.Account),
-		defaultingFunc)
+		func(sr *StreamRequest) {
+			if req.Replay != nil {
+				replay := *req.Replay
+				sr.Replay = &replay
+			}
+			defaultingFunc(sr)
+		})
 }
 
 func (sr *StreamRequest) GetSource() types.Source {
@@ -210,56 +229,16 @@ func (sr *StreamRequest) GetStrDataTypes() []string {
 	return strDataTypes
 }
 
-func (sr *StreamRequest) GetAccount() Account {
-	return sr.Account
-}
-
-func GetAccount() map[string]Account {
-	return map[string]Account{
-		"any":     AnyAccount,
-		"default": DefaultAccount,
-	}
+// The replayed session of a replay stream request, nil for the other sources
+func (sr *StreamRequest) GetReplay() *ReplayOptions {
+	return sr.Replay
 }
 
-func GetDataTypeMap() map[types.Source]func(types.AssetClass) map[types.DataType]types.DataType {
-	return map[types.Source]func(types.AssetClass) map[types.DataType]types.DataType{
-		types.Alpaca: getAlpacaDataType,
-	}
-}
-
-func getAlpacaDataType(assetClass types.AssetClass) map[types.DataType]types.DataType {
-	switch assetClass {
-	case types.Stock:
-		return map[types.DataType]types.DataType{
-			"bar":          types.Bar,
-			"daily-bars":   types.DailyBars,
-			"quotes":       types.Quotes,
-			"trades":       types.Trades,
-			"updated-bars": types.UpdatedBars,
-			"luld":         types.LULD,
-			"status":       types.Status,
-		}
-	case types.Crypto:
-		return map[types.DataType]types.DataType{
-			"bar":          types.Bar,
-			"orderbook":    types.Orderbook,
-			"daily-bars":   types.DailyBars,
-			"quotes":       types.Quotes,
-			"trades":       types.Trades,
-			"updated-bars": types.UpdatedBars,
-		}
-	case types.News:
-		return map[types.DataType]types.DataType{
-			"raw-text": types.RawText,
-		}
-	default:
-		return nil
-	}
+func (sr *StreamRequest) GetAccount() Account {
+	return sr.Account
 }
 
-func GetDataSourceMap() map[string]types.Source {
-	return map[string]types.Source{
-		"alpaca":   types.Alpaca,
-		"internal": types.Internal,
-	}
+// The account the stream is added to or removed from, any stands for the default account
+func (sr *StreamRequest) GetStreamAccount() Account {
+	return ResolveAccount(sr.Account)
 }
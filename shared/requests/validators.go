@@ -2,13 +2,25 @@ package requests
 
 import (
 	"fmt"
+	"path/filepath"
+	"strings"
+	"tradingplatform/shared/bars"
+	"tradingplatform/shared/calendar"
+	"tradingplatform/shared/export"
+	"tradingplatform/shared/indicators"
 	"tradingplatform/shared/types"
+	"unicode"
 
 	"github.com/go-playground/validator/v10"
 )
 
+// The source must be registered, any source is accepted by the components without registered sources
+// and validated by the dataprovider
 func IsValidDataSource(fl validator.FieldLevel) bool {
 	value := fl.Field().String()
+	if !HasRegisteredSources() {
+		return value != ""
+	}
 	_, exists := GetDataSourceMap()[value]
 	return exists
 }
@@ -38,22 +50,28 @@ func IsValidOperationStreamSubscribe(fl validator.FieldLevel) bool {
 }
 
 func IsValidDataType(fl validator.FieldLevel) bool {
+	if !HasRegisteredSources() {
+		return fl.Field().String() != ""
+	}
 	source := fl.Parent().FieldByName("Source").String()
 	assetClass := fl.Parent().FieldByName("AssetClass").String()
 	value := fl.Field().String()
-	dataTypeMap := GetDataTypeMap()[types.Source(source)]
-	_, exists := dataTypeMap(types.AssetClass(assetClass))[types.DataType(value)]
+	dataTypes := GetSourceDataTypes(types.Source(source), types.AssetClass(assetClass))
+	_, exists := dataTypes[types.DataType(value)]
 	return exists
 }
 
 func IsValidMultiDataType(fl validator.FieldLevel) bool {
+	if !HasRegisteredSources() {
+		return true
+	}
 	source := fl.Parent().FieldByName("Source").String()
 	assetClass := fl.Parent().FieldByName("AssetClass").String()
+	dataTypes := GetSourceDataTypes(types.Source(source), types.AssetClass(assetClass))
 	for i := 0; i < fl.Field().Len(); i++ {
 		// Get the value of the current element
 		value := fl.Field().Index(i).Interface().(types.DataType)
-		dataTypeMap := GetDataTypeMap()[types.Source(source)]
-		_, exists := dataTypeMap(types.AssetClass(assetClass))[types.DataType(value)]
+		_, exists := dataTypes[types.DataType(value)]
 
 		if !exists {
 			return false
@@ -61,9 +79,11 @@ func IsValidMultiDataType(fl validator.FieldLevel) bool {
 	}
 	return true
 }
+
+// Without data types all the data types of the source are streamed, they are filled in by the dataprovider
 func IsValidMultiDataTypeStream(fl validator.FieldLevel) bool {
 	operation := fl.Parent().FieldByName("Operation").String()
-	if operation == "get" {
+	if operation == "get" || !HasRegisteredSources() {
 		return true
 	}
 	return fl.Field().Len() > 0 && IsValidMultiDataType(fl)
@@ -77,12 +97,213 @@ func IsValidMultiSymbolStream(fl validator.FieldLevel) bool {
 	return fl.Field().Len() > 0
 }
 
+// The account must be any or the name of an account, whether the account is registered is checked by
+// the dataprovider handling the request
 func IsValidAccount(fl validator.FieldLevel) bool {
 	value := fl.Field().String()
-	_, exists := GetAccount()[value]
+	return value != "" && !strings.ContainsFunc(value, unicode.IsSpace)
+}
+
+// Gaps can be found for the data types of the bar tables of the datastorage
+func IsValidGapsDataType(fl validator.FieldLevel) bool {
+	value := types.DataType(fl.Field().String())
+	return value == types.Bar || value == types.DailyBars
+}
+
+// Indicators are computed on the bar stream of a source or on the bars built by the aggregator source
+func IsValidIndicatorBarType(fl validator.FieldLevel) bool {
+	value := types.DataType(fl.Field().String())
+	if value == types.Bar {
+		return true
+	}
+	_, err := bars.ParseSpecDataType(value)
+	return err == nil
+}
+
+func IsValidIndicators(fl validator.FieldLevel) bool {
+	operation := fl.Parent().FieldByName("Operation").String()
+	if operation == "get" {
+		return true
+	}
+	if fl.Field().Len() == 0 {
+		return false
+	}
+	for i := 0; i < fl.Field().Len(); i++ {
+		if _, err := indicators.ParseSpec(fl.Field().Index(i).String()); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Backtests run on the bars, trades and quotes of the asset class and on the news of the symbols
+func IsValidBacktestDataTypes(fl validator.FieldLevel) bool {
+	source := types.Source(fl.Parent().FieldByName("Source").String())
+	assetClass := types.AssetClass(fl.Parent().FieldByName("AssetClass").String())
+	if fl.Field().Len() == 0 {
+		return false
+	}
+	for i := 0; i < fl.Field().Len(); i++ {
+		value := types.DataType(fl.Field().Index(i).String())
+		switch value {
+		case types.Bar, types.Trades, types.Quotes:
+			if _, exists := GetSourceDataTypes(source, assetClass)[value]; HasRegisteredSources() && !exists {
+				return false
+			}
+		case types.RawText:
+			if _, exists := GetSourceDataTypes(source, types.News)[value]; HasRegisteredSources() && !exists {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Exports write the stored market data, news and sentiments
+func IsValidExportDataType(fl validator.FieldLevel) bool {
+	switch types.DataType(fl.Field().String()) {
+	case types.Bar, types.DailyBars, types.Trades, types.Quotes, types.Orderbook, types.RawText, types.Sentiment:
+		return true
+	}
+	return false
+}
+
+func IsValidExportFormat(fl validator.FieldLevel) bool {
+	value := export.Format(fl.Field().String())
+	return value == export.Parquet || value == export.CSV || value == export.JSONLines
+}
+
+// Export directories stay inside the export directory of the datastorage
+func IsValidExportDirectory(fl validator.FieldLevel) bool {
+	value := fl.Field().String()
+	return value == "" || filepath.IsLocal(value)
+}
+
+func IsValidOrderOperation(fl validator.FieldLevel) bool {
+	value := fl.Field().String()
+	_, exists := types.GetOrderRequestOpMap()[value]
+	return exists
+}
+
+func IsValidOrderBroker(fl validator.FieldLevel) bool {
+	value := fl.Field().String()
+	_, exists := types.GetBrokerMap()[value]
+	return exists
+}
+
+// Orders belong to a single account, the accounts of the brokers are configured in the execution
+// component
+func IsValidOrderAccount(fl validator.FieldLevel) bool {
+	value := fl.Field().String()
+	return value != "" && Account(value) != AnyAccount
+}
+
+func isOrderSubmit(fl validator.FieldLevel) bool {
+	return fl.Parent().FieldByName("Operation").String() == string(types.OrderSubmitOp)
+}
+
+// The ID of the order is required to cancel or replace it
+func IsValidOrderID(fl validator.FieldLevel) bool {
+	operation := types.OrderRequestOp(fl.Parent().FieldByName("Operation").String())
+	if operation != types.OrderCancelOp && operation != types.OrderReplaceOp {
+		return true
+	}
+	return fl.Field().String() != ""
+}
+
+// A replace request must change the quantity or a price of the order
+func IsValidOrderReplacement(fl validator.FieldLevel) bool {
+	parent := fl.Parent()
+	if types.OrderRequestOp(parent.FieldByName("Operation").String()) != types.OrderReplaceOp {
+		return true
+	}
+	return fl.Field().Float() > 0 ||
+		parent.FieldByName("LimitPrice").Float() > 0 ||
+		parent.FieldByName("StopPrice").Float() > 0
+}
+
+func IsValidOrderSource(fl validator.FieldLevel) bool {
+	return !isOrderSubmit(fl) || IsValidDataSource(fl)
+}
+
+func IsValidOrderAssetClass(fl validator.FieldLevel) bool {
+	return !isOrderSubmit(fl) || IsValidAssetClass(fl)
+}
+
+// The field must not be empty for the submit operation
+func IsRequiredForSubmit(fl validator.FieldLevel) bool {
+	return !isOrderSubmit(fl) || !fl.Field().IsZero()
+}
+
+func IsValidOrderSide(fl validator.FieldLevel) bool {
+	if !isOrderSubmit(fl) {
+		return true
+	}
+	_, exists := types.GetOrderSideMap()[fl.Field().String()]
+	return exists
+}
+
+func IsValidOrderType(fl validator.FieldLevel) bool {
+	if !isOrderSubmit(fl) {
+		return true
+	}
+	_, exists := types.GetOrderTypeMap()[fl.Field().String()]
+	return exists
+}
+
+// The price is required by the order type given as parameter and must be empty for the other types
+func IsValidOrderPrice(fl validator.FieldLevel) bool {
+	if !isOrderSubmit(fl) {
+		return true
+	}
+	orderType := fl.Parent().FieldByName("Type").String()
+	if orderType == fl.Param() {
+		return fl.Field().Float() > 0
+	}
+	return fl.Field().Float() == 0
+}
+
+func IsValidAccountOperation(fl validator.FieldLevel) bool {
+	value := fl.Field().String()
+	_, exists := types.GetAccountRequestOpMap()[value]
+	return exists
+}
+
+func isAccountAdd(fl validator.FieldLevel) bool {
+	return fl.Parent().FieldByName("Operation").String() == string(types.AccountAddOp)
+}
+
+// Accounts are added and removed by name, any is not the name of an account
+func IsValidAccountName(fl validator.FieldLevel) bool {
+	if fl.Parent().FieldByName("Operation").String() == string(types.AccountListOp) {
+		return true
+	}
+	value := fl.Field().String()
+	return value != "" && Account(value) != AnyAccount
+}
+
+// The field must not be empty for the add operation
+func IsRequiredForAdd(fl validator.FieldLevel) bool {
+	return !isAccountAdd(fl) || !fl.Field().IsZero()
+}
+
+func IsValidAccountFeed(fl validator.FieldLevel) bool {
+	if !isAccountAdd(fl) {
+		return true
+	}
+	_, exists := types.GetFeedMap()[fl.Field().String()]
+	return exists
+}
+
+func IsValidCalendar(fl validator.FieldLevel) bool {
+	value := fl.Field().String()
+	_, exists := calendar.GetCalendarNameMap()[value]
 	return exists
 }
 
+// Only the timeframes the providers and the storage support are valid
 func IsValidDataFrame(fl validator.FieldLevel) bool {
 	value := fl.Field().String()
 	_, exists := types.GetTimeFrameMap()[value]
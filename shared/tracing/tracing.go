@@ -0,0 +1,135 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"tradingplatform/shared/types"
+
+	"github.com/spf13/pflag"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type Exporter string
+
+const (
+	// Traces are propagated to the other components but not recorded
+	NoExporter Exporter = "none"
+	// Traces are sent to an OpenTelemetry collector over OTLP/HTTP
+	OTLPExporter Exporter = "otlp"
+	// Traces are appended to a file as JSON lines
+	FileExporter Exporter = "file"
+)
+
+const tracerName = "tradingplatform"
+
+// Configuration of the export of the traces of a component
+type Config struct {
+	Exporter Exporter
+	// URL of the OTLP/HTTP endpoint of the collector
+	Endpoint string
+	// Path of the file of the file exporter
+	File string
+	// Ratio of the traces started by the component that are recorded, traces started by another
+	// component are recorded if they were recorded there
+	SampleRatio float64
+}
+
+var enabled atomic.Bool
+
+// Add the flags configuring the tracing to a command
+func AddTracingFlags(flags *pflag.FlagSet) {
+	flags.String("trace-exporter", string(NoExporter), "Exporter of the traces (none, otlp or file)")
+	flags.String("trace-endpoint", "http://localhost:4318", "URL of the OTLP/HTTP endpoint the traces are sent to by the otlp exporter")
+	flags.String("trace-file", "traces.json", "Path of the file the traces are appended to by the file exporter")
+	flags.Float64("trace-sample-ratio", 1, "Ratio of the traces started by the component that are recorded")
+}
+
+// Initialize the tracing of a component from the flags added with AddTracingFlags, returns a function
+// flushing the recorded spans
+func InitFromFlags(component types.Component, flags *pflag.FlagSet) (func(), error) {
+	var cfg Config
+	exporter, _ := flags.GetString("trace-exporter")
+	cfg.Exporter = Exporter(exporter)
+	cfg.Endpoint, _ = flags.GetString("trace-endpoint")
+	cfg.File, _ = flags.GetString("trace-file")
+	cfg.SampleRatio, _ = flags.GetFloat64("trace-sample-ratio")
+	return Init(component, cfg)
+}
+
+// Initialize the tracing of a component, returns a function flushing the recorded spans. The trace
+// context is propagated in the headers of the NATS messages whatever the exporter
+func Init(component types.Component, cfg Config) (func(), error) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	var exporter sdktrace.SpanExporter
+	var file *os.File
+	var err error
+	switch cfg.Exporter {
+	case NoExporter, "":
+		return func() {}, nil
+	case OTLPExporter:
+		exporter, err = otlptracehttp.New(context.Background(), otlptracehttp.WithEndpointURL(cfg.Endpoint))
+	case FileExporter:
+		file, err = os.OpenFile(cfg.File, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return nil, err
+		}
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(file))
+	default:
+		return nil, fmt.Errorf("invalid trace exporter %s", cfg.Exporter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", string(component)))),
+	)
+	otel.SetTracerProvider(provider)
+	enabled.Store(true)
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		provider.Shutdown(ctx)
+		if file != nil {
+			file.Close()
+		}
+	}, nil
+}
+
+// IsEnabled returns whether the spans of the component are exported
+func IsEnabled() bool {
+	return enabled.Load()
+}
+
+// Start a span as child of the span of a context
+func Start(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// End a span, recording the error the spanned operation failed with
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,70 @@
+package types
+
+import "encoding/json"
+
+type Feed string
+type AccountRequestOp string
+
+const (
+	// Stock data of the IEX exchange, available to all Alpaca accounts
+	IEXFeed Feed = "iex"
+	// Stock data of all US exchanges, requires an Alpaca subscription
+	SIPFeed Feed = "sip"
+
+	AccountAddOp    AccountRequestOp = "add"
+	AccountRemoveOp AccountRequestOp = "remove"
+	AccountListOp   AccountRequestOp = "list"
+)
+
+func GetFeedMap() map[string]Feed {
+	return map[string]Feed{
+		"iex": IEXFeed,
+		"sip": SIPFeed,
+	}
+}
+
+func GetAccountRequestOpMap() map[string]AccountRequestOp {
+	return map[string]AccountRequestOp{
+		"add":    AccountAddOp,
+		"remove": AccountRemoveOp,
+		"list":   AccountListOp,
+	}
+}
+
+// An account of the registry without its secret
+type AccountSummary struct {
+	Name string
+	// The first characters of the key of the account
+	Key  string
+	Feed Feed
+	Live bool
+}
+
+type AccountResponse struct {
+	Response
+	Accounts []AccountSummary
+}
+
+func NewAccountError(err error) AccountResponse {
+	return NewAccountResponse(Failure, "", err, nil)
+}
+
+func NewAccountResponse(status OpStatus, message string, err error, accounts []AccountSummary) AccountResponse {
+	return AccountResponse{
+		Response: NewResponse(status, message, err),
+		Accounts: accounts,
+	}
+}
+
+func (r AccountResponse) Respond() string {
+	response, err := json.Marshal(r)
+	if err != nil {
+		marshalError := &Response{
+			Err:     "Error marshalling response",
+			Message: "",
+			Status:  Failure,
+		}
+		response, _ = json.Marshal(marshalError)
+	}
+	return string(response)
+}
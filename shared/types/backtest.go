@@ -0,0 +1,81 @@
+package types
+
+import "encoding/json"
+
+// A fill of an order of a backtest
+type BacktestFill struct {
+	OrderID   int64
+	Timestamp int64
+	Symbol    string
+	// buy or sell
+	Side     string
+	Quantity float64
+	// Price of the fill, including the slippage
+	Price      float64
+	Commission float64
+	// Profit and loss realized by the part of the fill closing a position, before commission
+	RealizedPnL float64
+}
+
+// The value of the account of a backtest at a point in time
+type EquityPoint struct {
+	Timestamp int64
+	Equity    float64
+	Cash      float64
+}
+
+// Summary statistics of a backtest, returns and drawdowns are fractions of the equity
+type BacktestSummary struct {
+	InitialCash float64
+	FinalEquity float64
+	TotalReturn float64
+	// Annualized Sharpe ratio of the returns between the points of the equity curve, without
+	// risk-free rate
+	Sharpe float64
+	// Largest decline of the equity from a previous peak
+	MaxDrawdown float64
+	// Notional traded divided by the average equity
+	Turnover   float64
+	Fills      int
+	Commission float64
+	// Number of market data events the strategy received
+	Events int
+}
+
+type BacktestResponse struct {
+	Response
+	Summary     BacktestSummary
+	EquityCurve []EquityPoint
+	Trades      []BacktestFill
+}
+
+func NewBacktestError(err error) BacktestResponse {
+	return NewBacktestResponse(Failure, "", err, BacktestSummary{}, nil, nil)
+}
+
+func NewBacktestResponse(status OpStatus,
+	message string,
+	err error,
+	summary BacktestSummary,
+	equityCurve []EquityPoint,
+	trades []BacktestFill) BacktestResponse {
+	return BacktestResponse{
+		Response:    NewResponse(status, message, err),
+		Summary:     summary,
+		EquityCurve: equityCurve,
+		Trades:      trades,
+	}
+}
+
+func (r BacktestResponse) Respond() string {
+	response, err := json.Marshal(r)
+	if err != nil {
+		marshalError := &Response{
+			Err:     "Error marshalling response",
+			Message: "",
+			Status:  Failure,
+		}
+		response, _ = json.Marshal(marshalError)
+	}
+	return string(response)
+}
@@ -1,5 +1,10 @@
 package types
 
+import (
+	"regexp"
+	"strconv"
+)
+
 type TimeFrame string
 type DataRequestOp string
 
@@ -13,10 +18,21 @@ const (
 	DataGetOp   DataRequestOp = "get"
 )
 
+const (
+	FiveMin    TimeFrame = "5min"
+	FifteenMin TimeFrame = "15min"
+	ThirtyMin  TimeFrame = "30min"
+	FourHour   TimeFrame = "4hour"
+)
+
 func GetTimeFrameMap() map[string]TimeFrame {
 	return map[string]TimeFrame{
 		"1min":   OneMin,
+		"5min":   FiveMin,
+		"15min":  FifteenMin,
+		"30min":  ThirtyMin,
 		"1hour":  OneHour,
+		"4hour":  FourHour,
 		"1day":   OneDay,
 		"1week":  OneWeek,
 		"1month": OneMonth,
@@ -24,6 +40,22 @@ func GetTimeFrameMap() map[string]TimeFrame {
 	}
 }
 
+var timeFramePattern = regexp.MustCompile(`^([1-9][0-9]*)(min|hour|day|week|month)$`)
+
+// ParseTimeFrame splits a timeframe made of a number of units (e.g. 5min, 4hour, 3month) into the
+// number and the single unit timeframe (1min, 1hour, 1day, 1week or 1month)
+func ParseTimeFrame(timeFrame TimeFrame) (int, TimeFrame, bool) {
+	match := timeFramePattern.FindStringSubmatch(string(timeFrame))
+	if match == nil {
+		return 0, "", false
+	}
+	count, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 0, "", false
+	}
+	return count, TimeFrame("1" + match[2]), true
+}
+
 func GetDataRequestOpMap() map[string]DataRequestOp {
 	return map[string]DataRequestOp{
 		"get": DataGetOp,
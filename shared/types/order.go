@@ -0,0 +1,75 @@
+package types
+
+type OrderSide string
+type OrderType string
+type OrderStatus string
+type OrderRequestOp string
+type Broker string
+
+const (
+	Buy  OrderSide = "buy"
+	Sell OrderSide = "sell"
+
+	// Market orders are filled at the next price of their symbol
+	MarketOrder OrderType = "market"
+	// Limit orders are filled at their limit price or better
+	LimitOrder OrderType = "limit"
+	// Stop orders become market orders once the price of their symbol reaches their stop price
+	StopOrder OrderType = "stop"
+
+	OrderNew             OrderStatus = "new"
+	OrderPartiallyFilled OrderStatus = "partially-filled"
+	OrderFilled          OrderStatus = "filled"
+	OrderCanceled        OrderStatus = "canceled"
+	// The order was replaced by a new order with the changes of a replace request
+	OrderReplaced OrderStatus = "replaced"
+	OrderExpired  OrderStatus = "expired"
+	OrderRejected OrderStatus = "rejected"
+
+	OrderSubmitOp OrderRequestOp = "submit"
+	OrderCancelOp OrderRequestOp = "cancel"
+	OrderGetOp    OrderRequestOp = "get"
+	// Replace the quantity or the prices of an open order
+	OrderReplaceOp OrderRequestOp = "replace"
+
+	// Orders matched by the execution component against the market data of the dataprovider
+	SimulatedBroker Broker = "simulated"
+	// Orders routed to the Alpaca trading API of the account
+	AlpacaBroker Broker = "alpaca"
+)
+
+func GetOrderSideMap() map[string]OrderSide {
+	return map[string]OrderSide{
+		"buy":  Buy,
+		"sell": Sell,
+	}
+}
+
+func GetOrderTypeMap() map[string]OrderType {
+	return map[string]OrderType{
+		"market": MarketOrder,
+		"limit":  LimitOrder,
+		"stop":   StopOrder,
+	}
+}
+
+func GetOrderRequestOpMap() map[string]OrderRequestOp {
+	return map[string]OrderRequestOp{
+		"submit":  OrderSubmitOp,
+		"cancel":  OrderCancelOp,
+		"get":     OrderGetOp,
+		"replace": OrderReplaceOp,
+	}
+}
+
+func GetBrokerMap() map[string]Broker {
+	return map[string]Broker{
+		"simulated": SimulatedBroker,
+		"alpaca":    AlpacaBroker,
+	}
+}
+
+// IsOpen returns whether an order with the status can still be filled
+func (s OrderStatus) IsOpen() bool {
+	return s == OrderNew || s == OrderPartiallyFilled
+}
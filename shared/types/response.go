@@ -2,8 +2,29 @@ package types
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 )
 
+// ErrNoData is matched with errors.Is by the errors of the data requests no data matches, on the
+// component answering the request and, through ResponseError, on the one receiving the answer
+var ErrNoData = errors.New("no data found")
+
+// NewNoDataError returns the error of a data request no data of a symbol matches
+func NewNoDataError(symbol string) error {
+	return fmt.Errorf("%w for %s", ErrNoData, symbol)
+}
+
+// ResponseError returns the error carried by the Err field of a response, the errors of the data
+// requests no data matches wrap ErrNoData
+func ResponseError(err string) error {
+	if rest, ok := strings.CutPrefix(err, ErrNoData.Error()); ok {
+		return fmt.Errorf("%w%s", ErrNoData, rest)
+	}
+	return errors.New(err)
+}
+
 type DataResponse struct {
 	Err           string
 	Message       string
@@ -42,6 +63,8 @@ type StreamResponse struct {
 	// TODO: Implement response that provides list of topics
 	Topics  string
 	Streams string
+	// State of the connections of the clients streaming the data, for the sources keeping connections
+	Connections string `json:",omitempty"`
 }
 
 func NewError(err error) Response {
@@ -145,3 +168,100 @@ func (r DataResponse) Respond() string {
 	}
 	return string(response)
 }
+
+// A range of missing data, between Start (inclusive) and End (exclusive) in Unix nanoseconds
+type DataGap struct {
+	Start int64
+	End   int64
+	// Number of missing bars in the range
+	Missing int
+	// Number of missing bars stored after requesting the range from the dataprovider
+	Filled int
+	Err    string `json:",omitempty"`
+}
+
+type DataGapsResponse struct {
+	Response
+	Gaps    []DataGap
+	Missing int
+	Filled  int
+}
+
+func NewDataGapsError(err error) DataGapsResponse {
+	return NewDataGapsResponse(Failure, "", err, nil)
+}
+
+func NewDataGapsResponse(status OpStatus, message string, err error, gaps []DataGap) DataGapsResponse {
+	newResponse := DataGapsResponse{
+		Response: NewResponse(status, message, err),
+		Gaps:     gaps,
+	}
+	for _, gap := range gaps {
+		newResponse.Missing += gap.Missing
+		newResponse.Filled += gap.Filled
+	}
+	return newResponse
+}
+
+func (r DataGapsResponse) Respond() string {
+	response, err := json.Marshal(r)
+	if err != nil {
+		marshalError := &Response{
+			Err:     "Error marshalling response",
+			Message: "",
+			Status:  Failure,
+		}
+		response, _ = json.Marshal(marshalError)
+	}
+	return string(response)
+}
+
+// A file written by an export, Path is relative to the directory of the export
+type ExportedFile struct {
+	Path     string
+	DataType DataType
+	Symbol   string
+	// Day of the rows of the file (2006-01-02, UTC)
+	Date string
+	Rows int
+	Size int64
+	// Hex encoded SHA-256 of the content of the file
+	SHA256 string
+}
+
+// Manifest of the files written by an export, the files written before a failure are listed as well
+type ExportResponse struct {
+	Response
+	Directory string
+	Files     []ExportedFile
+	Rows      int
+}
+
+func NewExportError(err error) ExportResponse {
+	return NewExportResponse(Failure, "", err, "", nil)
+}
+
+func NewExportResponse(status OpStatus, message string, err error, directory string, files []ExportedFile) ExportResponse {
+	newResponse := ExportResponse{
+		Response:  NewResponse(status, message, err),
+		Directory: directory,
+		Files:     files,
+	}
+	for _, file := range files {
+		newResponse.Rows += file.Rows
+	}
+	return newResponse
+}
+
+func (r ExportResponse) Respond() string {
+	response, err := json.Marshal(r)
+	if err != nil {
+		marshalError := &Response{
+			Err:     "Error marshalling response",
+			Message: "",
+			Status:  Failure,
+		}
+		response, _ = json.Marshal(marshalError)
+	}
+	return string(response)
+}
@@ -13,12 +13,20 @@ const (
 	DataProvider      Component     = "dataprovider"
 	DataStorage       Component     = "datastorage"
 	SentimentAnalyzer Component     = "sentiment-analyzer"
+	Indicators        Component     = "indicators"
+	Backtest          Component     = "backtest"
+	Execution         Component     = "execution"
 	Command           Functionality = "command"
 	Stream            Functionality = "stream"
 	Data              Functionality = "data"
 	Logging           Functionality = "logging"
+	QueueControl      Functionality = "queue"
 	Alpaca            Source        = "alpaca"
 	Internal          Source        = "internal"
+	Replay            Source        = "replay"
+	Synthetic         Source        = "synthetic"
+	Binance           Source        = "binance"
+	Aggregator        Source        = "aggregator"
 	Crypto            AssetClass    = "crypto"
 	Stock             AssetClass    = "stock"
 	News              AssetClass    = "news"
@@ -31,21 +39,46 @@ const (
 	DailyBars         DataType    = "daily-bars"
 	Quotes            DataType    = "quotes"
 	Trades            DataType    = "trades"
+	TradeUpdates      DataType    = "trade-updates"
 	UpdatedBars       DataType    = "updated-bars"
 	RawText           DataType    = "raw-text"
 	Sentiment         DataType    = "sentiment"
 	NewsWithSentiment DataType    = "news-with-sentiment"
+	Indicator         DataType    = "indicator"
+	Orders            DataType    = "orders"
+	Fills             DataType    = "fills"
+	Positions         DataType    = "positions"
+	Connection        DataType    = "connection"
 	Success           OpStatus    = "success"
 	Failure           OpStatus    = "failure"
 	Ollama            LLMProvider = "ollama"
 	GPT4All           LLMProvider = "gpt4all"
 )
 
+// Values of the Update field of a trade correcting or cancelling a trade sent before with the same ID,
+// such trades are published with the TradeUpdates data type on their own topic
+const (
+	TradeCanceled  = "canceled"
+	TradeIncorrect = "incorrect"
+	TradeCorrected = "corrected"
+)
+
 const (
 	Plain    SentimentAnalysisProcess = "plain"
 	Semantic SentimentAnalysisProcess = "semantic"
 )
 
+func GetComponentMap() map[string]Component {
+	return map[string]Component{
+		"dataprovider":       DataProvider,
+		"datastorage":        DataStorage,
+		"sentiment-analyzer": SentimentAnalyzer,
+		"indicators":         Indicators,
+		"backtest":           Backtest,
+		"execution":          Execution,
+	}
+}
+
 func GetAssetClassMap() map[string]AssetClass {
 	return map[string]AssetClass{
 		"stock":  Stock,
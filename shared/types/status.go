@@ -0,0 +1,46 @@
+package types
+
+import "encoding/json"
+
+// Result of checking the connection to a database a component depends on
+type DatabaseStatus struct {
+	Name      string
+	Connected bool
+	Err       string `json:",omitempty"`
+}
+
+type StatusResponse struct {
+	Response
+	Component Component
+	// Start of the component in Unix nanoseconds
+	StartedAt int64
+	// Time since the start of the component, in seconds
+	Uptime float64
+	// State of the NATS connection the component receives its commands on
+	Nats string
+	// Whether the component handles commands and all its databases are connected
+	Ready bool
+	// Number of commands being handled, without the status command
+	InFlightCommands int
+	// Response topics of the data queues being sent
+	Queues []string
+	// Active streams or subscribed topics of the component
+	Streams []string
+	// Streams of the local database that could not be subscribed again on startup, they are kept
+	// and subscribed again on the next startup
+	FailedStreams []string
+	Databases     []DatabaseStatus
+}
+
+func (r StatusResponse) Respond() string {
+	response, err := json.Marshal(r)
+	if err != nil {
+		marshalError := &Response{
+			Err:     "Error marshalling response",
+			Message: "",
+			Status:  Failure,
+		}
+		response, _ = json.Marshal(marshalError)
+	}
+	return string(response)
+}
@@ -1,12 +1,22 @@
 package types
 
 type StreamRequestOp string
+type ConnectionState string
 
 const (
 	StreamAddOp    StreamRequestOp = "add"
 	StreamRemoveOp StreamRequestOp = "remove"
 
 	StreamGetOp StreamRequestOp = "get"
+
+	// The connection of the client streaming the data of a source is up
+	Connected ConnectionState = "connected"
+	// The connection dropped, the client reconnects by itself
+	Disconnected ConnectionState = "disconnected"
+	// The client gave up reconnecting, a new client is connected and subscribed to the streams
+	Reconnecting ConnectionState = "reconnecting"
+	// The client was closed
+	Closed ConnectionState = "closed"
 )
 
 func GetStreamRequestOpMap() map[string]StreamRequestOp {
@@ -8,58 +8,85 @@ import (
 	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
-func HandleEntityQueue[T protoreflect.ProtoMessage](msg []*entities.Message, sample T, f func([]T)) {
+// HandleEntityQueue unmarshals the entities of a queue of messages, batch messages are unpacked,
+// and calls f with all of them
+func HandleEntityQueue[T protoreflect.ProtoMessage](msg []*entities.Message, sample T, f func([]T) error) error {
+	msg = entities.UnpackAll(msg)
 	entity := make([]T, len(msg))
 	for i, m := range msg {
 		clone := proto.Clone(sample)
 		err := proto.Unmarshal(m.Payload, clone)
 		if err != nil {
 			logging.Log().Error().Err(err).Msg("unmarshalling entity")
-			return
+			return err
 		}
+		entities.SyncTimestamps(clone)
 		entity[i] = clone.(T)
 	}
 
-	f(entity)
+	return f(entity)
 }
 
-func HandleEntity[T protoreflect.ProtoMessage](msg *entities.Message, sample T, f func(T)) {
+// HandleEntity unmarshals the entity of a message into sample and calls f with it. f is called
+// for each entity of a batch message, stopping at the first error
+func HandleEntity[T protoreflect.ProtoMessage](msg *entities.Message, sample T, f func(T) error) error {
+	if msg.IsBatch() {
+		for _, part := range msg.Unpack() {
+			if err := HandleEntity(part, proto.Clone(sample).(T), f); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
 	err := proto.Unmarshal(msg.Payload, sample)
 	if err != nil {
 		logging.Log().Error().Err(err).Msg("unmarshalling entity")
-		return
+		return err
 	}
+	entities.SyncTimestamps(sample)
 
-	f(sample)
+	return f(sample)
 }
 
-func HandleEntityWithConversion[T protoreflect.ProtoMessage, I any](msg *entities.Message, sample T, f func(I), converter func(T) I) {
+func HandleEntityWithConversion[T protoreflect.ProtoMessage, I any](msg *entities.Message, sample T, f func(I) error, converter func(T) I) error {
+	if msg.IsBatch() {
+		for _, part := range msg.Unpack() {
+			if err := HandleEntityWithConversion(part, sample, f, converter); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
 	entity := proto.Clone(sample)
 	err := proto.Unmarshal(msg.Payload, entity)
 	if err != nil {
 		logging.Log().Error().Err(err).Msg("unmarshalling entity")
-		return
+		return err
 	}
+	entities.SyncTimestamps(entity)
 
-	f(converter(entity.(T)))
+	return f(converter(entity.(T)))
 }
 
 // HandleEntityQueueWithConversion handles a queue of messages and performs conversion on the entities.
 // It takes in a slice of messages, a sample entity of type T, a function f to handle the converted entities,
 // and a converter function to convert the entities from type T to type I.
-// The function iterates over the messages, unmarshals each message into a clone of the sample entity,
-// and stores the converted entities in a slice. Finally, it calls the function f with the converted entities.
-func HandleEntityQueueWithConversion[T protoreflect.ProtoMessage, I any](msg []*entities.Message, sample T, f func([]I), converter func([]T) []I) {
+// Batch messages are unpacked first. The function iterates over the messages, unmarshals each message into a clone of the sample entity,
+// and stores the converted entities in a slice. Finally, it calls the function f with the converted entities
+// and returns its error.
+func HandleEntityQueueWithConversion[T protoreflect.ProtoMessage, I any](msg []*entities.Message, sample T, f func([]I) error, converter func([]T) []I) error {
+	msg = entities.UnpackAll(msg)
 	entity := make([]T, len(msg))
 	for i, m := range msg {
 		clone := proto.Clone(sample)
 		err := proto.Unmarshal(m.Payload, clone)
 		if err != nil {
 			logging.Log().Error().Err(err).Msg("unmarshalling entity")
-			return
+			return err
 		}
+		entities.SyncTimestamps(clone)
 		entity[i] = clone.(T)
 	}
 
-	f(converter(entity))
+	return f(converter(entity))
 }
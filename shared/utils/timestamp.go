@@ -0,0 +1,16 @@
+package utils
+
+import "time"
+
+// Timestamps below this value are assumed to be unix seconds rather than unix nanoseconds,
+// in seconds it is past the year 5000, in nanoseconds it is less than two minutes after the epoch
+const MaxSecondsTimestamp int64 = 1e11
+
+// Normalize a unix timestamp to nanoseconds, timestamps that are too small to be nanoseconds
+// are treated as seconds
+func NormalizeTimestamp(ts int64) int64 {
+	if ts > -MaxSecondsTimestamp && ts < MaxSecondsTimestamp {
+		return ts * int64(time.Second)
+	}
+	return ts
+}
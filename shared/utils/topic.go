@@ -23,6 +23,9 @@ func (t Topic) Generate() string {
 	if t.Functionality == types.Command {
 		return base
 	}
+	if t.Functionality == types.QueueControl {
+		return fmt.Sprintf("%s.%s", base, t.QueueID)
+	}
 	if t.Source != "" {
 		base = fmt.Sprintf("%s.%s", base, t.Source)
 	}
@@ -89,6 +92,15 @@ func NewCommandTopic(component types.Component) Topic {
 	}
 }
 
+// NewQueueControlTopic returns the topic on which the consumer of a data queue controls its producer
+func NewQueueControlTopic(component types.Component, queueID string) Topic {
+	return Topic{
+		Component:     component,
+		Functionality: types.QueueControl,
+		QueueID:       queueID,
+	}
+}
+
 func NewLoggingTopic(component types.Component) Topic {
 	return Topic{
 		Component:     component,
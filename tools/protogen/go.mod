@@ -0,0 +1,10 @@
+module tradingplatform/tools/protogen
+
+go 1.21
+
+require (
+	github.com/bufbuild/protocompile v0.6.0
+	google.golang.org/protobuf v1.31.0
+)
+
+require golang.org/x/sync v0.3.0 // indirect
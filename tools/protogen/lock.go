@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// The schema lock records every field that was ever published, a change is breaking if a
+// locked field disappears or changes number, type or label, or if a field number is reused.
+// Removing a field is only allowed if its number is reserved in the message.
+type schemaLock struct {
+	Messages map[string]*lockedMessage `json:"messages"`
+}
+
+type lockedMessage struct {
+	Fields   map[string]lockedField `json:"fields"`
+	Reserved []int32                `json:"reserved,omitempty"`
+}
+
+type lockedField struct {
+	Number   int32  `json:"number"`
+	Type     string `json:"type"`
+	TypeName string `json:"typeName,omitempty"`
+	Label    string `json:"label"`
+}
+
+// Read the lock file, a missing lock file is an empty lock
+func readLock(path string) (*schemaLock, error) {
+	lock := &schemaLock{Messages: map[string]*lockedMessage{}}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return lock, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, lock); err != nil {
+		return nil, fmt.Errorf("reading schema lock %s: %w", path, err)
+	}
+	return lock, nil
+}
+
+// Write the lock file
+func writeLock(path string, lock *schemaLock) error {
+	b, err := json.MarshalIndent(lock, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(b, '\n'), 0o644)
+}
+
+// Build the lock of the messages defined in the given files
+func newLock(fds []*descriptorpb.FileDescriptorProto) *schemaLock {
+	lock := &schemaLock{Messages: map[string]*lockedMessage{}}
+	for _, fd := range fds {
+		for _, msg := range fd.GetMessageType() {
+			addMessage(lock, "."+fd.GetPackage(), msg)
+		}
+	}
+	return lock
+}
+
+func addMessage(lock *schemaLock, prefix string, msg *descriptorpb.DescriptorProto) {
+	name := prefix + "." + msg.GetName()
+	locked := &lockedMessage{Fields: map[string]lockedField{}}
+	for _, field := range msg.GetField() {
+		locked.Fields[field.GetName()] = lockedField{
+			Number:   field.GetNumber(),
+			Type:     field.GetType().String(),
+			TypeName: field.GetTypeName(),
+			Label:    field.GetLabel().String(),
+		}
+	}
+	for _, r := range msg.GetReservedRange() {
+		// Reserved ranges are half-open
+		for n := r.GetStart(); n < r.GetEnd(); n++ {
+			locked.Reserved = append(locked.Reserved, n)
+		}
+	}
+	lock.Messages[name] = locked
+	for _, nested := range msg.GetNestedType() {
+		addMessage(lock, name, nested)
+	}
+}
+
+// Check that the current schema is backwards compatible with the lock and return the violations
+func checkCompatibility(lock *schemaLock, current *schemaLock) []string {
+	var violations []string
+	messageNames := make([]string, 0, len(lock.Messages))
+	for name := range lock.Messages {
+		messageNames = append(messageNames, name)
+	}
+	sort.Strings(messageNames)
+
+	for _, name := range messageNames {
+		locked := lock.Messages[name]
+		msg, ok := current.Messages[name]
+		if !ok {
+			violations = append(violations, fmt.Sprintf("message %s was removed", name))
+			continue
+		}
+		reserved := map[int32]bool{}
+		for _, n := range msg.Reserved {
+			reserved[n] = true
+		}
+		byNumber := map[int32]string{}
+		for fieldName, field := range msg.Fields {
+			byNumber[field.Number] = fieldName
+		}
+
+		fieldNames := make([]string, 0, len(locked.Fields))
+		for fieldName := range locked.Fields {
+			fieldNames = append(fieldNames, fieldName)
+		}
+		sort.Strings(fieldNames)
+		for _, fieldName := range fieldNames {
+			lockedField := locked.Fields[fieldName]
+			field, ok := msg.Fields[fieldName]
+			if !ok {
+				if other, used := byNumber[lockedField.Number]; used {
+					violations = append(violations, fmt.Sprintf("%s: field number %d of removed field %s is reused by %s",
+						name, lockedField.Number, fieldName, other))
+				} else if !reserved[lockedField.Number] {
+					violations = append(violations, fmt.Sprintf("%s: field %s (%d) was removed without reserving its number",
+						name, fieldName, lockedField.Number))
+				}
+				continue
+			}
+			if field.Number != lockedField.Number {
+				violations = append(violations, fmt.Sprintf("%s: field %s changed number from %d to %d",
+					name, fieldName, lockedField.Number, field.Number))
+			}
+			if field.Type != lockedField.Type || field.TypeName != lockedField.TypeName {
+				violations = append(violations, fmt.Sprintf("%s: field %s changed type from %s%s to %s%s",
+					name, fieldName, lockedField.Type, lockedField.TypeName, field.Type, field.TypeName))
+			}
+			if field.Label != lockedField.Label {
+				violations = append(violations, fmt.Sprintf("%s: field %s changed label from %s to %s",
+					name, fieldName, lockedField.Label, field.Label))
+			}
+		}
+	}
+	return violations
+}
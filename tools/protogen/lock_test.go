@@ -0,0 +1,161 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// Directory of the shared entities the schema lock belongs to
+const sharedRoot = "../../shared"
+
+// The schema lock checked in with the .proto files must accept them and record all of their fields,
+// run protogen with -update-lock after a compatible change of the schema
+func TestSchemaLockMatchesProtoFiles(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join(sharedRoot, "proto", "*.proto"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatalf("no .proto files found in %s", filepath.Join(sharedRoot, "proto"))
+	}
+	for i, file := range files {
+		rel, err := filepath.Rel(sharedRoot, file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[i] = filepath.ToSlash(rel)
+	}
+	sort.Strings(files)
+	fds, err := compile(sharedRoot, files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lock, err := readLock(filepath.Join(sharedRoot, "proto", "schema.lock.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	current := newLock(fds)
+
+	for _, violation := range checkCompatibility(lock, current) {
+		t.Error(violation)
+	}
+	for _, difference := range unlockedFields(lock, current) {
+		t.Errorf("%s is not recorded in the schema lock", difference)
+	}
+}
+
+func TestCheckCompatibility(t *testing.T) {
+	stringField := func(number int32) lockedField {
+		return lockedField{Number: number, Type: "TYPE_STRING", Label: "LABEL_OPTIONAL"}
+	}
+	locked := &schemaLock{Messages: map[string]*lockedMessage{
+		".entities.Bar": {Fields: map[string]lockedField{
+			"Symbol": stringField(1),
+			"Source": stringField(2),
+		}},
+	}}
+	tests := []struct {
+		name      string
+		current   map[string]lockedField
+		reserved  []int32
+		violation string
+	}{
+		{
+			name:    "unchanged",
+			current: map[string]lockedField{"Symbol": stringField(1), "Source": stringField(2)},
+		},
+		{
+			name:    "field added",
+			current: map[string]lockedField{"Symbol": stringField(1), "Source": stringField(2), "Tape": stringField(3)},
+		},
+		{
+			name:     "field removed with its number reserved",
+			current:  map[string]lockedField{"Symbol": stringField(1)},
+			reserved: []int32{2},
+		},
+		{
+			name:      "field removed",
+			current:   map[string]lockedField{"Symbol": stringField(1)},
+			violation: "field Source (2) was removed without reserving its number",
+		},
+		{
+			name:      "field number reused",
+			current:   map[string]lockedField{"Symbol": stringField(1), "Tape": stringField(2)},
+			violation: "field number 2 of removed field Source is reused by Tape",
+		},
+		{
+			name:      "field renumbered",
+			current:   map[string]lockedField{"Symbol": stringField(1), "Source": stringField(3)},
+			violation: "field Source changed number from 2 to 3",
+		},
+		{
+			name: "field type changed",
+			current: map[string]lockedField{
+				"Symbol": stringField(1),
+				"Source": {Number: 2, Type: "TYPE_INT64", Label: "LABEL_OPTIONAL"},
+			},
+			violation: "field Source changed type from TYPE_STRING to TYPE_INT64",
+		},
+		{
+			name: "field label changed",
+			current: map[string]lockedField{
+				"Symbol": stringField(1),
+				"Source": {Number: 2, Type: "TYPE_STRING", Label: "LABEL_REPEATED"},
+			},
+			violation: "field Source changed label from LABEL_OPTIONAL to LABEL_REPEATED",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			current := &schemaLock{Messages: map[string]*lockedMessage{
+				".entities.Bar": {Fields: test.current, Reserved: test.reserved},
+			}}
+			violations := checkCompatibility(locked, current)
+			if test.violation == "" {
+				if len(violations) > 0 {
+					t.Fatalf("unexpected violations %v", violations)
+				}
+				return
+			}
+			if len(violations) != 1 || !strings.Contains(violations[0], test.violation) {
+				t.Fatalf("expected the violation %q, got %v", test.violation, violations)
+			}
+		})
+	}
+
+	violations := checkCompatibility(locked, &schemaLock{Messages: map[string]*lockedMessage{}})
+	if len(violations) != 1 || violations[0] != "message .entities.Bar was removed" {
+		t.Fatalf("expected the removal of the message, got %v", violations)
+	}
+}
+
+// Messages and fields of the current schema that the lock does not record as they are
+func unlockedFields(lock *schemaLock, current *schemaLock) []string {
+	var unlocked []string
+	for name, msg := range current.Messages {
+		locked, ok := lock.Messages[name]
+		if !ok {
+			unlocked = append(unlocked, "message "+name)
+			continue
+		}
+		for fieldName, field := range msg.Fields {
+			if lockedField, ok := locked.Fields[fieldName]; !ok || lockedField != field {
+				unlocked = append(unlocked, "field "+name+"."+fieldName)
+			}
+		}
+		reserved := map[int32]bool{}
+		for _, n := range locked.Reserved {
+			reserved[n] = true
+		}
+		for _, n := range msg.Reserved {
+			if !reserved[n] {
+				unlocked = append(unlocked, fmt.Sprintf("reserved number %d of message %s", n, name))
+			}
+		}
+	}
+	sort.Strings(unlocked)
+	return unlocked
+}
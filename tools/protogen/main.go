@@ -0,0 +1,168 @@
+// Command protogen compiles the .proto files of the shared entities and generates their Go code.
+// The output is the same as running
+//
+//	protoc --go_out=. proto/*.proto
+//
+// from the shared directory with protoc v4.25.1 and protoc-gen-go v1.31.0, without requiring
+// protoc to be installed. Before generating, the schema is checked for breaking changes
+// against the schema lock (see lock.go).
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/bufbuild/protocompile"
+	"google.golang.org/protobuf/cmd/protoc-gen-go/internal_gengo"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// Version of protoc reported in the generated files
+var compilerVersion = &pluginpb.Version{
+	Major:  proto.Int32(4),
+	Minor:  proto.Int32(25),
+	Patch:  proto.Int32(1),
+	Suffix: proto.String(""),
+}
+
+func main() {
+	root := flag.String("root", ".", "Directory the .proto paths are relative to (where protoc would be run from)")
+	protoDir := flag.String("proto", "proto", "Directory containing the .proto files, relative to root")
+	lockPath := flag.String("lock", "proto/schema.lock.json", "Schema lock file, relative to root")
+	checkOnly := flag.Bool("check", false, "Only check the schema for breaking changes, do not generate")
+	updateLock := flag.Bool("update-lock", false, "Record the current schema in the lock file after checking it")
+	flag.Parse()
+
+	if err := run(*root, *protoDir, *lockPath, *checkOnly, *updateLock); err != nil {
+		fmt.Fprintln(os.Stderr, "protogen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(root string, protoDir string, lockPath string, checkOnly bool, updateLock bool) error {
+	files, err := filepath.Glob(filepath.Join(root, protoDir, "*.proto"))
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no .proto files found in %s", filepath.Join(root, protoDir))
+	}
+	for i, file := range files {
+		if files[i], err = filepath.Rel(root, file); err != nil {
+			return err
+		}
+		files[i] = filepath.ToSlash(files[i])
+	}
+	sort.Strings(files)
+
+	fds, err := compile(root, files)
+	if err != nil {
+		return err
+	}
+
+	lockFile := filepath.Join(root, lockPath)
+	lock, err := readLock(lockFile)
+	if err != nil {
+		return err
+	}
+	current := newLock(fds)
+	if violations := checkCompatibility(lock, current); len(violations) > 0 {
+		for _, violation := range violations {
+			fmt.Fprintln(os.Stderr, violation)
+		}
+		return fmt.Errorf("%d breaking change(s) in the schema", len(violations))
+	}
+	if updateLock {
+		if err := writeLock(lockFile, current); err != nil {
+			return err
+		}
+	}
+	if checkOnly {
+		return nil
+	}
+	return generate(root, files, fds)
+}
+
+// Compile the .proto files into file descriptors, dependencies come before their dependents
+func compile(root string, files []string) ([]*descriptorpb.FileDescriptorProto, error) {
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			ImportPaths: []string{root},
+		}),
+		SourceInfoMode: protocompile.SourceInfoStandard,
+	}
+	compiled, err := compiler.Compile(context.Background(), files...)
+	if err != nil {
+		return nil, err
+	}
+
+	var fds []*descriptorpb.FileDescriptorProto
+	seen := map[string]bool{}
+	var add func(fd *descriptorpb.FileDescriptorProto, deps func(string) *descriptorpb.FileDescriptorProto)
+	add = func(fd *descriptorpb.FileDescriptorProto, deps func(string) *descriptorpb.FileDescriptorProto) {
+		if seen[fd.GetName()] {
+			return
+		}
+		seen[fd.GetName()] = true
+		for _, dep := range fd.GetDependency() {
+			add(deps(dep), deps)
+		}
+		fds = append(fds, fd)
+	}
+	byName := map[string]*descriptorpb.FileDescriptorProto{}
+	for _, file := range compiled {
+		byName[file.Path()] = protodesc.ToFileDescriptorProto(file)
+		imports := file.Imports()
+		for i := 0; i < imports.Len(); i++ {
+			imported := imports.Get(i).FileDescriptor
+			if _, ok := byName[imported.Path()]; !ok {
+				byName[imported.Path()] = protodesc.ToFileDescriptorProto(imported)
+			}
+		}
+	}
+	for _, file := range files {
+		add(byName[file], func(name string) *descriptorpb.FileDescriptorProto { return byName[name] })
+	}
+	return fds, nil
+}
+
+// Generate the Go code of the files with protoc-gen-go and write it relative to root
+func generate(root string, files []string, fds []*descriptorpb.FileDescriptorProto) error {
+	req := &pluginpb.CodeGeneratorRequest{
+		FileToGenerate:  files,
+		CompilerVersion: compilerVersion,
+		ProtoFile:       fds,
+	}
+	plugin, err := protogen.Options{}.New(req)
+	if err != nil {
+		return err
+	}
+	plugin.SupportedFeatures = internal_gengo.SupportedFeatures
+	for _, file := range plugin.Files {
+		if file.Generate {
+			internal_gengo.GenerateFile(plugin, file)
+		}
+	}
+	resp := plugin.Response()
+	if resp.Error != nil {
+		return fmt.Errorf("generating go code: %s", resp.GetError())
+	}
+	for _, file := range resp.GetFile() {
+		path := filepath.Join(root, filepath.FromSlash(file.GetName()))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(file.GetContent()), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}